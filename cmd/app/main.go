@@ -1,12 +1,33 @@
 package main
 
 import (
+	"fmt"
+	"net/http"
+	"time"
+	// Образ собирается FROM scratch, без базы часовых поясов, а она нужна для тихих часов уведомлений
+	_ "time/tzdata"
+
 	"github.com/EM-Stawberry/Stawberry/internal/adapter/auth"
+	"github.com/EM-Stawberry/Stawberry/internal/adapter/denylist"
+	"github.com/EM-Stawberry/Stawberry/internal/adapter/oidc"
+	"github.com/EM-Stawberry/Stawberry/internal/adapter/realtime"
+	"github.com/EM-Stawberry/Stawberry/internal/adapter/storage"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/apikey"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/audit"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/category"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/eventbus"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/image"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/loginguard"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/notification"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/permission"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/reviews"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/shopmember"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/sociallogin"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/token"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/webhook"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/wishlist"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
 	"github.com/EM-Stawberry/Stawberry/internal/repository"
 	"github.com/EM-Stawberry/Stawberry/pkg/database"
@@ -52,25 +73,35 @@ func main() {
 
 	migrator.RunMigrationsWithZap(db, "migrations", log)
 
-	database.DefaultAdminAcc()
+	if cfg.DB.CreateDefaultAdmin {
+		if err := database.DefaultAdminAcc(); err != nil {
+			log.Fatal("Failed to create default admin account", zap.Error(err))
+		}
+	}
 
-	router, mailer, auditMiddleware := initializeApp(cfg, db, log)
+	hub := realtime.NewHub(cfg.Realtime.BufferSize)
+	router, mailer, auditMiddleware, workers := initializeApp(cfg, db, hub, log)
 
-	if err := server.StartServer(router, mailer, &cfg.Server, log); err != nil {
+	if err := server.StartServer(router, mailer, &cfg.Server, log, hub.Close); err != nil {
 		log.Fatal("Failed to start server", zap.Error(err))
 	}
 
+	for _, worker := range workers {
+		worker.Close()
+	}
 	auditMiddleware.Close()
 }
 
 func initializeApp(
 	cfg *config.Config,
 	db *sqlx.DB,
+	hub *realtime.Hub,
 	log *zap.Logger,
 ) (
 	*gin.Engine,
 	email.MailerService,
-	*middleware.AuditMiddleware) {
+	*middleware.AuditMiddleware,
+	[]backgroundWorker) {
 	mailer := email.NewMailer(log, &cfg.Email)
 	log.Info("Mailer initialized")
 
@@ -83,41 +114,100 @@ func initializeApp(
 	sellerReviewsRepository := repo.NewSellerReviewRepository(db, log)
 	auditRepository := repository.NewAuditRepository(db)
 	guestOfferRepository := guestofferrepo.NewRepository(db)
+	categoryRepository := repository.NewCategoryRepository(db)
+	imageRepository := repository.NewImageRepository(db)
+	wishlistRepository := repository.NewWishlistRepository(db)
+	permissionRepository := repository.NewPermissionRepository(db)
+	shopMemberRepository := repository.NewShopMemberRepository(db)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(db)
+	oidcStateRepository := repository.NewOIDCStateRepository(db)
+	apiKeyRepository := repository.NewAPIKeyRepository(db)
+	webhookRepository := repository.NewWebhookRepository(db)
+	outboxRepository := repository.NewOutboxRepository(db)
 	log.Info("Repositories initialized")
 
-	passwordManager := security.NewArgon2idPasswordManager()
-	jwtManager := auth.NewJWTManager(cfg.Token.Secret)
+	imageStorage, localFiles, err := initializeImageStorage(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize image storage", zap.Error(err))
+	}
+	log.Info("Image storage initialized", zap.String("storage", cfg.Image.Storage))
 
-	productService := product.NewService(productRepository)
-	offerService := offer.NewService(offerRepository, mailer)
+	passwordManager := security.NewArgon2idPasswordManager(&cfg.Password)
+	jwtManager, err := auth.NewJWTManager(&cfg.Token)
+	if err != nil {
+		log.Fatal("Failed to load token signing keys", zap.Error(err))
+	}
+
+	imageService := image.NewService(imageRepository, imageStorage, &cfg.Image)
+	productService := product.NewService(productRepository, imageService)
+	webhookService := webhook.NewService(webhookRepository, shopMemberRepository, &cfg.Webhook)
+	offerService := offer.NewService(offerRepository)
 	tokenService := token.NewService(
 		tokenRepository,
 		jwtManager,
+		initializeDenylist(cfg, db),
 		cfg.Token.RefreshTokenDuration,
 		cfg.Token.AccessTokenDuration,
 	)
-	userService := user.NewService(userRepository, tokenService, passwordManager, mailer)
-	notificationService := notification.NewService(notificationRepository)
-	productReviewsService := reviews.NewProductReviewService(productReviewsRepository, log)
-	sellerReviewsService := reviews.NewSellerReviewService(sellerReviewsRepository, log)
 	auditService := audit.NewAuditService(auditRepository)
-	guestOfferService := guestofferservice.NewService(guestOfferRepository, mailer, log)
+	loginGuardService := loginguard.NewService(loginAttemptRepository, &cfg.Login)
+	userService := user.NewService(
+		userRepository,
+		tokenService,
+		passwordManager,
+		mailer,
+		auditService,
+		loginGuardService,
+		&cfg.Account,
+	)
+	realtimePublisher, realtimeBroker := initializeRealtimePublisher(cfg, db, hub, log)
+	notificationService := notification.NewService(
+		notificationRepository,
+		shopMemberRepository,
+		userRepository,
+		mailer,
+		realtimePublisher,
+		log,
+	)
+	productReviewsService := reviews.NewProductReviewService(productReviewsRepository, log)
+	sellerReviewsService := reviews.NewSellerReviewService(sellerReviewsRepository, notificationService, log)
+	guestOfferService := guestofferservice.NewService(guestOfferRepository, mailer, outboxRepository, log)
+	categoryService := category.NewService(categoryRepository)
+	wishlistService := wishlist.NewService(wishlistRepository)
+	permissionService := permission.NewService(permissionRepository)
+	shopMemberService := shopmember.NewService(shopMemberRepository, mailer, &cfg.Shop)
+	apiKeyService := apikey.NewService(apiKeyRepository, shopMemberRepository)
+	socialLoginService := sociallogin.NewService(
+		initializeOIDCProviders(cfg),
+		oidcStateRepository,
+		userService,
+		&cfg.OIDC,
+	)
 	log.Info("Services initialized")
 
 	healthHandler := handler.NewHealthHandler()
 	productHandler := handler.NewProductHandler(productService)
 	offerHandler := handler.NewOfferHandler(offerService)
 	userHandler := handler.NewUserHandler(cfg, userService)
-	notificationHandler := handler.NewNotificationHandler(notificationService)
+	notificationHandler := handler.NewNotificationHandler(notificationService, hub, &cfg.Realtime)
 	productReviewsHandler := hdlr.NewProductReviewHandler(productReviewsService, log)
 	sellerReviewsHandler := hdlr.NewSellerReviewsHandler(sellerReviewsService, log)
 	auditHandler := handler.NewAuditHandler(auditService)
 	guestOfferHandler := guesthandler.NewHandler(guestOfferService, log)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	imageHandler := handler.NewImageHandler(imageService, cfg.Image.MaxSize, localFiles)
+	wishlistHandler := handler.NewWishlistHandler(wishlistService)
+	permissionHandler := handler.NewPermissionHandler(permissionService)
+	shopMemberHandler := handler.NewShopMemberHandler(shopMemberService)
+	jwksHandler := handler.NewJWKSHandler(jwtManager)
+	socialLoginHandler := handler.NewSocialLoginHandler(cfg, socialLoginService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
 	log.Info("Handlers initialized")
 
 	auditMiddleware := middleware.NewAuditMiddleware(&cfg.Audit, auditService, log)
 
-	router := handler.SetupRouter(
+	router, err := handler.SetupRouter(
 		healthHandler,
 		productHandler,
 		offerHandler,
@@ -128,11 +218,128 @@ func initializeApp(
 		guestOfferHandler,
 		userService,
 		tokenService,
+		apiKeyService,
 		basePath,
 		log,
 		auditMiddleware,
 		auditHandler,
+		categoryHandler,
+		imageHandler,
+		wishlistHandler,
+		permissionHandler,
+		permissionService,
+		shopMemberHandler,
+		jwksHandler,
+		socialLoginHandler,
+		apiKeyHandler,
+		webhookHandler,
+		cfg.Account.RequireVerifiedEmail,
+		cfg.Server.TrustedProxies,
+	)
+	if err != nil {
+		log.Fatal("Failed to set up router", zap.Error(err))
+	}
+
+	wishlistEvaluator := wishlist.NewEvaluator(
+		wishlistRepository,
+		productRepository,
+		notificationService,
+		cfg.Wishlist.EvaluationInterval,
+		log,
+	)
+
+	webhookDispatcher := webhook.NewDispatcher(
+		webhookRepository,
+		webhook.NewHTTPClient(&cfg.Webhook),
+		&cfg.Webhook,
+		log,
 	)
+	offerExpirer := offer.NewExpirer(offerService, cfg.Offer.ExpiryInterval, log)
+	notificationDigester := notification.NewDigester(notificationService, cfg.Notification.DigestInterval, log)
+
+	// Имена подписчиков хранятся в outbox вместе с событиями, их нельзя переименовывать
+	eventBus := eventbus.NewBus(outboxRepository, &cfg.Events, log)
+	eventBus.Subscribe("notifications", notificationService.HandleOfferEvent, entity.OfferEvents...)
+	eventBus.Subscribe("webhooks", webhookService.HandleOfferEvent, entity.OfferEvents...)
+	eventBus.Subscribe("audit", auditService.HandleEvent, entity.OfferEvents...)
+	eventBus.Subscribe("guest-offer-mailer", guestOfferService.HandleGuestOfferReceived, entity.EventGuestOfferReceived)
+	eventBus.Start()
+
+	workers := []backgroundWorker{
+		wishlistEvaluator,
+		webhookDispatcher,
+		offerExpirer,
+		notificationDigester,
+		eventBus,
+	}
+	if realtimeBroker != nil {
+		workers = append(workers, realtimeBroker)
+	}
 
-	return router, mailer, auditMiddleware
+	return router, mailer, auditMiddleware, workers
+}
+
+// backgroundWorker это фоновая задача, которую нужно остановить после остановки сервера
+type backgroundWorker interface {
+	Close()
+}
+
+// initializeDenylist выбирает хранилище отозванных access токенов. Список в памяти
+// годится только для одного экземпляра приложения, поэтому по умолчанию используется Postgres.
+// Ответы Postgres кэшируются на короткое время, чтобы не ходить в базу на каждый запрос.
+func initializeDenylist(cfg *config.Config, db *sqlx.DB) token.Denylist {
+	if cfg.Token.Denylist == "memory" {
+		return denylist.NewMemory(cfg.Token.DenylistSize)
+	}
+
+	store := repository.NewDenylistRepository(db)
+	if cfg.Token.DenylistCacheTTL <= 0 {
+		return store
+	}
+
+	return denylist.NewCached(store, cfg.Token.DenylistSize, cfg.Token.DenylistCacheTTL)
+}
+
+// initializeRealtimePublisher выбирает, как сообщения попадают в потоки пользователей.
+// Через Postgres они доходят до клиентов, подключенных к любому экземпляру приложения,
+// напрямую в hub только до подключенных к этому. Второе значение нужно остановить при выходе.
+func initializeRealtimePublisher(
+	cfg *config.Config,
+	db *sqlx.DB,
+	hub *realtime.Hub,
+	log *zap.Logger,
+) (notification.Publisher, backgroundWorker) {
+	if cfg.Realtime.Broker == "memory" {
+		return hub, nil
+	}
+
+	broker := realtime.NewPostgres(db, hub, log)
+	return broker, broker
+}
+
+// initializeOIDCProviders создает клиентов внешних провайдеров входа.
+// Метаданные провайдера загружаются при первом входе, поэтому недоступный провайдер не мешает запуску.
+func initializeOIDCProviders(cfg *config.Config) map[string]sociallogin.Provider {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	providers := make(map[string]sociallogin.Provider, len(cfg.OIDC.Providers))
+	for _, p := range cfg.OIDC.Providers {
+		providers[p.Name] = oidc.NewProvider(p, client)
+	}
+
+	return providers
+}
+
+// initializeImageStorage выбирает хранилище изображений. Локальное хранилище дополнительно
+// возвращается как LocalFiles, чтобы приложение само раздавало файлы по подписанным ссылкам.
+func initializeImageStorage(cfg *config.Config) (image.Storage, handler.LocalFiles, error) {
+	if cfg.Image.Storage == "s3" {
+		return storage.NewS3Storage(cfg), nil, nil
+	}
+
+	local, err := storage.NewLocalStorage(cfg.Image.LocalDir, cfg.Image.PublicURL, cfg.Image.SigningSecret)
+	if err != nil {
+		return nil, nil, fmt.Errorf("set IMAGE_SIGNING_SECRET for the local storage: %w", err)
+	}
+	return local, local, nil
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	flag "github.com/spf13/pflag"
@@ -25,6 +26,8 @@ type DBConfig struct {
 	Port         string
 	MaxOpenConns int
 	MaxIdleConns int
+	// CreateDefaultAdmin включает создание admin@admin.com с паролем DefAdmPswd при запуске
+	CreateDefaultAdmin bool
 }
 
 func (dbc *DBConfig) GetDBConnString() string {
@@ -38,12 +41,24 @@ type ServerConfig struct {
 	Domain  string
 	Port    string
 	GinMode string
+	// TrustedProxies адреса и подсети прокси, чьим X-Forwarded-For можно верить; пустой список отключает доверие
+	TrustedProxies []string
 }
 
 type TokenConfig struct {
 	Secret               string
 	AccessTokenDuration  time.Duration
 	RefreshTokenDuration time.Duration
+	// KeysDir каталог с ключами подписи; если пуст, используется HS256 с Secret
+	KeysDir     string
+	ActiveKeyID string
+	Issuer      string
+	Audience    string
+	// Denylist хранилище отозванных access токенов: postgres или memory
+	Denylist     string
+	DenylistSize int
+	// DenylistCacheTTL сколько экземпляр помнит ответы Postgres; 0 отключает кэш
+	DenylistCacheTTL time.Duration
 }
 
 type EmailConfig struct {
@@ -62,6 +77,132 @@ type AuditConfig struct {
 	BatchSize      int
 }
 
+type ImageConfig struct {
+	Storage       string
+	LocalDir      string
+	PublicURL     string
+	MaxSize       int64
+	ThumbnailSize int
+	URLTTL        time.Duration
+	// SigningSecret ключ HMAC для ссылок локального хранилища, не совпадает с ключами токенов
+	SigningSecret string
+}
+
+type AccountConfig struct {
+	VerificationTokenTTL time.Duration
+	ResetTokenTTL        time.Duration
+	UnlockTokenTTL       time.Duration
+	RequireVerifiedEmail bool
+	NotifyTokenReuse     bool
+	// MFAIssuer показывается в приложении-аутентификаторе рядом с почтой пользователя
+	MFAIssuer string
+	// MFAChallengeTTL время, за которое нужно ввести код второго фактора после пароля
+	MFAChallengeTTL   time.Duration
+	RecoveryCodeCount int
+}
+
+// LoginConfig задает защиту входа от перебора паролей
+type LoginConfig struct {
+	// FreeAttempts неудачных попыток подряд проходят без задержки, дальше задержка растет вдвое
+	FreeAttempts   int
+	IPFreeAttempts int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	// LockoutThreshold неудачных попыток подряд блокируют учетную запись на LockoutDuration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	// Window через столько времени без неудачных попыток счетчик сбрасывается
+	Window time.Duration
+}
+
+// PasswordConfig задает параметры Argon2id и ограничивает одновременные вычисления хэшей паролей,
+// каждое из которых занимает десятки мегабайт памяти
+type PasswordConfig struct {
+	HashConcurrency int
+	HashWait        time.Duration
+	// ArgonMemory объем памяти на одно вычисление в КиБ
+	ArgonMemory  uint32
+	ArgonTime    uint32
+	ArgonThreads uint8
+	// Pepper секрет, который подмешивается ко всем паролям. Его смена делает старые хэши непроверяемыми.
+	Pepper string
+}
+
+// OIDCProviderConfig описывает внешнего OIDC провайдера для входа
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL страница клиента, которая получает code и state и передает их в API
+	RedirectURL string
+	Scopes      []string
+}
+
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+	// StateTTL время, за которое нужно вернуться от провайдера
+	StateTTL time.Duration
+}
+
+type ShopConfig struct {
+	InvitationTTL time.Duration
+}
+
+type WishlistConfig struct {
+	EvaluationInterval time.Duration
+}
+
+// WebhookConfig задает доставку вебхуков магазинов
+type WebhookConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Timeout      time.Duration
+	// MaxAttempts неудачных попыток переводят доставку в dead, дальше ее можно только переотправить вручную
+	MaxAttempts int
+	// RetryBaseDelay задержка перед второй попыткой, дальше она растет вдвое до RetryMaxDelay
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// AllowPrivateTargets разрешает http и адреса во внутренних сетях, только для разработки
+	AllowPrivateTargets bool
+}
+
+// EventsConfig задает раздачу доменных событий из outbox подписчикам
+type EventsConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	// Lease время, на которое взятое в работу событие скрыто от других экземпляров приложения
+	Lease time.Duration
+	// MaxAttempts неудачных раздач переводят событие в dead
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// Retention сколько хранить обработанные события
+	Retention time.Duration
+}
+
+// RealtimeConfig задает поток уведомлений подключенным клиентам
+type RealtimeConfig struct {
+	// Broker раздает сообщения между экземплярами приложения: postgres (LISTEN/NOTIFY) или memory
+	Broker string
+	// BufferSize сообщений на подключение. Клиент, который не успевает их читать, отключается.
+	BufferSize        int
+	HeartbeatInterval time.Duration
+	// MaxConnectionTime после которого соединение закрывается, чтобы клиент переподключился со свежим токеном
+	MaxConnectionTime time.Duration
+}
+
+// NotificationConfig задает отправку отложенных писем с уведомлениями
+type NotificationConfig struct {
+	// DigestInterval как часто проверяется, кому пора отправить дайджест
+	DigestInterval time.Duration
+}
+
+type OfferConfig struct {
+	// ExpiryInterval как часто просроченные заявки переводятся в cancelled
+	ExpiryInterval time.Duration
+}
+
 type Config struct {
 	AccessKey     string
 	SecretKey     string
@@ -70,11 +211,23 @@ type Config struct {
 	SigningRegion string
 	Environment   string
 
-	DB     DBConfig
-	Server ServerConfig
-	Token  TokenConfig
-	Email  EmailConfig
-	Audit  AuditConfig
+	DB           DBConfig
+	Server       ServerConfig
+	Token        TokenConfig
+	Email        EmailConfig
+	Audit        AuditConfig
+	Image        ImageConfig
+	Wishlist     WishlistConfig
+	Shop         ShopConfig
+	Account      AccountConfig
+	Login        LoginConfig
+	Password     PasswordConfig
+	OIDC         OIDCConfig
+	Webhook      WebhookConfig
+	Offer        OfferConfig
+	Events       EventsConfig
+	Realtime     RealtimeConfig
+	Notification NotificationConfig
 }
 
 func LoadConfig() *Config {
@@ -97,6 +250,58 @@ func LoadConfig() *Config {
 	viper.SetDefault("DB_MAX_IDLE_CONNS", 10)
 	viper.SetDefault("SERVER_PORT", 8080)
 	viper.SetDefault("AUDIT_BATCH_SIZE", 100)
+	viper.SetDefault("IMAGE_STORAGE", "local")
+	viper.SetDefault("IMAGE_LOCAL_DIR", "./uploads")
+	viper.SetDefault("IMAGE_PUBLIC_URL", "/api/v1/images")
+	viper.SetDefault("IMAGE_MAX_SIZE", 5*1024*1024)
+	viper.SetDefault("IMAGE_THUMBNAIL_SIZE", 256)
+	viper.SetDefault("IMAGE_URL_TTL", time.Hour)
+	viper.SetDefault("TOKEN_ISSUER", "stawberry")
+	viper.SetDefault("TOKEN_AUDIENCE", "stawberry-api")
+	viper.SetDefault("TOKEN_DENYLIST", "postgres")
+	viper.SetDefault("TOKEN_DENYLIST_SIZE", 100000)
+	viper.SetDefault("TOKEN_DENYLIST_CACHE_TTL", 5*time.Second)
+	viper.SetDefault("WISHLIST_EVAL_INTERVAL", 10*time.Minute)
+	viper.SetDefault("SHOP_INVITATION_TTL", 72*time.Hour)
+	viper.SetDefault("ACCOUNT_VERIFICATION_TTL", 24*time.Hour)
+	viper.SetDefault("ACCOUNT_RESET_TTL", time.Hour)
+	viper.SetDefault("ACCOUNT_UNLOCK_TTL", time.Hour)
+	viper.SetDefault("ACCOUNT_NOTIFY_TOKEN_REUSE", true)
+	viper.SetDefault("ACCOUNT_MFA_ISSUER", "Stawberry")
+	viper.SetDefault("ACCOUNT_MFA_CHALLENGE_TTL", 5*time.Minute)
+	viper.SetDefault("ACCOUNT_RECOVERY_CODES", 10)
+	viper.SetDefault("LOGIN_FREE_ATTEMPTS", 3)
+	viper.SetDefault("LOGIN_IP_FREE_ATTEMPTS", 20)
+	viper.SetDefault("LOGIN_BASE_DELAY", time.Second)
+	viper.SetDefault("LOGIN_MAX_DELAY", 15*time.Minute)
+	viper.SetDefault("LOGIN_LOCKOUT_THRESHOLD", 10)
+	viper.SetDefault("LOGIN_LOCKOUT_DURATION", time.Hour)
+	viper.SetDefault("LOGIN_WINDOW", time.Hour)
+	viper.SetDefault("PASSWORD_HASH_CONCURRENCY", 4)
+	viper.SetDefault("PASSWORD_HASH_WAIT", 2*time.Second)
+	viper.SetDefault("PASSWORD_ARGON_MEMORY", 64*1024)
+	viper.SetDefault("PASSWORD_ARGON_TIME", 1)
+	viper.SetDefault("PASSWORD_ARGON_THREADS", 4)
+	viper.SetDefault("OIDC_STATE_TTL", 10*time.Minute)
+	viper.SetDefault("WEBHOOK_POLL_INTERVAL", 5*time.Second)
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 20)
+	viper.SetDefault("WEBHOOK_TIMEOUT", 10*time.Second)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 10)
+	viper.SetDefault("WEBHOOK_RETRY_BASE_DELAY", 30*time.Second)
+	viper.SetDefault("WEBHOOK_RETRY_MAX_DELAY", 6*time.Hour)
+	viper.SetDefault("OFFER_EXPIRY_INTERVAL", time.Minute)
+	viper.SetDefault("EVENTS_POLL_INTERVAL", time.Second)
+	viper.SetDefault("EVENTS_BATCH_SIZE", 100)
+	viper.SetDefault("EVENTS_LEASE", time.Minute)
+	viper.SetDefault("EVENTS_MAX_ATTEMPTS", 10)
+	viper.SetDefault("EVENTS_RETRY_BASE_DELAY", 5*time.Second)
+	viper.SetDefault("EVENTS_RETRY_MAX_DELAY", time.Hour)
+	viper.SetDefault("EVENTS_RETENTION", 7*24*time.Hour)
+	viper.SetDefault("REALTIME_BROKER", "postgres")
+	viper.SetDefault("REALTIME_BUFFER_SIZE", 64)
+	viper.SetDefault("REALTIME_HEARTBEAT_INTERVAL", 25*time.Second)
+	viper.SetDefault("REALTIME_MAX_CONNECTION_TIME", time.Hour)
+	viper.SetDefault("NOTIFICATION_DIGEST_INTERVAL", 5*time.Minute)
 
 	config := &Config{
 		AccessKey:     viper.GetString("ACCESS_KEY"),
@@ -114,16 +319,28 @@ func LoadConfig() *Config {
 			Port:         viper.GetString("DB_PORT"),
 			MaxOpenConns: viper.GetInt("DB_MAX_OPEN_CONNS"),
 			MaxIdleConns: viper.GetInt("DB_MAX_IDLE_CONNS"),
+
+			CreateDefaultAdmin: viper.GetBool("DEFAULT_ADMIN_CREATE"),
 		},
 		Server: ServerConfig{
 			Domain:  viper.GetString("SERVER_DOMAIN"),
 			Port:    viper.GetString("SERVER_PORT"),
 			GinMode: viper.GetString("GIN_MODE"),
+
+			TrustedProxies: loadTrustedProxies(),
 		},
 		Token: TokenConfig{
 			Secret:               viper.GetString("TOKEN_SECRET"),
 			AccessTokenDuration:  viper.GetDuration("TOKEN_ACCESS_DURATION"),
 			RefreshTokenDuration: viper.GetDuration("TOKEN_REFRESH_DURATION"),
+			KeysDir:              viper.GetString("TOKEN_KEYS_DIR"),
+			ActiveKeyID:          viper.GetString("TOKEN_ACTIVE_KID"),
+			Issuer:               viper.GetString("TOKEN_ISSUER"),
+			Audience:             viper.GetString("TOKEN_AUDIENCE"),
+			Denylist:             viper.GetString("TOKEN_DENYLIST"),
+			DenylistSize:         viper.GetInt("TOKEN_DENYLIST_SIZE"),
+
+			DenylistCacheTTL: viper.GetDuration("TOKEN_DENYLIST_CACHE_TTL"),
 		},
 		Email: EmailConfig{
 			Enabled:    viper.GetBool("EMAIL_ENABLED") || viper.GetBool("mail"),
@@ -139,7 +356,125 @@ func LoadConfig() *Config {
 			QueueSize:      viper.GetInt("AUDIT_QUEUE_SIZE"),
 			BatchSize:      viper.GetInt("AUDIT_BATCH_SIZE"),
 		},
+		Image: ImageConfig{
+			Storage:       viper.GetString("IMAGE_STORAGE"),
+			LocalDir:      viper.GetString("IMAGE_LOCAL_DIR"),
+			PublicURL:     viper.GetString("IMAGE_PUBLIC_URL"),
+			MaxSize:       viper.GetInt64("IMAGE_MAX_SIZE"),
+			ThumbnailSize: viper.GetInt("IMAGE_THUMBNAIL_SIZE"),
+			URLTTL:        viper.GetDuration("IMAGE_URL_TTL"),
+			SigningSecret: viper.GetString("IMAGE_SIGNING_SECRET"),
+		},
+		Wishlist: WishlistConfig{
+			EvaluationInterval: viper.GetDuration("WISHLIST_EVAL_INTERVAL"),
+		},
+		Shop: ShopConfig{
+			InvitationTTL: viper.GetDuration("SHOP_INVITATION_TTL"),
+		},
+		Account: AccountConfig{
+			VerificationTokenTTL: viper.GetDuration("ACCOUNT_VERIFICATION_TTL"),
+			ResetTokenTTL:        viper.GetDuration("ACCOUNT_RESET_TTL"),
+			UnlockTokenTTL:       viper.GetDuration("ACCOUNT_UNLOCK_TTL"),
+			RequireVerifiedEmail: viper.GetBool("ACCOUNT_REQUIRE_VERIFIED_EMAIL"),
+			NotifyTokenReuse:     viper.GetBool("ACCOUNT_NOTIFY_TOKEN_REUSE"),
+			MFAIssuer:            viper.GetString("ACCOUNT_MFA_ISSUER"),
+			MFAChallengeTTL:      viper.GetDuration("ACCOUNT_MFA_CHALLENGE_TTL"),
+			RecoveryCodeCount:    viper.GetInt("ACCOUNT_RECOVERY_CODES"),
+		},
+		Login: LoginConfig{
+			FreeAttempts:     viper.GetInt("LOGIN_FREE_ATTEMPTS"),
+			IPFreeAttempts:   viper.GetInt("LOGIN_IP_FREE_ATTEMPTS"),
+			BaseDelay:        viper.GetDuration("LOGIN_BASE_DELAY"),
+			MaxDelay:         viper.GetDuration("LOGIN_MAX_DELAY"),
+			LockoutThreshold: viper.GetInt("LOGIN_LOCKOUT_THRESHOLD"),
+			LockoutDuration:  viper.GetDuration("LOGIN_LOCKOUT_DURATION"),
+			Window:           viper.GetDuration("LOGIN_WINDOW"),
+		},
+		Password: PasswordConfig{
+			HashConcurrency: viper.GetInt("PASSWORD_HASH_CONCURRENCY"),
+			HashWait:        viper.GetDuration("PASSWORD_HASH_WAIT"),
+			ArgonMemory:     viper.GetUint32("PASSWORD_ARGON_MEMORY"),
+			ArgonTime:       viper.GetUint32("PASSWORD_ARGON_TIME"),
+			ArgonThreads:    uint8(viper.GetUint("PASSWORD_ARGON_THREADS")),
+			Pepper:          viper.GetString("PASSWORD_PEPPER"),
+		},
+		OIDC: OIDCConfig{
+			Providers: loadOIDCProviders(),
+			StateTTL:  viper.GetDuration("OIDC_STATE_TTL"),
+		},
+		Webhook: WebhookConfig{
+			PollInterval:        viper.GetDuration("WEBHOOK_POLL_INTERVAL"),
+			BatchSize:           viper.GetInt("WEBHOOK_BATCH_SIZE"),
+			Timeout:             viper.GetDuration("WEBHOOK_TIMEOUT"),
+			MaxAttempts:         viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			RetryBaseDelay:      viper.GetDuration("WEBHOOK_RETRY_BASE_DELAY"),
+			RetryMaxDelay:       viper.GetDuration("WEBHOOK_RETRY_MAX_DELAY"),
+			AllowPrivateTargets: viper.GetBool("WEBHOOK_ALLOW_PRIVATE_TARGETS"),
+		},
+		Offer: OfferConfig{
+			ExpiryInterval: viper.GetDuration("OFFER_EXPIRY_INTERVAL"),
+		},
+		Events: EventsConfig{
+			PollInterval:   viper.GetDuration("EVENTS_POLL_INTERVAL"),
+			BatchSize:      viper.GetInt("EVENTS_BATCH_SIZE"),
+			Lease:          viper.GetDuration("EVENTS_LEASE"),
+			MaxAttempts:    viper.GetInt("EVENTS_MAX_ATTEMPTS"),
+			RetryBaseDelay: viper.GetDuration("EVENTS_RETRY_BASE_DELAY"),
+			RetryMaxDelay:  viper.GetDuration("EVENTS_RETRY_MAX_DELAY"),
+			Retention:      viper.GetDuration("EVENTS_RETENTION"),
+		},
+		Realtime: RealtimeConfig{
+			Broker:            viper.GetString("REALTIME_BROKER"),
+			BufferSize:        viper.GetInt("REALTIME_BUFFER_SIZE"),
+			HeartbeatInterval: viper.GetDuration("REALTIME_HEARTBEAT_INTERVAL"),
+			MaxConnectionTime: viper.GetDuration("REALTIME_MAX_CONNECTION_TIME"),
+		},
+		Notification: NotificationConfig{
+			DigestInterval: viper.GetDuration("NOTIFICATION_DIGEST_INTERVAL"),
+		},
 	}
 
 	return config
 }
+
+// loadTrustedProxies читает адреса и подсети прокси, перечисленные через запятую в TRUSTED_PROXIES.
+// По умолчанию список пуст, и IP клиента берется из адреса соединения.
+func loadTrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(viper.GetString("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// loadOIDCProviders читает провайдеров, перечисленных через запятую в OIDC_PROVIDERS.
+// Настройки провайдера name задаются переменными OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET,
+// _REDIRECT_URL и _SCOPES (через пробел, по умолчанию "openid email profile").
+func loadOIDCProviders() []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(viper.GetString("OIDC_PROVIDERS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		scopes := strings.Fields(viper.GetString(prefix + "SCOPES"))
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "email", "profile"}
+		}
+
+		providers = append(providers, OIDCProviderConfig{
+			Name:         strings.ToLower(name),
+			Issuer:       viper.GetString(prefix + "ISSUER"),
+			ClientID:     viper.GetString(prefix + "CLIENT_ID"),
+			ClientSecret: viper.GetString(prefix + "CLIENT_SECRET"),
+			RedirectURL:  viper.GetString(prefix + "REDIRECT_URL"),
+			Scopes:       scopes,
+		})
+	}
+
+	return providers
+}
@@ -10,8 +10,6 @@ import (
 	"net/http/httptest"
 	"time"
 
-	"github.com/EM-Stawberry/Stawberry/pkg/email"
-
 	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
 
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
@@ -102,10 +100,11 @@ func mockAuthShopOwnerMiddleware() gin.HandlerFunc {
 			Password: "no",
 			Email:    "user1email",
 			Phone:    "user1phone",
-			IsStore:  true,
+			Role:     entity.RoleShop,
 		}
 		c.Set("user", mockUser)
 		c.Set(helpers.UserIDKey, uint(1))
+		c.Set(helpers.UserRoleKey, entity.RoleShop)
 		c.Set(helpers.UserIsStoreKey, true)
 		c.Set(helpers.UserName, "user1")
 		c.Set(helpers.UserEmail, "user1email")
@@ -121,10 +120,11 @@ func mockAuthBuyerMiddleware() gin.HandlerFunc {
 			Password: "no",
 			Email:    "user2email",
 			Phone:    "user2phone",
-			IsStore:  false,
+			Role:     entity.RoleUser,
 		}
 		c.Set("user", mockUser)
 		c.Set(helpers.UserIDKey, uint(2))
+		c.Set(helpers.UserRoleKey, entity.RoleUser)
 		c.Set(helpers.UserIsStoreKey, false)
 		c.Set(helpers.UserName, "user2")
 		c.Set(helpers.UserEmail, "user2email")
@@ -140,10 +140,11 @@ func mockAuthIncorrectShopOwnerMiddleware() gin.HandlerFunc {
 			Password: "no",
 			Email:    "user3email",
 			Phone:    "user3phone",
-			IsStore:  true,
+			Role:     entity.RoleShop,
 		}
 		c.Set("user", mockUser)
 		c.Set(helpers.UserIDKey, uint(3))
+		c.Set(helpers.UserRoleKey, entity.RoleShop)
 		c.Set(helpers.UserIsStoreKey, true)
 		c.Set(helpers.UserName, "user3")
 		c.Set(helpers.UserEmail, "user3email")
@@ -151,27 +152,6 @@ func mockAuthIncorrectShopOwnerMiddleware() gin.HandlerFunc {
 	}
 }
 
-type mockMailer struct{}
-
-func newMockMailer() email.MailerService {
-	return &mockMailer{}
-}
-
-func (m *mockMailer) Registered(userName string, userMail string) {
-}
-
-func (m *mockMailer) StatusUpdate(offerID uint, status string, userMail string) {
-}
-
-func (m *mockMailer) OfferReceived(offerID uint, userMail string) {
-}
-
-func (m *mockMailer) SendGuestOfferNotification(email string, subject string, body string) {
-}
-
-func (m *mockMailer) Stop(ctx context.Context) {
-}
-
 func setupRouter(authMiddleware gin.HandlerFunc, method, path string, handlerFunc gin.HandlerFunc) *gin.Engine {
 	router := gin.New()
 	gin.SetMode(gin.TestMode)
@@ -207,10 +187,9 @@ var _ = ginkgo.Describe("offer handlers", ginkgo.Ordered, func() {
 			slog.Error(err.Error())
 			ginkgo.Fail("Failed to get database connection")
 		}
-		mailer := newMockMailer()
 
 		offerRepo = repository.NewOfferRepository(db)
-		offerServ = offer.NewService(offerRepo, mailer)
+		offerServ = offer.NewService(offerRepo)
 		offerHand = handler.NewOfferHandler(offerServ)
 	})
 
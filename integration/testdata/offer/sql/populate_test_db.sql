@@ -1,10 +1,10 @@
 -- test data
-insert into users (name, phone_number, password_hash, email, is_store)
-values ('user1','user1phone', 'no','user1email', true);
-insert into users (name, phone_number, password_hash, email, is_store)
-values ('user2','user2phone', 'no','user2email', false);
-insert into users (name, phone_number, password_hash, email, is_store)
-values ('user3','user3phone', 'no','user3email', true);
+insert into users (name, phone_number, password_hash, email, role)
+values ('user1','user1phone', 'no','user1email', 'shop');
+insert into users (name, phone_number, password_hash, email, role)
+values ('user2','user2phone', 'no','user2email', 'user');
+insert into users (name, phone_number, password_hash, email, role)
+values ('user3','user3phone', 'no','user3email', 'shop');
 
 insert into shops (name, user_id) values ('shop1', 1);
 insert into shops (name, user_id) values ('shop2', 1);
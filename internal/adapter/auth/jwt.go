@@ -1,80 +1,161 @@
 package auth
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/EM-Stawberry/Stawberry/config"
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-type AccessTokenClaims struct {
-	UserID    uint
-	IssuedAt  time.Time
-	ExpiresAt time.Time
+// accessClaims это содержимое access токена: стандартные claims и роль пользователя
+type accessClaims struct {
+	jwt.RegisteredClaims
+	Role entity.Role `json:"role"`
 }
 
+// JWTManager выпускает и проверяет access токены.
+// Если задан каталог ключей, токены подписываются асимметрично (RS256 или EdDSA) с указанием kid
+// и могут проверяться другими сервисами по JWKS. Иначе используется HS256 с общим секретом.
 type JWTManager struct {
-	secret        string
-	signingMethod jwt.SigningMethod
+	secret    string
+	keys      map[string]signingKey
+	activeKID string
+	issuer    string
+	audience  string
 }
 
-func NewJWTManager(secret string) *JWTManager {
-	return &JWTManager{
-		secret:        secret,
-		signingMethod: jwt.SigningMethodHS256,
+func NewJWTManager(cfg *config.TokenConfig) (*JWTManager, error) {
+	j := &JWTManager{
+		secret:   cfg.Secret,
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
 	}
+
+	if cfg.KeysDir == "" {
+		return j, nil
+	}
+
+	keys, activeKID, err := loadKeys(cfg.KeysDir, cfg.ActiveKeyID)
+	if err != nil {
+		return nil, err
+	}
+	j.keys = keys
+	j.activeKID = activeKID
+
+	return j, nil
 }
 
-func (j *JWTManager) Generate(userID uint, duration time.Duration) (string, error) {
-	claims := jwt.MapClaims{
-		"sub": userID,
-		"iat": time.Now().Unix(),
-		"exp": time.Now().Add(duration).Unix(),
+// Generate выпускает access токен и возвращает его вместе с содержимым
+func (j *JWTManager) Generate(
+	userID uint,
+	role entity.Role,
+	duration time.Duration,
+) (string, entity.AccessToken, error) {
+	now := time.Now()
+	claims := accessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    j.issuer,
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			Audience:  jwt.ClaimStrings{j.audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        uuid.NewString(),
+		},
+		Role: role,
+	}
+
+	var (
+		tokenString string
+		err         error
+	)
+	if j.keys == nil {
+		tokenString, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(j.secret))
+	} else {
+		key := j.keys[j.activeKID]
+		token := jwt.NewWithClaims(key.method, claims)
+		token.Header["kid"] = key.kid
+		tokenString, err = token.SignedString(key.private)
 	}
-	token := jwt.NewWithClaims(j.signingMethod, claims)
-	tokenString, err := token.SignedString([]byte(j.secret))
 	if err != nil {
-		appErr := apperror.ErrInvalidToken
-		appErr.WrappedErr = err
-		return "", appErr
+		return "", entity.AccessToken{}, apperror.New(apperror.InternalError, "failed to sign access token", err)
 	}
-	return tokenString, nil
+
+	return tokenString, entity.AccessToken{
+		ID:        claims.ID,
+		UserID:    userID,
+		Role:      role,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
 }
 
 func (j *JWTManager) Parse(token string) (entity.AccessToken, error) {
-	claim := jwt.MapClaims{}
-	_, err := jwt.ParseWithClaims(token, claim, func(token *jwt.Token) (any, error) {
-		if token.Header["alg"] != j.signingMethod.Alg() {
-			return nil, apperror.ErrInvalidToken
-		}
-		return []byte(j.secret), nil
-	})
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(token, &claims, j.keyFunc,
+		jwt.WithIssuer(j.issuer),
+		jwt.WithAudience(j.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
 	if err != nil {
-		return entity.AccessToken{}, apperror.ErrInvalidToken
+		return entity.AccessToken{}, apperror.New(apperror.InvalidToken, "invalid token", err)
 	}
 
-	userID, ok := claim["sub"].(float64)
-	if !ok {
+	userID, err := strconv.ParseUint(claims.Subject, 10, 0)
+	if err != nil || userID == 0 {
 		return entity.AccessToken{}, apperror.ErrInvalidToken
 	}
 
-	unixExpiresAt, ok := claim["exp"].(float64)
-	if !ok {
+	if claims.ID == "" || claims.IssuedAt == nil || !claims.Role.IsValid() {
 		return entity.AccessToken{}, apperror.ErrInvalidToken
 	}
-	expiresAt := time.Unix(int64(unixExpiresAt), 0)
 
-	unixIssuedAt, ok := claim["iat"].(float64)
+	return entity.AccessToken{
+		ID:        claims.ID,
+		UserID:    uint(userID),
+		Role:      claims.Role,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// keyFunc подбирает ключ проверки по kid и не дает подменить алгоритм в заголовке токена
+func (j *JWTManager) keyFunc(token *jwt.Token) (any, error) {
+	if j.keys == nil {
+		if token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return []byte(j.secret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.keys[kid]
 	if !ok {
-		return entity.AccessToken{}, apperror.ErrInvalidToken
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %s", token.Method.Alg(), kid)
 	}
 
-	issuedAt := time.Unix(int64(unixIssuedAt), 0)
+	return key.public, nil
+}
 
-	return entity.AccessToken{
-		UserID:    uint(userID),
-		IssuedAt:  issuedAt,
-		ExpiresAt: expiresAt,
-	}, nil
+// PublicKeys возвращает ключи проверки для публикации в JWKS.
+// В режиме HS256 публиковать нечего.
+func (j *JWTManager) PublicKeys() []entity.PublicKey {
+	keys := make([]entity.PublicKey, 0, len(j.keys))
+	for _, kid := range sortedKIDs(j.keys) {
+		key := j.keys[kid]
+		keys = append(keys, entity.PublicKey{
+			KID:       key.kid,
+			Algorithm: key.method.Alg(),
+			Key:       key.public,
+		})
+	}
+	return keys
 }
@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func writePEM(path, blockType string, der []byte) {
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	Expect(err).NotTo(HaveOccurred())
+}
+
+var _ = Describe("JWTManager", func() {
+	var cfg config.TokenConfig
+
+	BeforeEach(func() {
+		cfg = config.TokenConfig{
+			Secret:   "secret",
+			Issuer:   "stawberry",
+			Audience: "stawberry-api",
+		}
+	})
+
+	Context("with HS256 secret", func() {
+		It("should round-trip standard claims", func() {
+			manager, err := NewJWTManager(&cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			token, issued, err := manager.Generate(7, entity.RoleShop, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			access, err := manager.Parse(token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(access.UserID).To(Equal(uint(7)))
+			Expect(access.Role).To(Equal(entity.RoleShop))
+			Expect(access.ID).NotTo(BeEmpty())
+			Expect(access.ID).To(Equal(issued.ID))
+			Expect(manager.PublicKeys()).To(BeEmpty())
+		})
+
+		It("should reject a token for another audience", func() {
+			manager, err := NewJWTManager(&cfg)
+			Expect(err).NotTo(HaveOccurred())
+			token, _, err := manager.Generate(7, entity.RoleUser, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg.Audience = "other-api"
+			other, err := NewJWTManager(&cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = other.Parse(token)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("with a keys directory", func() {
+		var dir string
+
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			cfg.KeysDir = dir
+
+			rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).NotTo(HaveOccurred())
+			writePEM(filepath.Join(dir, "2025-01.pem"), "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+			_, edKey, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			der, err := x509.MarshalPKCS8PrivateKey(edKey)
+			Expect(err).NotTo(HaveOccurred())
+			writePEM(filepath.Join(dir, "2025-02.pem"), "PRIVATE KEY", der)
+		})
+
+		It("should sign with the latest key and publish all keys", func() {
+			manager, err := NewJWTManager(&cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			token, _, err := manager.Generate(1, entity.RoleUser, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.Header["kid"]).To(Equal("2025-02"))
+			Expect(parsed.Method.Alg()).To(Equal("EdDSA"))
+
+			_, err = manager.Parse(token)
+			Expect(err).NotTo(HaveOccurred())
+
+			keys := manager.PublicKeys()
+			Expect(keys).To(HaveLen(2))
+			Expect(keys[0].Algorithm).To(Equal("RS256"))
+		})
+
+		It("should accept tokens signed with a previous key during the overlap window", func() {
+			cfg.ActiveKeyID = "2025-01"
+			previous, err := NewJWTManager(&cfg)
+			Expect(err).NotTo(HaveOccurred())
+			token, _, err := previous.Generate(1, entity.RoleUser, time.Minute)
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg.ActiveKeyID = ""
+			current, err := NewJWTManager(&cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			access, err := current.Parse(token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(access.UserID).To(Equal(uint(1)))
+		})
+
+		It("should reject an HS256 token signed with the public key", func() {
+			manager, err := NewJWTManager(&cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			claims := jwt.MapClaims{
+				"iss": cfg.Issuer, "aud": cfg.Audience, "sub": "1", "role": "admin", "jti": "x",
+				"iat": time.Now().Unix(), "exp": time.Now().Add(time.Minute).Unix(),
+			}
+			forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+			forged.Header["kid"] = "2025-01"
+			token, err := forged.SignedString([]byte("anything"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = manager.Parse(token)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail when the active key has no private part", func() {
+			cfg.ActiveKeyID = "missing"
+			_, err := NewJWTManager(&cfg)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
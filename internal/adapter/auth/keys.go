@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	privateKeySuffix = ".pem"
+	publicKeySuffix  = ".pub.pem"
+)
+
+// signingKey это ключ из набора. У выведенного из ротации ключа может не быть приватной части,
+// тогда он используется только для проверки ранее выданных токенов.
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// loadKeys читает ключи из каталога. Имя файла без расширения служит kid:
+// <kid>.pem содержит приватный ключ RSA или Ed25519 (PKCS#8 или PKCS#1),
+// <kid>.pub.pem содержит публичный ключ выведенного из ротации ключа.
+// Подписывает ключ activeKID, а если он не задан, то ключ с наибольшим kid.
+// Остальные ключи принимаются при проверке, пока лежат в каталоге, что и дает окно перекрытия при ротации.
+func loadKeys(dir, activeKID string) (map[string]signingKey, string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read keys dir: %w", err)
+	}
+
+	keys := make(map[string]signingKey)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, privateKeySuffix) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read key %s: %w", name, err)
+		}
+
+		var key signingKey
+		if kid, ok := strings.CutSuffix(name, publicKeySuffix); ok {
+			if _, exists := keys[kid]; exists {
+				continue
+			}
+			key, err = parsePublicKey(kid, data)
+		} else {
+			kid = strings.TrimSuffix(name, privateKeySuffix)
+			key, err = parsePrivateKey(kid, data)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse key %s: %w", name, err)
+		}
+		keys[key.kid] = key
+	}
+
+	if activeKID == "" {
+		for kid, key := range keys {
+			if key.private != nil && kid > activeKID {
+				activeKID = kid
+			}
+		}
+	}
+
+	active, ok := keys[activeKID]
+	if !ok || active.private == nil {
+		return nil, "", fmt.Errorf("no private key for active kid %q in %s", activeKID, dir)
+	}
+
+	return keys, activeKID, nil
+}
+
+func parsePrivateKey(kid string, data []byte) (signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return signingKey{}, errors.New("no PEM block found")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		rsaKey, rsaErr := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if rsaErr != nil {
+			return signingKey{}, err
+		}
+		parsed = rsaKey
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return signingKey{}, errors.New("unsupported private key type")
+	}
+
+	key, err := parsePublic(kid, signer.Public())
+	if err != nil {
+		return signingKey{}, err
+	}
+	key.private = signer
+
+	return key, nil
+}
+
+func parsePublicKey(kid string, data []byte) (signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return signingKey{}, errors.New("no PEM block found")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	return parsePublic(kid, parsed)
+}
+
+func parsePublic(kid string, public crypto.PublicKey) (signingKey, error) {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		return signingKey{kid: kid, method: jwt.SigningMethodRS256, public: public}, nil
+	case ed25519.PublicKey:
+		return signingKey{kid: kid, method: jwt.SigningMethodEdDSA, public: public}, nil
+	default:
+		return signingKey{}, errors.New("only RSA and Ed25519 keys are supported")
+	}
+}
+
+// sortedKIDs возвращает kid ключей в стабильном порядке
+func sortedKIDs(keys map[string]signingKey) []string {
+	kids := make([]string, 0, len(keys))
+	for kid := range keys {
+		kids = append(kids, kid)
+	}
+	slices.Sort(kids)
+	return kids
+}
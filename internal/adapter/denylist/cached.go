@@ -0,0 +1,64 @@
+package denylist
+
+import (
+	"context"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// Store это общее хранилище отозванных токенов, например Postgres
+type Store interface {
+	Add(ctx context.Context, tokens ...entity.RevokedAccessToken) error
+	Contains(ctx context.Context, jti string) (bool, error)
+}
+
+// Cached запоминает ответы общего хранилища на короткое время, чтобы не обращаться
+// к нему на каждый запрос. Токены, отозванные этим экземпляром, видны сразу,
+// отозванные другими экземплярами начинают отклоняться не позже чем через ttl.
+type Cached struct {
+	store   Store
+	ttl     time.Duration
+	revoked *Memory
+	allowed *Memory
+}
+
+func NewCached(store Store, size int, ttl time.Duration) *Cached {
+	return &Cached{
+		store:   store,
+		ttl:     ttl,
+		revoked: NewMemory(size),
+		allowed: NewMemory(size),
+	}
+}
+
+func (c *Cached) Add(ctx context.Context, tokens ...entity.RevokedAccessToken) error {
+	if err := c.store.Add(ctx, tokens...); err != nil {
+		return err
+	}
+
+	return c.revoked.Add(ctx, tokens...)
+}
+
+func (c *Cached) Contains(ctx context.Context, jti string) (bool, error) {
+	if revoked, _ := c.revoked.Contains(ctx, jti); revoked {
+		return true, nil
+	}
+	if allowed, _ := c.allowed.Contains(ctx, jti); allowed {
+		return false, nil
+	}
+
+	revoked, err := c.store.Contains(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	// Отзыв необратим, но срок жизни токена здесь неизвестен, поэтому оба ответа хранятся ttl
+	cached := c.allowed
+	if revoked {
+		cached = c.revoked
+	}
+	_ = cached.Add(ctx, entity.RevokedAccessToken{JTI: jti, ExpiresAt: time.Now().Add(c.ttl)})
+
+	return revoked, nil
+}
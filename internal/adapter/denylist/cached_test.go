@@ -0,0 +1,76 @@
+package denylist
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type countingStore struct {
+	*Memory
+	lookups int
+	err     error
+}
+
+func (s *countingStore) Contains(ctx context.Context, jti string) (bool, error) {
+	s.lookups++
+	if s.err != nil {
+		return false, s.err
+	}
+	return s.Memory.Contains(ctx, jti)
+}
+
+var _ = Describe("Cached", func() {
+	var (
+		ctx     context.Context
+		store   *countingStore
+		list    *Cached
+		expires time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = &countingStore{Memory: NewMemory(10)}
+		list = NewCached(store, 10, time.Minute)
+		expires = time.Now().Add(time.Hour)
+	})
+
+	It("should ask the store once per ttl", func() {
+		Expect(list.Contains(ctx, "a")).To(BeFalse())
+		Expect(list.Contains(ctx, "a")).To(BeFalse())
+
+		Expect(store.lookups).To(Equal(1))
+	})
+
+	It("should see tokens revoked through it without asking the store", func() {
+		Expect(list.Contains(ctx, "a")).To(BeFalse())
+		Expect(list.Add(ctx, entity.RevokedAccessToken{JTI: "a", ExpiresAt: expires})).To(Succeed())
+
+		Expect(list.Contains(ctx, "a")).To(BeTrue())
+		Expect(store.lookups).To(Equal(1))
+	})
+
+	It("should see tokens revoked elsewhere once the ttl passes", func() {
+		list = NewCached(store, 10, time.Millisecond)
+		Expect(list.Contains(ctx, "a")).To(BeFalse())
+
+		Expect(store.Add(ctx, entity.RevokedAccessToken{JTI: "a", ExpiresAt: expires})).To(Succeed())
+		time.Sleep(5 * time.Millisecond)
+
+		Expect(list.Contains(ctx, "a")).To(BeTrue())
+	})
+
+	It("should not cache store errors", func() {
+		store.err = errors.New("db down")
+		_, err := list.Contains(ctx, "a")
+		Expect(err).To(HaveOccurred())
+
+		store.err = nil
+		Expect(list.Contains(ctx, "a")).To(BeFalse())
+		Expect(store.lookups).To(Equal(2))
+	})
+})
@@ -0,0 +1,84 @@
+package denylist
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type memoryEntry struct {
+	jti       string
+	expiresAt time.Time
+}
+
+// Memory это список отозванных access токенов в памяти процесса с вытеснением по LRU.
+// Подходит для одного экземпляра приложения: после перезапуска список пуст.
+// Размер нужно выбирать с запасом, так как вытесненный до истечения токен снова станет действительным.
+type Memory struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func NewMemory(size int) *Memory {
+	return &Memory{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+func (m *Memory) Add(_ context.Context, tokens ...entity.RevokedAccessToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, t := range tokens {
+		if !t.ExpiresAt.After(now) {
+			continue
+		}
+
+		if el, ok := m.entries[t.JTI]; ok {
+			entry := el.Value.(*memoryEntry)
+			if t.ExpiresAt.After(entry.expiresAt) {
+				entry.expiresAt = t.ExpiresAt
+			}
+			m.order.MoveToFront(el)
+			continue
+		}
+
+		m.entries[t.JTI] = m.order.PushFront(&memoryEntry{jti: t.JTI, expiresAt: t.ExpiresAt})
+		for m.order.Len() > m.size {
+			m.remove(m.order.Back())
+		}
+	}
+
+	return nil
+}
+
+func (m *Memory) Contains(_ context.Context, jti string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[jti]
+	if !ok {
+		return false, nil
+	}
+
+	if !el.Value.(*memoryEntry).expiresAt.After(time.Now()) {
+		m.remove(el)
+		return false, nil
+	}
+
+	m.order.MoveToFront(el)
+	return true, nil
+}
+
+func (m *Memory) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.entries, el.Value.(*memoryEntry).jti)
+}
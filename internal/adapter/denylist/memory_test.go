@@ -0,0 +1,51 @@
+package denylist
+
+import (
+	"context"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Memory", func() {
+	var (
+		ctx     context.Context
+		list    *Memory
+		expires time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		list = NewMemory(2)
+		expires = time.Now().Add(time.Minute)
+	})
+
+	It("should report revoked tokens", func() {
+		Expect(list.Add(ctx, entity.RevokedAccessToken{JTI: "a", ExpiresAt: expires})).To(Succeed())
+
+		Expect(list.Contains(ctx, "a")).To(BeTrue())
+		Expect(list.Contains(ctx, "b")).To(BeFalse())
+	})
+
+	It("should forget tokens after they expire", func() {
+		Expect(list.Add(ctx, entity.RevokedAccessToken{JTI: "a", ExpiresAt: time.Now().Add(-time.Second)})).To(Succeed())
+
+		Expect(list.Contains(ctx, "a")).To(BeFalse())
+	})
+
+	It("should evict the least recently used token when full", func() {
+		Expect(list.Add(ctx,
+			entity.RevokedAccessToken{JTI: "a", ExpiresAt: expires},
+			entity.RevokedAccessToken{JTI: "b", ExpiresAt: expires},
+		)).To(Succeed())
+		Expect(list.Contains(ctx, "a")).To(BeTrue())
+
+		Expect(list.Add(ctx, entity.RevokedAccessToken{JTI: "c", ExpiresAt: expires})).To(Succeed())
+
+		Expect(list.Contains(ctx, "a")).To(BeTrue())
+		Expect(list.Contains(ctx, "b")).To(BeFalse())
+		Expect(list.Contains(ctx, "c")).To(BeTrue())
+	})
+})
@@ -0,0 +1,104 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/adapter/oidc/oidctest"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Provider", func() {
+	var (
+		server   *oidctest.Server
+		provider *Provider
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = oidctest.NewServer("client", "secret", oidctest.User{
+			Subject:       "42",
+			Email:         "User@Example.com",
+			EmailVerified: true,
+			Name:          "Test User",
+		})
+		DeferCleanup(server.Close)
+
+		provider = NewProvider(config.OIDCProviderConfig{
+			Name:         "local",
+			Issuer:       server.URL,
+			ClientID:     "client",
+			ClientSecret: "secret",
+			RedirectURL:  "http://app.local/callback",
+			Scopes:       []string{"openid", "email"},
+		}, http.DefaultClient)
+	})
+
+	It("should complete the authorization code flow with PKCE", func() {
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed, err := url.Parse(authURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.Query().Get("code_challenge")).To(Equal(CodeChallenge("verifier")))
+		Expect(parsed.Query().Get("code_challenge_method")).To(Equal("S256"))
+
+		code, state, err := server.Authorize(authURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(state).To(Equal("state"))
+
+		identity, err := provider.Exchange(ctx, code, "verifier", "nonce")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(identity).To(Equal(entity.ExternalIdentity{
+			Subject:       "42",
+			Email:         "user@example.com",
+			EmailVerified: true,
+			Name:          "Test User",
+		}))
+	})
+
+	It("should reject a wrong code verifier", func() {
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+		Expect(err).NotTo(HaveOccurred())
+		code, _, err := server.Authorize(authURL)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = provider.Exchange(ctx, code, "other-verifier", "nonce")
+		Expect(err).To(MatchError(ContainSubstring("invalid_grant")))
+	})
+
+	It("should reject a mismatched nonce", func() {
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+		Expect(err).NotTo(HaveOccurred())
+		code, _, err := server.Authorize(authURL)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = provider.Exchange(ctx, code, "verifier", "other-nonce")
+		Expect(err).To(MatchError(ContainSubstring("nonce")))
+	})
+
+	It("should fail for a client unknown to the provider", func() {
+		server.ClientID = "other-client"
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = server.Authorize(authURL)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not accept a code twice", func() {
+		authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier")
+		Expect(err).NotTo(HaveOccurred())
+		code, _, err := server.Authorize(authURL)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = provider.Exchange(ctx, code, "verifier", "nonce")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = provider.Exchange(ctx, code, "verifier", "nonce")
+		Expect(err).To(HaveOccurred())
+	})
+})
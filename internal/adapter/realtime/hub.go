@@ -0,0 +1,101 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type subscriber struct {
+	userID   uint
+	messages chan entity.RealtimeMessage
+}
+
+// Hub раздает сообщения потокам, открытым в этом экземпляре приложения.
+// Сам по себе подходит для одного экземпляра, для нескольких сообщения нужно публиковать через Postgres.
+type Hub struct {
+	mu          sync.Mutex
+	bufferSize  int
+	subscribers map[uint]map[*subscriber]struct{}
+	closed      bool
+}
+
+func NewHub(bufferSize int) *Hub {
+	return &Hub{
+		bufferSize:  bufferSize,
+		subscribers: make(map[uint]map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe открывает поток сообщений пользователя. Канал закрывается после вызова
+// возвращенной функции или если клиент не успевает читать сообщения.
+func (h *Hub) Subscribe(userID uint) (<-chan entity.RealtimeMessage, func()) {
+	sub := &subscriber{userID: userID, messages: make(chan entity.RealtimeMessage, h.bufferSize)}
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		close(sub.messages)
+		return sub.messages, func() {}
+	}
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[*subscriber]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub.messages, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(sub)
+	}
+}
+
+// Publish отправляет сообщение потокам этого экземпляра
+func (h *Hub) Publish(_ context.Context, message entity.RealtimeMessage) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers[message.UserID] {
+		select {
+		case sub.messages <- message:
+		default:
+			// Клиент отстал: закрываем поток, после переподключения он перечитает уведомления
+			h.remove(sub)
+		}
+	}
+
+	return nil
+}
+
+// Close закрывает все потоки и не дает открыть новые. Вызывается при остановке сервера,
+// чтобы он не ждал отключения клиентов.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, subs := range h.subscribers {
+		for sub := range subs {
+			h.remove(sub)
+		}
+	}
+}
+
+// remove закрывает поток один раз, вызывается под mu
+func (h *Hub) remove(sub *subscriber) {
+	subs, ok := h.subscribers[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok = subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.userID)
+	}
+	close(sub.messages)
+}
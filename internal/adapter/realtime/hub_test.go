@@ -0,0 +1,72 @@
+package realtime
+
+import (
+	"context"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hub", func() {
+	var (
+		ctx context.Context
+		hub *Hub
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		hub = NewHub(2)
+	})
+
+	It("should deliver messages only to the streams of the recipient", func() {
+		first, unsubscribeFirst := hub.Subscribe(1)
+		defer unsubscribeFirst()
+		second, unsubscribeSecond := hub.Subscribe(1)
+		defer unsubscribeSecond()
+		other, unsubscribeOther := hub.Subscribe(2)
+		defer unsubscribeOther()
+
+		message := entity.RealtimeMessage{UserID: 1, Type: entity.RealtimeNotification, Data: []byte(`{"id":1}`)}
+		Expect(hub.Publish(ctx, message)).To(Succeed())
+
+		Expect(first).To(Receive(Equal(message)))
+		Expect(second).To(Receive(Equal(message)))
+		Expect(other).NotTo(Receive())
+	})
+
+	It("should close the stream of a client that does not keep up", func() {
+		messages, unsubscribe := hub.Subscribe(1)
+		defer unsubscribe()
+
+		for range 3 {
+			Expect(hub.Publish(ctx, entity.RealtimeMessage{UserID: 1, Type: "offer.created"})).To(Succeed())
+		}
+
+		Expect(messages).To(Receive())
+		Expect(messages).To(Receive())
+		Expect(messages).To(BeClosed())
+	})
+
+	It("should allow unsubscribing twice", func() {
+		messages, unsubscribe := hub.Subscribe(1)
+
+		unsubscribe()
+		unsubscribe()
+
+		Expect(messages).To(BeClosed())
+		Expect(hub.Publish(ctx, entity.RealtimeMessage{UserID: 1})).To(Succeed())
+	})
+
+	It("should close all streams on Close", func() {
+		messages, unsubscribe := hub.Subscribe(1)
+		defer unsubscribe()
+
+		hub.Close()
+
+		Expect(messages).To(BeClosed())
+
+		late, _ := hub.Subscribe(1)
+		Expect(late).To(BeClosed())
+	})
+})
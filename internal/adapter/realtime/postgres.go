@@ -0,0 +1,127 @@
+package realtime
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+const (
+	channel = "stawberry_realtime"
+
+	// maxPayload чуть меньше предела NOTIFY в 8000 байт
+	maxPayload = 7900
+
+	reconnectDelay = 5 * time.Second
+)
+
+// Postgres публикует сообщения через NOTIFY и слушает канал через LISTEN,
+// передавая полученное в Hub. Так сообщение доходит до потоков во всех экземплярах приложения,
+// включая тот, который его опубликовал.
+type Postgres struct {
+	db   *sqlx.DB
+	hub  *Hub
+	log  *zap.Logger
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+func NewPostgres(db *sqlx.DB, hub *Hub, log *zap.Logger) *Postgres {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Postgres{
+		db:   db,
+		hub:  hub,
+		log:  log,
+		stop: cancel,
+		done: make(chan struct{}),
+	}
+
+	go p.run(ctx)
+
+	return p
+}
+
+func (p *Postgres) Publish(ctx context.Context, message entity.RealtimeMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return apperror.New(apperror.InternalError, "failed to encode realtime message", err)
+	}
+	if len(payload) > maxPayload {
+		return apperror.New(apperror.InternalError,
+			fmt.Sprintf("realtime message is too large: %d bytes", len(payload)), nil)
+	}
+
+	if _, err = p.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", channel, string(payload)); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to publish realtime message", err)
+	}
+
+	return nil
+}
+
+// Close перестает слушать канал
+func (p *Postgres) Close() {
+	p.stop()
+	<-p.done
+}
+
+func (p *Postgres) run(ctx context.Context) {
+	defer close(p.done)
+
+	for {
+		err := p.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		p.log.Error("Realtime listener disconnected, reconnecting", zap.Error(err))
+
+		select {
+		case <-time.After(reconnectDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// listen держит отдельное соединение с LISTEN, пока оно не оборвется или ctx не будет отменен.
+// Соединение не возвращается в пул, так как на нем осталась подписка.
+func (p *Postgres) listen(ctx context.Context) error {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var listenErr error
+	_ = conn.Raw(func(driverConn any) error {
+		pgConn := driverConn.(*stdlib.Conn).Conn()
+
+		if _, listenErr = pgConn.Exec(ctx, "LISTEN "+channel); listenErr != nil {
+			return driver.ErrBadConn
+		}
+
+		for {
+			notification, err := pgConn.WaitForNotification(ctx)
+			if err != nil {
+				listenErr = err
+				return driver.ErrBadConn
+			}
+
+			var message entity.RealtimeMessage
+			if err = json.Unmarshal([]byte(notification.Payload), &message); err != nil {
+				p.log.Error("Failed to decode realtime message", zap.Error(err))
+				continue
+			}
+			_ = p.hub.Publish(ctx, message)
+		}
+	})
+
+	return listenErr
+}
@@ -2,6 +2,7 @@ package apperror
 
 import (
 	"fmt"
+	"time"
 )
 
 const (
@@ -15,6 +16,7 @@ const (
 	InvalidFingerprint = "INVALID_FINGERPRINT"
 	Conflict           = "CONFLICT"
 	Forbidden          = "FORBIDDEN"
+	TooManyRequests    = "TOO_MANY_REQUESTS"
 )
 
 type AppError interface {
@@ -44,21 +46,60 @@ func New(code, message string, cause error) *Error {
 	return &Error{ErrCode: code, ErrMsg: message, WrappedErr: cause}
 }
 
+// RetryError это ошибка, после которой запрос можно повторить не раньше RetryAfter.
+// Обработчик ошибок выставляет по ней заголовок Retry-After.
+type RetryError struct {
+	AppError
+	RetryAfter time.Duration
+}
+
+func NewRetry(code, message string, retryAfter time.Duration) *RetryError {
+	return &RetryError{AppError: New(code, message, nil), RetryAfter: retryAfter}
+}
+
 var (
 	ErrProductNotFound = New(NotFound, "product not found", nil)
 	ErrStoreNotFound   = New(NotFound, "store not found", nil)
 
+	ErrShopMemberNotFound     = New(NotFound, "user is not a member of the shop", nil)
+	ErrShopInvitationNotFound = New(NotFound, "invitation not found", nil)
+
+	ErrCategoryNotFound = New(NotFound, "category not found", nil)
+
+	ErrImageNotFound = New(NotFound, "image not found", nil)
+
+	ErrWishlistItemNotFound = New(NotFound, "product is not in wishlist", nil)
+	ErrSavedSearchNotFound  = New(NotFound, "saved search not found", nil)
+
 	ErrOfferNotFound = New(NotFound, "offer not found", nil)
 
 	ErrUserNotFound             = New(NotFound, "user not found", nil)
+	ErrUserBanned               = New(Forbidden, "user is banned", nil)
 	ErrIncorrectPassword        = New(Unauthorized, "incorrect password", nil)
 	ErrFailedToGeneratePassword = New(InternalError, "failed to generate password", nil)
 	ErrInvalidFingerprint       = New(InvalidFingerprint, "fingerprints don't match", nil)
+	ErrInvalidMFACode           = New(Unauthorized, "invalid two-factor authentication code", nil)
+	ErrMFANotEnabled            = New(Conflict, "two-factor authentication is not enabled", nil)
+	ErrMFAAlreadyEnabled        = New(Conflict, "two-factor authentication is already enabled", nil)
+
+	ErrPermissionNotGranted = New(NotFound, "permission is not granted to user", nil)
 
-	ErrInvalidToken  = New(InvalidToken, "invalid token", nil)
-	ErrTokenNotFound = New(NotFound, "token not found", nil)
+	ErrInvalidToken       = New(InvalidToken, "invalid token", nil)
+	ErrRefreshTokenReused = New(InvalidToken, "refresh token reuse detected", nil)
+	ErrAccessTokenRevoked = New(InvalidToken, "access token revoked", nil)
+	ErrInvalidActionToken = New(BadRequest, "token is invalid, expired or already used", nil)
+	ErrTokenNotFound      = New(NotFound, "token not found", nil)
+
+	ErrIdentityProviderNotFound = New(NotFound, "identity provider not found", nil)
+	ErrInvalidLoginState        = New(BadRequest, "login state is invalid or expired", nil)
 
 	ErrNotificationNotFound = New(NotFound, "notification not found", nil)
+
+	ErrAPIKeyNotFound = New(NotFound, "api key not found", nil)
+	ErrInvalidAPIKey  = New(Unauthorized, "invalid, expired or revoked api key", nil)
+
+	ErrWebhookNotFound         = New(NotFound, "webhook not found", nil)
+	ErrWebhookDeliveryNotFound = New(NotFound, "webhook delivery not found", nil)
 )
 
 // ReviewError представляет ошибку, связанную с отзывами
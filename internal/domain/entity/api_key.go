@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// APIKey это ключ магазина для интеграций. Ключ действует от имени создавшего его сотрудника,
+// только в пределах своего магазина и только в своих областях. Секрет хранится в виде хэша,
+// ключ находится по открытому префиксу.
+type APIKey struct {
+	ID         uint
+	ShopID     uint
+	CreatedBy  uint
+	Name       string
+	Prefix     string
+	SecretHash string
+	Scopes     []Permission
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// HasScope сообщает, что ключ выдан с областью scope
+func (k APIKey) HasScope(scope Permission) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// Active сообщает, что ключ не отозван и не истек
+func (k APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
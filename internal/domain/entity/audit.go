@@ -2,11 +2,22 @@ package entity
 
 import "time"
 
+// Записи аудита о событиях безопасности, которые возникают не из HTTP-запроса напрямую
+const (
+	AuditMethodSecurity            = "SECURITY"
+	SecurityEventRefreshTokenReuse = "security/refresh_token_reuse"
+
+	// AuditMethodEvent помечает записи о доменных событиях, Url в них это тип события
+	AuditMethodEvent = "EVENT"
+)
+
 type AuditEntry struct {
 	Method     string
 	Url        string
 	RespStatus int
 	UserID     uint
+	// APIKeyID это ключ магазина, по которому выполнен запрос, 0 для запросов по access токену
+	APIKeyID   uint
 	IP         string
 	UserRole   string
 	ReceivedAt time.Time
@@ -0,0 +1,21 @@
+package entity
+
+// Category узел дерева категорий, хранящегося в виде nested set (lft/rgt)
+type Category struct {
+	ID       uint
+	Name     string
+	ParentID *uint
+	Lft      int
+	Rgt      int
+	Depth    int
+	// ProductCount количество продуктов, привязанных непосредственно к категории
+	ProductCount int
+	// TotalProductCount количество продуктов в категории и всех её подкатегориях
+	TotalProductCount int
+	Children          []Category
+}
+
+// IsLeaf сообщает, что у категории нет подкатегорий
+func (c Category) IsLeaf() bool {
+	return c.Rgt-c.Lft == 1
+}
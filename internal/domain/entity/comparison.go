@@ -0,0 +1,19 @@
+package entity
+
+type ComparedProduct struct {
+	Product
+	ShopPrices []ShopPrice `json:"shop_prices"`
+}
+
+// ComparedAttribute это строка матрицы сравнения: значения атрибута в порядке продуктов,
+// nil если у продукта атрибута нет
+type ComparedAttribute struct {
+	Name    string        `json:"name"`
+	Values  []interface{} `json:"values"`
+	Differs bool          `json:"differs"`
+}
+
+type ProductComparison struct {
+	Products   []ComparedProduct   `json:"products"`
+	Attributes []ComparedAttribute `json:"attributes"`
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// EventType это тип доменного события
+type EventType string
+
+const (
+	EventOfferCreated       EventType = "offer.created"
+	EventOfferAccepted      EventType = "offer.accepted"
+	EventOfferDeclined      EventType = "offer.declined"
+	EventOfferCancelled     EventType = "offer.cancelled"
+	EventOfferExpired       EventType = "offer.expired"
+	EventGuestOfferReceived EventType = "guest_offer.received"
+)
+
+// OfferEvents перечисляет события жизненного цикла заявки
+var OfferEvents = []EventType{
+	EventOfferCreated,
+	EventOfferAccepted,
+	EventOfferDeclined,
+	EventOfferCancelled,
+	EventOfferExpired,
+}
+
+// OfferStatusEvents сопоставляет новый статус заявки с событием
+var OfferStatusEvents = map[string]EventType{
+	"accepted":  EventOfferAccepted,
+	"declined":  EventOfferDeclined,
+	"cancelled": EventOfferCancelled,
+}
+
+type EventStatus string
+
+const (
+	EventPending   EventStatus = "pending"
+	EventProcessed EventStatus = "processed"
+	// EventDead событие исчерпало попытки, часть подписчиков его так и не обработала
+	EventDead EventStatus = "dead"
+)
+
+// Event это доменное событие из outbox. Оно записывается в одной транзакции с изменением,
+// которое его вызвало, и доставляется каждому подписчику хотя бы один раз.
+type Event struct {
+	ID uint
+	// UID не меняется при повторах, по нему подписчики и внешние получатели отбрасывают дубликаты
+	UID     string
+	Type    EventType
+	ActorID uint
+	// Payload JSON тела события, например OfferEvent
+	Payload       []byte
+	Status        EventStatus
+	Completed     []string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	ProcessedAt   *time.Time
+	CreatedAt     time.Time
+}
+
+// CompletedBy сообщает, что подписчик subscriber уже обработал событие
+func (e Event) CompletedBy(subscriber string) bool {
+	return slices.Contains(e.Completed, subscriber)
+}
+
+// OfferEvent это тело событий offer.*
+type OfferEvent struct {
+	Offer Offer
+}
+
+// GuestOfferEvent это тело события guest_offer.received
+type GuestOfferEvent struct {
+	Offer          GuestOfferData
+	ShopOwnerEmail string
+}
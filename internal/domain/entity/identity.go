@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// ExternalIdentity это пользователь внешнего провайдера по данным проверенного ID токена
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OIDCLoginState это незавершенный вход через внешнего провайдера.
+// Хранится до возврата пользователя от провайдера и гасится при первом использовании.
+type OIDCLoginState struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	// Fingerprint клиента, начавшего вход: завершить вход может только он
+	Fingerprint string
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type ProductImage struct {
+	Key          string    `json:"key"`
+	ThumbnailKey string    `json:"-"`
+	ProductID    int       `json:"-"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	CreatedAt    time.Time `json:"created_at"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// LoginAttempts это счетчик неудачных попыток входа по учетной записи или по адресу клиента
+type LoginAttempts struct {
+	Key          string
+	Failures     int
+	LastFailedAt time.Time
+	// BlockedUntil задает, до какого момента попытки входа отклоняются, нулевое значение значит без ограничений
+	BlockedUntil time.Time
+}
@@ -0,0 +1,13 @@
+package entity
+
+// TOTPEnrollment это данные для подключения приложения-аутентификатора
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// RecoveryCode это неиспользованный резервный код. В БД хранится только его хэш.
+type RecoveryCode struct {
+	ID   uint
+	Hash string
+}
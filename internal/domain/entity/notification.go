@@ -2,9 +2,155 @@ package entity
 
 import "time"
 
+// NotificationType это вид уведомления, по нему клиент выбирает иконку и действие
+type NotificationType string
+
+const (
+	NotificationOfferReceived      NotificationType = "offer_received"
+	NotificationOfferStatusChanged NotificationType = "offer_status_changed"
+	NotificationReviewReceived     NotificationType = "review_received"
+	NotificationPriceDropped       NotificationType = "price_dropped"
+	NotificationSearchMatched      NotificationType = "search_matched"
+	NotificationSystem             NotificationType = "system"
+)
+
+// NotificationTypes это все виды уведомлений, для каждого пользователь выбирает канал
+var NotificationTypes = []NotificationType{
+	NotificationOfferReceived,
+	NotificationOfferStatusChanged,
+	NotificationReviewReceived,
+	NotificationPriceDropped,
+	NotificationSearchMatched,
+	NotificationSystem,
+}
+
 type Notification struct {
-	ID      uint      `json:"id"`
-	UserID  uint      `json:"user_id"`
-	Message string    `json:"message"`
-	SentAt  time.Time `json:"sent_at"`
+	ID      uint
+	UserID  uint
+	Type    NotificationType
+	Message string
+	// Link это путь в клиенте, который открывается по уведомлению, может быть пустым
+	Link   string
+	ReadAt *time.Time
+	SentAt time.Time
+	// EmailPending означает, что уведомление еще нужно отправить письмом в дайджесте
+	EmailPending bool
+	// EventUID это UID события outbox, из которого создано уведомление, пустой у остальных уведомлений.
+	// Повторная доставка события не создает второе уведомление тому же пользователю.
+	EventUID string
+}
+
+// NotificationChannel это способ доставки уведомлений одного вида
+type NotificationChannel string
+
+const (
+	// NotificationChannelInApp только в списке уведомлений и в потоке
+	NotificationChannelInApp NotificationChannel = "in_app"
+	// NotificationChannelEmail в списке уведомлений и письмом
+	NotificationChannelEmail NotificationChannel = "email"
+	// NotificationChannelNone уведомление не создается
+	NotificationChannelNone NotificationChannel = "none"
+)
+
+// DigestFrequency это как часто письма с уведомлениями собираются в одно
+type DigestFrequency string
+
+const (
+	// DigestOff письмо отправляется сразу, кроме тихих часов
+	DigestOff    DigestFrequency = "off"
+	DigestHourly DigestFrequency = "hourly"
+	DigestDaily  DigestFrequency = "daily"
+)
+
+// defaultNotificationChannels повторяют письма, которые отправлялись до появления настроек
+var defaultNotificationChannels = map[NotificationType]NotificationChannel{
+	NotificationOfferReceived:      NotificationChannelEmail,
+	NotificationOfferStatusChanged: NotificationChannelEmail,
+	NotificationReviewReceived:     NotificationChannelInApp,
+	NotificationPriceDropped:       NotificationChannelEmail,
+	NotificationSearchMatched:      NotificationChannelEmail,
+	NotificationSystem:             NotificationChannelInApp,
+}
+
+// QuietHours это время суток в минутах от полуночи, когда письма не отправляются.
+// Start больше End, если тихие часы переходят через полночь.
+type QuietHours struct {
+	Start int
+	End   int
+}
+
+type NotificationPreferences struct {
+	UserID uint
+	// Channels хранит выбор пользователя, для остальных видов действует канал по умолчанию
+	Channels   map[NotificationType]NotificationChannel
+	QuietHours *QuietHours
+	// Timezone это IANA зона, в которой заданы тихие часы
+	Timezone     string
+	Digest       DigestFrequency
+	LastDigestAt *time.Time
+}
+
+// DefaultNotificationPreferences это настройки пользователя, который их не менял
+func DefaultNotificationPreferences(userID uint) NotificationPreferences {
+	return NotificationPreferences{
+		UserID:   userID,
+		Channels: map[NotificationType]NotificationChannel{},
+		Timezone: "UTC",
+		Digest:   DigestOff,
+	}
+}
+
+// Channel возвращает канал для уведомлений вида t
+func (p NotificationPreferences) Channel(t NotificationType) NotificationChannel {
+	if channel, ok := p.Channels[t]; ok {
+		return channel
+	}
+	if channel, ok := defaultNotificationChannels[t]; ok {
+		return channel
+	}
+	return NotificationChannelInApp
+}
+
+// InQuietHours сообщает, приходится ли now на тихие часы пользователя
+func (p NotificationPreferences) InQuietHours(now time.Time) bool {
+	if p.QuietHours == nil {
+		return false
+	}
+
+	location, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+
+	start, end := p.QuietHours.Start, p.QuietHours.End
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// SendNow сообщает, можно ли отправить письмо сразу, а не откладывать его до дайджеста
+func (p NotificationPreferences) SendNow(now time.Time) bool {
+	return p.Digest == DigestOff && !p.InQuietHours(now)
+}
+
+// DigestDue сообщает, пора ли отправить пользователю отложенные письма
+func (p NotificationPreferences) DigestDue(now time.Time) bool {
+	if p.InQuietHours(now) {
+		return false
+	}
+	if p.LastDigestAt == nil {
+		return true
+	}
+
+	switch p.Digest {
+	case DigestHourly:
+		return now.Sub(*p.LastDigestAt) >= time.Hour
+	case DigestDaily:
+		return now.Sub(*p.LastDigestAt) >= 24*time.Hour
+	default:
+		return true
+	}
 }
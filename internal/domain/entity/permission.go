@@ -0,0 +1,16 @@
+package entity
+
+// Permission это право на отдельное действие, выдается пользователю поверх его роли
+type Permission string
+
+const (
+	PermissionOffersRespond  Permission = "offers:respond"
+	PermissionInventoryWrite Permission = "inventory:write"
+	PermissionAuditRead      Permission = "audit:read"
+)
+
+// Permissions это реестр прав, которые можно выдать пользователю, с их описанием.
+// Права в пределах магазина дает только роль сотрудника, см. ShopRolePermissions.
+var Permissions = map[Permission]string{
+	PermissionAuditRead: "read audit logs",
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// PricePoint это цена продукта в магазине, установленная в момент ChangedAt
+type PricePoint struct {
+	ProductID int       `json:"-"`
+	ShopID    int       `json:"-"`
+	ShopName  string    `json:"-"`
+	Price     int       `json:"price"`
+	Currency  string    `json:"currency"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// PriceWindow это статистика цены за окно [Start, End). Avg взвешена по времени действия цены.
+type PriceWindow struct {
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Min      int       `json:"min"`
+	Avg      int       `json:"avg"`
+	Max      int       `json:"max"`
+	Currency string    `json:"currency"`
+}
+
+type ShopPriceHistory struct {
+	ShopID   int           `json:"shop_id"`
+	ShopName string        `json:"shop_name"`
+	Points   []PricePoint  `json:"points"`
+	Windows  []PriceWindow `json:"windows"`
+}
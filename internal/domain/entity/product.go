@@ -10,6 +10,7 @@ type Product struct {
 	AverageRating float64                `json:"average_rating"`
 	CountReviews  int                    `json:"count_reviews"`
 	Attributes    map[string]interface{} `json:"product_attributes"`
+	Images        []ProductImage         `json:"images"`
 }
 
 type NewProduct struct {
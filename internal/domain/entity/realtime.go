@@ -0,0 +1,14 @@
+package entity
+
+import "encoding/json"
+
+// RealtimeNotification это тип сообщения о новом уведомлении. Остальные сообщения
+// о заявках называются так же, как доменные события: offer.accepted, offer.created и т.д.
+const RealtimeNotification = "notification"
+
+// RealtimeMessage отправляется всем открытым потокам пользователя
+type RealtimeMessage struct {
+	UserID uint            `json:"user_id"`
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data"`
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// ShopRole это роль сотрудника в магазине, совпадает со значениями типа shop_member_role в БД
+type ShopRole string
+
+const (
+	ShopRoleOwner   ShopRole = "owner"
+	ShopRoleManager ShopRole = "manager"
+	ShopRoleAgent   ShopRole = "agent"
+)
+
+// ShopRolePermissions это права, которые роль дает в пределах своего магазина
+var ShopRolePermissions = map[ShopRole][]Permission{
+	ShopRoleOwner:   {PermissionOffersRespond, PermissionInventoryWrite},
+	ShopRoleManager: {PermissionOffersRespond, PermissionInventoryWrite},
+	ShopRoleAgent:   {PermissionOffersRespond},
+}
+
+// ShopRolesWith возвращает роли, дающие право в пределах магазина
+func ShopRolesWith(permission Permission) []ShopRole {
+	roles := make([]ShopRole, 0, len(ShopRolePermissions))
+	for _, role := range []ShopRole{ShopRoleOwner, ShopRoleManager, ShopRoleAgent} {
+		if slices.Contains(ShopRolePermissions[role], permission) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+type ShopMember struct {
+	ShopID    uint
+	UserID    uint
+	UserName  string
+	UserEmail string
+	Role      ShopRole
+	CreatedAt time.Time
+}
+
+type ShopInvitation struct {
+	ID         uint
+	ShopID     uint
+	Email      string
+	Role       ShopRole
+	TokenHash  string
+	InvitedBy  uint
+	ExpiresAt  time.Time
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,14 @@
+package entity
+
+// ShopPrice это предложение продукта в конкретном магазине с рейтингом продавца
+type ShopPrice struct {
+	ProductID          int     `json:"-"`
+	ShopID             int     `json:"shop_id"`
+	ShopName           string  `json:"shop_name"`
+	SellerID           int     `json:"seller_id"`
+	Price              int     `json:"price"`
+	Currency           string  `json:"currency"`
+	IsAvailable        bool    `json:"is_available"`
+	SellerRating       float64 `json:"seller_rating"`
+	SellerReviewsCount int     `json:"seller_reviews_count"`
+}
@@ -1,13 +1,19 @@
 package entity
 
 import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type AccessToken struct {
+	// ID это jti токена
+	ID        string
 	UserID    uint
+	Role      Role
 	IssuedAt  time.Time
 	ExpiresAt time.Time
 }
@@ -19,6 +25,16 @@ type RefreshToken struct {
 	RevokedAt   *time.Time
 	Fingerprint string
 	UserID      uint
+	// FamilyID общий для всех токенов, выпущенных по цепочке обновлений от одного входа
+	FamilyID uuid.UUID
+	// ReplacedBy заполняется, когда токен обменян на новый при обновлении
+	ReplacedBy *uuid.UUID
+	// LastUsedAt, LastIP и UserAgent описывают последний вход или обновление токенов в этой сессии
+	LastUsedAt time.Time
+	LastIP     string
+	UserAgent  string
+	// AccessJTI это jti access токена, выпущенного вместе с этим refresh токеном
+	AccessJTI string
 }
 
 func (rt RefreshToken) IsValid() bool {
@@ -28,3 +44,44 @@ func (rt RefreshToken) IsValid() bool {
 	}
 	return rt.ExpiresAt.After(now)
 }
+
+// IsRotated сообщает, что токен уже был обменян на новый.
+// Повторное предъявление такого токена означает, что его кто-то украл.
+func (rt RefreshToken) IsRotated() bool {
+	return rt.ReplacedBy != nil
+}
+
+// SetClient запоминает, с какого устройства и адреса использовалась сессия
+func (rt *RefreshToken) SetClient(client ClientInfo) {
+	rt.LastUsedAt = rt.CreatedAt
+	rt.LastIP = client.IP
+	rt.UserAgent = client.UserAgent
+}
+
+// DeviceLabel возвращает короткое имя устройства, построенное по отпечатку.
+// Сам отпечаток наружу не отдается, так как к нему привязан refresh токен.
+func (rt RefreshToken) DeviceLabel() string {
+	sum := sha256.Sum256([]byte(rt.Fingerprint))
+	return "device-" + hex.EncodeToString(sum[:4])
+}
+
+// RevokedAccessToken это запись списка отозванных access токенов.
+// Хранить ее нужно только до истечения срока самого токена.
+type RevokedAccessToken struct {
+	JTI       string
+	ExpiresAt time.Time
+}
+
+// ClientInfo это данные клиента, от имени которого открывается или обновляется сессия
+type ClientInfo struct {
+	Fingerprint string
+	IP          string
+	UserAgent   string
+}
+
+// PublicKey это открытый ключ проверки access токенов, публикуемый в JWKS
+type PublicKey struct {
+	KID       string
+	Algorithm string
+	Key       crypto.PublicKey
+}
@@ -1,10 +1,63 @@
 package entity
 
+import "time"
+
+// Role это роль пользователя, совпадает со значениями типа user_role в БД
+type Role string
+
+const (
+	RoleUser   Role = "user"
+	RoleShop   Role = "shop"
+	RoleAdmin  Role = "admin"
+	RoleSystem Role = "system"
+)
+
+// IsValid проверяет, что роль входит в число известных
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleUser, RoleShop, RoleAdmin, RoleSystem:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	ID       uint
 	Name     string
 	Password string
 	Email    string
 	Phone    string
-	IsStore  bool
+	Role     Role
+	// EmailVerifiedAt пустой, пока пользователь не подтвердил почту
+	EmailVerifiedAt *time.Time
+	// BannedAt заполнен, если пользователь заблокирован администратором
+	BannedAt *time.Time
+	// TOTPSecret задан с начала подключения двухфакторной аутентификации,
+	// а TOTPEnabledAt заполняется, когда подключение подтверждено кодом
+	TOTPSecret    string
+	TOTPEnabledAt *time.Time
+	TOTPLastStep  int64
+}
+
+// MFAEnabled сообщает, требуется ли при входе второй фактор
+func (u User) MFAEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// Назначение одноразовых токенов, отправляемых на почту
+const (
+	TokenPurposeVerifyEmail   = "verify_email"
+	TokenPurposeResetPassword = "reset_password"
+	TokenPurposeUnlockAccount = "unlock_account"
+	// TokenPurposeMFAChallenge выдается после проверки пароля и обменивается на токены вместе с кодом TOTP
+	TokenPurposeMFAChallenge = "mfa_challenge"
+)
+
+// UserActionToken это одноразовый токен подтверждения почты или сброса пароля.
+// В БД хранится только хеш токена.
+type UserActionToken struct {
+	UserID    uint
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
 }
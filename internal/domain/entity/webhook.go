@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// WebhookEvent это тип события, о котором магазин может получать вебхуки
+type WebhookEvent string
+
+const (
+	WebhookOfferCreated   WebhookEvent = "offer.created"
+	WebhookOfferAccepted  WebhookEvent = "offer.accepted"
+	WebhookOfferDeclined  WebhookEvent = "offer.declined"
+	WebhookOfferCancelled WebhookEvent = "offer.cancelled"
+	WebhookOfferExpired   WebhookEvent = "offer.expired"
+)
+
+// WebhookEvents перечисляет все события, на которые можно подписаться
+var WebhookEvents = []WebhookEvent{
+	WebhookOfferCreated,
+	WebhookOfferAccepted,
+	WebhookOfferDeclined,
+	WebhookOfferCancelled,
+	WebhookOfferExpired,
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead доставка исчерпала попытки и отправляется снова только вручную
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookEndpoint это адрес магазина, на который отправляются события. Каждая доставка
+// подписывается секретом адреса, чтобы магазин мог проверить, что запрос пришел от нас.
+type WebhookEndpoint struct {
+	ID        uint
+	ShopID    uint
+	CreatedBy uint
+	URL       string
+	Secret    string
+	Events    []WebhookEvent
+	Active    bool
+	CreatedAt time.Time
+}
+
+// Subscribed сообщает, что адрес принимает событие event
+func (e WebhookEndpoint) Subscribed(event WebhookEvent) bool {
+	return e.Active && slices.Contains(e.Events, event)
+}
+
+// WebhookDelivery это отправка одного события на один адрес вместе с ее текущим состоянием
+type WebhookDelivery struct {
+	ID             uint
+	EndpointID     uint
+	EventID        string
+	Event          WebhookEvent
+	Payload        []byte
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
+
+// WebhookAttempt это запись журнала об одной попытке доставки
+type WebhookAttempt struct {
+	ID           uint
+	DeliveryID   uint
+	StatusCode   *int
+	Error        string
+	ResponseBody string
+	Duration     time.Duration
+	AttemptedAt  time.Time
+}
+
+// WebhookJob это доставка, взятая в работу, вместе с адресом и секретом для подписи
+type WebhookJob struct {
+	Delivery WebhookDelivery
+	URL      string
+	Secret   string
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+type WishlistItem struct {
+	UserID       uint
+	ProductID    int
+	ProductName  string
+	TargetPrice  *int
+	MinimalPrice int
+	CreatedAt    time.Time
+}
+
+// PriceDrop это товар из списка желаемого, минимальная цена на который опустилась до целевой
+type PriceDrop struct {
+	UserID      uint
+	UserEmail   string
+	ProductID   int
+	ProductName string
+	Price       int
+	TargetPrice int
+	// NotifiedPrice цена из прошлого уведомления, nil если о снижении еще не уведомляли
+	NotifiedPrice *int
+}
+
+// SavedSearch хранит фильтр каталога в сериализованном виде.
+// LastProductID это наибольший ID продукта, о котором пользователь уже знает.
+type SavedSearch struct {
+	ID            uint
+	UserID        uint
+	UserEmail     string
+	Name          string
+	Filter        []byte
+	LastProductID int
+	CreatedAt     time.Time
+}
@@ -0,0 +1,164 @@
+package apikey
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=apikey_mock_test.go -package=apikey Repository ShopMembers
+
+type Repository interface {
+	InsertAPIKey(ctx context.Context, key entity.APIKey) (uint, error)
+	SelectAPIKeys(ctx context.Context, shopID uint) ([]entity.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (entity.APIKey, error)
+	RevokeAPIKey(ctx context.Context, shopID, keyID uint) error
+	TouchAPIKey(ctx context.Context, keyID uint) error
+}
+
+// ShopMembers возвращает роль пользователя в магазине
+type ShopMembers interface {
+	GetMemberRole(ctx context.Context, shopID, userID uint) (entity.ShopRole, error)
+}
+
+type Service struct {
+	apiKeyRepository Repository
+	shopMembers      ShopMembers
+}
+
+func NewService(apiKeyRepository Repository, shopMembers ShopMembers) *Service {
+	return &Service{
+		apiKeyRepository: apiKeyRepository,
+		shopMembers:      shopMembers,
+	}
+}
+
+// CreateKey выпускает ключ магазина и возвращает его вместе с самим ключом, который показывается один раз.
+// Выпускать ключи могут владелец и менеджер, и только с областями, которые дает их собственная роль.
+func (s *Service) CreateKey(
+	ctx context.Context,
+	shopID, actorID uint,
+	name string,
+	scopes []entity.Permission,
+	expiresAt *time.Time,
+) (entity.APIKey, string, error) {
+	role, err := s.managerRole(ctx, shopID, actorID)
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	if len(scopes) == 0 {
+		return entity.APIKey{}, "", apperror.New(apperror.BadRequest, "at least one scope is required", nil)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(entity.ShopRolePermissions[role], scope) {
+			return entity.APIKey{}, "", apperror.New(apperror.BadRequest,
+				"scope "+string(scope)+" is not available for your role", nil)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return entity.APIKey{}, "", apperror.New(apperror.BadRequest, "expiry must be in the future", nil)
+	}
+
+	rawKey, prefix, secretHash, err := security.GenerateAPIKey()
+	if err != nil {
+		return entity.APIKey{}, "", apperror.New(apperror.InternalError, "failed to generate api key", err)
+	}
+
+	key := entity.APIKey{
+		ShopID:     shopID,
+		CreatedBy:  actorID,
+		Name:       name,
+		Prefix:     prefix,
+		SecretHash: secretHash,
+		Scopes:     slices.Compact(slices.Sorted(slices.Values(scopes))),
+		ExpiresAt:  expiresAt,
+		CreatedAt:  time.Now(),
+	}
+
+	key.ID, err = s.apiKeyRepository.InsertAPIKey(ctx, key)
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	return key, rawKey, nil
+}
+
+// GetKeys возвращает ключи магазина без секретов
+func (s *Service) GetKeys(ctx context.Context, shopID, actorID uint) ([]entity.APIKey, error) {
+	if _, err := s.managerRole(ctx, shopID, actorID); err != nil {
+		return nil, err
+	}
+
+	return s.apiKeyRepository.SelectAPIKeys(ctx, shopID)
+}
+
+// RevokeKey отзывает ключ магазина, ключ перестает работать сразу
+func (s *Service) RevokeKey(ctx context.Context, shopID, actorID, keyID uint) error {
+	if _, err := s.managerRole(ctx, shopID, actorID); err != nil {
+		return err
+	}
+
+	return s.apiKeyRepository.RevokeAPIKey(ctx, shopID, keyID)
+}
+
+// Authenticate проверяет ключ из запроса. Ключ перестает действовать, если создавший его сотрудник
+// ушел из магазина или его роль больше не дает какую-то из областей ключа.
+func (s *Service) Authenticate(ctx context.Context, rawKey string) (entity.APIKey, error) {
+	prefix, secretHash, ok := security.ParseAPIKey(rawKey)
+	if !ok {
+		return entity.APIKey{}, apperror.ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepository.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, apperror.ErrAPIKeyNotFound) {
+			return entity.APIKey{}, apperror.ErrInvalidAPIKey
+		}
+		return entity.APIKey{}, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(secretHash)) != 1 || !key.Active(time.Now()) {
+		return entity.APIKey{}, apperror.ErrInvalidAPIKey
+	}
+
+	role, err := s.shopMembers.GetMemberRole(ctx, key.ShopID, key.CreatedBy)
+	if err != nil {
+		if errors.Is(err, apperror.ErrShopMemberNotFound) {
+			return entity.APIKey{}, apperror.ErrInvalidAPIKey
+		}
+		return entity.APIKey{}, err
+	}
+	for _, scope := range key.Scopes {
+		if !slices.Contains(entity.ShopRolePermissions[role], scope) {
+			return entity.APIKey{}, apperror.ErrInvalidAPIKey
+		}
+	}
+
+	if err = s.apiKeyRepository.TouchAPIKey(ctx, key.ID); err != nil {
+		return entity.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+// managerRole возвращает роль сотрудника, который может управлять ключами магазина
+func (s *Service) managerRole(ctx context.Context, shopID, actorID uint) (entity.ShopRole, error) {
+	role, err := s.shopMembers.GetMemberRole(ctx, shopID, actorID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrShopMemberNotFound) {
+			return "", apperror.New(apperror.Forbidden, "not a member of the shop", err)
+		}
+		return "", err
+	}
+	if role != entity.ShopRoleOwner && role != entity.ShopRoleManager {
+		return "", apperror.New(apperror.Forbidden, "only owner and manager can manage api keys", nil)
+	}
+	return role, nil
+}
@@ -0,0 +1,153 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func expectAppErrorCode(err error, code string) {
+	var appErr *apperror.Error
+	ExpectWithOffset(1, errors.As(err, &appErr)).To(BeTrue())
+	ExpectWithOffset(1, appErr.Code()).To(Equal(code))
+}
+
+var _ = Describe("APIKeyService", func() {
+	var (
+		ctrl        *gomock.Controller
+		mockRepo    *MockRepository
+		mockMembers *MockShopMembers
+		service     *Service
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockMembers = NewMockShopMembers(ctrl)
+		service = NewService(mockRepo, mockMembers)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateKey", func() {
+		It("should store the hashed secret and return a key that authenticates", func() {
+			var stored entity.APIKey
+			mockMembers.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleManager, nil)
+			mockRepo.EXPECT().InsertAPIKey(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, key entity.APIKey) (uint, error) {
+					stored = key
+					return 5, nil
+				})
+
+			key, rawKey, err := service.CreateKey(ctx, 1, 10, "erp", []entity.Permission{
+				entity.PermissionOffersRespond, entity.PermissionInventoryWrite, entity.PermissionOffersRespond,
+			}, nil)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key.ID).To(Equal(uint(5)))
+			Expect(rawKey).To(HavePrefix("stw_" + stored.Prefix + "_"))
+			Expect(stored.SecretHash).NotTo(ContainSubstring(rawKey))
+			Expect(stored.Scopes).To(Equal([]entity.Permission{
+				entity.PermissionInventoryWrite, entity.PermissionOffersRespond,
+			}))
+
+			prefix, secretHash, ok := security.ParseAPIKey(rawKey)
+			Expect(ok).To(BeTrue())
+			Expect(prefix).To(Equal(stored.Prefix))
+			Expect(secretHash).To(Equal(stored.SecretHash))
+		})
+
+		It("should not grant scopes the role does not have", func() {
+			mockMembers.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleOwner, nil)
+
+			_, _, err := service.CreateKey(ctx, 1, 10, "erp", []entity.Permission{entity.PermissionAuditRead}, nil)
+
+			expectAppErrorCode(err, apperror.BadRequest)
+		})
+
+		It("should forbid agents to create keys", func() {
+			mockMembers.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleAgent, nil)
+
+			_, _, err := service.CreateKey(ctx, 1, 10, "erp", []entity.Permission{entity.PermissionOffersRespond}, nil)
+
+			expectAppErrorCode(err, apperror.Forbidden)
+		})
+	})
+
+	Describe("Authenticate", func() {
+		var (
+			rawKey string
+			key    entity.APIKey
+		)
+
+		BeforeEach(func() {
+			var err error
+			var prefix, secretHash string
+			rawKey, prefix, secretHash, err = security.GenerateAPIKey()
+			Expect(err).NotTo(HaveOccurred())
+			key = entity.APIKey{
+				ID:         5,
+				ShopID:     1,
+				CreatedBy:  10,
+				Prefix:     prefix,
+				SecretHash: secretHash,
+				Scopes:     []entity.Permission{entity.PermissionInventoryWrite},
+			}
+		})
+
+		It("should accept an active key and record its use", func() {
+			mockRepo.EXPECT().GetAPIKeyByPrefix(ctx, key.Prefix).Return(key, nil)
+			mockMembers.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleManager, nil)
+			mockRepo.EXPECT().TouchAPIKey(ctx, uint(5)).Return(nil)
+
+			got, err := service.Authenticate(ctx, rawKey)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got.ID).To(Equal(uint(5)))
+		})
+
+		It("should reject a wrong secret", func() {
+			mockRepo.EXPECT().GetAPIKeyByPrefix(ctx, key.Prefix).Return(key, nil)
+
+			_, err := service.Authenticate(ctx, "stw_"+key.Prefix+"_wrong")
+
+			Expect(err).To(Equal(apperror.ErrInvalidAPIKey))
+		})
+
+		It("should reject a revoked or expired key", func() {
+			past := time.Now().Add(-time.Minute)
+			key.ExpiresAt = &past
+			mockRepo.EXPECT().GetAPIKeyByPrefix(ctx, key.Prefix).Return(key, nil)
+
+			_, err := service.Authenticate(ctx, rawKey)
+
+			Expect(err).To(Equal(apperror.ErrInvalidAPIKey))
+		})
+
+		It("should reject a key whose creator lost the scope", func() {
+			mockRepo.EXPECT().GetAPIKeyByPrefix(ctx, key.Prefix).Return(key, nil)
+			mockMembers.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleAgent, nil)
+
+			_, err := service.Authenticate(ctx, rawKey)
+
+			Expect(err).To(Equal(apperror.ErrInvalidAPIKey))
+		})
+
+		It("should reject a malformed key without a lookup", func() {
+			_, err := service.Authenticate(ctx, "not-a-key")
+
+			Expect(err).To(Equal(apperror.ErrInvalidAPIKey))
+		})
+	})
+})
@@ -2,6 +2,7 @@ package audit
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
@@ -40,3 +41,20 @@ func (as *AuditService) DisplayLogs(
 ) {
 	return as.auditRepository.GetLogs(ctx, fromT, toT, uid, limit, offset)
 }
+
+// HandleEvent записывает доменное событие в журнал аудита
+func (as *AuditService) HandleEvent(_ context.Context, event entity.Event) error {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	return as.auditRepository.LogStore([]entity.AuditEntry{{
+		Method:     entity.AuditMethodEvent,
+		Url:        string(event.Type),
+		UserID:     event.ActorID,
+		ReceivedAt: event.CreatedAt,
+		ReqBody:    payload,
+		RespBody:   map[string]interface{}{},
+	}})
+}
@@ -0,0 +1,99 @@
+package category
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=category_mock_test.go -package=category Repository
+
+type Repository interface {
+	GetCategories(ctx context.Context) ([]entity.Category, error)
+	GetSubtree(ctx context.Context, id uint) ([]entity.Category, error)
+	GetBreadcrumbs(ctx context.Context, id uint) ([]entity.Category, error)
+	InsertCategory(ctx context.Context, name string, parentID *uint) (uint, error)
+	MoveCategory(ctx context.Context, id uint, parentID *uint) error
+	DeleteCategory(ctx context.Context, id uint) error
+}
+
+type Service struct {
+	categoryRepository Repository
+}
+
+func NewService(categoryRepository Repository) *Service {
+	return &Service{categoryRepository: categoryRepository}
+}
+
+// GetTree возвращает полное дерево категорий
+func (cs *Service) GetTree(ctx context.Context) ([]entity.Category, error) {
+	categories, err := cs.categoryRepository.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTree(categories), nil
+}
+
+// GetSubtree возвращает категорию со всеми подкатегориями
+func (cs *Service) GetSubtree(ctx context.Context, id uint) (entity.Category, error) {
+	categories, err := cs.categoryRepository.GetSubtree(ctx, id)
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	tree := buildTree(categories)
+	if len(tree) == 0 {
+		return entity.Category{}, apperror.ErrCategoryNotFound
+	}
+
+	return tree[0], nil
+}
+
+// GetBreadcrumbs возвращает цепочку категорий от корня до указанной
+func (cs *Service) GetBreadcrumbs(ctx context.Context, id uint) ([]entity.Category, error) {
+	return cs.categoryRepository.GetBreadcrumbs(ctx, id)
+}
+
+func (cs *Service) CreateCategory(ctx context.Context, name string, parentID *uint) (uint, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, apperror.New(apperror.BadRequest, "category name must not be empty", nil)
+	}
+
+	return cs.categoryRepository.InsertCategory(ctx, name, parentID)
+}
+
+// MoveCategory переносит категорию под нового родителя, nil означает перенос в корень
+func (cs *Service) MoveCategory(ctx context.Context, id uint, parentID *uint) error {
+	if parentID != nil && *parentID == id {
+		return apperror.New(apperror.BadRequest, "category cannot be its own parent", nil)
+	}
+
+	return cs.categoryRepository.MoveCategory(ctx, id, parentID)
+}
+
+func (cs *Service) DeleteCategory(ctx context.Context, id uint) error {
+	return cs.categoryRepository.DeleteCategory(ctx, id)
+}
+
+// buildTree собирает вложенное дерево из плоского списка категорий, упорядоченного по lft
+func buildTree(categories []entity.Category) []entity.Category {
+	tree, _ := buildLevel(categories, 0, math.MaxInt)
+	return tree
+}
+
+// buildLevel собирает узлы, лежащие внутри границы rgt родителя, начиная с позиции i,
+// и возвращает позицию первого узла за пределами родителя
+func buildLevel(categories []entity.Category, i, parentRgt int) ([]entity.Category, int) {
+	var level []entity.Category
+	for i < len(categories) && categories[i].Rgt < parentRgt {
+		node := categories[i]
+		node.Children, i = buildLevel(categories, i+1, node.Rgt)
+		level = append(level, node)
+	}
+	return level, i
+}
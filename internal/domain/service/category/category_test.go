@@ -0,0 +1,129 @@
+package category
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("CategoryService", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockRepo *MockRepository
+		service  *Service
+		ctx      context.Context
+		flat     []entity.Category
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		service = NewService(mockRepo)
+		ctx = context.Background()
+
+		// Electronics(1,10) -> Computers(2,7) -> Laptops(3,4), Desktops(5,6); Phones(8,9); Books(11,12)
+		flat = []entity.Category{
+			{ID: 1, Name: "Electronics", Lft: 1, Rgt: 10},
+			{ID: 2, Name: "Computers", Lft: 2, Rgt: 7},
+			{ID: 3, Name: "Laptops", Lft: 3, Rgt: 4},
+			{ID: 4, Name: "Desktops", Lft: 5, Rgt: 6},
+			{ID: 5, Name: "Phones", Lft: 8, Rgt: 9},
+			{ID: 6, Name: "Books", Lft: 11, Rgt: 12},
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("GetTree", func() {
+		It("should build nested tree from nested set", func() {
+			mockRepo.EXPECT().GetCategories(ctx).Return(flat, nil)
+
+			tree, err := service.GetTree(ctx)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tree).To(HaveLen(2))
+			Expect(tree[0].Name).To(Equal("Electronics"))
+			Expect(tree[0].Children).To(HaveLen(2))
+			Expect(tree[0].Children[0].Name).To(Equal("Computers"))
+			Expect(tree[0].Children[0].Children).To(HaveLen(2))
+			Expect(tree[0].Children[0].Children[1].Name).To(Equal("Desktops"))
+			Expect(tree[0].Children[1].Name).To(Equal("Phones"))
+			Expect(tree[0].Children[1].Children).To(BeEmpty())
+			Expect(tree[1].Name).To(Equal("Books"))
+		})
+
+		It("should return repository error", func() {
+			mockRepo.EXPECT().GetCategories(ctx).Return(nil, errors.New("db error"))
+
+			tree, err := service.GetTree(ctx)
+
+			Expect(err).To(HaveOccurred())
+			Expect(tree).To(BeNil())
+		})
+	})
+
+	Describe("GetSubtree", func() {
+		It("should return root of the subtree with children", func() {
+			mockRepo.EXPECT().GetSubtree(ctx, uint(2)).Return(flat[1:4], nil)
+
+			root, err := service.GetSubtree(ctx, 2)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(root.ID).To(Equal(uint(2)))
+			Expect(root.Children).To(HaveLen(2))
+		})
+
+		It("should return not found for empty subtree", func() {
+			mockRepo.EXPECT().GetSubtree(ctx, uint(42)).Return([]entity.Category{}, nil)
+
+			_, err := service.GetSubtree(ctx, 42)
+
+			Expect(err).To(MatchError(apperror.ErrCategoryNotFound))
+		})
+	})
+
+	Describe("CreateCategory", func() {
+		It("should reject empty name", func() {
+			_, err := service.CreateCategory(ctx, "   ", nil)
+
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should insert trimmed name", func() {
+			parentID := uint(1)
+			mockRepo.EXPECT().InsertCategory(ctx, "Tablets", &parentID).Return(uint(7), nil)
+
+			id, err := service.CreateCategory(ctx, " Tablets ", &parentID)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(id).To(Equal(uint(7)))
+		})
+	})
+
+	Describe("MoveCategory", func() {
+		It("should reject moving category under itself", func() {
+			id := uint(3)
+
+			err := service.MoveCategory(ctx, id, &id)
+
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should move category to the root", func() {
+			mockRepo.EXPECT().MoveCategory(ctx, uint(3), nil).Return(nil)
+
+			Expect(service.MoveCategory(ctx, 3, nil)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,164 @@
+package eventbus
+
+import (
+	"context"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"go.uber.org/zap"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=eventbus_mock_test.go -package=eventbus Repository
+
+type Repository interface {
+	ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]entity.Event, error)
+	FinishEvent(ctx context.Context, event entity.Event) error
+	DeleteProcessedEvents(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Handler обрабатывает событие. Событие может прийти повторно, если обработка
+// или сохранение ее результата не завершились, поэтому обработчик должен это переносить.
+type Handler func(ctx context.Context, event entity.Event) error
+
+type subscription struct {
+	name    string
+	handler Handler
+}
+
+// Bus раздает события из outbox подписчикам. Каждый подписчик получает событие хотя бы один раз,
+// в том числе после перезапуска приложения. Подписчик, который уже обработал событие,
+// не получает его снова при повторе из-за ошибки другого подписчика.
+type Bus struct {
+	repository  Repository
+	cfg         *config.EventsConfig
+	log         *zap.Logger
+	subscribers map[entity.EventType][]subscription
+	lastCleanup time.Time
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+func NewBus(repository Repository, cfg *config.EventsConfig, log *zap.Logger) *Bus {
+	return &Bus{
+		repository:  repository,
+		cfg:         cfg,
+		log:         log,
+		subscribers: make(map[entity.EventType][]subscription),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Subscribe подписывает обработчик на события types. Имя подписчика сохраняется вместе
+// с событием, поэтому его нельзя менять, пока в outbox есть необработанные события.
+// Подписываться нужно до Start.
+func (b *Bus) Subscribe(name string, handler Handler, types ...entity.EventType) {
+	for _, t := range types {
+		b.subscribers[t] = append(b.subscribers[t], subscription{name: name, handler: handler})
+	}
+}
+
+// Start запускает раздачу событий
+func (b *Bus) Start() {
+	go b.run(b.cfg.PollInterval)
+}
+
+// Close останавливает раздачу и дожидается завершения текущего прохода
+func (b *Bus) Close() {
+	close(b.stop)
+	<-b.done
+}
+
+func (b *Bus) run(interval time.Duration) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.relay(context.Background())
+			b.cleanup(context.Background())
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+// relay раздает одну пачку событий по порядку их записи
+func (b *Bus) relay(ctx context.Context) {
+	events, err := b.repository.ClaimEvents(ctx, b.cfg.BatchSize, b.cfg.Lease)
+	if err != nil {
+		b.log.Error("Failed to claim outbox events", zap.Error(err))
+		return
+	}
+
+	for _, event := range events {
+		b.dispatch(ctx, event)
+	}
+}
+
+func (b *Bus) dispatch(ctx context.Context, event entity.Event) {
+	event.LastError = ""
+	for _, sub := range b.subscribers[event.Type] {
+		if event.CompletedBy(sub.name) {
+			continue
+		}
+		if err := sub.handler(ctx, event); err != nil {
+			b.log.Warn("Event subscriber failed",
+				zap.String("subscriber", sub.name), zap.String("event", string(event.Type)),
+				zap.Uint("event_id", event.ID), zap.Error(err))
+			event.LastError = sub.name + ": " + err.Error()
+			continue
+		}
+		event.Completed = append(event.Completed, sub.name)
+	}
+
+	now := time.Now()
+	event.Attempts++
+
+	switch {
+	case event.LastError == "":
+		event.Status = entity.EventProcessed
+		event.ProcessedAt = &now
+	case event.Attempts >= b.cfg.MaxAttempts:
+		event.Status = entity.EventDead
+		b.log.Error("Outbox event is dead",
+			zap.Uint("event_id", event.ID), zap.String("event", string(event.Type)),
+			zap.String("last_error", event.LastError))
+	default:
+		event.NextAttemptAt = now.Add(b.backoff(event.Attempts))
+	}
+
+	if err := b.repository.FinishEvent(ctx, event); err != nil {
+		b.log.Error("Failed to save outbox event state", zap.Uint("event_id", event.ID), zap.Error(err))
+	}
+}
+
+// cleanup раз в час удаляет события, обработанные раньше cfg.Retention
+func (b *Bus) cleanup(ctx context.Context) {
+	if time.Since(b.lastCleanup) < time.Hour {
+		return
+	}
+	b.lastCleanup = time.Now()
+
+	deleted, err := b.repository.DeleteProcessedEvents(ctx, time.Now().Add(-b.cfg.Retention))
+	if err != nil {
+		b.log.Error("Failed to delete processed outbox events", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		b.log.Info("Processed outbox events deleted", zap.Int64("count", deleted))
+	}
+}
+
+// backoff возвращает задержку после attempts неудачных раздач
+func (b *Bus) backoff(attempts int) time.Duration {
+	delay := b.cfg.RetryBaseDelay
+	for i := 1; i < attempts && delay < b.cfg.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, b.cfg.RetryMaxDelay)
+}
@@ -0,0 +1,152 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("Bus", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockRepo *MockRepository
+		bus      *Bus
+		ctx      context.Context
+		calls    []string
+		failing  map[string]bool
+	)
+
+	handler := func(name string) Handler {
+		return func(_ context.Context, _ entity.Event) error {
+			calls = append(calls, name)
+			if failing[name] {
+				return errors.New("unavailable")
+			}
+			return nil
+		}
+	}
+
+	event := func(attempts int, completed ...string) entity.Event {
+		return entity.Event{
+			ID:        1,
+			UID:       "e1",
+			Type:      entity.EventOfferCreated,
+			Status:    entity.EventPending,
+			Attempts:  attempts,
+			Completed: completed,
+		}
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		// Без запуска фонового цикла, проходы вызываются напрямую
+		bus = NewBus(mockRepo, &config.EventsConfig{
+			BatchSize:      10,
+			Lease:          time.Minute,
+			MaxAttempts:    3,
+			RetryBaseDelay: time.Minute,
+			RetryMaxDelay:  time.Hour,
+			Retention:      24 * time.Hour,
+		}, zap.NewNop())
+		ctx = context.Background()
+		calls = nil
+		failing = map[string]bool{}
+
+		bus.Subscribe("mailer", handler("mailer"), entity.EventOfferCreated)
+		bus.Subscribe("webhooks", handler("webhooks"), entity.EventOfferCreated, entity.EventOfferExpired)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	It("should mark the event processed when every subscriber succeeds", func() {
+		mockRepo.EXPECT().ClaimEvents(ctx, 10, time.Minute).Return([]entity.Event{event(0)}, nil)
+		mockRepo.EXPECT().FinishEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e entity.Event) error {
+			Expect(e.Status).To(Equal(entity.EventProcessed))
+			Expect(e.Attempts).To(Equal(1))
+			Expect(e.ProcessedAt).NotTo(BeNil())
+			Expect(e.Completed).To(Equal([]string{"mailer", "webhooks"}))
+			Expect(e.LastError).To(BeEmpty())
+			return nil
+		})
+
+		bus.relay(ctx)
+
+		Expect(calls).To(Equal([]string{"mailer", "webhooks"}))
+	})
+
+	It("should keep successful subscribers and schedule a retry when one fails", func() {
+		failing["webhooks"] = true
+		mockRepo.EXPECT().FinishEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e entity.Event) error {
+			Expect(e.Status).To(Equal(entity.EventPending))
+			Expect(e.Completed).To(Equal([]string{"mailer"}))
+			Expect(e.LastError).To(Equal("webhooks: unavailable"))
+			Expect(e.NextAttemptAt).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+			return nil
+		})
+
+		bus.dispatch(ctx, event(0))
+	})
+
+	It("should not call subscribers that already handled the event on retry", func() {
+		mockRepo.EXPECT().FinishEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e entity.Event) error {
+			Expect(e.Status).To(Equal(entity.EventProcessed))
+			Expect(e.Attempts).To(Equal(2))
+			Expect(e.Completed).To(Equal([]string{"mailer", "webhooks"}))
+			return nil
+		})
+
+		bus.dispatch(ctx, event(1, "mailer"))
+
+		Expect(calls).To(Equal([]string{"webhooks"}))
+	})
+
+	It("should mark the event dead after the last attempt", func() {
+		failing["webhooks"] = true
+		mockRepo.EXPECT().FinishEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e entity.Event) error {
+			Expect(e.Status).To(Equal(entity.EventDead))
+			Expect(e.Attempts).To(Equal(3))
+			Expect(e.ProcessedAt).To(BeNil())
+			return nil
+		})
+
+		bus.dispatch(ctx, event(2, "mailer"))
+	})
+
+	It("should mark events without subscribers processed", func() {
+		mockRepo.EXPECT().FinishEvent(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e entity.Event) error {
+			Expect(e.Status).To(Equal(entity.EventProcessed))
+			return nil
+		})
+
+		bus.dispatch(ctx, entity.Event{ID: 2, Type: entity.EventGuestOfferReceived, Status: entity.EventPending})
+
+		Expect(calls).To(BeEmpty())
+	})
+
+	It("should delete old processed events at most once an hour", func() {
+		mockRepo.EXPECT().DeleteProcessedEvents(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+				Expect(before).To(BeTemporally("~", time.Now().Add(-24*time.Hour), time.Second))
+				return 5, nil
+			}).Times(1)
+
+		bus.cleanup(ctx)
+		bus.cleanup(ctx)
+	})
+
+	It("should cap the retry delay", func() {
+		Expect(bus.backoff(1)).To(Equal(time.Minute))
+		Expect(bus.backoff(3)).To(Equal(4 * time.Minute))
+		Expect(bus.backoff(30)).To(Equal(time.Hour))
+	})
+})
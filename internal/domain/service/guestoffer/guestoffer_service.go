@@ -2,6 +2,7 @@ package guestoffer
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -16,15 +17,22 @@ type NotificationSender interface {
 	SendGuestOfferNotification(email string, subject string, body string)
 }
 
+// Outbox stores the event that the guest offer was received
+type Outbox interface {
+	InsertEvent(ctx context.Context, eventType entity.EventType, actorID uint, payload any) error
+}
+
 // Service describes the interface for the guest offer service
 type Service interface {
 	ProcessGuestOffer(ctx context.Context, offerData entity.GuestOfferData) error
+	HandleGuestOfferReceived(ctx context.Context, event entity.Event) error
 }
 
 // GuestOfferService implements the Service interface
 type GuestOfferService struct {
 	storeInfoGetter    guestofferrepo.StoreInfoGetter
 	notificationSender NotificationSender
+	outbox             Outbox
 	log                *zap.Logger
 }
 
@@ -32,16 +40,19 @@ type GuestOfferService struct {
 func NewService(
 	storeInfoGetter guestofferrepo.StoreInfoGetter,
 	notificationSender NotificationSender,
+	outbox Outbox,
 	log *zap.Logger,
 ) Service {
 	return &GuestOfferService{
 		storeInfoGetter:    storeInfoGetter,
 		notificationSender: notificationSender,
+		outbox:             outbox,
 		log:                log,
 	}
 }
 
-// ProcessGuestOffer handles the guest offer business logic
+// ProcessGuestOffer checks the store and records the guest offer event.
+// The store owner is notified by HandleGuestOfferReceived when the event is dispatched.
 func (s *GuestOfferService) ProcessGuestOffer(ctx context.Context, offerData entity.GuestOfferData) error {
 	shopOwnerEmail, err := s.storeInfoGetter.GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID)
 	if err != nil {
@@ -55,6 +66,26 @@ func (s *GuestOfferService) ProcessGuestOffer(ctx context.Context, offerData ent
 		return fmt.Errorf("failed to get store owner email from repository: %w", err)
 	}
 
+	err = s.outbox.InsertEvent(ctx, entity.EventGuestOfferReceived, 0, entity.GuestOfferEvent{
+		Offer:          offerData,
+		ShopOwnerEmail: shopOwnerEmail,
+	})
+	if err != nil {
+		s.log.Error("Failed to record guest offer event", zap.Error(err), zap.Uint("store_id", offerData.StoreID))
+		return fmt.Errorf("failed to record guest offer event: %w", err)
+	}
+
+	return nil
+}
+
+// HandleGuestOfferReceived emails the guest offer to the store owner
+func (s *GuestOfferService) HandleGuestOfferReceived(_ context.Context, event entity.Event) error {
+	var payload entity.GuestOfferEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+	offerData := payload.Offer
+
 	emailSubject := "New guest offer received"
 	emailBody := fmt.Sprintf(
 		"A new guest offer has been received:\n\n"+
@@ -73,7 +104,7 @@ func (s *GuestOfferService) ProcessGuestOffer(ctx context.Context, offerData ent
 		offerData.GuestPhone,
 	)
 
-	s.notificationSender.SendGuestOfferNotification(shopOwnerEmail, emailSubject, emailBody)
+	s.notificationSender.SendGuestOfferNotification(payload.ShopOwnerEmail, emailSubject, emailBody)
 
 	return nil
 }
@@ -2,6 +2,7 @@ package guestoffer_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -21,6 +22,7 @@ var _ = Describe("GuestOfferService", func() {
 		ctrl                   *gomock.Controller
 		mockStoreInfoGetter    *repomocks.MockStoreInfoGetter
 		mockNotificationSender *guestofferservice.MockNotificationSender
+		mockOutbox             *guestofferservice.MockOutbox
 		service                guestofferservice.Service
 		ctx                    context.Context
 		log                    *zap.Logger
@@ -31,9 +33,10 @@ var _ = Describe("GuestOfferService", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockStoreInfoGetter = repomocks.NewMockStoreInfoGetter(ctrl)
 		mockNotificationSender = guestofferservice.NewMockNotificationSender(ctrl)
+		mockOutbox = guestofferservice.NewMockOutbox(ctrl)
 		log = zaptest.NewLogger(GinkgoT())
 
-		service = guestofferservice.NewService(mockStoreInfoGetter, mockNotificationSender, log)
+		service = guestofferservice.NewService(mockStoreInfoGetter, mockNotificationSender, mockOutbox, log)
 		ctx = context.Background()
 
 		offerData = entity.GuestOfferData{
@@ -53,42 +56,45 @@ var _ = Describe("GuestOfferService", func() {
 
 	Describe("ProcessGuestOffer", func() {
 		Context("when getting store owner email is successful", func() {
-			It("should send a notification and return nil", func() {
-				expectedEmail := "owner@example.com"
-
+			It("should record the guest offer event and return nil", func() {
 				mockStoreInfoGetter.EXPECT().
 					GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID).
-					Return(expectedEmail, nil).
+					Return("owner@example.com", nil).
 					Times(1)
 
-				expectedSubject := "New guest offer received"
-				expectedBody := fmt.Sprintf(
-					"A new guest offer has been received:\n\n"+
-						"Product ID: %d\n"+
-						"Store ID: %d\n"+
-						"Proposed Price: %.2f %s\n"+
-						"Guest Name: %s\n"+
-						"Guest Email: %s\n"+
-						"Guest Phone: %s",
-					offerData.ProductID,
-					offerData.StoreID,
-					offerData.Price,
-					offerData.Currency,
-					offerData.GuestName,
-					offerData.GuestEmail,
-					offerData.GuestPhone,
-				)
-
-				mockNotificationSender.EXPECT().
-					SendGuestOfferNotification(expectedEmail, expectedSubject, expectedBody).
+				mockOutbox.EXPECT().
+					InsertEvent(ctx, entity.EventGuestOfferReceived, uint(0), entity.GuestOfferEvent{
+						Offer:          offerData,
+						ShopOwnerEmail: "owner@example.com",
+					}).
+					Return(nil).
 					Times(1)
 
+				mockNotificationSender.EXPECT().SendGuestOfferNotification(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
 				err := service.ProcessGuestOffer(ctx, offerData)
 
 				Expect(err).NotTo(HaveOccurred())
 			})
 		})
 
+		Context("when recording the event fails", func() {
+			It("should return a wrapped error", func() {
+				repoError := errors.New("some database error")
+				mockStoreInfoGetter.EXPECT().
+					GetStoreOwnerEmailByStoreID(ctx, offerData.StoreID).
+					Return("owner@example.com", nil).
+					Times(1)
+
+				mockOutbox.EXPECT().InsertEvent(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(repoError).Times(1)
+
+				err := service.ProcessGuestOffer(ctx, offerData)
+
+				Expect(err).To(HaveOccurred())
+				Expect(errors.Is(err, repoError)).To(BeTrue())
+			})
+		})
+
 		Context("when getting store owner email returns StoreNotFound error", func() {
 			It("should return a GuestOfferStoreNotFound error", func() {
 				repoError := apperror.NewGuestOfferError(apperror.GuestOfferStoreNotFound, "store not found")
@@ -126,4 +132,40 @@ var _ = Describe("GuestOfferService", func() {
 			})
 		})
 	})
+
+	Describe("HandleGuestOfferReceived", func() {
+		It("should email the guest offer to the store owner", func() {
+			payload, err := json.Marshal(entity.GuestOfferEvent{Offer: offerData, ShopOwnerEmail: "owner@example.com"})
+			Expect(err).NotTo(HaveOccurred())
+
+			expectedSubject := "New guest offer received"
+			expectedBody := fmt.Sprintf(
+				"A new guest offer has been received:\n\n"+
+					"Product ID: %d\n"+
+					"Store ID: %d\n"+
+					"Proposed Price: %.2f %s\n"+
+					"Guest Name: %s\n"+
+					"Guest Email: %s\n"+
+					"Guest Phone: %s",
+				offerData.ProductID,
+				offerData.StoreID,
+				offerData.Price,
+				offerData.Currency,
+				offerData.GuestName,
+				offerData.GuestEmail,
+				offerData.GuestPhone,
+			)
+
+			mockNotificationSender.EXPECT().
+				SendGuestOfferNotification("owner@example.com", expectedSubject, expectedBody).
+				Times(1)
+
+			err = service.HandleGuestOfferReceived(ctx, entity.Event{
+				Type:    entity.EventGuestOfferReceived,
+				Payload: payload,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
 })
@@ -53,6 +53,44 @@ func (mr *MockNotificationSenderMockRecorder) SendGuestOfferNotification(email,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendGuestOfferNotification", reflect.TypeOf((*MockNotificationSender)(nil).SendGuestOfferNotification), email, subject, body)
 }
 
+// MockOutbox is a mock of Outbox interface.
+type MockOutbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxMockRecorder is the mock recorder for MockOutbox.
+type MockOutboxMockRecorder struct {
+	mock *MockOutbox
+}
+
+// NewMockOutbox creates a new mock instance.
+func NewMockOutbox(ctrl *gomock.Controller) *MockOutbox {
+	mock := &MockOutbox{ctrl: ctrl}
+	mock.recorder = &MockOutboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutbox) EXPECT() *MockOutboxMockRecorder {
+	return m.recorder
+}
+
+// InsertEvent mocks base method.
+func (m *MockOutbox) InsertEvent(ctx context.Context, eventType entity.EventType, actorID uint, payload any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEvent", ctx, eventType, actorID, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertEvent indicates an expected call of InsertEvent.
+func (mr *MockOutboxMockRecorder) InsertEvent(ctx, eventType, actorID, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEvent", reflect.TypeOf((*MockOutbox)(nil).InsertEvent), ctx, eventType, actorID, payload)
+}
+
 // MockService is a mock of Service interface.
 type MockService struct {
 	ctrl     *gomock.Controller
@@ -77,6 +115,20 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// HandleGuestOfferReceived mocks base method.
+func (m *MockService) HandleGuestOfferReceived(ctx context.Context, event entity.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleGuestOfferReceived", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleGuestOfferReceived indicates an expected call of HandleGuestOfferReceived.
+func (mr *MockServiceMockRecorder) HandleGuestOfferReceived(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleGuestOfferReceived", reflect.TypeOf((*MockService)(nil).HandleGuestOfferReceived), ctx, event)
+}
+
 // ProcessGuestOffer mocks base method.
 func (m *MockService) ProcessGuestOffer(ctx context.Context, offerData entity.GuestOfferData) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,271 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	// Регистрация декодеров поддерживаемых форматов
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+)
+
+// maxPixels ограничивает размер декодируемого изображения, чтобы маленький файл
+// с огромным разрешением не исчерпал память сервера
+const maxPixels = 40_000_000
+
+const thumbnailQuality = 85
+
+var allowedTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+//go:generate mockgen -source=$GOFILE -destination=image_mock_test.go -package=image Repository Storage
+
+type Repository interface {
+	InsertImage(ctx context.Context, img entity.ProductImage) error
+	SelectImagesByProductIDs(ctx context.Context, productIDs []int) ([]entity.ProductImage, error)
+	GetImage(ctx context.Context, productID int, key string) (entity.ProductImage, error)
+	DeleteImage(ctx context.Context, productID int, key string) error
+	IsProductSoldByUser(ctx context.Context, productID int, userID uint) (bool, error)
+}
+
+// Storage это объектное хранилище, в котором лежат сами файлы изображений
+type Storage interface {
+	Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error
+	Delete(ctx context.Context, key string) error
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+type Service struct {
+	imageRepository Repository
+	storage         Storage
+	cfg             *config.ImageConfig
+}
+
+func NewService(imageRepository Repository, storage Storage, cfg *config.ImageConfig) *Service {
+	return &Service{
+		imageRepository: imageRepository,
+		storage:         storage,
+		cfg:             cfg,
+	}
+}
+
+// UploadProductImage проверяет загруженный файл, строит превью и сохраняет оба файла в хранилище.
+// Загружать изображения может администратор или продавец, у которого продукт есть в ассортименте.
+func (s *Service) UploadProductImage(
+	ctx context.Context,
+	productID int,
+	userID uint,
+	isAdmin bool,
+	data []byte,
+) (entity.ProductImage, error) {
+	if int64(len(data)) > s.cfg.MaxSize {
+		return entity.ProductImage{}, apperror.New(apperror.BadRequest,
+			fmt.Sprintf("image must not exceed %d bytes", s.cfg.MaxSize), nil)
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := allowedTypes[contentType]
+	if !ok {
+		return entity.ProductImage{}, apperror.New(apperror.BadRequest,
+			"unsupported image type "+contentType, nil)
+	}
+
+	if err := s.checkAccess(ctx, productID, userID, isAdmin); err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	thumbnail, err := s.makeThumbnail(data)
+	if err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	name := uuid.NewString()
+	img := entity.ProductImage{
+		Key:          fmt.Sprintf("products/%d/%s.%s", productID, name, ext),
+		ThumbnailKey: fmt.Sprintf("products/%d/%s_thumb.jpg", productID, name),
+		ProductID:    productID,
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		CreatedAt:    time.Now(),
+	}
+
+	err = s.storage.Put(ctx, img.Key, contentType, bytes.NewReader(data), img.Size)
+	if err != nil {
+		return entity.ProductImage{}, apperror.New(apperror.InternalError, "failed to store image", err)
+	}
+
+	err = s.storage.Put(ctx, img.ThumbnailKey, "image/jpeg", bytes.NewReader(thumbnail), int64(len(thumbnail)))
+	if err != nil {
+		_ = s.storage.Delete(ctx, img.Key)
+		return entity.ProductImage{}, apperror.New(apperror.InternalError, "failed to store thumbnail", err)
+	}
+
+	if err = s.imageRepository.InsertImage(ctx, img); err != nil {
+		_ = s.storage.Delete(ctx, img.Key)
+		_ = s.storage.Delete(ctx, img.ThumbnailKey)
+		return entity.ProductImage{}, err
+	}
+
+	if err = s.sign(ctx, &img); err != nil {
+		return entity.ProductImage{}, err
+	}
+
+	return img, nil
+}
+
+// GetProductImages возвращает изображения продукта с подписанными ссылками
+func (s *Service) GetProductImages(ctx context.Context, productID int) ([]entity.ProductImage, error) {
+	images, err := s.ProductImages(ctx, []int{productID})
+	if err != nil {
+		return nil, err
+	}
+	return images[productID], nil
+}
+
+// ProductImages возвращает изображения нескольких продуктов одним запросом, сгруппированные по ID продукта
+func (s *Service) ProductImages(ctx context.Context, productIDs []int) (map[int][]entity.ProductImage, error) {
+	result := make(map[int][]entity.ProductImage, len(productIDs))
+	if len(productIDs) == 0 {
+		return result, nil
+	}
+
+	images, err := s.imageRepository.SelectImagesByProductIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range images {
+		if err = s.sign(ctx, &images[i]); err != nil {
+			return nil, err
+		}
+		result[images[i].ProductID] = append(result[images[i].ProductID], images[i])
+	}
+
+	return result, nil
+}
+
+// DeleteProductImage удаляет изображение продукта по имени файла из ключа
+func (s *Service) DeleteProductImage(
+	ctx context.Context,
+	productID int,
+	fileName string,
+	userID uint,
+	isAdmin bool,
+) error {
+	if fileName == "" || path.Base(fileName) != fileName {
+		return apperror.New(apperror.BadRequest, "invalid image name", nil)
+	}
+
+	if err := s.checkAccess(ctx, productID, userID, isAdmin); err != nil {
+		return err
+	}
+
+	img, err := s.imageRepository.GetImage(ctx, productID, fmt.Sprintf("products/%d/%s", productID, fileName))
+	if err != nil {
+		return err
+	}
+
+	if err = s.storage.Delete(ctx, img.Key); err != nil {
+		return apperror.New(apperror.InternalError, "failed to delete image", err)
+	}
+	if img.ThumbnailKey != "" {
+		if err = s.storage.Delete(ctx, img.ThumbnailKey); err != nil {
+			return apperror.New(apperror.InternalError, "failed to delete thumbnail", err)
+		}
+	}
+
+	return s.imageRepository.DeleteImage(ctx, productID, img.Key)
+}
+
+func (s *Service) checkAccess(ctx context.Context, productID int, userID uint, isAdmin bool) error {
+	if isAdmin {
+		return nil
+	}
+
+	sold, err := s.imageRepository.IsProductSoldByUser(ctx, productID, userID)
+	if err != nil {
+		return err
+	}
+	if !sold {
+		return apperror.New(apperror.Forbidden, "only sellers of the product can manage its images", nil)
+	}
+
+	return nil
+}
+
+func (s *Service) sign(ctx context.Context, img *entity.ProductImage) error {
+	var err error
+	img.URL, err = s.storage.SignedURL(ctx, img.Key, s.cfg.URLTTL)
+	if err != nil {
+		return apperror.New(apperror.InternalError, "failed to sign image url", err)
+	}
+
+	if img.ThumbnailKey != "" {
+		img.ThumbnailURL, err = s.storage.SignedURL(ctx, img.ThumbnailKey, s.cfg.URLTTL)
+		if err != nil {
+			return apperror.New(apperror.InternalError, "failed to sign thumbnail url", err)
+		}
+	}
+
+	return nil
+}
+
+// makeThumbnail уменьшает изображение так, чтобы оно вписалось в квадрат ThumbnailSize,
+// и кодирует результат в JPEG. Маленькие изображения не растягиваются.
+func (s *Service) makeThumbnail(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperror.New(apperror.BadRequest, "failed to read image", err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, apperror.New(apperror.BadRequest, "image resolution is too large", nil)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperror.New(apperror.BadRequest, "failed to decode image", err)
+	}
+
+	width, height := fitSize(src.Bounds().Dx(), src.Bounds().Dy(), s.cfg.ThumbnailSize)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	// JPEG не поддерживает прозрачность, поэтому подкладываем белый фон
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, apperror.New(apperror.InternalError, "failed to encode thumbnail", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func fitSize(width, height, limit int) (int, int) {
+	if width <= limit && height <= limit {
+		return width, height
+	}
+	if width >= height {
+		return limit, max(1, height*limit/width)
+	}
+	return max(1, width*limit/height), limit
+}
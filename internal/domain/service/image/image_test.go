@@ -0,0 +1,181 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func pngImage(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	Expect(png.Encode(&buf, img)).To(Succeed())
+	return buf.Bytes()
+}
+
+var _ = Describe("ImageService", func() {
+	var (
+		ctrl        *gomock.Controller
+		mockRepo    *MockRepository
+		mockStorage *MockStorage
+		service     *Service
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockStorage = NewMockStorage(ctrl)
+		service = NewService(mockRepo, mockStorage, &config.ImageConfig{
+			MaxSize:       1024 * 1024,
+			ThumbnailSize: 64,
+			URLTTL:        time.Hour,
+		})
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("UploadProductImage", func() {
+		It("should store image with thumbnail and return signed urls", func() {
+			data := pngImage(200, 100)
+			var thumbnail []byte
+
+			mockRepo.EXPECT().IsProductSoldByUser(ctx, 5, uint(7)).Return(true, nil)
+			mockStorage.EXPECT().Put(ctx, gomock.Any(), "image/png", gomock.Any(), int64(len(data))).Return(nil)
+			mockStorage.EXPECT().Put(ctx, gomock.Any(), "image/jpeg", gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _, _ string, body *bytes.Reader, _ int64) error {
+					thumbnail = make([]byte, body.Len())
+					_, err := body.Read(thumbnail)
+					return err
+				})
+			mockRepo.EXPECT().InsertImage(ctx, gomock.Any()).Return(nil)
+			mockStorage.EXPECT().SignedURL(ctx, gomock.Any(), time.Hour).Return("signed", nil).Times(2)
+
+			img, err := service.UploadProductImage(ctx, 5, 7, false, data)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(img.Key).To(MatchRegexp(`^products/5/[0-9a-f-]+\.png$`))
+			Expect(img.ThumbnailKey).To(MatchRegexp(`^products/5/[0-9a-f-]+_thumb\.jpg$`))
+			Expect(img.ContentType).To(Equal("image/png"))
+			Expect(img.URL).To(Equal("signed"))
+			Expect(img.ThumbnailURL).To(Equal("signed"))
+
+			decoded, err := jpeg.DecodeConfig(bytes.NewReader(thumbnail))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decoded.Width).To(Equal(64))
+			Expect(decoded.Height).To(Equal(32))
+		})
+
+		It("should reject files that are not images", func() {
+			_, err := service.UploadProductImage(ctx, 5, 7, false, []byte("just some text"))
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should reject files larger than the limit", func() {
+			_, err := service.UploadProductImage(ctx, 5, 7, false, make([]byte, 2*1024*1024))
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should forbid upload for users who do not sell the product", func() {
+			mockRepo.EXPECT().IsProductSoldByUser(ctx, 5, uint(7)).Return(false, nil)
+
+			_, err := service.UploadProductImage(ctx, 5, 7, false, pngImage(10, 10))
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.Forbidden))
+		})
+
+		It("should remove stored files when insert fails", func() {
+			mockStorage.EXPECT().Put(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			mockRepo.EXPECT().InsertImage(ctx, gomock.Any()).Return(apperror.ErrProductNotFound)
+			mockStorage.EXPECT().Delete(ctx, gomock.Any()).Return(nil).Times(2)
+
+			_, err := service.UploadProductImage(ctx, 5, 7, true, pngImage(10, 10))
+
+			Expect(err).To(MatchError(apperror.ErrProductNotFound))
+		})
+	})
+
+	Describe("ProductImages", func() {
+		It("should group signed images by product", func() {
+			mockRepo.EXPECT().SelectImagesByProductIDs(ctx, []int{1, 2}).Return([]entity.ProductImage{
+				{Key: "products/1/a.png", ThumbnailKey: "products/1/a_thumb.jpg", ProductID: 1},
+				{Key: "products/1/b.png", ProductID: 1},
+				{Key: "products/2/c.png", ProductID: 2},
+			}, nil)
+			mockStorage.EXPECT().SignedURL(ctx, gomock.Any(), time.Hour).
+				DoAndReturn(func(_ context.Context, key string, _ time.Duration) (string, error) {
+					return "https://cdn/" + key, nil
+				}).Times(4)
+
+			images, err := service.ProductImages(ctx, []int{1, 2})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(images[1]).To(HaveLen(2))
+			Expect(images[1][0].ThumbnailURL).To(Equal("https://cdn/products/1/a_thumb.jpg"))
+			Expect(images[1][1].ThumbnailURL).To(BeEmpty())
+			Expect(images[2]).To(HaveLen(1))
+		})
+	})
+
+	Describe("DeleteProductImage", func() {
+		It("should delete files and record", func() {
+			img := entity.ProductImage{Key: "products/1/a.png", ThumbnailKey: "products/1/a_thumb.jpg", ProductID: 1}
+			mockRepo.EXPECT().GetImage(ctx, 1, "products/1/a.png").Return(img, nil)
+			mockStorage.EXPECT().Delete(ctx, img.Key).Return(nil)
+			mockStorage.EXPECT().Delete(ctx, img.ThumbnailKey).Return(nil)
+			mockRepo.EXPECT().DeleteImage(ctx, 1, img.Key).Return(nil)
+
+			Expect(service.DeleteProductImage(ctx, 1, "a.png", 7, true)).To(Succeed())
+		})
+
+		It("should reject names with path separators", func() {
+			err := service.DeleteProductImage(ctx, 1, "../2/a.png", 7, true)
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+	})
+
+	Describe("fitSize", func() {
+		It("should keep small images as is", func() {
+			w, h := fitSize(30, 20, 64)
+			Expect(w).To(Equal(30))
+			Expect(h).To(Equal(20))
+		})
+
+		It("should fit tall images by height", func() {
+			w, h := fitSize(100, 400, 64)
+			Expect(w).To(Equal(16))
+			Expect(h).To(Equal(64))
+		})
+	})
+})
@@ -0,0 +1,129 @@
+package loginguard
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=loginguard_mock_test.go -package=loginguard Repository
+
+type Repository interface {
+	Get(ctx context.Context, keys []string) ([]entity.LoginAttempts, error)
+	RegisterFailure(ctx context.Context, key string, window time.Duration) (entity.LoginAttempts, error)
+	Block(ctx context.Context, key string, until time.Time, resetFailures bool) error
+	Reset(ctx context.Context, key string) error
+}
+
+// Service защищает вход от перебора паролей. Неудачные попытки считаются отдельно
+// по учетной записи и по адресу клиента. После бесплатных попыток каждая следующая
+// неудача удваивает задержку, а при достижении порога учетная запись блокируется.
+type Service struct {
+	repository Repository
+	cfg        *config.LoginConfig
+}
+
+func NewService(repository Repository, cfg *config.LoginConfig) *Service {
+	return &Service{
+		repository: repository,
+		cfg:        cfg,
+	}
+}
+
+// Check отклоняет попытку входа, если учетная запись или адрес клиента еще заблокированы
+func (s *Service) Check(ctx context.Context, email, ip string) error {
+	attempts, err := s.repository.Get(ctx, keys(email, ip))
+	if err != nil {
+		return err
+	}
+
+	var wait time.Duration
+	now := time.Now()
+	for _, a := range attempts {
+		wait = max(wait, a.BlockedUntil.Sub(now))
+	}
+
+	if wait > 0 {
+		return apperror.NewRetry(apperror.TooManyRequests, "too many failed login attempts, retry later", wait)
+	}
+
+	return nil
+}
+
+// Fail учитывает неудачную попытку входа и выставляет задержку.
+// Возвращает true, если эта попытка заблокировала учетную запись.
+func (s *Service) Fail(ctx context.Context, email, ip string) (bool, error) {
+	now := time.Now()
+
+	account, err := s.repository.RegisterFailure(ctx, accountKey(email), s.cfg.Window)
+	if err != nil {
+		return false, err
+	}
+
+	locked := s.cfg.LockoutThreshold > 0 && account.Failures >= s.cfg.LockoutThreshold
+	if locked {
+		err = s.repository.Block(ctx, account.Key, now.Add(s.cfg.LockoutDuration), true)
+	} else if d := s.delay(account.Failures, s.cfg.FreeAttempts); d > 0 {
+		err = s.repository.Block(ctx, account.Key, now.Add(d), false)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if ip == "" {
+		return locked, nil
+	}
+
+	client, err := s.repository.RegisterFailure(ctx, ipKey(ip), s.cfg.Window)
+	if err != nil {
+		return false, err
+	}
+
+	if d := s.delay(client.Failures, s.cfg.IPFreeAttempts); d > 0 {
+		if err = s.repository.Block(ctx, client.Key, now.Add(d), false); err != nil {
+			return false, err
+		}
+	}
+
+	return locked, nil
+}
+
+// Reset снимает ограничения с учетной записи после успешного входа или разблокировки по письму.
+// Счетчик адреса клиента не сбрасывается, иначе перебор чужих паролей можно чередовать с входом в свою учетную запись.
+func (s *Service) Reset(ctx context.Context, email string) error {
+	return s.repository.Reset(ctx, accountKey(email))
+}
+
+// delay возвращает задержку после failures неудач подряд: base, 2*base, 4*base... но не больше MaxDelay
+func (s *Service) delay(failures, free int) time.Duration {
+	n := failures - free - 1
+	if n < 0 {
+		return 0
+	}
+
+	d := s.cfg.BaseDelay
+	for ; n > 0 && d < s.cfg.MaxDelay; n-- {
+		d *= 2
+	}
+
+	return min(d, s.cfg.MaxDelay)
+}
+
+func keys(email, ip string) []string {
+	if ip == "" {
+		return []string{accountKey(email)}
+	}
+	return []string{accountKey(email), ipKey(ip)}
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
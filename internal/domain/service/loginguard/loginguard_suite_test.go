@@ -0,0 +1,13 @@
+package loginguard
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLoginGuard(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Login Guard Service Suite")
+}
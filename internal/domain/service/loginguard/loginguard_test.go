@@ -0,0 +1,143 @@
+package loginguard
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("LoginGuard", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockRepo *MockRepository
+		service  *Service
+		ctx      context.Context
+		cfg      config.LoginConfig
+	)
+
+	const (
+		accKey = "account:user@example.com"
+		ipKey  = "ip:192.0.2.1"
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		cfg = config.LoginConfig{
+			FreeAttempts:     3,
+			IPFreeAttempts:   20,
+			BaseDelay:        time.Second,
+			MaxDelay:         time.Minute,
+			LockoutThreshold: 10,
+			LockoutDuration:  time.Hour,
+			Window:           time.Hour,
+		}
+		service = NewService(mockRepo, &cfg)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("Check", func() {
+		It("should pass when nothing is blocked", func() {
+			mockRepo.EXPECT().Get(ctx, []string{accKey, ipKey}).
+				Return([]entity.LoginAttempts{{Key: accKey, Failures: 2}}, nil)
+
+			Expect(service.Check(ctx, " User@Example.com", "192.0.2.1")).To(Succeed())
+		})
+
+		It("should reject with retry-after while blocked", func() {
+			mockRepo.EXPECT().Get(ctx, []string{accKey, ipKey}).
+				Return([]entity.LoginAttempts{
+					{Key: accKey, BlockedUntil: time.Now().Add(10 * time.Second)},
+					{Key: ipKey, BlockedUntil: time.Now().Add(time.Minute)},
+				}, nil)
+
+			err := service.Check(ctx, "user@example.com", "192.0.2.1")
+
+			var retryErr *apperror.RetryError
+			Expect(errors.As(err, &retryErr)).To(BeTrue())
+			Expect(retryErr.Code()).To(Equal(apperror.TooManyRequests))
+			Expect(retryErr.RetryAfter).To(BeNumerically("~", time.Minute, time.Second))
+		})
+	})
+
+	Describe("Fail", func() {
+		It("should not delay free attempts", func() {
+			mockRepo.EXPECT().RegisterFailure(ctx, accKey, time.Hour).
+				Return(entity.LoginAttempts{Key: accKey, Failures: 3}, nil)
+			mockRepo.EXPECT().RegisterFailure(ctx, ipKey, time.Hour).
+				Return(entity.LoginAttempts{Key: ipKey, Failures: 3}, nil)
+
+			locked, err := service.Fail(ctx, "user@example.com", "192.0.2.1")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(locked).To(BeFalse())
+		})
+
+		It("should double the delay after each failure", func() {
+			mockRepo.EXPECT().RegisterFailure(ctx, accKey, time.Hour).
+				Return(entity.LoginAttempts{Key: accKey, Failures: 6}, nil)
+			mockRepo.EXPECT().Block(ctx, accKey, gomock.Any(), false).
+				DoAndReturn(func(_ context.Context, _ string, until time.Time, _ bool) error {
+					Expect(time.Until(until)).To(BeNumerically("~", 4*time.Second, time.Second))
+					return nil
+				})
+			mockRepo.EXPECT().RegisterFailure(ctx, ipKey, time.Hour).
+				Return(entity.LoginAttempts{Key: ipKey, Failures: 6}, nil)
+
+			locked, err := service.Fail(ctx, "user@example.com", "192.0.2.1")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(locked).To(BeFalse())
+		})
+
+		It("should cap the delay", func() {
+			mockRepo.EXPECT().RegisterFailure(ctx, accKey, time.Hour).
+				Return(entity.LoginAttempts{Key: accKey, Failures: 1}, nil)
+			mockRepo.EXPECT().RegisterFailure(ctx, ipKey, time.Hour).
+				Return(entity.LoginAttempts{Key: ipKey, Failures: 500}, nil)
+			mockRepo.EXPECT().Block(ctx, ipKey, gomock.Any(), false).
+				DoAndReturn(func(_ context.Context, _ string, until time.Time, _ bool) error {
+					Expect(time.Until(until)).To(BeNumerically("~", time.Minute, time.Second))
+					return nil
+				})
+
+			_, err := service.Fail(ctx, "user@example.com", "192.0.2.1")
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should lock the account at the threshold", func() {
+			mockRepo.EXPECT().RegisterFailure(ctx, accKey, time.Hour).
+				Return(entity.LoginAttempts{Key: accKey, Failures: 10}, nil)
+			mockRepo.EXPECT().Block(ctx, accKey, gomock.Any(), true).
+				DoAndReturn(func(_ context.Context, _ string, until time.Time, _ bool) error {
+					Expect(time.Until(until)).To(BeNumerically("~", time.Hour, time.Second))
+					return nil
+				})
+
+			locked, err := service.Fail(ctx, "user@example.com", "")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(locked).To(BeTrue())
+		})
+	})
+
+	Describe("Reset", func() {
+		It("should clear the account counter only", func() {
+			mockRepo.EXPECT().Reset(ctx, accKey).Return(nil)
+
+			Expect(service.Reset(ctx, "user@example.com")).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,59 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Digester периодически отправляет письма с уведомлениями, отложенными
+// до дайджеста или до конца тихих часов
+type Digester struct {
+	service *Service
+	log     *zap.Logger
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func NewDigester(service *Service, interval time.Duration, log *zap.Logger) *Digester {
+	d := &Digester{
+		service: service,
+		log:     log,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go d.run(interval)
+
+	return d
+}
+
+// Close останавливает отправку и дожидается завершения текущего прохода
+func (d *Digester) Close() {
+	close(d.stop)
+	<-d.done
+}
+
+func (d *Digester) run(interval time.Duration) {
+	defer close(d.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sent, err := d.service.SendDigests(context.Background())
+			if err != nil {
+				d.log.Error("Failed to send notification digests", zap.Error(err))
+				continue
+			}
+			if sent > 0 {
+				d.log.Info("Notification digests sent", zap.Int("count", sent))
+			}
+		case <-d.stop:
+			return
+		}
+	}
+}
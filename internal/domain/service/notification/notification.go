@@ -1,19 +1,429 @@
 package notification
 
-import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	"go.uber.org/zap"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=notification_mock_test.go -package=notification Repository ShopMembers Users Publisher
 
 type Repository interface {
-	SelectUserNotifications(id string, offset, limit int) ([]entity.Notification, int, error)
+	SelectUserNotifications(
+		ctx context.Context,
+		userID uint,
+		unreadOnly bool,
+		limit, offset int,
+	) ([]entity.Notification, int, error)
+	CountUnreadNotifications(ctx context.Context, userID uint) (int, error)
+	InsertNotifications(ctx context.Context, notifications []entity.Notification) ([]entity.Notification, error)
+	MarkNotificationRead(ctx context.Context, userID, notificationID uint) error
+	MarkAllNotificationsRead(ctx context.Context, userID uint) (int64, error)
+	SelectNotificationPreferences(ctx context.Context, userIDs []uint) ([]entity.NotificationPreferences, error)
+	UpsertNotificationPreferences(ctx context.Context, preferences entity.NotificationPreferences) error
+	SelectPendingEmailUserIDs(ctx context.Context) ([]uint, error)
+	ClaimNotificationDigest(ctx context.Context, userID uint, sentAt time.Time) ([]entity.Notification, error)
+}
+
+// ShopMembers находит сотрудников магазина, которые получают уведомления о заявках
+type ShopMembers interface {
+	SelectMembers(ctx context.Context, shopID uint) ([]entity.ShopMember, error)
+}
+
+// Users находит адрес, на который отправляются письма с уведомлениями
+type Users interface {
+	GetUserByID(ctx context.Context, id uint) (entity.User, error)
+}
+
+// Publisher отправляет сообщения в открытые потоки пользователей
+type Publisher interface {
+	Publish(ctx context.Context, message entity.RealtimeMessage) error
+}
+
+// realtimeNotification это уведомление в потоке, поля совпадают с ответом GET /notifications
+type realtimeNotification struct {
+	ID      uint      `json:"id"`
+	Type    string    `json:"type"`
+	Message string    `json:"message"`
+	Link    string    `json:"link,omitempty"`
+	Read    bool      `json:"read"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// realtimeOffer это состояние заявки в потоке
+type realtimeOffer struct {
+	ID        uint      `json:"id"`
+	ShopID    uint      `json:"shop_id"`
+	ProductID uint      `json:"product_id"`
+	Price     float64   `json:"price"`
+	Currency  string    `json:"currency"`
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Service struct {
 	notificationRepository Repository
+	shopMembers            ShopMembers
+	users                  Users
+	mailer                 email.MailerService
+	publisher              Publisher
+	log                    *zap.Logger
+}
+
+func NewService(
+	notificationRepository Repository,
+	shopMembers ShopMembers,
+	users Users,
+	mailer email.MailerService,
+	publisher Publisher,
+	log *zap.Logger,
+) *Service {
+	return &Service{
+		notificationRepository: notificationRepository,
+		shopMembers:            shopMembers,
+		users:                  users,
+		mailer:                 mailer,
+		publisher:              publisher,
+		log:                    log,
+	}
+}
+
+func (ns *Service) GetNotifications(
+	ctx context.Context,
+	userID uint,
+	unreadOnly bool,
+	page, limit int,
+) ([]entity.Notification, int, error) {
+	offset := (page - 1) * limit
+	return ns.notificationRepository.SelectUserNotifications(ctx, userID, unreadOnly, limit, offset)
+}
+
+func (ns *Service) CountUnread(ctx context.Context, userID uint) (int, error) {
+	return ns.notificationRepository.CountUnreadNotifications(ctx, userID)
+}
+
+func (ns *Service) MarkRead(ctx context.Context, userID, notificationID uint) error {
+	return ns.notificationRepository.MarkNotificationRead(ctx, userID, notificationID)
 }
 
-func NewService(notificationRepository Repository) *Service {
-	return &Service{notificationRepository}
+func (ns *Service) MarkAllRead(ctx context.Context, userID uint) (int64, error) {
+	return ns.notificationRepository.MarkAllNotificationsRead(ctx, userID)
 }
 
-func (ns *Service) GetNotification(id string, offset int, limit int) ([]entity.Notification, int, error) {
-	return ns.notificationRepository.SelectUserNotifications(id, offset, limit)
+// GetPreferences возвращает настройки уведомлений пользователя, в том числе не измененные им
+func (ns *Service) GetPreferences(ctx context.Context, userID uint) (entity.NotificationPreferences, error) {
+	preferences, err := ns.preferences(ctx, []uint{userID})
+	if err != nil {
+		return entity.NotificationPreferences{}, err
+	}
+
+	return preferences[userID], nil
+}
+
+// UpdatePreferences заменяет настройки уведомлений пользователя
+func (ns *Service) UpdatePreferences(ctx context.Context, preferences entity.NotificationPreferences) error {
+	for t, channel := range preferences.Channels {
+		if !slices.Contains(entity.NotificationTypes, t) {
+			return apperror.New(apperror.BadRequest, fmt.Sprintf("unknown notification type %q", t), nil)
+		}
+		switch channel {
+		case entity.NotificationChannelInApp, entity.NotificationChannelEmail, entity.NotificationChannelNone:
+		default:
+			return apperror.New(apperror.BadRequest,
+				fmt.Sprintf("channel must be in_app, email or none, got %q", channel), nil)
+		}
+	}
+
+	switch preferences.Digest {
+	case entity.DigestOff, entity.DigestHourly, entity.DigestDaily:
+	default:
+		return apperror.New(apperror.BadRequest, "digest must be off, hourly or daily", nil)
+	}
+
+	if _, err := time.LoadLocation(preferences.Timezone); err != nil {
+		return apperror.New(apperror.BadRequest, "unknown timezone", err)
+	}
+
+	if q := preferences.QuietHours; q != nil {
+		const day = 24 * 60
+		if q.Start < 0 || q.Start >= day || q.End < 0 || q.End >= day || q.Start == q.End {
+			return apperror.New(apperror.BadRequest, "quiet hours must be two different times of day", nil)
+		}
+	}
+
+	return ns.notificationRepository.UpsertNotificationPreferences(ctx, preferences)
+}
+
+// Notify сохраняет уведомление пользователю и отправляет его в открытые потоки
+func (ns *Service) Notify(ctx context.Context, notification entity.Notification) error {
+	_, err := ns.save(ctx, []entity.Notification{notification})
+	return err
+}
+
+// HandleOfferEvent уведомляет магазин о новой и отмененной заявке,
+// а покупателя о решении магазина и об истечении заявки. Получатели уведомления
+// также получают в потоке новое состояние заявки. Outbox доставляет события хотя бы раз,
+// поэтому повторно доставленное событие ничего не отправляет второй раз.
+func (ns *Service) HandleOfferEvent(ctx context.Context, event entity.Event) error {
+	var payload entity.OfferEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+	offer := payload.Offer
+
+	notification := entity.Notification{
+		Type:     entity.NotificationOfferStatusChanged,
+		Link:     fmt.Sprintf("/offers/%d", offer.ID),
+		EventUID: event.UID,
+	}
+	var (
+		recipients []uint
+		err        error
+	)
+
+	switch event.Type {
+	case entity.EventOfferCreated:
+		notification.Type = entity.NotificationOfferReceived
+		notification.Message = fmt.Sprintf("New offer #%d: %.2f %s", offer.ID, offer.Price, offer.Currency)
+		recipients, err = ns.shopRecipients(ctx, offer.ShopID)
+	case entity.EventOfferCancelled:
+		notification.Message = fmt.Sprintf("Offer #%d has been cancelled by the buyer", offer.ID)
+		recipients, err = ns.shopRecipients(ctx, offer.ShopID)
+	case entity.EventOfferAccepted:
+		notification.Message = fmt.Sprintf("Your offer #%d has been accepted", offer.ID)
+		recipients = []uint{offer.UserID}
+	case entity.EventOfferDeclined:
+		notification.Message = fmt.Sprintf("Your offer #%d has been declined", offer.ID)
+		recipients = []uint{offer.UserID}
+	case entity.EventOfferExpired:
+		notification.Message = fmt.Sprintf("Your offer #%d has expired", offer.ID)
+		recipients = []uint{offer.UserID}
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	notifications := make([]entity.Notification, len(recipients))
+	for i, userID := range recipients {
+		notifications[i] = notification
+		notifications[i].UserID = userID
+	}
+	repeated, err := ns.save(ctx, notifications)
+	if err != nil {
+		return err
+	}
+	if repeated {
+		return nil
+	}
+
+	data, err := json.Marshal(realtimeOffer{
+		ID:        offer.ID,
+		ShopID:    offer.ShopID,
+		ProductID: offer.ProductID,
+		Price:     offer.Price,
+		Currency:  offer.Currency,
+		Status:    offer.Status,
+		UpdatedAt: offer.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	for _, userID := range recipients {
+		ns.publish(ctx, entity.RealtimeMessage{UserID: userID, Type: string(event.Type), Data: data})
+	}
+
+	return nil
+}
+
+// shopRecipients возвращает сотрудников магазина, которые могут отвечать на заявки
+func (ns *Service) shopRecipients(ctx context.Context, shopID uint) ([]uint, error) {
+	members, err := ns.shopMembers.SelectMembers(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := entity.ShopRolesWith(entity.PermissionOffersRespond)
+	recipients := make([]uint, 0, len(members))
+	for _, member := range members {
+		if slices.Contains(roles, member.Role) {
+			recipients = append(recipients, member.UserID)
+		}
+	}
+
+	return recipients, nil
+}
+
+// save сохраняет уведомления по настройкам получателей, отправляет их в потоки
+// и отправляет письма тем, кто не откладывает их до дайджеста. В потоки и письма попадают
+// только действительно сохраненные уведомления. Возвращает true, если все они уже были
+// сохранены при прошлой доставке того же события.
+func (ns *Service) save(ctx context.Context, notifications []entity.Notification) (bool, error) {
+	userIDs := make([]uint, 0, len(notifications))
+	for _, n := range notifications {
+		userIDs = append(userIDs, n.UserID)
+	}
+	preferences, err := ns.preferences(ctx, userIDs)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	stored := make([]entity.Notification, 0, len(notifications))
+	for _, n := range notifications {
+		p := preferences[n.UserID]
+		switch p.Channel(n.Type) {
+		case entity.NotificationChannelNone:
+			continue
+		case entity.NotificationChannelEmail:
+			n.EmailPending = !p.SendNow(now)
+		}
+		stored = append(stored, n)
+	}
+	if len(stored) == 0 {
+		return false, nil
+	}
+
+	inserted, err := ns.notificationRepository.InsertNotifications(ctx, stored)
+	if err != nil {
+		return false, err
+	}
+	if len(inserted) == 0 {
+		return true, nil
+	}
+
+	for _, n := range inserted {
+		data, err := json.Marshal(realtimeNotification{
+			ID:      n.ID,
+			Type:    string(n.Type),
+			Message: n.Message,
+			Link:    n.Link,
+			Read:    n.ReadAt != nil,
+			SentAt:  n.SentAt,
+		})
+		if err != nil {
+			return false, err
+		}
+		ns.publish(ctx, entity.RealtimeMessage{UserID: n.UserID, Type: entity.RealtimeNotification, Data: data})
+
+		if preferences[n.UserID].Channel(n.Type) == entity.NotificationChannelEmail && !n.EmailPending {
+			ns.email(ctx, n)
+		}
+	}
+
+	return false, nil
+}
+
+// preferences возвращает настройки пользователей, для не менявших их подставляются настройки по умолчанию
+func (ns *Service) preferences(
+	ctx context.Context,
+	userIDs []uint,
+) (map[uint]entity.NotificationPreferences, error) {
+	stored, err := ns.notificationRepository.SelectNotificationPreferences(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make(map[uint]entity.NotificationPreferences, len(userIDs))
+	for _, userID := range userIDs {
+		preferences[userID] = entity.DefaultNotificationPreferences(userID)
+	}
+	for _, p := range stored {
+		preferences[p.UserID] = p
+	}
+
+	return preferences, nil
+}
+
+// email отправляет уведомление письмом. Уведомление уже сохранено, поэтому ошибка только логируется.
+func (ns *Service) email(ctx context.Context, notification entity.Notification) {
+	user, err := ns.users.GetUserByID(ctx, notification.UserID)
+	if err != nil {
+		ns.log.Warn("Failed to find notification recipient",
+			zap.Uint("user_id", notification.UserID), zap.Error(err))
+		return
+	}
+
+	ns.mailer.Notification(notification.Message, user.Email)
+}
+
+// publish отправляет сообщение в потоки. Уведомление уже сохранено, поэтому ошибка только логируется:
+// клиент увидит его при следующем запросе списка.
+func (ns *Service) publish(ctx context.Context, message entity.RealtimeMessage) {
+	if err := ns.publisher.Publish(ctx, message); err != nil {
+		ns.log.Warn("Failed to publish realtime message",
+			zap.Uint("user_id", message.UserID), zap.String("type", message.Type), zap.Error(err))
+	}
+}
+
+// SendDigests отправляет каждому пользователю, которому пора, одно письмо со всеми
+// отложенными уведомлениями и возвращает количество отправленных писем.
+// Уведомления, которые пользователь уже прочитал, в письмо не попадают.
+func (ns *Service) SendDigests(ctx context.Context) (int, error) {
+	userIDs, err := ns.notificationRepository.SelectPendingEmailUserIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	preferences, err := ns.preferences(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	sent := 0
+	for _, userID := range userIDs {
+		if !preferences[userID].DigestDue(now) {
+			continue
+		}
+
+		ok, err := ns.sendDigest(ctx, userID, now)
+		if err != nil {
+			// Остальные пользователи не должны ждать, пока исправится ошибка одного
+			ns.log.Error("Failed to send notification digest", zap.Uint("user_id", userID), zap.Error(err))
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// sendDigest отправляет дайджест одному пользователю и сообщает, было ли отправлено письмо.
+// Отложенные уведомления сначала забираются из базы, поэтому при нескольких экземплярах
+// приложения письмо отправляет только тот, кому они достались.
+func (ns *Service) sendDigest(ctx context.Context, userID uint, now time.Time) (bool, error) {
+	// Получатель ищется до того, как уведомления забраны, чтобы при ошибке они дождались следующего прохода
+	user, err := ns.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	notifications, err := ns.notificationRepository.ClaimNotificationDigest(ctx, userID, now)
+	if err != nil {
+		return false, err
+	}
+
+	messages := make([]string, 0, len(notifications))
+	for _, n := range notifications {
+		if n.ReadAt == nil {
+			messages = append(messages, n.Message)
+		}
+	}
+	if len(messages) == 0 {
+		return false, nil
+	}
+
+	ns.mailer.NotificationDigest(messages, user.Email)
+
+	return true, nil
 }
@@ -0,0 +1,293 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: notification.go
+//
+// Generated by this command:
+//
+//	mockgen -source=notification.go -destination=notification_mock_test.go -package=notification Repository ShopMembers Users Publisher
+//
+
+// Package notification is a generated GoMock package.
+package notification
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimNotificationDigest mocks base method.
+func (m *MockRepository) ClaimNotificationDigest(ctx context.Context, userID uint, sentAt time.Time) ([]entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNotificationDigest", ctx, userID, sentAt)
+	ret0, _ := ret[0].([]entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNotificationDigest indicates an expected call of ClaimNotificationDigest.
+func (mr *MockRepositoryMockRecorder) ClaimNotificationDigest(ctx, userID, sentAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNotificationDigest", reflect.TypeOf((*MockRepository)(nil).ClaimNotificationDigest), ctx, userID, sentAt)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockRepository) CountUnreadNotifications(ctx context.Context, userID uint) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockRepositoryMockRecorder) CountUnreadNotifications(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockRepository)(nil).CountUnreadNotifications), ctx, userID)
+}
+
+// InsertNotifications mocks base method.
+func (m *MockRepository) InsertNotifications(ctx context.Context, notifications []entity.Notification) ([]entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertNotifications", ctx, notifications)
+	ret0, _ := ret[0].([]entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertNotifications indicates an expected call of InsertNotifications.
+func (mr *MockRepositoryMockRecorder) InsertNotifications(ctx, notifications any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNotifications", reflect.TypeOf((*MockRepository)(nil).InsertNotifications), ctx, notifications)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockRepository) MarkAllNotificationsRead(ctx context.Context, userID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockRepositoryMockRecorder) MarkAllNotificationsRead(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockRepository)(nil).MarkAllNotificationsRead), ctx, userID)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockRepository) MarkNotificationRead(ctx context.Context, userID, notificationID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, userID, notificationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockRepositoryMockRecorder) MarkNotificationRead(ctx, userID, notificationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockRepository)(nil).MarkNotificationRead), ctx, userID, notificationID)
+}
+
+// SelectNotificationPreferences mocks base method.
+func (m *MockRepository) SelectNotificationPreferences(ctx context.Context, userIDs []uint) ([]entity.NotificationPreferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectNotificationPreferences", ctx, userIDs)
+	ret0, _ := ret[0].([]entity.NotificationPreferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectNotificationPreferences indicates an expected call of SelectNotificationPreferences.
+func (mr *MockRepositoryMockRecorder) SelectNotificationPreferences(ctx, userIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectNotificationPreferences", reflect.TypeOf((*MockRepository)(nil).SelectNotificationPreferences), ctx, userIDs)
+}
+
+// SelectPendingEmailUserIDs mocks base method.
+func (m *MockRepository) SelectPendingEmailUserIDs(ctx context.Context) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectPendingEmailUserIDs", ctx)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectPendingEmailUserIDs indicates an expected call of SelectPendingEmailUserIDs.
+func (mr *MockRepositoryMockRecorder) SelectPendingEmailUserIDs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectPendingEmailUserIDs", reflect.TypeOf((*MockRepository)(nil).SelectPendingEmailUserIDs), ctx)
+}
+
+// SelectUserNotifications mocks base method.
+func (m *MockRepository) SelectUserNotifications(ctx context.Context, userID uint, unreadOnly bool, limit, offset int) ([]entity.Notification, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectUserNotifications", ctx, userID, unreadOnly, limit, offset)
+	ret0, _ := ret[0].([]entity.Notification)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectUserNotifications indicates an expected call of SelectUserNotifications.
+func (mr *MockRepositoryMockRecorder) SelectUserNotifications(ctx, userID, unreadOnly, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUserNotifications", reflect.TypeOf((*MockRepository)(nil).SelectUserNotifications), ctx, userID, unreadOnly, limit, offset)
+}
+
+// UpsertNotificationPreferences mocks base method.
+func (m *MockRepository) UpsertNotificationPreferences(ctx context.Context, preferences entity.NotificationPreferences) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreferences", ctx, preferences)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertNotificationPreferences indicates an expected call of UpsertNotificationPreferences.
+func (mr *MockRepositoryMockRecorder) UpsertNotificationPreferences(ctx, preferences any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreferences", reflect.TypeOf((*MockRepository)(nil).UpsertNotificationPreferences), ctx, preferences)
+}
+
+// MockShopMembers is a mock of ShopMembers interface.
+type MockShopMembers struct {
+	ctrl     *gomock.Controller
+	recorder *MockShopMembersMockRecorder
+	isgomock struct{}
+}
+
+// MockShopMembersMockRecorder is the mock recorder for MockShopMembers.
+type MockShopMembersMockRecorder struct {
+	mock *MockShopMembers
+}
+
+// NewMockShopMembers creates a new mock instance.
+func NewMockShopMembers(ctrl *gomock.Controller) *MockShopMembers {
+	mock := &MockShopMembers{ctrl: ctrl}
+	mock.recorder = &MockShopMembersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShopMembers) EXPECT() *MockShopMembersMockRecorder {
+	return m.recorder
+}
+
+// SelectMembers mocks base method.
+func (m *MockShopMembers) SelectMembers(ctx context.Context, shopID uint) ([]entity.ShopMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectMembers", ctx, shopID)
+	ret0, _ := ret[0].([]entity.ShopMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectMembers indicates an expected call of SelectMembers.
+func (mr *MockShopMembersMockRecorder) SelectMembers(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectMembers", reflect.TypeOf((*MockShopMembers)(nil).SelectMembers), ctx, shopID)
+}
+
+// MockUsers is a mock of Users interface.
+type MockUsers struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsersMockRecorder
+	isgomock struct{}
+}
+
+// MockUsersMockRecorder is the mock recorder for MockUsers.
+type MockUsersMockRecorder struct {
+	mock *MockUsers
+}
+
+// NewMockUsers creates a new mock instance.
+func NewMockUsers(ctrl *gomock.Controller) *MockUsers {
+	mock := &MockUsers{ctrl: ctrl}
+	mock.recorder = &MockUsersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsers) EXPECT() *MockUsersMockRecorder {
+	return m.recorder
+}
+
+// GetUserByID mocks base method.
+func (m *MockUsers) GetUserByID(ctx context.Context, id uint) (entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, id)
+	ret0, _ := ret[0].(entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockUsersMockRecorder) GetUserByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUsers)(nil).GetUserByID), ctx, id)
+}
+
+// MockPublisher is a mock of Publisher interface.
+type MockPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockPublisherMockRecorder is the mock recorder for MockPublisher.
+type MockPublisherMockRecorder struct {
+	mock *MockPublisher
+}
+
+// NewMockPublisher creates a new mock instance.
+func NewMockPublisher(ctrl *gomock.Controller) *MockPublisher {
+	mock := &MockPublisher{ctrl: ctrl}
+	mock.recorder = &MockPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockPublisher) Publish(ctx context.Context, message entity.RealtimeMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockPublisherMockRecorder) Publish(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockPublisher)(nil).Publish), ctx, message)
+}
@@ -0,0 +1,321 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("NotificationService", func() {
+	var (
+		ctrl        *gomock.Controller
+		mockRepo    *MockRepository
+		mockMembers *MockShopMembers
+		mockUsers   *MockUsers
+		mockMailer  *mock_email.MockMailerService
+		mockPub     *MockPublisher
+		service     *Service
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockMembers = NewMockShopMembers(ctrl)
+		mockUsers = NewMockUsers(ctrl)
+		mockMailer = mock_email.NewMockMailerService(ctrl)
+		mockPub = NewMockPublisher(ctrl)
+		service = NewService(mockRepo, mockMembers, mockUsers, mockMailer, mockPub, zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	// inserted имитирует RETURNING: присваивает уведомлениям ID и время отправки
+	inserted := func(_ context.Context, notifications []entity.Notification) ([]entity.Notification, error) {
+		out := make([]entity.Notification, len(notifications))
+		for i, n := range notifications {
+			n.ID = uint(100 + i)
+			n.SentAt = time.Date(2025, 7, 3, 9, 0, 0, 0, time.UTC)
+			out[i] = n
+		}
+		return out, nil
+	}
+
+	It("should page notifications", func() {
+		mockRepo.EXPECT().SelectUserNotifications(ctx, uint(3), true, 20, 40).Return(nil, 0, nil)
+
+		_, _, err := service.GetNotifications(ctx, 3, true, 3, 20)
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("HandleOfferEvent", func() {
+		offer := entity.Offer{ID: 7, ShopID: 1, UserID: 20, Price: 99.5, Currency: "USD"}
+
+		offerEvent := func(eventType entity.EventType) entity.Event {
+			payload, err := json.Marshal(entity.OfferEvent{Offer: offer})
+			Expect(err).NotTo(HaveOccurred())
+			return entity.Event{UID: "6f1c2b9e-0d1a-4c47-9a55-3b1f0d1e2a10", Type: eventType, Payload: payload}
+		}
+
+		It("should notify shop members who can respond about a new offer", func() {
+			mockMembers.EXPECT().SelectMembers(ctx, uint(1)).Return([]entity.ShopMember{
+				{UserID: 1, Role: entity.ShopRoleOwner},
+				{UserID: 2, Role: entity.ShopRoleAgent},
+			}, nil)
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{1, 2}).Return(nil, nil)
+			mockRepo.EXPECT().InsertNotifications(ctx, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, notifications []entity.Notification) ([]entity.Notification, error) {
+					Expect(notifications).To(HaveLen(2))
+					Expect(notifications[0].UserID).To(Equal(uint(1)))
+					Expect(notifications[1].UserID).To(Equal(uint(2)))
+					Expect(notifications[0].Type).To(Equal(entity.NotificationOfferReceived))
+					Expect(notifications[0].Message).To(Equal("New offer #7: 99.50 USD"))
+					Expect(notifications[0].Link).To(Equal("/offers/7"))
+					Expect(notifications[1].EventUID).To(Equal("6f1c2b9e-0d1a-4c47-9a55-3b1f0d1e2a10"))
+					return inserted(ctx, notifications)
+				})
+
+			var published []entity.RealtimeMessage
+			mockPub.EXPECT().Publish(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, message entity.RealtimeMessage) error {
+					published = append(published, message)
+					return nil
+				}).Times(4)
+			mockUsers.EXPECT().GetUserByID(ctx, uint(1)).Return(entity.User{Email: "owner@shop.com"}, nil)
+			mockUsers.EXPECT().GetUserByID(ctx, uint(2)).Return(entity.User{Email: "agent@shop.com"}, nil)
+			mockMailer.EXPECT().Notification("New offer #7: 99.50 USD", "owner@shop.com")
+			mockMailer.EXPECT().Notification("New offer #7: 99.50 USD", "agent@shop.com")
+
+			Expect(service.HandleOfferEvent(ctx, offerEvent(entity.EventOfferCreated))).To(Succeed())
+
+			Expect(published[0].UserID).To(Equal(uint(1)))
+			Expect(published[0].Type).To(Equal(entity.RealtimeNotification))
+			Expect(published[2].UserID).To(Equal(uint(1)))
+			Expect(published[2].Type).To(Equal("offer.created"))
+			Expect(published[3].UserID).To(Equal(uint(2)))
+
+			var sent struct {
+				ID   uint   `json:"id"`
+				Type string `json:"type"`
+				Read bool   `json:"read"`
+			}
+			Expect(json.Unmarshal(published[0].Data, &sent)).To(Succeed())
+			Expect(sent.ID).To(Equal(uint(100)))
+			Expect(sent.Type).To(Equal("offer_received"))
+			Expect(sent.Read).To(BeFalse())
+		})
+
+		It("should notify the buyer about the shop decision", func() {
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{20}).Return(nil, nil)
+			mockRepo.EXPECT().InsertNotifications(ctx, []entity.Notification{{
+				UserID:   20,
+				Type:     entity.NotificationOfferStatusChanged,
+				Message:  "Your offer #7 has been declined",
+				Link:     "/offers/7",
+				EventUID: "6f1c2b9e-0d1a-4c47-9a55-3b1f0d1e2a10",
+			}}).DoAndReturn(inserted)
+			mockPub.EXPECT().Publish(ctx, gomock.Any()).Return(nil).Times(2)
+			mockUsers.EXPECT().GetUserByID(ctx, uint(20)).Return(entity.User{Email: "buyer@mail.com"}, nil)
+			mockMailer.EXPECT().Notification("Your offer #7 has been declined", "buyer@mail.com")
+
+			Expect(service.HandleOfferEvent(ctx, offerEvent(entity.EventOfferDeclined))).To(Succeed())
+		})
+
+		It("should tell the shop that the buyer cancelled the offer", func() {
+			mockMembers.EXPECT().SelectMembers(ctx, uint(1)).Return([]entity.ShopMember{
+				{UserID: 1, Role: entity.ShopRoleOwner},
+			}, nil)
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{1}).Return(nil, nil)
+			mockRepo.EXPECT().InsertNotifications(ctx, []entity.Notification{{
+				UserID:   1,
+				Type:     entity.NotificationOfferStatusChanged,
+				Message:  "Offer #7 has been cancelled by the buyer",
+				Link:     "/offers/7",
+				EventUID: "6f1c2b9e-0d1a-4c47-9a55-3b1f0d1e2a10",
+			}}).DoAndReturn(inserted)
+			mockPub.EXPECT().Publish(ctx, gomock.Any()).Return(nil).Times(2)
+			mockUsers.EXPECT().GetUserByID(ctx, uint(1)).Return(entity.User{Email: "owner@shop.com"}, nil)
+			mockMailer.EXPECT().Notification("Offer #7 has been cancelled by the buyer", "owner@shop.com")
+
+			Expect(service.HandleOfferEvent(ctx, offerEvent(entity.EventOfferCancelled))).To(Succeed())
+		})
+
+		It("should not send anything again when the event is delivered twice", func() {
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{20}).Return(nil, nil)
+			mockRepo.EXPECT().InsertNotifications(ctx, gomock.Any()).Return(nil, nil)
+
+			Expect(service.HandleOfferEvent(ctx, offerEvent(entity.EventOfferAccepted))).To(Succeed())
+		})
+
+		It("should not fail the event when the stream is unavailable", func() {
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{20}).Return(nil, nil)
+			mockRepo.EXPECT().InsertNotifications(ctx, gomock.Any()).DoAndReturn(inserted)
+			mockPub.EXPECT().Publish(ctx, gomock.Any()).Return(errors.New("listener is down")).Times(2)
+			mockUsers.EXPECT().GetUserByID(ctx, uint(20)).Return(entity.User{Email: "buyer@mail.com"}, nil)
+			mockMailer.EXPECT().Notification("Your offer #7 has expired", "buyer@mail.com")
+
+			Expect(service.HandleOfferEvent(ctx, offerEvent(entity.EventOfferExpired))).To(Succeed())
+		})
+	})
+
+	Describe("preferences", func() {
+		notification := entity.Notification{UserID: 20, Type: entity.NotificationPriceDropped, Message: "Cheaper"}
+
+		It("should not store notifications the user turned off", func() {
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{20}).Return([]entity.NotificationPreferences{{
+				UserID:   20,
+				Channels: map[entity.NotificationType]entity.NotificationChannel{entity.NotificationPriceDropped: "none"},
+				Timezone: "UTC",
+				Digest:   entity.DigestOff,
+			}}, nil)
+
+			Expect(service.Notify(ctx, notification)).To(Succeed())
+		})
+
+		It("should keep in-app notifications out of email", func() {
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{20}).Return([]entity.NotificationPreferences{{
+				UserID:   20,
+				Channels: map[entity.NotificationType]entity.NotificationChannel{entity.NotificationPriceDropped: "in_app"},
+				Timezone: "UTC",
+				Digest:   entity.DigestOff,
+			}}, nil)
+			mockRepo.EXPECT().InsertNotifications(ctx, []entity.Notification{notification}).DoAndReturn(inserted)
+			mockPub.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+
+			Expect(service.Notify(ctx, notification)).To(Succeed())
+		})
+
+		It("should leave the email for the digest", func() {
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{20}).Return([]entity.NotificationPreferences{{
+				UserID:   20,
+				Timezone: "UTC",
+				Digest:   entity.DigestDaily,
+			}}, nil)
+			pending := notification
+			pending.EmailPending = true
+			mockRepo.EXPECT().InsertNotifications(ctx, []entity.Notification{pending}).DoAndReturn(inserted)
+			mockPub.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+
+			Expect(service.Notify(ctx, notification)).To(Succeed())
+		})
+
+		It("should hold the email during quiet hours", func() {
+			// Тихие часы вокруг текущего времени, с переходом через полночь, если он выпадет
+			now := time.Now().UTC()
+			minute := now.Hour()*60 + now.Minute()
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{20}).Return([]entity.NotificationPreferences{{
+				UserID:     20,
+				QuietHours: &entity.QuietHours{Start: (minute + 24*60 - 60) % (24 * 60), End: (minute + 60) % (24 * 60)},
+				Timezone:   "UTC",
+				Digest:     entity.DigestOff,
+			}}, nil)
+			pending := notification
+			pending.EmailPending = true
+			mockRepo.EXPECT().InsertNotifications(ctx, []entity.Notification{pending}).DoAndReturn(inserted)
+			mockPub.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+
+			Expect(service.Notify(ctx, notification)).To(Succeed())
+		})
+
+		It("should reject an unknown channel", func() {
+			err := service.UpdatePreferences(ctx, entity.NotificationPreferences{
+				UserID:   20,
+				Channels: map[entity.NotificationType]entity.NotificationChannel{entity.NotificationPriceDropped: "sms"},
+				Timezone: "UTC",
+				Digest:   entity.DigestOff,
+			})
+
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should reject an unknown timezone", func() {
+			err := service.UpdatePreferences(ctx, entity.NotificationPreferences{
+				UserID:   20,
+				Timezone: "Mars/Olympus",
+				Digest:   entity.DigestHourly,
+			})
+
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+	})
+
+	Describe("SendDigests", func() {
+		It("should send one email with unread notifications to users whose digest is due", func() {
+			lastHour := time.Now().Add(-30 * time.Minute)
+			mockRepo.EXPECT().SelectPendingEmailUserIDs(ctx).Return([]uint{20, 30}, nil)
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{20, 30}).Return([]entity.NotificationPreferences{
+				{UserID: 20, Timezone: "UTC", Digest: entity.DigestDaily},
+				{UserID: 30, Timezone: "UTC", Digest: entity.DigestHourly, LastDigestAt: &lastHour},
+			}, nil)
+
+			readAt := time.Now()
+			mockUsers.EXPECT().GetUserByID(ctx, uint(20)).Return(entity.User{Email: "buyer@mail.com"}, nil)
+			mockRepo.EXPECT().ClaimNotificationDigest(ctx, uint(20), gomock.Any()).Return([]entity.Notification{
+				{ID: 1, UserID: 20, Message: "First"},
+				{ID: 2, UserID: 20, Message: "Already seen", ReadAt: &readAt},
+				{ID: 3, UserID: 20, Message: "Second"},
+			}, nil)
+			mockMailer.EXPECT().NotificationDigest([]string{"First", "Second"}, "buyer@mail.com")
+
+			sent, err := service.SendDigests(ctx)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sent).To(Equal(1))
+		})
+
+		It("should not email when everything was read in the app", func() {
+			readAt := time.Now()
+			mockRepo.EXPECT().SelectPendingEmailUserIDs(ctx).Return([]uint{20}, nil)
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{20}).Return(nil, nil)
+			mockUsers.EXPECT().GetUserByID(ctx, uint(20)).Return(entity.User{Email: "buyer@mail.com"}, nil)
+			mockRepo.EXPECT().ClaimNotificationDigest(ctx, uint(20), gomock.Any()).Return([]entity.Notification{
+				{ID: 1, UserID: 20, Message: "Seen", ReadAt: &readAt},
+			}, nil)
+
+			sent, err := service.SendDigests(ctx)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sent).To(BeZero())
+		})
+
+		It("should not email when another instance already claimed the digest", func() {
+			mockRepo.EXPECT().SelectPendingEmailUserIDs(ctx).Return([]uint{20}, nil)
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{20}).Return(nil, nil)
+			mockUsers.EXPECT().GetUserByID(ctx, uint(20)).Return(entity.User{Email: "buyer@mail.com"}, nil)
+			mockRepo.EXPECT().ClaimNotificationDigest(ctx, uint(20), gomock.Any()).Return(nil, nil)
+
+			sent, err := service.SendDigests(ctx)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sent).To(BeZero())
+		})
+
+		It("should leave notifications pending when the recipient cannot be loaded", func() {
+			mockRepo.EXPECT().SelectPendingEmailUserIDs(ctx).Return([]uint{20}, nil)
+			mockRepo.EXPECT().SelectNotificationPreferences(ctx, []uint{20}).Return(nil, nil)
+			mockUsers.EXPECT().GetUserByID(ctx, uint(20)).Return(entity.User{}, errors.New("db down"))
+
+			sent, err := service.SendDigests(ctx)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sent).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,59 @@
+package offer
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Expirer периодически отменяет просроченные заявки. Пока заявка не отменена,
+// она уже не показывается покупателю и на нее нельзя ответить.
+type Expirer struct {
+	service *Service
+	log     *zap.Logger
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func NewExpirer(service *Service, interval time.Duration, log *zap.Logger) *Expirer {
+	e := &Expirer{
+		service: service,
+		log:     log,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go e.run(interval)
+
+	return e
+}
+
+// Close останавливает проверки и дожидается завершения текущего прохода
+func (e *Expirer) Close() {
+	close(e.stop)
+	<-e.done
+}
+
+func (e *Expirer) run(interval time.Duration) {
+	defer close(e.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			expired, err := e.service.ExpireOffers(context.Background())
+			if err != nil {
+				e.log.Error("Failed to expire offers", zap.Error(err))
+				continue
+			}
+			if expired > 0 {
+				e.log.Info("Expired offers cancelled", zap.Int("count", expired))
+			}
+		case <-e.stop:
+			return
+		}
+	}
+}
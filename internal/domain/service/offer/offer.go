@@ -6,7 +6,6 @@ import (
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
-	"github.com/EM-Stawberry/Stawberry/pkg/email"
 )
 
 type Repository interface {
@@ -15,6 +14,7 @@ type Repository interface {
 	SelectUserOffers(ctx context.Context, userID uint, limit, offset int) ([]entity.Offer, int, error)
 	UpdateOfferStatus(ctx context.Context, offer entity.Offer, userID uint, isStore bool) (entity.Offer, error)
 	DeleteOffer(ctx context.Context, offerID uint) (entity.Offer, error)
+	ExpireOffers(ctx context.Context) ([]entity.Offer, error)
 }
 
 const (
@@ -28,17 +28,15 @@ const (
 
 type Service struct {
 	offerRepository Repository
-	mailer          email.MailerService
 }
 
-func NewService(offerRepository Repository, mailer email.MailerService) *Service {
-	return &Service{offerRepository: offerRepository, mailer: mailer}
+func NewService(offerRepository Repository) *Service {
+	return &Service{offerRepository: offerRepository}
 }
 
 func (os *Service) CreateOffer(
 	ctx context.Context,
 	offer entity.Offer,
-	user entity.User,
 ) (uint, error) {
 
 	t := time.Now()
@@ -47,14 +45,7 @@ func (os *Service) CreateOffer(
 	offer.UpdatedAt = t
 	offer.ExpiresAt = t.Add(offerLifetime)
 
-	offerID, err := os.offerRepository.InsertOffer(ctx, offer)
-	if err != nil {
-		return 0, err
-	}
-
-	os.mailer.Registered(user.Name, user.Email)
-
-	return offerID, nil
+	return os.offerRepository.InsertOffer(ctx, offer)
 }
 
 func (os *Service) GetOffer(
@@ -113,3 +104,13 @@ func (os *Service) DeleteOffer(
 ) (entity.Offer, error) {
 	return os.offerRepository.DeleteOffer(ctx, offerID)
 }
+
+// ExpireOffers отменяет просроченные заявки, события о них раздаются через outbox
+func (os *Service) ExpireOffers(ctx context.Context) (int, error) {
+	offers, err := os.offerRepository.ExpireOffers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(offers), nil
+}
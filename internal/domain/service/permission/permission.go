@@ -0,0 +1,58 @@
+package permission
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=permission_mock_test.go -package=permission Repository
+
+type Repository interface {
+	SelectUserPermissions(ctx context.Context, userID uint) ([]entity.Permission, error)
+	InsertUserPermission(ctx context.Context, userID uint, permission entity.Permission, grantedBy uint) error
+	DeleteUserPermission(ctx context.Context, userID uint, permission entity.Permission) error
+}
+
+type Service struct {
+	permissionRepository Repository
+}
+
+func NewService(permissionRepository Repository) *Service {
+	return &Service{permissionRepository: permissionRepository}
+}
+
+func (s *Service) GetUserPermissions(ctx context.Context, userID uint) ([]entity.Permission, error) {
+	return s.permissionRepository.SelectUserPermissions(ctx, userID)
+}
+
+// GrantPermission выдает пользователю право из реестра entity.Permissions
+func (s *Service) GrantPermission(
+	ctx context.Context,
+	userID uint,
+	permission entity.Permission,
+	grantedBy uint,
+) error {
+	if err := validatePermission(permission); err != nil {
+		return err
+	}
+
+	return s.permissionRepository.InsertUserPermission(ctx, userID, permission, grantedBy)
+}
+
+func (s *Service) RevokePermission(ctx context.Context, userID uint, permission entity.Permission) error {
+	if err := validatePermission(permission); err != nil {
+		return err
+	}
+
+	return s.permissionRepository.DeleteUserPermission(ctx, userID, permission)
+}
+
+func validatePermission(permission entity.Permission) error {
+	if _, ok := entity.Permissions[permission]; !ok {
+		return apperror.New(apperror.BadRequest, fmt.Sprintf("unknown permission %q", permission), nil)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: permission.go
+//
+// Generated by this command:
+//
+//	mockgen -source=permission.go -destination=permission_mock_test.go -package=permission Repository
+//
+
+// Package permission is a generated GoMock package.
+package permission
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteUserPermission mocks base method.
+func (m *MockRepository) DeleteUserPermission(ctx context.Context, userID uint, permission entity.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserPermission", ctx, userID, permission)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserPermission indicates an expected call of DeleteUserPermission.
+func (mr *MockRepositoryMockRecorder) DeleteUserPermission(ctx, userID, permission any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserPermission", reflect.TypeOf((*MockRepository)(nil).DeleteUserPermission), ctx, userID, permission)
+}
+
+// InsertUserPermission mocks base method.
+func (m *MockRepository) InsertUserPermission(ctx context.Context, userID uint, permission entity.Permission, grantedBy uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserPermission", ctx, userID, permission, grantedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUserPermission indicates an expected call of InsertUserPermission.
+func (mr *MockRepositoryMockRecorder) InsertUserPermission(ctx, userID, permission, grantedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserPermission", reflect.TypeOf((*MockRepository)(nil).InsertUserPermission), ctx, userID, permission, grantedBy)
+}
+
+// SelectUserPermissions mocks base method.
+func (m *MockRepository) SelectUserPermissions(ctx context.Context, userID uint) ([]entity.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectUserPermissions", ctx, userID)
+	ret0, _ := ret[0].([]entity.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectUserPermissions indicates an expected call of SelectUserPermissions.
+func (mr *MockRepositoryMockRecorder) SelectUserPermissions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectUserPermissions", reflect.TypeOf((*MockRepository)(nil).SelectUserPermissions), ctx, userID)
+}
@@ -0,0 +1,13 @@
+package permission
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPermission(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Permission Service Suite")
+}
@@ -0,0 +1,83 @@
+package permission
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("PermissionService", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockRepo *MockRepository
+		service  *Service
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		service = NewService(mockRepo)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("GrantPermission", func() {
+		It("should grant known permission", func() {
+			mockRepo.EXPECT().InsertUserPermission(ctx, uint(2), entity.PermissionAuditRead, uint(1)).Return(nil)
+
+			err := service.GrantPermission(ctx, 2, entity.PermissionAuditRead, 1)
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject unknown permission", func() {
+			err := service.GrantPermission(ctx, 2, "offers:delete_everything", 1)
+
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should reject shop permissions that only a shop role gives", func() {
+			err := service.GrantPermission(ctx, 2, entity.PermissionOffersRespond, 1)
+
+			var appErr *apperror.Error
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+
+		It("should pass repository error through", func() {
+			mockRepo.EXPECT().InsertUserPermission(ctx, uint(42), entity.PermissionAuditRead, uint(1)).
+				Return(apperror.ErrUserNotFound)
+
+			err := service.GrantPermission(ctx, 42, entity.PermissionAuditRead, 1)
+
+			Expect(err).To(MatchError(apperror.ErrUserNotFound))
+		})
+	})
+
+	Describe("RevokePermission", func() {
+		It("should revoke granted permission", func() {
+			mockRepo.EXPECT().DeleteUserPermission(ctx, uint(2), entity.PermissionAuditRead).Return(nil)
+
+			err := service.RevokePermission(ctx, 2, entity.PermissionAuditRead)
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject unknown permission", func() {
+			err := service.RevokePermission(ctx, 2, "unknown")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
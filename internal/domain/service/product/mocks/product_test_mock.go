@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	model "github.com/EM-Stawberry/Stawberry/internal/repository/model"
@@ -51,6 +52,21 @@ func (mr *MockRepositoryMockRecorder) GetAttributesByID(ctx, productID interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttributesByID", reflect.TypeOf((*MockRepository)(nil).GetAttributesByID), ctx, productID)
 }
 
+// GetAttributesByProductIDs mocks base method.
+func (m *MockRepository) GetAttributesByProductIDs(ctx context.Context, productIDs []int) (map[int]map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttributesByProductIDs", ctx, productIDs)
+	ret0, _ := ret[0].(map[int]map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttributesByProductIDs indicates an expected call of GetAttributesByProductIDs.
+func (mr *MockRepositoryMockRecorder) GetAttributesByProductIDs(ctx, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttributesByProductIDs", reflect.TypeOf((*MockRepository)(nil).GetAttributesByProductIDs), ctx, productIDs)
+}
+
 // GetAverageRatingByProductID mocks base method.
 func (m *MockRepository) GetAverageRatingByProductID(ctx context.Context, productID int) (float64, int, error) {
 	m.ctrl.T.Helper()
@@ -97,6 +113,21 @@ func (mr *MockRepositoryMockRecorder) GetFilteredProductsCount(ctx, filter inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilteredProductsCount", reflect.TypeOf((*MockRepository)(nil).GetFilteredProductsCount), ctx, filter)
 }
 
+// GetPriceHistory mocks base method.
+func (m *MockRepository) GetPriceHistory(ctx context.Context, productID int, shopID *int, from, to time.Time) ([]entity.PricePoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceHistory", ctx, productID, shopID, from, to)
+	ret0, _ := ret[0].([]entity.PricePoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceHistory indicates an expected call of GetPriceHistory.
+func (mr *MockRepositoryMockRecorder) GetPriceHistory(ctx, productID, shopID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceHistory", reflect.TypeOf((*MockRepository)(nil).GetPriceHistory), ctx, productID, shopID, from, to)
+}
+
 // GetPriceRangeByProductID mocks base method.
 func (m *MockRepository) GetPriceRangeByProductID(ctx context.Context, productID int) (int, int, error) {
 	m.ctrl.T.Helper()
@@ -113,6 +144,21 @@ func (mr *MockRepositoryMockRecorder) GetPriceRangeByProductID(ctx, productID in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceRangeByProductID", reflect.TypeOf((*MockRepository)(nil).GetPriceRangeByProductID), ctx, productID)
 }
 
+// GetPriceRangesByProductIDs mocks base method.
+func (m *MockRepository) GetPriceRangesByProductIDs(ctx context.Context, productIDs []int) (map[int]model.PriceRange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceRangesByProductIDs", ctx, productIDs)
+	ret0, _ := ret[0].(map[int]model.PriceRange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceRangesByProductIDs indicates an expected call of GetPriceRangesByProductIDs.
+func (mr *MockRepositoryMockRecorder) GetPriceRangesByProductIDs(ctx, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceRangesByProductIDs", reflect.TypeOf((*MockRepository)(nil).GetPriceRangesByProductIDs), ctx, productIDs)
+}
+
 // GetProductByID mocks base method.
 func (m *MockRepository) GetProductByID(ctx context.Context, id string) (entity.Product, error) {
 	m.ctrl.T.Helper()
@@ -127,3 +173,86 @@ func (mr *MockRepositoryMockRecorder) GetProductByID(ctx, id interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductByID", reflect.TypeOf((*MockRepository)(nil).GetProductByID), ctx, id)
 }
+
+// GetProductsByIDs mocks base method.
+func (m *MockRepository) GetProductsByIDs(ctx context.Context, productIDs []int) ([]entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductsByIDs", ctx, productIDs)
+	ret0, _ := ret[0].([]entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductsByIDs indicates an expected call of GetProductsByIDs.
+func (mr *MockRepositoryMockRecorder) GetProductsByIDs(ctx, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsByIDs", reflect.TypeOf((*MockRepository)(nil).GetProductsByIDs), ctx, productIDs)
+}
+
+// GetReviewStatsByProductIDs mocks base method.
+func (m *MockRepository) GetReviewStatsByProductIDs(ctx context.Context, productIDs []int) (map[int]model.ReviewStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewStatsByProductIDs", ctx, productIDs)
+	ret0, _ := ret[0].(map[int]model.ReviewStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewStatsByProductIDs indicates an expected call of GetReviewStatsByProductIDs.
+func (mr *MockRepositoryMockRecorder) GetReviewStatsByProductIDs(ctx, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewStatsByProductIDs", reflect.TypeOf((*MockRepository)(nil).GetReviewStatsByProductIDs), ctx, productIDs)
+}
+
+// GetShopPricesByProductIDs mocks base method.
+func (m *MockRepository) GetShopPricesByProductIDs(ctx context.Context, productIDs []int) ([]entity.ShopPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShopPricesByProductIDs", ctx, productIDs)
+	ret0, _ := ret[0].([]entity.ShopPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShopPricesByProductIDs indicates an expected call of GetShopPricesByProductIDs.
+func (mr *MockRepositoryMockRecorder) GetShopPricesByProductIDs(ctx, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShopPricesByProductIDs", reflect.TypeOf((*MockRepository)(nil).GetShopPricesByProductIDs), ctx, productIDs)
+}
+
+// MockImageProvider is a mock of ImageProvider interface.
+type MockImageProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageProviderMockRecorder
+}
+
+// MockImageProviderMockRecorder is the mock recorder for MockImageProvider.
+type MockImageProviderMockRecorder struct {
+	mock *MockImageProvider
+}
+
+// NewMockImageProvider creates a new mock instance.
+func NewMockImageProvider(ctrl *gomock.Controller) *MockImageProvider {
+	mock := &MockImageProvider{ctrl: ctrl}
+	mock.recorder = &MockImageProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageProvider) EXPECT() *MockImageProviderMockRecorder {
+	return m.recorder
+}
+
+// ProductImages mocks base method.
+func (m *MockImageProvider) ProductImages(ctx context.Context, productIDs []int) (map[int][]entity.ProductImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProductImages", ctx, productIDs)
+	ret0, _ := ret[0].(map[int][]entity.ProductImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProductImages indicates an expected call of ProductImages.
+func (mr *MockImageProviderMockRecorder) ProductImages(ctx, productIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProductImages", reflect.TypeOf((*MockImageProvider)(nil).ProductImages), ctx, productIDs)
+}
@@ -0,0 +1,199 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// Размеры окон агрегации истории цен
+const (
+	WindowHour  = "hour"
+	WindowDay   = "day"
+	WindowWeek  = "week"
+	WindowMonth = "month"
+)
+
+// maxPriceWindows ограничивает количество окон в одном ответе
+const maxPriceWindows = 1000
+
+type window struct {
+	start, end time.Time
+}
+
+// GetPriceHistory возвращает историю цен продукта по магазинам за период [from, to)
+// и статистику min/avg/max по окнам заданного размера
+func (ps *Service) GetPriceHistory(
+	ctx context.Context,
+	productID int,
+	shopID *int,
+	from, to time.Time,
+	windowSize string,
+) ([]entity.ShopPriceHistory, error) {
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		return nil, apperror.New(apperror.BadRequest, "from must be before to", nil)
+	}
+
+	windows, err := splitWindows(from.UTC(), to.UTC(), windowSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = ps.ProductRepository.GetProductByID(ctx, strconv.Itoa(productID)); err != nil {
+		return nil, err
+	}
+
+	points, err := ps.ProductRepository.GetPriceHistory(ctx, productID, shopID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]entity.ShopPriceHistory, 0)
+	for start := 0; start < len(points); {
+		end := start
+		for end < len(points) && points[end].ShopID == points[start].ShopID {
+			end++
+		}
+
+		shopPoints := points[start:end]
+		history = append(history, entity.ShopPriceHistory{
+			ShopID:   shopPoints[0].ShopID,
+			ShopName: shopPoints[0].ShopName,
+			Points:   shopPoints,
+			Windows:  aggregateWindows(shopPoints, windows),
+		})
+		start = end
+	}
+
+	return history, nil
+}
+
+// splitWindows делит период на окна, выровненные по началу часа, дня, недели (понедельник) или месяца в UTC.
+// Крайние окна обрезаются границами периода.
+func splitWindows(from, to time.Time, windowSize string) ([]window, error) {
+	var truncate func(time.Time) time.Time
+	var next func(time.Time) time.Time
+
+	switch windowSize {
+	case WindowHour:
+		truncate = func(t time.Time) time.Time { return t.Truncate(time.Hour) }
+		next = func(t time.Time) time.Time { return t.Add(time.Hour) }
+	case WindowDay:
+		truncate = startOfDay
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	case WindowWeek:
+		truncate = func(t time.Time) time.Time {
+			day := startOfDay(t)
+			return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		}
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case WindowMonth:
+		truncate = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		}
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	default:
+		return nil, apperror.New(apperror.BadRequest,
+			fmt.Sprintf("window must be one of %s, %s, %s, %s", WindowHour, WindowDay, WindowWeek, WindowMonth), nil)
+	}
+
+	var windows []window
+	for start := truncate(from); start.Before(to); start = next(start) {
+		if len(windows) == maxPriceWindows {
+			return nil, apperror.New(apperror.BadRequest,
+				fmt.Sprintf("period is too long for the window, at most %d windows allowed", maxPriceWindows), nil)
+		}
+		windows = append(windows, window{start: maxTime(start, from), end: minTime(next(start), to)})
+	}
+
+	return windows, nil
+}
+
+// aggregateWindows считает статистику цены одного магазина по окнам.
+// Цена действует с момента изменения до следующего изменения, поэтому среднее взвешивается по времени.
+// Окна до первого известного изменения цены пропускаются.
+func aggregateWindows(points []entity.PricePoint, windows []window) []entity.PriceWindow {
+	result := make([]entity.PriceWindow, 0, len(windows))
+	current := -1
+
+	for _, w := range windows {
+		for current+1 < len(points) && !points[current+1].ChangedAt.After(w.start) {
+			current++
+		}
+
+		start := w.start
+		idx := current
+		if idx < 0 {
+			if len(points) == 0 || !points[0].ChangedAt.Before(w.end) {
+				continue
+			}
+			idx = 0
+			start = points[0].ChangedAt
+		}
+
+		stat := entity.PriceWindow{
+			Start:    w.start,
+			End:      w.end,
+			Min:      points[idx].Price,
+			Max:      points[idx].Price,
+			Currency: points[idx].Currency,
+		}
+
+		var weighted, total float64
+		for ; ; idx++ {
+			segmentEnd := w.end
+			if idx+1 < len(points) && points[idx+1].ChangedAt.Before(w.end) {
+				segmentEnd = points[idx+1].ChangedAt
+			}
+
+			price := points[idx].Price
+			stat.Min = min(stat.Min, price)
+			stat.Max = max(stat.Max, price)
+			stat.Currency = points[idx].Currency
+
+			duration := segmentEnd.Sub(start).Seconds()
+			weighted += float64(price) * duration
+			total += duration
+
+			if !segmentEnd.Before(w.end) {
+				break
+			}
+			start = segmentEnd
+		}
+
+		if total > 0 {
+			stat.Avg = int(math.Round(weighted / total))
+		} else {
+			stat.Avg = stat.Max
+		}
+		result = append(result, stat)
+	}
+
+	return result
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
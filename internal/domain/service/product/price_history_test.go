@@ -0,0 +1,104 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/product/mocks"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func at(day, hour int) time.Time {
+	return time.Date(2025, time.June, day, hour, 0, 0, 0, time.UTC)
+}
+
+var _ = Describe("GetPriceHistory", func() {
+	var (
+		mockCtrl *gomock.Controller
+		mockRepo *mocks.MockRepository
+		svc      *Service
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepository(mockCtrl)
+		svc = NewService(mockRepo, nil)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("groups points by shop and aggregates daily windows", func() {
+		from, to := at(2, 0), at(4, 0)
+		mockRepo.EXPECT().GetProductByID(ctx, "1").Return(entity.Product{ID: 1}, nil)
+		mockRepo.EXPECT().GetPriceHistory(ctx, 1, nil, from, to).Return([]entity.PricePoint{
+			// цена на начало периода
+			{ShopID: 10, ShopName: "A", Price: 1000, Currency: "RUB", ChangedAt: at(1, 12)},
+			{ShopID: 10, ShopName: "A", Price: 2000, Currency: "RUB", ChangedAt: at(2, 6)},
+			{ShopID: 10, ShopName: "A", Price: 1500, Currency: "RUB", ChangedAt: at(3, 12)},
+			// магазин начал продавать посреди периода
+			{ShopID: 11, ShopName: "B", Price: 900, Currency: "RUB", ChangedAt: at(3, 18)},
+		}, nil)
+
+		history, err := svc.GetPriceHistory(ctx, 1, nil, from, to, WindowDay)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(history).To(HaveLen(2))
+
+		shopA := history[0]
+		Expect(shopA.ShopName).To(Equal("A"))
+		Expect(shopA.Points).To(HaveLen(3))
+		Expect(shopA.Windows).To(HaveLen(2))
+		Expect(shopA.Windows[0].Start).To(Equal(at(2, 0)))
+		Expect(shopA.Windows[0].Min).To(Equal(1000))
+		Expect(shopA.Windows[0].Max).To(Equal(2000))
+		// 6 часов по 1000 и 18 часов по 2000
+		Expect(shopA.Windows[0].Avg).To(Equal(1750))
+		Expect(shopA.Windows[1].Min).To(Equal(1500))
+		Expect(shopA.Windows[1].Avg).To(Equal(1750))
+
+		shopB := history[1]
+		Expect(shopB.Windows).To(HaveLen(1))
+		Expect(shopB.Windows[0].Start).To(Equal(at(3, 0)))
+		Expect(shopB.Windows[0].Avg).To(Equal(900))
+	})
+
+	It("rejects unknown window", func() {
+		_, err := svc.GetPriceHistory(ctx, 1, nil, at(2, 0), at(3, 0), "year")
+
+		var appErr apperror.AppError
+		Expect(errors.As(err, &appErr)).To(BeTrue())
+		Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+	})
+
+	It("rejects too many windows", func() {
+		_, err := svc.GetPriceHistory(ctx, 1, nil, at(1, 0).AddDate(-1, 0, 0), at(1, 0), WindowHour)
+
+		var appErr apperror.AppError
+		Expect(errors.As(err, &appErr)).To(BeTrue())
+		Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+	})
+})
+
+var _ = Describe("splitWindows", func() {
+	It("aligns weeks to monday and clips edges", func() {
+		// 4 июня 2025 - среда
+		windows, err := splitWindows(at(4, 10), at(12, 0), WindowWeek)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(windows).To(HaveLen(2))
+		Expect(windows[0].start).To(Equal(at(4, 10)))
+		Expect(windows[0].end).To(Equal(at(9, 0)))
+		Expect(windows[1].start).To(Equal(at(9, 0)))
+		Expect(windows[1].end).To(Equal(at(12, 0)))
+	})
+})
@@ -2,8 +2,12 @@ package product
 
 import (
 	"context"
-	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
 
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 
 	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
@@ -16,14 +20,26 @@ type Repository interface {
 	GetAttributesByID(ctx context.Context, productID string) (map[string]interface{}, error)
 	GetPriceRangeByProductID(ctx context.Context, productID int) (int, int, error)
 	GetAverageRatingByProductID(ctx context.Context, productID int) (float64, int, error)
+	GetShopPricesByProductIDs(ctx context.Context, productIDs []int) ([]entity.ShopPrice, error)
+	GetProductsByIDs(ctx context.Context, productIDs []int) ([]entity.Product, error)
+	GetAttributesByProductIDs(ctx context.Context, productIDs []int) (map[int]map[string]interface{}, error)
+	GetPriceRangesByProductIDs(ctx context.Context, productIDs []int) (map[int]model.PriceRange, error)
+	GetReviewStatsByProductIDs(ctx context.Context, productIDs []int) (map[int]model.ReviewStats, error)
+	GetPriceHistory(ctx context.Context, productID int, shopID *int, from, to time.Time) ([]entity.PricePoint, error)
+}
+
+// ImageProvider отдает изображения продуктов с подписанными ссылками
+type ImageProvider interface {
+	ProductImages(ctx context.Context, productIDs []int) (map[int][]entity.ProductImage, error)
 }
 
 type Service struct {
 	ProductRepository Repository
+	imageProvider     ImageProvider
 }
 
-func NewService(productRepo Repository) *Service {
-	return &Service{ProductRepository: productRepo}
+func NewService(productRepo Repository, imageProvider ImageProvider) *Service {
+	return &Service{ProductRepository: productRepo, imageProvider: imageProvider}
 }
 
 // GetProductByID получает продукт по его ID
@@ -46,7 +62,12 @@ func (ps *Service) GetProductByID(
 		return entity.Product{}, err
 	}
 
-	return enrichedProduct, nil
+	products := []entity.Product{enrichedProduct}
+	if err = ps.attachImages(ctx, products); err != nil {
+		return entity.Product{}, err
+	}
+
+	return products[0], nil
 }
 
 func (ps *Service) GetFilteredProducts(ctx context.Context,
@@ -54,26 +75,198 @@ func (ps *Service) GetFilteredProducts(ctx context.Context,
 	limit, offset int) ([]entity.Product, int, error) {
 	products, err := ps.ProductRepository.GetFilteredProducts(ctx, filter, limit, offset)
 	if err != nil {
-		fmt.Println("Ошибка при получении продуктов")
 		return nil, 0, err
 	}
 
 	count, err := ps.ProductRepository.GetFilteredProductsCount(ctx, filter)
 	if err != nil {
-		fmt.Println("Ошибка при получении количества")
 		return nil, 0, err
 	}
 	for i := range products {
 		products[i], err = ps.enrichProducts(ctx, products[i])
 		if err != nil {
-			fmt.Println("Ошибка при обогащении продуктов")
 			return nil, 0, err
 		}
 	}
 
+	if err = ps.attachImages(ctx, products); err != nil {
+		return nil, 0, err
+	}
+
 	return products, count, nil
 }
 
+// GetProductShopPrices возвращает предложения продукта во всех магазинах
+func (ps *Service) GetProductShopPrices(ctx context.Context, id int) ([]entity.ShopPrice, error) {
+	if _, err := ps.ProductRepository.GetProductByID(ctx, strconv.Itoa(id)); err != nil {
+		return nil, err
+	}
+
+	return ps.ProductRepository.GetShopPricesByProductIDs(ctx, []int{id})
+}
+
+// CompareProducts собирает продукты для сравнения: атрибуты сводятся в общую матрицу
+// по объединению ключей, к каждому продукту добавляются цены по магазинам.
+// Каждый вид данных загружается одним запросом сразу для всех продуктов.
+func (ps *Service) CompareProducts(ctx context.Context, ids []int) (entity.ProductComparison, error) {
+	products, err := ps.productsByIDs(ctx, ids)
+	if err != nil {
+		return entity.ProductComparison{}, err
+	}
+
+	attributes, err := ps.ProductRepository.GetAttributesByProductIDs(ctx, ids)
+	if err != nil {
+		return entity.ProductComparison{}, err
+	}
+	for i := range products {
+		products[i].Attributes = attributes[products[i].ID]
+	}
+
+	if err = ps.enrichProductList(ctx, products); err != nil {
+		return entity.ProductComparison{}, err
+	}
+
+	if err = ps.attachImages(ctx, products); err != nil {
+		return entity.ProductComparison{}, err
+	}
+
+	prices, err := ps.ProductRepository.GetShopPricesByProductIDs(ctx, ids)
+	if err != nil {
+		return entity.ProductComparison{}, err
+	}
+
+	pricesByProduct := make(map[int][]entity.ShopPrice, len(ids))
+	for _, price := range prices {
+		pricesByProduct[price.ProductID] = append(pricesByProduct[price.ProductID], price)
+	}
+
+	comparison := entity.ProductComparison{
+		Products:   make([]entity.ComparedProduct, len(products)),
+		Attributes: buildAttributeMatrix(products),
+	}
+	for i, product := range products {
+		comparison.Products[i] = entity.ComparedProduct{
+			Product:    product,
+			ShopPrices: pricesByProduct[product.ID],
+		}
+	}
+
+	return comparison, nil
+}
+
+// productsByIDs возвращает продукты в порядке ids, если не найден хотя бы один, возвращается ошибка
+func (ps *Service) productsByIDs(ctx context.Context, ids []int) ([]entity.Product, error) {
+	found, err := ps.ProductRepository.GetProductsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]entity.Product, len(found))
+	for _, product := range found {
+		byID[product.ID] = product
+	}
+
+	products := make([]entity.Product, len(ids))
+	for i, id := range ids {
+		product, ok := byID[id]
+		if !ok {
+			return nil, apperror.ErrProductNotFound
+		}
+		products[i] = product
+	}
+
+	return products, nil
+}
+
+// buildAttributeMatrix выравнивает атрибуты продуктов по объединению ключей в алфавитном порядке.
+// Атрибут отличается, если хотя бы у одного продукта значение другое или отсутствует.
+func buildAttributeMatrix(products []entity.Product) []entity.ComparedAttribute {
+	keys := make(map[string]struct{})
+	for _, product := range products {
+		for key := range product.Attributes {
+			keys[key] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(keys))
+	for key := range keys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+
+	matrix := make([]entity.ComparedAttribute, len(names))
+	for i, name := range names {
+		values := make([]interface{}, len(products))
+		differs := false
+		for j, product := range products {
+			values[j] = product.Attributes[name]
+			if j > 0 && !reflect.DeepEqual(values[j], values[0]) {
+				differs = true
+			}
+		}
+		matrix[i] = entity.ComparedAttribute{Name: name, Values: values, Differs: differs}
+	}
+
+	return matrix
+}
+
+// attachImages подгружает изображения сразу для всех продуктов одним запросом
+func (ps *Service) attachImages(ctx context.Context, products []entity.Product) error {
+	if ps.imageProvider == nil || len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(products))
+	for i := range products {
+		ids[i] = products[i].ID
+	}
+
+	images, err := ps.imageProvider.ProductImages(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		products[i].Images = images[products[i].ID]
+	}
+
+	return nil
+}
+
+// enrichProductList добавляет продуктам диапазон цен, среднюю оценку и количество отзывов
+// двумя запросами на весь список
+func (ps *Service) enrichProductList(ctx context.Context, products []entity.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(products))
+	for i := range products {
+		ids[i] = products[i].ID
+	}
+
+	priceRanges, err := ps.ProductRepository.GetPriceRangesByProductIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	reviewStats, err := ps.ProductRepository.GetReviewStatsByProductIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		priceRange := priceRanges[products[i].ID]
+		stats := reviewStats[products[i].ID]
+		products[i].MinimalPrice = priceRange.Min
+		products[i].MaximalPrice = priceRange.Max
+		products[i].AverageRating = stats.Average
+		products[i].CountReviews = stats.Count
+	}
+
+	return nil
+}
+
 // EnrichProducts выполняет обогащение продукта информацией о диапазоне цены, средней оценке и количестве отзывов
 func (ps *Service) enrichProducts(
 	ctx context.Context,
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/product/mocks"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -62,3 +64,147 @@ var _ = Describe("EnrichProducts", func() {
 		Expect(err).To(MatchError("rating error"))
 	})
 })
+
+var _ = Describe("AttachImages", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockImages *mocks.MockImageProvider
+		svc        *Service
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockImages = mocks.NewMockImageProvider(mockCtrl)
+		svc = &Service{imageProvider: mockImages}
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("attaches images to each product", func() {
+		products := []entity.Product{{ID: 1}, {ID: 2}}
+		mockImages.EXPECT().ProductImages(ctx, []int{1, 2}).Return(map[int][]entity.ProductImage{
+			1: {{Key: "products/1/a.png"}},
+		}, nil)
+
+		Expect(svc.attachImages(ctx, products)).To(Succeed())
+		Expect(products[0].Images).To(HaveLen(1))
+		Expect(products[1].Images).To(BeEmpty())
+	})
+
+	It("skips loading without image provider", func() {
+		svc = &Service{}
+
+		Expect(svc.attachImages(ctx, []entity.Product{{ID: 1}})).To(Succeed())
+	})
+})
+
+var _ = Describe("CompareProducts", func() {
+	var (
+		mockCtrl *gomock.Controller
+		mockRepo *mocks.MockRepository
+		svc      *Service
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepository(mockCtrl)
+		svc = NewService(mockRepo, nil)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("builds attribute matrix and groups shop prices", func() {
+		ids := []int{1, 2}
+		mockRepo.EXPECT().GetProductsByIDs(ctx, ids).
+			Return([]entity.Product{{ID: 2}, {ID: 1}}, nil)
+		mockRepo.EXPECT().GetAttributesByProductIDs(ctx, ids).Return(map[int]map[string]interface{}{
+			1: {"color": "black", "ram": 8.0},
+			2: {"color": "black", "weight": "1kg"},
+		}, nil)
+		mockRepo.EXPECT().GetPriceRangesByProductIDs(ctx, ids).
+			Return(map[int]model.PriceRange{1: {Min: 1000, Max: 2000}}, nil)
+		mockRepo.EXPECT().GetReviewStatsByProductIDs(ctx, ids).
+			Return(map[int]model.ReviewStats{2: {Average: 4.0, Count: 3}}, nil)
+		mockRepo.EXPECT().GetShopPricesByProductIDs(ctx, ids).Return([]entity.ShopPrice{
+			{ProductID: 1, ShopID: 10, Price: 1000},
+			{ProductID: 1, ShopID: 11, Price: 2000},
+		}, nil)
+
+		result, err := svc.CompareProducts(ctx, ids)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Products).To(HaveLen(2))
+		Expect(result.Products[0].ID).To(Equal(1))
+		Expect(result.Products[0].ShopPrices).To(HaveLen(2))
+		Expect(result.Products[0].MinimalPrice).To(Equal(1000))
+		Expect(result.Products[0].CountReviews).To(BeZero())
+		Expect(result.Products[1].ID).To(Equal(2))
+		Expect(result.Products[1].ShopPrices).To(BeEmpty())
+		Expect(result.Products[1].MinimalPrice).To(BeZero())
+		Expect(result.Products[1].AverageRating).To(Equal(4.0))
+
+		Expect(result.Attributes).To(HaveLen(3))
+		Expect(result.Attributes[0].Name).To(Equal("color"))
+		Expect(result.Attributes[0].Differs).To(BeFalse())
+		Expect(result.Attributes[1].Name).To(Equal("ram"))
+		Expect(result.Attributes[1].Values).To(Equal([]interface{}{8.0, nil}))
+		Expect(result.Attributes[1].Differs).To(BeTrue())
+		Expect(result.Attributes[2].Name).To(Equal("weight"))
+		Expect(result.Attributes[2].Differs).To(BeTrue())
+	})
+
+	It("returns error if one of the products is missing", func() {
+		mockRepo.EXPECT().GetProductsByIDs(ctx, []int{1, 2}).Return([]entity.Product{{ID: 1}}, nil)
+
+		_, err := svc.CompareProducts(ctx, []int{1, 2})
+		Expect(err).To(MatchError(apperror.ErrProductNotFound))
+	})
+})
+
+var _ = Describe("GetProductShopPrices", func() {
+	var (
+		mockCtrl *gomock.Controller
+		mockRepo *mocks.MockRepository
+		svc      *Service
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockRepo = mocks.NewMockRepository(mockCtrl)
+		svc = NewService(mockRepo, nil)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("returns shop prices of existing product", func() {
+		mockRepo.EXPECT().GetProductByID(ctx, "1").Return(entity.Product{ID: 1}, nil)
+		mockRepo.EXPECT().GetShopPricesByProductIDs(ctx, []int{1}).Return([]entity.ShopPrice{
+			{ProductID: 1, ShopID: 10, ShopName: "Shop A", SellerRating: 4.5},
+		}, nil)
+
+		prices, err := svc.GetProductShopPrices(ctx, 1)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(prices).To(HaveLen(1))
+		Expect(prices[0].ShopName).To(Equal("Shop A"))
+	})
+
+	It("returns error if product does not exist", func() {
+		mockRepo.EXPECT().GetProductByID(ctx, "1").Return(entity.Product{}, errors.New("product not found"))
+
+		_, err := svc.GetProductShopPrices(ctx, 1)
+		Expect(err).To(MatchError("product not found"))
+	})
+})
@@ -17,6 +17,11 @@ type SellerReviewRepository interface {
 	GetSellerByID(ctx context.Context, sellerID int) (entity.SellerReview, error)
 }
 
+// Notifier sends in-app notifications to the reviewed seller
+type Notifier interface {
+	Notify(ctx context.Context, notification entity.Notification) error
+}
+
 // SellerReviewsService defines the interface for seller review business logic.
 type SellerReviewsService interface {
 	AddReview(ctx context.Context, sellerID int, userID int, rating int, review string) (int, error)
@@ -24,14 +29,16 @@ type SellerReviewsService interface {
 }
 
 type SellerReviewService struct {
-	srs    SellerReviewRepository
-	logger *zap.Logger
+	srs      SellerReviewRepository
+	notifier Notifier
+	logger   *zap.Logger
 }
 
-func NewSellerReviewService(srr SellerReviewRepository, l *zap.Logger) SellerReviewsService {
+func NewSellerReviewService(srr SellerReviewRepository, notifier Notifier, l *zap.Logger) SellerReviewsService {
 	return &SellerReviewService{
-		srs:    srr,
-		logger: l,
+		srs:      srr,
+		notifier: notifier,
+		logger:   l,
 	}
 }
 
@@ -54,14 +61,25 @@ func (s *SellerReviewService) AddReview(
 	}
 
 	log.Info("Adding a review")
-	sellerID, err = s.srs.AddReview(ctx, sellerID, userID, rating, review)
+	reviewID, err := s.srs.AddReview(ctx, sellerID, userID, rating, review)
 	if err != nil {
 		log.Warn("Failed to add review", zap.Error(err))
 		return 0, fmt.Errorf("op: %s, err: %w", op, err)
 	}
 
+	// The review is already saved, so a failed notification is only logged
+	err = s.notifier.Notify(ctx, entity.Notification{
+		UserID:  uint(sellerID),
+		Type:    entity.NotificationReviewReceived,
+		Message: fmt.Sprintf("You received a new %d-star review", rating),
+		Link:    fmt.Sprintf("/sellers/%d/reviews", sellerID),
+	})
+	if err != nil {
+		log.Warn("Failed to notify seller about review", zap.Int("sellerID", sellerID), zap.Error(err))
+	}
+
 	log.Info("Review added successfully")
-	return sellerID, nil
+	return reviewID, nil
 }
 
 func (s *SellerReviewService) GetReviewsByID(
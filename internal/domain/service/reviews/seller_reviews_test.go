@@ -2,6 +2,7 @@ package reviews_test
 
 import (
 	"context"
+	"errors"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
@@ -56,17 +57,29 @@ func (m *mockSellerReviewRepository) GetReviewsBySellerID(
 	return m.reviews[sellerID], nil
 }
 
+type mockNotifier struct {
+	notifications []entity.Notification
+	err           error
+}
+
+func (m *mockNotifier) Notify(_ context.Context, notification entity.Notification) error {
+	m.notifications = append(m.notifications, notification)
+	return m.err
+}
+
 var _ = Describe("SellerReviewService", func() {
 	var (
-		service reviews.SellerReviewsService
-		repo    *mockSellerReviewRepository
-		ctx     context.Context
+		service  reviews.SellerReviewsService
+		repo     *mockSellerReviewRepository
+		notifier *mockNotifier
+		ctx      context.Context
 	)
 
 	BeforeEach(func() {
 		ctx = context.Background()
 		repo = newMockSellerReviewRepository()
-		service = reviews.NewSellerReviewService(repo, zap.NewNop())
+		notifier = &mockNotifier{}
+		service = reviews.NewSellerReviewService(repo, notifier, zap.NewNop())
 	})
 
 	Context("AddReview", func() {
@@ -98,6 +111,19 @@ var _ = Describe("SellerReviewService", func() {
 			Expect(reviews[0].UserID).To(Equal(userID))
 			Expect(reviews[0].Rating).To(Equal(rating))
 			Expect(reviews[0].Review).To(Equal(review))
+			Expect(notifier.notifications).To(HaveLen(1))
+			Expect(notifier.notifications[0].UserID).To(Equal(uint(sellerID)))
+			Expect(notifier.notifications[0].Type).To(Equal(entity.NotificationReviewReceived))
+		})
+
+		It("should keep the review if the seller cannot be notified", func() {
+			repo.sellers[1] = entity.SellerReview{SellerID: 1}
+			notifier.err = errors.New("db error")
+
+			_, err := service.AddReview(ctx, 1, 2, 4, "Fine")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.reviews[1]).To(HaveLen(1))
 		})
 
 		It("should return error for non-existent seller", func() {
@@ -107,6 +133,7 @@ var _ = Describe("SellerReviewService", func() {
 			// Assert
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("seller not found"))
+			Expect(notifier.notifications).To(BeEmpty())
 		})
 	})
 
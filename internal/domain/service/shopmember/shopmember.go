@@ -0,0 +1,163 @@
+package shopmember
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=shopmember_mock_test.go -package=shopmember Repository
+
+type Repository interface {
+	GetShopName(ctx context.Context, shopID uint) (string, error)
+	GetMemberRole(ctx context.Context, shopID, userID uint) (entity.ShopRole, error)
+	SelectMembers(ctx context.Context, shopID uint) ([]entity.ShopMember, error)
+	InsertInvitation(ctx context.Context, invitation entity.ShopInvitation) (uint, error)
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (entity.ShopInvitation, error)
+	AcceptInvitation(ctx context.Context, invitation entity.ShopInvitation, userID uint) error
+	DeleteMember(ctx context.Context, shopID, userID uint) error
+}
+
+type Service struct {
+	shopMemberRepository Repository
+	mailer               email.MailerService
+	invitationTTL        time.Duration
+}
+
+func NewService(shopMemberRepository Repository, mailer email.MailerService, cfg *config.ShopConfig) *Service {
+	return &Service{
+		shopMemberRepository: shopMemberRepository,
+		mailer:               mailer,
+		invitationTTL:        cfg.InvitationTTL,
+	}
+}
+
+// GetMembers возвращает сотрудников магазина, список доступен только самим сотрудникам
+func (s *Service) GetMembers(ctx context.Context, shopID, actorID uint) ([]entity.ShopMember, error) {
+	if _, err := s.actorRole(ctx, shopID, actorID); err != nil {
+		return nil, err
+	}
+
+	return s.shopMemberRepository.SelectMembers(ctx, shopID)
+}
+
+// InviteMember отправляет приглашение на почту. Владелец приглашает менеджеров и агентов,
+// менеджер только агентов.
+func (s *Service) InviteMember(
+	ctx context.Context,
+	shopID, actorID uint,
+	userMail string,
+	role entity.ShopRole,
+) (uint, error) {
+	if role != entity.ShopRoleManager && role != entity.ShopRoleAgent {
+		return 0, apperror.New(apperror.BadRequest, "role must be manager or agent", nil)
+	}
+
+	actorRole, err := s.actorRole(ctx, shopID, actorID)
+	if err != nil {
+		return 0, err
+	}
+	if !canManage(actorRole, role) {
+		return 0, apperror.New(apperror.Forbidden, "not allowed to invite members with this role", nil)
+	}
+
+	shopName, err := s.shopMemberRepository.GetShopName(ctx, shopID)
+	if err != nil {
+		return 0, err
+	}
+
+	token, tokenHash, err := security.GenerateOpaqueToken()
+	if err != nil {
+		return 0, apperror.New(apperror.InternalError, "failed to generate invitation token", err)
+	}
+
+	userMail = strings.ToLower(strings.TrimSpace(userMail))
+	id, err := s.shopMemberRepository.InsertInvitation(ctx, entity.ShopInvitation{
+		ShopID:    shopID,
+		Email:     userMail,
+		Role:      role,
+		TokenHash: tokenHash,
+		InvitedBy: actorID,
+		ExpiresAt: time.Now().Add(s.invitationTTL),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.mailer.ShopInvitation(shopName, string(role), token, userMail)
+
+	return id, nil
+}
+
+// AcceptInvitation добавляет пользователя в магазин. Принять приглашение может только владелец почты,
+// на которую оно отправлено.
+func (s *Service) AcceptInvitation(ctx context.Context, token string, userID uint, userMail string) error {
+	invitation, err := s.shopMemberRepository.GetInvitationByTokenHash(ctx, security.HashOpaqueToken(token))
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(invitation.Email, strings.TrimSpace(userMail)) {
+		return apperror.New(apperror.Forbidden, "invitation was sent to another email", nil)
+	}
+	if invitation.AcceptedAt != nil {
+		return apperror.New(apperror.Conflict, "invitation has already been accepted", nil)
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return apperror.New(apperror.BadRequest, "invitation has expired", nil)
+	}
+
+	return s.shopMemberRepository.AcceptInvitation(ctx, invitation, userID)
+}
+
+// RemoveMember убирает сотрудника из магазина. Сотрудник может уйти сам,
+// владелец убирает кого угодно, кроме себя, менеджер только агентов.
+func (s *Service) RemoveMember(ctx context.Context, shopID, actorID, userID uint) error {
+	actorRole, err := s.actorRole(ctx, shopID, actorID)
+	if err != nil {
+		return err
+	}
+
+	memberRole, err := s.shopMemberRepository.GetMemberRole(ctx, shopID, userID)
+	if err != nil {
+		return err
+	}
+	if memberRole == entity.ShopRoleOwner {
+		return apperror.New(apperror.BadRequest, "shop owner cannot be removed", nil)
+	}
+	if actorID != userID && !canManage(actorRole, memberRole) {
+		return apperror.New(apperror.Forbidden, "not allowed to remove members with this role", nil)
+	}
+
+	return s.shopMemberRepository.DeleteMember(ctx, shopID, userID)
+}
+
+// actorRole возвращает роль пользователя в магазине, посторонним доступ запрещен
+func (s *Service) actorRole(ctx context.Context, shopID, actorID uint) (entity.ShopRole, error) {
+	role, err := s.shopMemberRepository.GetMemberRole(ctx, shopID, actorID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrShopMemberNotFound) {
+			return "", apperror.New(apperror.Forbidden, "not a member of the shop", err)
+		}
+		return "", err
+	}
+	return role, nil
+}
+
+func canManage(actor, member entity.ShopRole) bool {
+	switch actor {
+	case entity.ShopRoleOwner:
+		return member != entity.ShopRoleOwner
+	case entity.ShopRoleManager:
+		return member == entity.ShopRoleAgent
+	default:
+		return false
+	}
+}
@@ -0,0 +1,145 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: shopmember.go
+//
+// Generated by this command:
+//
+//	mockgen -source=shopmember.go -destination=shopmember_mock_test.go -package=shopmember Repository
+//
+
+// Package shopmember is a generated GoMock package.
+package shopmember
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AcceptInvitation mocks base method.
+func (m *MockRepository) AcceptInvitation(ctx context.Context, invitation entity.ShopInvitation, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", ctx, invitation, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockRepositoryMockRecorder) AcceptInvitation(ctx, invitation, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockRepository)(nil).AcceptInvitation), ctx, invitation, userID)
+}
+
+// DeleteMember mocks base method.
+func (m *MockRepository) DeleteMember(ctx context.Context, shopID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMember", ctx, shopID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMember indicates an expected call of DeleteMember.
+func (mr *MockRepositoryMockRecorder) DeleteMember(ctx, shopID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMember", reflect.TypeOf((*MockRepository)(nil).DeleteMember), ctx, shopID, userID)
+}
+
+// GetInvitationByTokenHash mocks base method.
+func (m *MockRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (entity.ShopInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvitationByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(entity.ShopInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvitationByTokenHash indicates an expected call of GetInvitationByTokenHash.
+func (mr *MockRepositoryMockRecorder) GetInvitationByTokenHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvitationByTokenHash", reflect.TypeOf((*MockRepository)(nil).GetInvitationByTokenHash), ctx, tokenHash)
+}
+
+// GetMemberRole mocks base method.
+func (m *MockRepository) GetMemberRole(ctx context.Context, shopID, userID uint) (entity.ShopRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberRole", ctx, shopID, userID)
+	ret0, _ := ret[0].(entity.ShopRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberRole indicates an expected call of GetMemberRole.
+func (mr *MockRepositoryMockRecorder) GetMemberRole(ctx, shopID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberRole", reflect.TypeOf((*MockRepository)(nil).GetMemberRole), ctx, shopID, userID)
+}
+
+// GetShopName mocks base method.
+func (m *MockRepository) GetShopName(ctx context.Context, shopID uint) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShopName", ctx, shopID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShopName indicates an expected call of GetShopName.
+func (mr *MockRepositoryMockRecorder) GetShopName(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShopName", reflect.TypeOf((*MockRepository)(nil).GetShopName), ctx, shopID)
+}
+
+// InsertInvitation mocks base method.
+func (m *MockRepository) InsertInvitation(ctx context.Context, invitation entity.ShopInvitation) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertInvitation", ctx, invitation)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertInvitation indicates an expected call of InsertInvitation.
+func (mr *MockRepositoryMockRecorder) InsertInvitation(ctx, invitation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertInvitation", reflect.TypeOf((*MockRepository)(nil).InsertInvitation), ctx, invitation)
+}
+
+// SelectMembers mocks base method.
+func (m *MockRepository) SelectMembers(ctx context.Context, shopID uint) ([]entity.ShopMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectMembers", ctx, shopID)
+	ret0, _ := ret[0].([]entity.ShopMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectMembers indicates an expected call of SelectMembers.
+func (mr *MockRepositoryMockRecorder) SelectMembers(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectMembers", reflect.TypeOf((*MockRepository)(nil).SelectMembers), ctx, shopID)
+}
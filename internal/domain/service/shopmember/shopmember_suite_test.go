@@ -0,0 +1,13 @@
+package shopmember
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestShopMember(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shop Member Service Suite")
+}
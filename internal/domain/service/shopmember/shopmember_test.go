@@ -0,0 +1,171 @@
+package shopmember
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func expectAppErrorCode(err error, code string) {
+	var appErr *apperror.Error
+	ExpectWithOffset(1, errors.As(err, &appErr)).To(BeTrue())
+	ExpectWithOffset(1, appErr.Code()).To(Equal(code))
+}
+
+var _ = Describe("ShopMemberService", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *MockRepository
+		mockMailer *mock_email.MockMailerService
+		service    *Service
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockMailer = mock_email.NewMockMailerService(ctrl)
+		service = NewService(mockRepo, mockMailer, &config.ShopConfig{InvitationTTL: time.Hour})
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("InviteMember", func() {
+		It("should store hashed token and mail the plain one", func() {
+			var token string
+			var stored entity.ShopInvitation
+
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleOwner, nil)
+			mockRepo.EXPECT().GetShopName(ctx, uint(1)).Return("Shop", nil)
+			mockRepo.EXPECT().InsertInvitation(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, invitation entity.ShopInvitation) (uint, error) {
+					stored = invitation
+					return 7, nil
+				})
+			mockMailer.EXPECT().ShopInvitation("Shop", "manager", gomock.Any(), "staff@shop.com").
+				Do(func(_, _, t, _ string) { token = t })
+
+			id, err := service.InviteMember(ctx, 1, 10, " Staff@Shop.com ", entity.ShopRoleManager)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(id).To(Equal(uint(7)))
+			Expect(stored.Email).To(Equal("staff@shop.com"))
+			Expect(stored.InvitedBy).To(Equal(uint(10)))
+			Expect(stored.TokenHash).To(Equal(security.HashOpaqueToken(token)))
+			Expect(stored.TokenHash).ToNot(Equal(token))
+			Expect(stored.ExpiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+		})
+
+		It("should not let manager invite another manager", func() {
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(1), uint(11)).Return(entity.ShopRoleManager, nil)
+
+			_, err := service.InviteMember(ctx, 1, 11, "staff@shop.com", entity.ShopRoleManager)
+
+			expectAppErrorCode(err, apperror.Forbidden)
+		})
+
+		It("should reject outsiders", func() {
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(1), uint(12)).Return(entity.ShopRole(""), apperror.ErrShopMemberNotFound)
+
+			_, err := service.InviteMember(ctx, 1, 12, "staff@shop.com", entity.ShopRoleAgent)
+
+			expectAppErrorCode(err, apperror.Forbidden)
+		})
+
+		It("should not invite owners", func() {
+			_, err := service.InviteMember(ctx, 1, 10, "staff@shop.com", entity.ShopRoleOwner)
+
+			expectAppErrorCode(err, apperror.BadRequest)
+		})
+	})
+
+	Describe("AcceptInvitation", func() {
+		var invitation entity.ShopInvitation
+
+		BeforeEach(func() {
+			invitation = entity.ShopInvitation{
+				ID:        7,
+				ShopID:    1,
+				Email:     "staff@shop.com",
+				Role:      entity.ShopRoleAgent,
+				ExpiresAt: time.Now().Add(time.Hour),
+			}
+		})
+
+		It("should add member when email matches", func() {
+			mockRepo.EXPECT().GetInvitationByTokenHash(ctx, security.HashOpaqueToken("token")).Return(invitation, nil)
+			mockRepo.EXPECT().AcceptInvitation(ctx, invitation, uint(20)).Return(nil)
+
+			err := service.AcceptInvitation(ctx, "token", 20, "Staff@shop.com")
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject invitation for another email", func() {
+			mockRepo.EXPECT().GetInvitationByTokenHash(ctx, gomock.Any()).Return(invitation, nil)
+
+			err := service.AcceptInvitation(ctx, "token", 20, "other@shop.com")
+
+			expectAppErrorCode(err, apperror.Forbidden)
+		})
+
+		It("should reject expired invitation", func() {
+			invitation.ExpiresAt = time.Now().Add(-time.Minute)
+			mockRepo.EXPECT().GetInvitationByTokenHash(ctx, gomock.Any()).Return(invitation, nil)
+
+			err := service.AcceptInvitation(ctx, "token", 20, "staff@shop.com")
+
+			expectAppErrorCode(err, apperror.BadRequest)
+		})
+
+		It("should reject accepted invitation", func() {
+			acceptedAt := time.Now()
+			invitation.AcceptedAt = &acceptedAt
+			mockRepo.EXPECT().GetInvitationByTokenHash(ctx, gomock.Any()).Return(invitation, nil)
+
+			err := service.AcceptInvitation(ctx, "token", 20, "staff@shop.com")
+
+			expectAppErrorCode(err, apperror.Conflict)
+		})
+	})
+
+	Describe("RemoveMember", func() {
+		It("should let agent leave the shop", func() {
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(1), uint(30)).Return(entity.ShopRoleAgent, nil).Times(2)
+			mockRepo.EXPECT().DeleteMember(ctx, uint(1), uint(30)).Return(nil)
+
+			err := service.RemoveMember(ctx, 1, 30, 30)
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should not let agent remove others", func() {
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(1), uint(30)).Return(entity.ShopRoleAgent, nil)
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(1), uint(31)).Return(entity.ShopRoleAgent, nil)
+
+			err := service.RemoveMember(ctx, 1, 30, 31)
+
+			expectAppErrorCode(err, apperror.Forbidden)
+		})
+
+		It("should never remove the owner", func() {
+			mockRepo.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleOwner, nil).Times(2)
+
+			err := service.RemoveMember(ctx, 1, 10, 10)
+
+			expectAppErrorCode(err, apperror.BadRequest)
+		})
+	})
+})
@@ -0,0 +1,139 @@
+package sociallogin
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=sociallogin_mock_test.go -package=sociallogin Provider Repository UserService
+
+// Provider это внешний OIDC провайдер с authorization code flow и PKCE
+type Provider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (entity.ExternalIdentity, error)
+}
+
+type Repository interface {
+	InsertState(ctx context.Context, state entity.OIDCLoginState) error
+	UseState(ctx context.Context, stateHash, provider string) (entity.OIDCLoginState, error)
+}
+
+// UserService выполняет вход пользователя по проверенной внешней учетной записи
+type UserService interface {
+	AuthenticateExternal(
+		ctx context.Context,
+		identity entity.ExternalIdentity,
+		client entity.ClientInfo,
+	) (user.LoginResult, error)
+}
+
+// Service ведет вход через внешних провайдеров: запоминает state, nonce и PKCE code_verifier
+// при переходе к провайдеру и проверяет их, когда клиент возвращается с кодом.
+// Начатый вход привязан к fingerprint клиента, поэтому чужой code и state не подсунуть.
+type Service struct {
+	providers   map[string]Provider
+	repository  Repository
+	userService UserService
+	stateTTL    time.Duration
+}
+
+func NewService(
+	providers map[string]Provider,
+	repository Repository,
+	userService UserService,
+	cfg *config.OIDCConfig,
+) *Service {
+	return &Service{
+		providers:   providers,
+		repository:  repository,
+		userService: userService,
+		stateTTL:    cfg.StateTTL,
+	}
+}
+
+// Providers возвращает имена настроенных провайдеров
+func (s *Service) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Begin начинает вход через провайдера и возвращает адрес его страницы входа
+func (s *Service) Begin(ctx context.Context, providerName, fingerprint string) (string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", apperror.ErrIdentityProviderNotFound
+	}
+
+	state, stateHash, err := security.GenerateOpaqueToken()
+	if err != nil {
+		return "", apperror.New(apperror.InternalError, "failed to generate state", err)
+	}
+	nonce, _, err := security.GenerateOpaqueToken()
+	if err != nil {
+		return "", apperror.New(apperror.InternalError, "failed to generate nonce", err)
+	}
+	verifier, _, err := security.GenerateOpaqueToken()
+	if err != nil {
+		return "", apperror.New(apperror.InternalError, "failed to generate code verifier", err)
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return "", apperror.New(apperror.InternalError, "identity provider is unavailable", err)
+	}
+
+	err = s.repository.InsertState(ctx, entity.OIDCLoginState{
+		StateHash:    stateHash,
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		Fingerprint:  fingerprint,
+		ExpiresAt:    time.Now().Add(s.stateTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// Complete завершает вход: гасит state, обменивает код на ID токен и входит как связанный пользователь
+func (s *Service) Complete(
+	ctx context.Context,
+	providerName,
+	state,
+	code string,
+	client entity.ClientInfo,
+) (user.LoginResult, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return user.LoginResult{}, apperror.ErrIdentityProviderNotFound
+	}
+
+	loginState, err := s.repository.UseState(ctx, security.HashOpaqueToken(state), providerName)
+	if err != nil {
+		return user.LoginResult{}, err
+	}
+	if loginState.Fingerprint != client.Fingerprint {
+		return user.LoginResult{}, apperror.ErrInvalidFingerprint
+	}
+
+	identity, err := provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return user.LoginResult{}, apperror.New(apperror.Unauthorized, "external login failed", err)
+	}
+	identity.Provider = providerName
+
+	return s.userService.AuthenticateExternal(ctx, identity, client)
+}
@@ -0,0 +1,13 @@
+package sociallogin
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSocialLogin(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Social Login Service Suite")
+}
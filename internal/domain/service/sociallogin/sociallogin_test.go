@@ -0,0 +1,174 @@
+package sociallogin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/adapter/oidc"
+	"github.com/EM-Stawberry/Stawberry/internal/adapter/oidc/oidctest"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Service", func() {
+	var (
+		ctrl            *gomock.Controller
+		mockProvider    *MockProvider
+		mockRepo        *MockRepository
+		mockUserService *MockUserService
+		service         *Service
+		ctx             context.Context
+		client          entity.ClientInfo
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockProvider = NewMockProvider(ctrl)
+		mockRepo = NewMockRepository(ctrl)
+		mockUserService = NewMockUserService(ctrl)
+		service = NewService(map[string]Provider{"local": mockProvider}, mockRepo, mockUserService,
+			&config.OIDCConfig{StateTTL: 10 * time.Minute})
+		ctx = context.Background()
+		client = entity.ClientInfo{Fingerprint: "fp", IP: "192.0.2.1"}
+	})
+
+	Describe("Begin", func() {
+		It("should store the state with the verifier sent to the provider", func() {
+			var sentState, sentNonce, sentVerifier string
+			mockProvider.EXPECT().AuthCodeURL(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, state, nonce, verifier string) (string, error) {
+					sentState, sentNonce, sentVerifier = state, nonce, verifier
+					return "https://idp/authorize", nil
+				})
+			mockRepo.EXPECT().InsertState(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, state entity.OIDCLoginState) error {
+					Expect(state.StateHash).To(Equal(security.HashOpaqueToken(sentState)))
+					Expect(state.Nonce).To(Equal(sentNonce))
+					Expect(state.CodeVerifier).To(Equal(sentVerifier))
+					Expect(state.Fingerprint).To(Equal("fp"))
+					Expect(state.Provider).To(Equal("local"))
+					Expect(state.ExpiresAt).To(BeTemporally("~", time.Now().Add(10*time.Minute), time.Second))
+					return nil
+				})
+
+			authURL, err := service.Begin(ctx, "local", "fp")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(authURL).To(Equal("https://idp/authorize"))
+		})
+
+		It("should reject an unknown provider", func() {
+			_, err := service.Begin(ctx, "unknown", "fp")
+
+			Expect(err).To(Equal(apperror.ErrIdentityProviderNotFound))
+		})
+	})
+
+	Describe("Complete", func() {
+		state := "state"
+		loginState := entity.OIDCLoginState{
+			Provider:     "local",
+			Nonce:        "nonce",
+			CodeVerifier: "verifier",
+			Fingerprint:  "fp",
+		}
+
+		It("should log in with the identity returned by the provider", func() {
+			mockRepo.EXPECT().UseState(ctx, security.HashOpaqueToken(state), "local").Return(loginState, nil)
+			mockProvider.EXPECT().Exchange(ctx, "code", "verifier", "nonce").
+				Return(entity.ExternalIdentity{Subject: "42", Email: "user@example.com", EmailVerified: true}, nil)
+			mockUserService.EXPECT().AuthenticateExternal(ctx, entity.ExternalIdentity{
+				Provider:      "local",
+				Subject:       "42",
+				Email:         "user@example.com",
+				EmailVerified: true,
+			}, client).Return(user.LoginResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+
+			result, err := service.Complete(ctx, "local", state, "code", client)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.AccessToken).To(Equal("access"))
+		})
+
+		It("should reject a state started by another client", func() {
+			mockRepo.EXPECT().UseState(ctx, security.HashOpaqueToken(state), "local").Return(loginState, nil)
+
+			_, err := service.Complete(ctx, "local", state, "code", entity.ClientInfo{Fingerprint: "other"})
+
+			Expect(err).To(Equal(apperror.ErrInvalidFingerprint))
+		})
+
+		It("should reject a failed code exchange", func() {
+			mockRepo.EXPECT().UseState(ctx, security.HashOpaqueToken(state), "local").Return(loginState, nil)
+			mockProvider.EXPECT().Exchange(ctx, "code", "verifier", "nonce").
+				Return(entity.ExternalIdentity{}, errors.New("invalid_grant"))
+
+			_, err := service.Complete(ctx, "local", state, "code", client)
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.Unauthorized))
+		})
+	})
+
+	Describe("with the local test provider", func() {
+		It("should complete the whole flow offline", func() {
+			server := oidctest.NewServer("client", "secret", oidctest.User{
+				Subject:       "42",
+				Email:         "user@example.com",
+				EmailVerified: true,
+			})
+			DeferCleanup(server.Close)
+
+			provider := oidc.NewProvider(config.OIDCProviderConfig{
+				Issuer:       server.URL,
+				ClientID:     "client",
+				ClientSecret: "secret",
+				RedirectURL:  "http://app.local/callback",
+				Scopes:       []string{"openid", "email"},
+			}, http.DefaultClient)
+			service = NewService(map[string]Provider{"local": provider}, mockRepo, mockUserService,
+				&config.OIDCConfig{StateTTL: time.Minute})
+
+			var stored entity.OIDCLoginState
+			mockRepo.EXPECT().InsertState(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, state entity.OIDCLoginState) error {
+					stored = state
+					return nil
+				})
+
+			authURL, err := service.Begin(ctx, "local", "fp")
+			Expect(err).NotTo(HaveOccurred())
+
+			code, state, err := server.Authorize(authURL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(url.QueryEscape(state)).NotTo(BeEmpty())
+
+			mockRepo.EXPECT().UseState(ctx, security.HashOpaqueToken(state), "local").Return(stored, nil)
+			mockUserService.EXPECT().AuthenticateExternal(ctx, gomock.Any(), client).
+				DoAndReturn(func(
+					_ context.Context,
+					identity entity.ExternalIdentity,
+					_ entity.ClientInfo,
+				) (user.LoginResult, error) {
+					Expect(identity.Provider).To(Equal("local"))
+					Expect(identity.Subject).To(Equal("42"))
+					return user.LoginResult{AccessToken: "access"}, nil
+				})
+
+			result, err := service.Complete(ctx, "local", state, code, client)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.AccessToken).To(Equal("access"))
+		})
+	})
+})
@@ -10,9 +10,7 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 )
 
-//go:generate mockgen -source=$GOFILE -destination=token_mock_test.go -package=token Repository
-
-//go:generate mockgen -source=$GOFILE -destination=token_mock_test.go -package=token Repository
+//go:generate mockgen -source=$GOFILE -destination=token_mock_test.go -package=token Repository, JWTManager, Denylist
 
 type Repository interface {
 	InsertToken(ctx context.Context, token entity.RefreshToken) error
@@ -21,24 +19,41 @@ type Repository interface {
 	GetByUUID(ctx context.Context, uuid string) (entity.RefreshToken, error)
 	Update(ctx context.Context, refresh entity.RefreshToken) (entity.RefreshToken, error)
 	CleanExpired(ctx context.Context, userID uint, retain uint) error
+	Rotate(ctx context.Context, oldUUID uuid.UUID, token entity.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	GetIssuedSinceByUserID(ctx context.Context, userID uint, since time.Time) ([]entity.RefreshToken, error)
+	GetIssuedSinceByFamily(ctx context.Context, familyID uuid.UUID, since time.Time) ([]entity.RefreshToken, error)
+}
+
+// Denylist хранит jti отозванных access токенов до истечения их срока
+type Denylist interface {
+	Add(ctx context.Context, tokens ...entity.RevokedAccessToken) error
+	Contains(ctx context.Context, jti string) (bool, error)
 }
 
 type JWTManager interface {
-	Generate(userID uint, duration time.Duration) (string, error)
+	Generate(userID uint, role entity.Role, duration time.Duration) (string, entity.AccessToken, error)
 	Parse(token string) (entity.AccessToken, error)
 }
 
 type Service struct {
 	tokenRepository Repository
 	jwtManager      JWTManager
+	denylist        Denylist
 	refreshLife     time.Duration
 	accessLife      time.Duration
 }
 
-func NewService(tokenRepo Repository, jwtManager JWTManager, refreshLife, accessLife time.Duration) *Service {
+func NewService(
+	tokenRepo Repository,
+	jwtManager JWTManager,
+	denylist Denylist,
+	refreshLife, accessLife time.Duration,
+) *Service {
 	return &Service{
 		tokenRepository: tokenRepo,
 		jwtManager:      jwtManager,
+		denylist:        denylist,
 		refreshLife:     refreshLife,
 		accessLife:      accessLife,
 	}
@@ -49,13 +64,14 @@ func (ts *Service) GenerateTokens(
 	ctx context.Context,
 	fingerprint string,
 	userID uint,
+	role entity.Role,
 ) (string, entity.RefreshToken, error) {
 
 	if ctx.Err() != nil {
 		return "", entity.RefreshToken{}, ctx.Err()
 	}
 
-	accessToken, err := ts.jwtManager.Generate(userID, ts.accessLife)
+	accessToken, access, err := ts.jwtManager.Generate(userID, role, ts.accessLife)
 	if err != nil {
 		return "", entity.RefreshToken{}, err
 	}
@@ -68,6 +84,7 @@ func (ts *Service) GenerateTokens(
 	if err != nil {
 		return "", entity.RefreshToken{}, err
 	}
+	entityRefreshToken.AccessJTI = access.ID
 
 	return accessToken, entityRefreshToken, nil
 }
@@ -91,6 +108,14 @@ func (ts *Service) ValidateToken(
 		return entity.AccessToken{}, apperror.ErrInvalidToken
 	}
 
+	revoked, err := ts.denylist.Contains(ctx, accessToken.ID)
+	if err != nil {
+		return entity.AccessToken{}, err
+	}
+	if revoked {
+		return entity.AccessToken{}, apperror.ErrAccessTokenRevoked
+	}
+
 	return accessToken, nil
 }
 
@@ -120,6 +145,25 @@ func (ts *Service) RevokeActivesByUserID(
 	return ts.tokenRepository.RevokeActivesByUserID(ctx, userID, retainActive)
 }
 
+// RevokeAllByUserID аннулирует все активные токены обновления пользователя, не сохраняя последние сессии,
+// и отзывает еще не истекшие access токены этих сессий.
+// Используется, когда все сессии могли быть скомпрометированы, например при сбросе пароля.
+func (ts *Service) RevokeAllByUserID(
+	ctx context.Context,
+	userID uint,
+) error {
+	if err := ts.tokenRepository.RevokeActivesByUserID(ctx, userID, 0); err != nil {
+		return err
+	}
+
+	issued, err := ts.tokenRepository.GetIssuedSinceByUserID(ctx, userID, time.Now().Add(-ts.accessLife))
+	if err != nil {
+		return err
+	}
+
+	return ts.denyAccess(ctx, issued)
+}
+
 // retainExpired определяет количество отозванных и устаревших токенов, которые
 // сохраняются в базе при вызове CleanUpExpiredByUserID
 const retainExpired = 5
@@ -132,6 +176,49 @@ func (ts *Service) CleanUpExpiredByUserID(
 	return ts.tokenRepository.CleanExpired(ctx, userID, retainExpired)
 }
 
+// Rotate отзывает старый refresh токен и сохраняет выпущенный взамен
+func (ts *Service) Rotate(
+	ctx context.Context,
+	oldUUID uuid.UUID,
+	token entity.RefreshToken,
+) error {
+	return ts.tokenRepository.Rotate(ctx, oldUUID, token)
+}
+
+// RevokeFamily отзывает все токены, выпущенные по цепочке обновлений от одного входа,
+// вместе с еще не истекшими access токенами этой сессии
+func (ts *Service) RevokeFamily(
+	ctx context.Context,
+	familyID uuid.UUID,
+) error {
+	if err := ts.tokenRepository.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+
+	issued, err := ts.tokenRepository.GetIssuedSinceByFamily(ctx, familyID, time.Now().Add(-ts.accessLife))
+	if err != nil {
+		return err
+	}
+
+	return ts.denyAccess(ctx, issued)
+}
+
+// denyAccess заносит в список отозванных access токены, выпущенные вместе с refresh токенами
+func (ts *Service) denyAccess(ctx context.Context, issued []entity.RefreshToken) error {
+	revoked := make([]entity.RevokedAccessToken, 0, len(issued))
+	for _, t := range issued {
+		if t.AccessJTI == "" {
+			continue
+		}
+		revoked = append(revoked, entity.RevokedAccessToken{
+			JTI:       t.AccessJTI,
+			ExpiresAt: t.CreatedAt.Add(ts.accessLife),
+		})
+	}
+
+	return ts.denylist.Add(ctx, revoked...)
+}
+
 func (ts *Service) GetByUUID(
 	ctx context.Context,
 	uuid string,
@@ -147,7 +234,8 @@ func (ts *Service) Update(
 }
 
 // generateRefresh создает новый refresh токен обновления с указанным
-// userID, fingerprint и сроком действия.
+// userID, fingerprint и сроком действия. Токен открывает новую семью,
+// при обновлении сессии вызывающая сторона переносит в него FamilyID старого токена.
 func generateRefresh(fingerprint string, userID uint, refreshLife time.Duration) (entity.RefreshToken, error) {
 	now := time.Now()
 
@@ -163,5 +251,6 @@ func generateRefresh(fingerprint string, userID uint, refreshLife time.Duration)
 		RevokedAt:   nil,
 		Fingerprint: fingerprint,
 		UserID:      userID,
+		FamilyID:    refreshUUID,
 	}, nil
 }
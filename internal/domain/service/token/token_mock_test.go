@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=token.go -destination=token_mock_test.go -package=token Repository
+//	mockgen -source=token.go -destination=token_mock_test.go -package=token Repository, JWTManager, Denylist
 //
 
 // Package token is a generated GoMock package.
@@ -15,6 +15,7 @@ import (
 	time "time"
 
 	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -72,18 +73,48 @@ func (mr *MockRepositoryMockRecorder) GetActivesTokenByUserID(ctx, userID any) *
 }
 
 // GetByUUID mocks base method.
-func (m *MockRepository) GetByUUID(ctx context.Context, uuid string) (entity.RefreshToken, error) {
+func (m *MockRepository) GetByUUID(ctx context.Context, arg1 string) (entity.RefreshToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUUID", ctx, uuid)
+	ret := m.ctrl.Call(m, "GetByUUID", ctx, arg1)
 	ret0, _ := ret[0].(entity.RefreshToken)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByUUID indicates an expected call of GetByUUID.
-func (mr *MockRepositoryMockRecorder) GetByUUID(ctx, uuid any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetByUUID(ctx, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUUID", reflect.TypeOf((*MockRepository)(nil).GetByUUID), ctx, uuid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUUID", reflect.TypeOf((*MockRepository)(nil).GetByUUID), ctx, arg1)
+}
+
+// GetIssuedSinceByFamily mocks base method.
+func (m *MockRepository) GetIssuedSinceByFamily(ctx context.Context, familyID uuid.UUID, since time.Time) ([]entity.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIssuedSinceByFamily", ctx, familyID, since)
+	ret0, _ := ret[0].([]entity.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIssuedSinceByFamily indicates an expected call of GetIssuedSinceByFamily.
+func (mr *MockRepositoryMockRecorder) GetIssuedSinceByFamily(ctx, familyID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssuedSinceByFamily", reflect.TypeOf((*MockRepository)(nil).GetIssuedSinceByFamily), ctx, familyID, since)
+}
+
+// GetIssuedSinceByUserID mocks base method.
+func (m *MockRepository) GetIssuedSinceByUserID(ctx context.Context, userID uint, since time.Time) ([]entity.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIssuedSinceByUserID", ctx, userID, since)
+	ret0, _ := ret[0].([]entity.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIssuedSinceByUserID indicates an expected call of GetIssuedSinceByUserID.
+func (mr *MockRepositoryMockRecorder) GetIssuedSinceByUserID(ctx, userID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssuedSinceByUserID", reflect.TypeOf((*MockRepository)(nil).GetIssuedSinceByUserID), ctx, userID, since)
 }
 
 // InsertToken mocks base method.
@@ -114,6 +145,34 @@ func (mr *MockRepositoryMockRecorder) RevokeActivesByUserID(ctx, userID, retain
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeActivesByUserID", reflect.TypeOf((*MockRepository)(nil).RevokeActivesByUserID), ctx, userID, retain)
 }
 
+// RevokeFamily mocks base method.
+func (m *MockRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockRepositoryMockRecorder) RevokeFamily(ctx, familyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockRepository)(nil).RevokeFamily), ctx, familyID)
+}
+
+// Rotate mocks base method.
+func (m *MockRepository) Rotate(ctx context.Context, oldUUID uuid.UUID, token entity.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx, oldUUID, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockRepositoryMockRecorder) Rotate(ctx, oldUUID, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockRepository)(nil).Rotate), ctx, oldUUID, token)
+}
+
 // Update mocks base method.
 func (m *MockRepository) Update(ctx context.Context, refresh entity.RefreshToken) (entity.RefreshToken, error) {
 	m.ctrl.T.Helper()
@@ -129,6 +188,64 @@ func (mr *MockRepositoryMockRecorder) Update(ctx, refresh any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, refresh)
 }
 
+// MockDenylist is a mock of Denylist interface.
+type MockDenylist struct {
+	ctrl     *gomock.Controller
+	recorder *MockDenylistMockRecorder
+	isgomock struct{}
+}
+
+// MockDenylistMockRecorder is the mock recorder for MockDenylist.
+type MockDenylistMockRecorder struct {
+	mock *MockDenylist
+}
+
+// NewMockDenylist creates a new mock instance.
+func NewMockDenylist(ctrl *gomock.Controller) *MockDenylist {
+	mock := &MockDenylist{ctrl: ctrl}
+	mock.recorder = &MockDenylistMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDenylist) EXPECT() *MockDenylistMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockDenylist) Add(ctx context.Context, tokens ...entity.RevokedAccessToken) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range tokens {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Add", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockDenylistMockRecorder) Add(ctx any, tokens ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, tokens...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockDenylist)(nil).Add), varargs...)
+}
+
+// Contains mocks base method.
+func (m *MockDenylist) Contains(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Contains", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Contains indicates an expected call of Contains.
+func (mr *MockDenylistMockRecorder) Contains(ctx, jti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Contains", reflect.TypeOf((*MockDenylist)(nil).Contains), ctx, jti)
+}
+
 // MockJWTManager is a mock of JWTManager interface.
 type MockJWTManager struct {
 	ctrl     *gomock.Controller
@@ -154,18 +271,19 @@ func (m *MockJWTManager) EXPECT() *MockJWTManagerMockRecorder {
 }
 
 // Generate mocks base method.
-func (m *MockJWTManager) Generate(userID uint, duration time.Duration) (string, error) {
+func (m *MockJWTManager) Generate(userID uint, role entity.Role, duration time.Duration) (string, entity.AccessToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Generate", userID, duration)
+	ret := m.ctrl.Call(m, "Generate", userID, role, duration)
 	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(entity.AccessToken)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Generate indicates an expected call of Generate.
-func (mr *MockJWTManagerMockRecorder) Generate(userID, duration any) *gomock.Call {
+func (mr *MockJWTManagerMockRecorder) Generate(userID, role, duration any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockJWTManager)(nil).Generate), userID, duration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockJWTManager)(nil).Generate), userID, role, duration)
 }
 
 // Parse mocks base method.
@@ -25,6 +25,7 @@ var _ = Describe("TokenService", func() {
 		ctrl        *gomock.Controller
 		repo        *MockRepository
 		jwtManager  *MockJWTManager
+		denylist    *MockDenylist
 		service     *Service
 		refreshLife time.Duration
 		accessLife  time.Duration
@@ -38,7 +39,8 @@ var _ = Describe("TokenService", func() {
 
 		repo = NewMockRepository(ctrl)
 		jwtManager = NewMockJWTManager(ctrl)
-		service = NewService(repo, jwtManager, refreshLife, accessLife)
+		denylist = NewMockDenylist(ctrl)
+		service = NewService(repo, jwtManager, denylist, refreshLife, accessLife)
 	})
 
 	AfterEach(func() {
@@ -62,17 +64,20 @@ var _ = Describe("TokenService", func() {
 					mockJWT := "mock.jwt.token"
 
 					jwtManager.EXPECT().
-						Generate(userID, accessLife).
-						Return(mockJWT, nil).
+						Generate(userID, entity.RoleUser, accessLife).
+						Return(mockJWT, entity.AccessToken{ID: "jti-1"}, nil).
 						Times(1)
 
-					accessToken, refreshToken, err := service.GenerateTokens(context.Background(), fingerprint, userID)
+					accessToken, refreshToken, err := service.GenerateTokens(
+						context.Background(), fingerprint, userID, entity.RoleUser)
 
 					Expect(err).NotTo(HaveOccurred())
 					Expect(accessToken).To(Equal(mockJWT))
 					Expect(refreshToken.UUID).NotTo(BeEmpty())
 					Expect(refreshToken.Fingerprint).To(Equal(fingerprint))
 					Expect(refreshToken.UserID).To(Equal(userID))
+					Expect(refreshToken.FamilyID).To(Equal(refreshToken.UUID))
+					Expect(refreshToken.AccessJTI).To(Equal("jti-1"))
 				})
 			})
 
@@ -83,11 +88,12 @@ var _ = Describe("TokenService", func() {
 					mockJWTErr := fmt.Errorf("jwt error")
 
 					jwtManager.EXPECT().
-						Generate(userID, accessLife).
-						Return("", mockJWTErr).
+						Generate(userID, entity.RoleUser, accessLife).
+						Return("", entity.AccessToken{}, mockJWTErr).
 						Times(1)
 
-					accessToken, refreshToken, err := service.GenerateTokens(context.Background(), fingerprint, userID)
+					accessToken, refreshToken, err := service.GenerateTokens(
+						context.Background(), fingerprint, userID, entity.RoleUser)
 
 					Expect(err).To(HaveOccurred())
 
@@ -105,12 +111,14 @@ var _ = Describe("TokenService", func() {
 				It("should return the access token entity", func() {
 					validToken := "valid-token"
 					expectedAccessToken := entity.AccessToken{
+						ID:        "jti-1",
 						UserID:    1,
 						IssuedAt:  time.Now(),
 						ExpiresAt: time.Now().Add(time.Hour),
 					}
 
 					jwtManager.EXPECT().Parse(validToken).Return(expectedAccessToken, nil).Times(1)
+					denylist.EXPECT().Contains(gomock.Any(), "jti-1").Return(false, nil).Times(1)
 
 					accessToken, err := service.ValidateToken(context.Background(), validToken)
 
@@ -119,6 +127,26 @@ var _ = Describe("TokenService", func() {
 				})
 			})
 
+			When("the token is revoked", func() {
+				It("should return ErrAccessTokenRevoked", func() {
+					revokedToken := "revoked-token"
+					revokedAccessToken := entity.AccessToken{
+						ID:        "jti-2",
+						UserID:    1,
+						IssuedAt:  time.Now(),
+						ExpiresAt: time.Now().Add(time.Hour),
+					}
+
+					jwtManager.EXPECT().Parse(revokedToken).Return(revokedAccessToken, nil).Times(1)
+					denylist.EXPECT().Contains(gomock.Any(), "jti-2").Return(true, nil).Times(1)
+
+					accessToken, err := service.ValidateToken(context.Background(), revokedToken)
+
+					Expect(err).To(MatchError(apperror.ErrAccessTokenRevoked))
+					Expect(accessToken).To(Equal(entity.AccessToken{}))
+				})
+			})
+
 			When("the token is expired", func() {
 				It("should return ErrInvalidToken", func() {
 					expiredToken := "expired-token"
@@ -243,6 +271,46 @@ var _ = Describe("TokenService", func() {
 			})
 		})
 
+		Describe("RevokeAllByUserID", func() {
+			It("should revoke active tokens without retaining recent sessions and deny their access tokens", func() {
+				userID := uint(1)
+				refreshToken.AccessJTI = "jti-1"
+				legacy := entity.RefreshToken{UUID: uuid.New(), CreatedAt: time.Now(), UserID: userID}
+
+				repo.EXPECT().RevokeActivesByUserID(ctx, userID, uint(0)).Return(nil).Times(1)
+				repo.EXPECT().GetIssuedSinceByUserID(ctx, userID, gomock.Any()).
+					Return([]entity.RefreshToken{refreshToken, legacy}, nil).Times(1)
+				denylist.EXPECT().Add(ctx, entity.RevokedAccessToken{
+					JTI:       "jti-1",
+					ExpiresAt: refreshToken.CreatedAt.Add(accessLife),
+				}).Return(nil).Times(1)
+
+				Expect(service.RevokeAllByUserID(ctx, userID)).To(Succeed())
+			})
+		})
+
+		Describe("RevokeFamily", func() {
+			It("should revoke the family of the token and deny its access tokens", func() {
+				refreshToken.AccessJTI = "jti-1"
+
+				repo.EXPECT().RevokeFamily(ctx, refreshToken.FamilyID).Return(nil).Times(1)
+				repo.EXPECT().GetIssuedSinceByFamily(ctx, refreshToken.FamilyID, gomock.Any()).
+					Return([]entity.RefreshToken{refreshToken}, nil).Times(1)
+				denylist.EXPECT().Add(ctx, gomock.Any()).Return(nil).Times(1)
+
+				Expect(service.RevokeFamily(ctx, refreshToken.FamilyID)).To(Succeed())
+			})
+
+			When("the repository fails to revoke the family", func() {
+				It("should not touch the denylist", func() {
+					mockErr := fmt.Errorf("db revoke error")
+					repo.EXPECT().RevokeFamily(ctx, refreshToken.FamilyID).Return(mockErr).Times(1)
+
+					Expect(service.RevokeFamily(ctx, refreshToken.FamilyID)).To(MatchError(mockErr))
+				})
+			})
+		})
+
 		Describe("GetByUUID", func() {
 			It("should call the repository's GetByUUID method and return the token", func() {
 				uuidStr := refreshToken.UUID.String()
@@ -5,7 +5,6 @@ type User struct {
 	Password string
 	Email    string
 	Phone    string
-	IsStore  bool
 }
 
 type UpdateUser struct {
@@ -13,3 +12,11 @@ type UpdateUser struct {
 	Password string
 	Email    string
 }
+
+// LoginResult это итог входа по паролю: пара токенов или, если подключен второй фактор,
+// одноразовый токен для второго шага входа
+type LoginResult struct {
+	AccessToken  string
+	RefreshToken string
+	MFAChallenge string
+}
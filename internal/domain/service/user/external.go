@@ -0,0 +1,80 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// AuthenticateExternal выполняет вход по учетной записи внешнего провайдера, проверенной по ID токену.
+// Второй фактор, если он подключен, требуется так же, как при входе по паролю.
+func (us *Service) AuthenticateExternal(
+	ctx context.Context,
+	identity entity.ExternalIdentity,
+	client entity.ClientInfo,
+) (LoginResult, error) {
+	user, err := us.externalUser(ctx, identity)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	if user.BannedAt != nil {
+		return LoginResult{}, apperror.ErrUserBanned
+	}
+
+	if user.MFAEnabled() {
+		return us.mfaChallenge(ctx, user)
+	}
+
+	accessToken, refreshToken, err := us.startSession(ctx, user, client)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	return LoginResult{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// externalUser находит пользователя, связанного с внешней учетной записью.
+// Новая учетная запись связывается с пользователем с той же почтой, только если почту подтвердили
+// и провайдер, и сам пользователь: иначе чужой аккаунт, заранее зарегистрированный на эту почту,
+// получил бы вход через провайдера. Если такого пользователя нет, он создается.
+func (us *Service) externalUser(ctx context.Context, identity entity.ExternalIdentity) (entity.User, error) {
+	userID, err := us.userRepository.TouchIdentity(ctx, identity)
+	if err == nil {
+		return us.userRepository.GetUserByID(ctx, userID)
+	}
+	if !errors.Is(err, apperror.ErrUserNotFound) {
+		return entity.User{}, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return entity.User{}, apperror.New(apperror.BadRequest, "identity provider did not confirm the email", nil)
+	}
+
+	user, err := us.userRepository.GetUser(ctx, identity.Email)
+	switch {
+	case err == nil:
+		if user.EmailVerifiedAt == nil {
+			return entity.User{}, apperror.New(apperror.Conflict,
+				"email is registered but not verified, log in with password and verify it first", nil)
+		}
+		if err = us.userRepository.LinkIdentity(ctx, user.ID, identity); err != nil {
+			return entity.User{}, err
+		}
+		return user, nil
+	case errors.Is(err, apperror.ErrUserNotFound):
+		if identity.Name == "" {
+			identity.Name, _, _ = strings.Cut(identity.Email, "@")
+		}
+		userID, err = us.userRepository.InsertExternalUser(ctx, identity)
+		if err != nil {
+			return entity.User{}, err
+		}
+		return us.userRepository.GetUserByID(ctx, userID)
+	default:
+		return entity.User{}, err
+	}
+}
@@ -0,0 +1,151 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("UserService AuthenticateExternal", func() {
+	var (
+		ctrl             *gomock.Controller
+		mockRepo         *MockRepository
+		mockTokenService *MockTokenService
+		userService      *Service
+		ctx              context.Context
+		client           entity.ClientInfo
+		identity         entity.ExternalIdentity
+		testUser         entity.User
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockTokenService = NewMockTokenService(ctrl)
+		userService = NewService(mockRepo, mockTokenService, NewMockPasswordManager(ctrl),
+			mock_email.NewMockMailerService(ctrl), NewMockSecurityLog(ctrl), NewMockLoginGuard(ctrl),
+			&config.AccountConfig{MFAChallengeTTL: 5 * time.Minute})
+		ctx = context.Background()
+		client = entity.ClientInfo{Fingerprint: "fp", IP: "192.0.2.1"}
+
+		identity = entity.ExternalIdentity{
+			Provider:      "local",
+			Subject:       "42",
+			Email:         "test@example.com",
+			EmailVerified: true,
+		}
+		verifiedAt := time.Now()
+		testUser = entity.User{
+			ID:              1,
+			Email:           "test@example.com",
+			Role:            entity.RoleUser,
+			EmailVerifiedAt: &verifiedAt,
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	expectSession := func() {
+		mockTokenService.EXPECT().RevokeActivesByUserID(ctx, testUser.ID).Return(nil)
+		mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, testUser.ID).Return(nil)
+		mockTokenService.EXPECT().GenerateTokens(ctx, client.Fingerprint, testUser.ID, testUser.Role).
+			Return("access", entity.RefreshToken{}, nil)
+		mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+	}
+
+	Context("when identity is already linked", func() {
+		It("should log in the linked user", func() {
+			mockRepo.EXPECT().TouchIdentity(ctx, identity).Return(testUser.ID, nil)
+			mockRepo.EXPECT().GetUserByID(ctx, testUser.ID).Return(testUser, nil)
+			expectSession()
+
+			result, err := userService.AuthenticateExternal(ctx, identity, client)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.AccessToken).To(Equal("access"))
+		})
+
+		It("should require the second factor when it is enabled", func() {
+			enabledAt := time.Now()
+			testUser.TOTPSecret = "secret"
+			testUser.TOTPEnabledAt = &enabledAt
+			mockRepo.EXPECT().TouchIdentity(ctx, identity).Return(testUser.ID, nil)
+			mockRepo.EXPECT().GetUserByID(ctx, testUser.ID).Return(testUser, nil)
+			mockRepo.EXPECT().InsertActionToken(ctx, gomock.Any()).Return(nil)
+
+			result, err := userService.AuthenticateExternal(ctx, identity, client)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.MFAChallenge).ToNot(BeEmpty())
+			Expect(result.AccessToken).To(BeEmpty())
+		})
+	})
+
+	Context("when a user with the same email exists", func() {
+		BeforeEach(func() {
+			mockRepo.EXPECT().TouchIdentity(ctx, identity).Return(uint(0), apperror.ErrUserNotFound)
+		})
+
+		It("should link the identity if the email is verified", func() {
+			mockRepo.EXPECT().GetUser(ctx, identity.Email).Return(testUser, nil)
+			mockRepo.EXPECT().LinkIdentity(ctx, testUser.ID, identity).Return(nil)
+			expectSession()
+
+			result, err := userService.AuthenticateExternal(ctx, identity, client)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.AccessToken).To(Equal("access"))
+		})
+
+		It("should not link the identity if the local email is not verified", func() {
+			testUser.EmailVerifiedAt = nil
+			mockRepo.EXPECT().GetUser(ctx, identity.Email).Return(testUser, nil)
+
+			_, err := userService.AuthenticateExternal(ctx, identity, client)
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.Conflict))
+		})
+	})
+
+	Context("when identity is new", func() {
+		BeforeEach(func() {
+			mockRepo.EXPECT().TouchIdentity(ctx, gomock.Any()).Return(uint(0), apperror.ErrUserNotFound)
+		})
+
+		It("should create a user named after the email", func() {
+			mockRepo.EXPECT().GetUser(ctx, identity.Email).Return(entity.User{}, apperror.ErrUserNotFound)
+			created := identity
+			created.Name = "test"
+			mockRepo.EXPECT().InsertExternalUser(ctx, created).Return(testUser.ID, nil)
+			mockRepo.EXPECT().GetUserByID(ctx, testUser.ID).Return(testUser, nil)
+			expectSession()
+
+			_, err := userService.AuthenticateExternal(ctx, identity, client)
+
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should reject an email the provider did not verify", func() {
+			identity.EmailVerified = false
+
+			_, err := userService.AuthenticateExternal(ctx, identity, client)
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+	})
+})
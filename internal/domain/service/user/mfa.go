@@ -0,0 +1,224 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
+)
+
+// EnrollTOTP начинает подключение второго фактора: создает секрет и otpauth URI для приложения.
+// Второй фактор начинает действовать только после подтверждения кодом в ConfirmTOTP.
+func (us *Service) EnrollTOTP(ctx context.Context, userID uint) (entity.TOTPEnrollment, error) {
+	user, err := us.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return entity.TOTPEnrollment{}, err
+	}
+	if user.MFAEnabled() {
+		return entity.TOTPEnrollment{}, apperror.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		return entity.TOTPEnrollment{}, apperror.New(apperror.InternalError, "failed to generate totp secret", err)
+	}
+
+	if err = us.userRepository.SetTOTPSecret(ctx, userID, secret); err != nil {
+		return entity.TOTPEnrollment{}, err
+	}
+
+	return entity.TOTPEnrollment{
+		Secret: secret,
+		URI:    security.TOTPURI(us.accountCfg.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP включает второй фактор после проверки кода из приложения
+// и возвращает резервные коды. Они показываются только один раз.
+func (us *Service) ConfirmTOTP(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := us.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled() {
+		return nil, apperror.ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, apperror.New(apperror.Conflict, "two-factor authentication enrollment is not started", nil)
+	}
+
+	step, ok := security.ValidateTOTP(user.TOTPSecret, strings.TrimSpace(code), time.Now(), 0)
+	if !ok {
+		return nil, apperror.ErrInvalidMFACode
+	}
+
+	codes, hashes, err := us.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = us.userRepository.EnableTOTP(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// DisableTOTP отключает второй фактор после проверки пароля
+func (us *Service) DisableTOTP(ctx context.Context, userID uint, password string) error {
+	user, err := us.checkPassword(ctx, userID, password)
+	if err != nil {
+		return err
+	}
+	if user.TOTPSecret == "" {
+		return apperror.ErrMFANotEnabled
+	}
+
+	return us.userRepository.DisableTOTP(ctx, userID)
+}
+
+// RegenerateRecoveryCodes после проверки пароля заменяет резервные коды новыми
+func (us *Service) RegenerateRecoveryCodes(ctx context.Context, userID uint, password string) ([]string, error) {
+	user, err := us.checkPassword(ctx, userID, password)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled() {
+		return nil, apperror.ErrMFANotEnabled
+	}
+
+	codes, hashes, err := us.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = us.userRepository.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// VerifyMFA завершает вход второго шага: гасит токен проверки из Authenticate,
+// проверяет код TOTP или резервный код и выдает токены.
+// Токен проверки одноразовый, после неверного кода вход начинается заново с пароля.
+func (us *Service) VerifyMFA(
+	ctx context.Context,
+	challenge,
+	code string,
+	client entity.ClientInfo,
+) (string, string, error) {
+	userID, err := us.userRepository.UseActionToken(ctx, security.HashOpaqueToken(challenge),
+		entity.TokenPurposeMFAChallenge)
+	if err != nil {
+		return "", "", err
+	}
+
+	user, err := us.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err = us.loginGuard.Check(ctx, user.Email, client.IP); err != nil {
+		return "", "", err
+	}
+
+	if user.BannedAt != nil {
+		return "", "", apperror.ErrUserBanned
+	}
+
+	if !user.MFAEnabled() {
+		return "", "", apperror.ErrMFANotEnabled
+	}
+
+	if err = us.checkMFACode(ctx, user, strings.TrimSpace(code)); err != nil {
+		if errors.Is(err, apperror.ErrInvalidMFACode) {
+			if failErr := us.loginFailed(ctx, user.Email, client.IP, &user); failErr != nil {
+				return "", "", failErr
+			}
+		}
+		return "", "", err
+	}
+
+	if err = us.loginGuard.Reset(ctx, user.Email); err != nil {
+		return "", "", err
+	}
+
+	return us.startSession(ctx, user, client)
+}
+
+// mfaChallenge выдает токен проверки для второго шага входа
+func (us *Service) mfaChallenge(ctx context.Context, user entity.User) (LoginResult, error) {
+	challenge, err := us.issueActionToken(ctx, user.ID, entity.TokenPurposeMFAChallenge, us.accountCfg.MFAChallengeTTL)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	return LoginResult{MFAChallenge: challenge}, nil
+}
+
+// checkMFACode проверяет шестизначный код TOTP, а любой другой код сверяет с резервными
+func (us *Service) checkMFACode(ctx context.Context, user entity.User, code string) error {
+	if isTOTPCode(code) {
+		step, ok := security.ValidateTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+		if !ok {
+			return apperror.ErrInvalidMFACode
+		}
+		return us.userRepository.UseTOTPStep(ctx, user.ID, step)
+	}
+
+	codes, err := us.userRepository.GetRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	code = strings.ToLower(code)
+	for _, recovery := range codes {
+		compared, err := us.passwordManager.Compare(code, recovery.Hash)
+		if err != nil {
+			return passwordError(err, "failed to compare recovery code")
+		}
+		if compared {
+			return us.userRepository.UseRecoveryCode(ctx, recovery.ID)
+		}
+	}
+
+	return apperror.ErrInvalidMFACode
+}
+
+// generateRecoveryCodes возвращает резервные коды для пользователя и их хэши для хранения
+func (us *Service) generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, us.accountCfg.RecoveryCodeCount)
+	hashes := make([]string, us.accountCfg.RecoveryCodeCount)
+	for i := range codes {
+		code, err := security.GenerateRecoveryCode()
+		if err != nil {
+			return nil, nil, apperror.New(apperror.InternalError, "failed to generate recovery code", err)
+		}
+
+		hash, err := us.passwordManager.Hash(code)
+		if err != nil {
+			return nil, nil, passwordError(err, "failed to hash recovery code")
+		}
+
+		codes[i], hashes[i] = code, hash
+	}
+
+	return codes, hashes, nil
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
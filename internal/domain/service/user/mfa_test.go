@@ -0,0 +1,230 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("UserService MFA", func() {
+	var (
+		ctrl                *gomock.Controller
+		mockRepo            *MockRepository
+		mockTokenService    *MockTokenService
+		mockPasswordManager *MockPasswordManager
+		mockLoginGuard      *MockLoginGuard
+		userService         *Service
+		ctx                 context.Context
+		client              entity.ClientInfo
+		secret              string
+		testUser            entity.User
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockTokenService = NewMockTokenService(ctrl)
+		mockPasswordManager = NewMockPasswordManager(ctrl)
+		mockLoginGuard = NewMockLoginGuard(ctrl)
+		userService = NewService(mockRepo, mockTokenService, mockPasswordManager,
+			mock_email.NewMockMailerService(ctrl), NewMockSecurityLog(ctrl), mockLoginGuard,
+			&config.AccountConfig{
+				UnlockTokenTTL:    time.Hour,
+				MFAIssuer:         "Stawberry",
+				MFAChallengeTTL:   5 * time.Minute,
+				RecoveryCodeCount: 3,
+			})
+		ctx = context.Background()
+		client = entity.ClientInfo{Fingerprint: "fp", IP: "192.0.2.1"}
+
+		var err error
+		secret, err = security.GenerateTOTPSecret()
+		Expect(err).ToNot(HaveOccurred())
+
+		enabledAt := time.Now()
+		testUser = entity.User{
+			ID:            1,
+			Email:         "test@example.com",
+			Password:      "hashed-password",
+			Role:          entity.RoleShop,
+			TOTPSecret:    secret,
+			TOTPEnabledAt: &enabledAt,
+		}
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	// currentCode возвращает код и шаг, для которого он вычислен
+	currentCode := func() (string, int64) {
+		step := security.TOTPStep(time.Now())
+		code, err := security.TOTPCode(secret, step)
+		Expect(err).ToNot(HaveOccurred())
+		return code, step
+	}
+
+	Describe("Authenticate", func() {
+		It("should return a challenge instead of tokens", func() {
+			mockLoginGuard.EXPECT().Check(ctx, testUser.Email, client.IP).Return(nil)
+			mockRepo.EXPECT().GetUser(ctx, testUser.Email).Return(testUser, nil)
+			mockPasswordManager.EXPECT().Compare("password", testUser.Password).Return(true, nil)
+			mockPasswordManager.EXPECT().NeedsRehash(testUser.Password).Return(false)
+			mockRepo.EXPECT().InsertActionToken(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, token entity.UserActionToken) error {
+					Expect(token.Purpose).To(Equal(entity.TokenPurposeMFAChallenge))
+					Expect(token.ExpiresAt).To(BeTemporally("~", time.Now().Add(5*time.Minute), time.Second))
+					return nil
+				})
+
+			result, err := userService.Authenticate(ctx, testUser.Email, "password", client)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.MFAChallenge).ToNot(BeEmpty())
+			Expect(result.AccessToken).To(BeEmpty())
+			Expect(result.RefreshToken).To(BeEmpty())
+		})
+	})
+
+	Describe("VerifyMFA", func() {
+		challenge := "challenge"
+
+		BeforeEach(func() {
+			mockRepo.EXPECT().
+				UseActionToken(ctx, security.HashOpaqueToken(challenge), entity.TokenPurposeMFAChallenge).
+				Return(testUser.ID, nil)
+			mockRepo.EXPECT().GetUserByID(ctx, testUser.ID).Return(testUser, nil)
+			mockLoginGuard.EXPECT().Check(ctx, testUser.Email, client.IP).Return(nil)
+		})
+
+		Context("when totp code is valid", func() {
+			It("should issue tokens", func() {
+				code, step := currentCode()
+				mockRepo.EXPECT().UseTOTPStep(ctx, testUser.ID, step).Return(nil)
+				mockLoginGuard.EXPECT().Reset(ctx, testUser.Email).Return(nil)
+				mockTokenService.EXPECT().RevokeActivesByUserID(ctx, testUser.ID).Return(nil)
+				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, testUser.ID).Return(nil)
+				mockTokenService.EXPECT().GenerateTokens(ctx, client.Fingerprint, testUser.ID, testUser.Role).
+					Return("access", entity.RefreshToken{}, nil)
+				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+
+				access, _, err := userService.VerifyMFA(ctx, challenge, code, client)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(access).To(Equal("access"))
+			})
+		})
+
+		Context("when totp code is wrong", func() {
+			It("should count a failed login", func() {
+				code, _ := currentCode()
+				wrong := string('0'+(code[0]-'0'+1)%10) + code[1:]
+				mockLoginGuard.EXPECT().Fail(ctx, testUser.Email, client.IP).Return(false, nil)
+
+				_, _, err := userService.VerifyMFA(ctx, challenge, wrong, client)
+
+				Expect(err).To(Equal(apperror.ErrInvalidMFACode))
+			})
+		})
+
+		Context("when the same totp code is accepted concurrently", func() {
+			It("should reject the second use", func() {
+				code, step := currentCode()
+				mockRepo.EXPECT().UseTOTPStep(ctx, testUser.ID, step).Return(apperror.ErrInvalidMFACode)
+				mockLoginGuard.EXPECT().Fail(ctx, testUser.Email, client.IP).Return(false, nil)
+
+				_, _, err := userService.VerifyMFA(ctx, challenge, code, client)
+
+				Expect(err).To(Equal(apperror.ErrInvalidMFACode))
+			})
+		})
+
+		Context("when recovery code is used", func() {
+			It("should use up the matching code", func() {
+				mockRepo.EXPECT().GetRecoveryCodes(ctx, testUser.ID).Return([]entity.RecoveryCode{
+					{ID: 1, Hash: "hash-1"},
+					{ID: 2, Hash: "hash-2"},
+				}, nil)
+				mockPasswordManager.EXPECT().Compare("abcde-fghij", "hash-1").Return(false, nil)
+				mockPasswordManager.EXPECT().Compare("abcde-fghij", "hash-2").Return(true, nil)
+				mockRepo.EXPECT().UseRecoveryCode(ctx, uint(2)).Return(nil)
+				mockLoginGuard.EXPECT().Reset(ctx, testUser.Email).Return(nil)
+				mockTokenService.EXPECT().RevokeActivesByUserID(ctx, testUser.ID).Return(nil)
+				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, testUser.ID).Return(nil)
+				mockTokenService.EXPECT().GenerateTokens(ctx, client.Fingerprint, testUser.ID, testUser.Role).
+					Return("access", entity.RefreshToken{}, nil)
+				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+
+				_, _, err := userService.VerifyMFA(ctx, challenge, "ABCDE-FGHIJ", client)
+
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("ConfirmTOTP", func() {
+		BeforeEach(func() {
+			testUser.TOTPEnabledAt = nil
+		})
+
+		Context("when code is valid", func() {
+			It("should enable totp and return recovery codes", func() {
+				code, step := currentCode()
+				mockRepo.EXPECT().GetUserByID(ctx, testUser.ID).Return(testUser, nil)
+				mockPasswordManager.EXPECT().Hash(gomock.Any()).Return("hash", nil).Times(3)
+				mockRepo.EXPECT().EnableTOTP(ctx, testUser.ID, step, []string{"hash", "hash", "hash"}).Return(nil)
+
+				codes, err := userService.ConfirmTOTP(ctx, testUser.ID, code)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(codes).To(HaveLen(3))
+				Expect(codes[0]).To(MatchRegexp(`^[a-z2-7]{5}-[a-z2-7]{5}$`))
+			})
+		})
+
+		Context("when code is invalid", func() {
+			It("should not enable totp", func() {
+				mockRepo.EXPECT().GetUserByID(ctx, testUser.ID).Return(testUser, nil)
+
+				_, err := userService.ConfirmTOTP(ctx, testUser.ID, "abcdef")
+
+				Expect(err).To(Equal(apperror.ErrInvalidMFACode))
+			})
+		})
+	})
+
+	Describe("EnrollTOTP", func() {
+		Context("when totp is already enabled", func() {
+			It("should return conflict", func() {
+				mockRepo.EXPECT().GetUserByID(ctx, testUser.ID).Return(testUser, nil)
+
+				_, err := userService.EnrollTOTP(ctx, testUser.ID)
+
+				Expect(err).To(Equal(apperror.ErrMFAAlreadyEnabled))
+			})
+		})
+
+		Context("when totp is not enabled", func() {
+			It("should store a new secret and return the otpauth uri", func() {
+				testUser.TOTPEnabledAt = nil
+				mockRepo.EXPECT().GetUserByID(ctx, testUser.ID).Return(testUser, nil)
+				mockRepo.EXPECT().SetTOTPSecret(ctx, testUser.ID, gomock.Any()).Return(nil)
+
+				enrollment, err := userService.EnrollTOTP(ctx, testUser.ID)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(enrollment.URI).To(HavePrefix("otpauth://totp/Stawberry:test@example.com?"))
+				Expect(enrollment.URI).To(ContainSubstring("secret=" + enrollment.Secret))
+			})
+		})
+	})
+})
@@ -2,36 +2,82 @@ package user
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/EM-Stawberry/Stawberry/config"
 	"github.com/EM-Stawberry/Stawberry/pkg/email"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/google/uuid"
 )
 
-//go:generate mockgen -source=$GOFILE -destination=user_mock_test.go -package=user Repository, TokenService
+//go:generate mockgen -source=$GOFILE -destination=user_mock_test.go -package=user Repository, TokenService, SecurityLog, LoginGuard
 
 type Repository interface {
 	InsertUser(ctx context.Context, user User) (uint, error)
 	GetUser(ctx context.Context, email string) (entity.User, error)
 	GetUserByID(ctx context.Context, id uint) (entity.User, error)
+	InsertActionToken(ctx context.Context, token entity.UserActionToken) error
+	UseActionToken(ctx context.Context, tokenHash, purpose string) (uint, error)
+	MarkEmailVerified(ctx context.Context, userID uint) error
+	UpdatePassword(ctx context.Context, userID uint, passwordHash string) error
+	ReplacePasswordHash(ctx context.Context, userID uint, oldHash, newHash string) error
+	UpdateProfile(ctx context.Context, userID uint, name, phone *string) error
+	UpdateEmail(ctx context.Context, userID uint, email string) error
+	AnonymizeUser(ctx context.Context, userID uint) error
+	SetBanned(ctx context.Context, userID uint, banned bool) error
+	SetTOTPSecret(ctx context.Context, userID uint, secret string) error
+	EnableTOTP(ctx context.Context, userID uint, step int64, codeHashes []string) error
+	DisableTOTP(ctx context.Context, userID uint) error
+	UseTOTPStep(ctx context.Context, userID uint, step int64) error
+	GetRecoveryCodes(ctx context.Context, userID uint) ([]entity.RecoveryCode, error)
+	UseRecoveryCode(ctx context.Context, codeID uint) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error
+	TouchIdentity(ctx context.Context, identity entity.ExternalIdentity) (uint, error)
+	LinkIdentity(ctx context.Context, userID uint, identity entity.ExternalIdentity) error
+	InsertExternalUser(ctx context.Context, identity entity.ExternalIdentity) (uint, error)
 }
 
 // PasswordManager выполняет операции с паролями, такие как хеширование и проверка
 type PasswordManager interface {
 	Hash(password string) (string, error)
 	Compare(password, hash string) (bool, error)
+	NeedsRehash(hash string) bool
 }
 
 type TokenService interface {
-	GenerateTokens(ctx context.Context, fingerprint string, userID uint) (string, entity.RefreshToken, error)
+	GenerateTokens(
+		ctx context.Context,
+		fingerprint string,
+		userID uint,
+		role entity.Role,
+	) (string, entity.RefreshToken, error)
 	InsertToken(ctx context.Context, token entity.RefreshToken) error
 	RevokeActivesByUserID(ctx context.Context, userID uint) error
+	RevokeAllByUserID(ctx context.Context, userID uint) error
 	GetByUUID(ctx context.Context, uuid string) (entity.RefreshToken, error)
 	Update(ctx context.Context, refresh entity.RefreshToken) (entity.RefreshToken, error)
 	CleanUpExpiredByUserID(ctx context.Context, userID uint) error
+	GetActivesTokenByUserID(ctx context.Context, userID uint) ([]entity.RefreshToken, error)
+	Rotate(ctx context.Context, oldUUID uuid.UUID, token entity.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+}
+
+// SecurityLog записывает события безопасности в журнал аудита
+type SecurityLog interface {
+	Log(entries []entity.AuditEntry) error
+}
+
+// LoginGuard ограничивает неудачные попытки входа по учетной записи и адресу клиента
+type LoginGuard interface {
+	Check(ctx context.Context, email, ip string) error
+	Fail(ctx context.Context, email, ip string) (bool, error)
+	Reset(ctx context.Context, email string) error
 }
 
 type Service struct {
@@ -39,18 +85,27 @@ type Service struct {
 	tokenService    TokenService
 	passwordManager PasswordManager
 	mailer          email.MailerService
+	securityLog     SecurityLog
+	loginGuard      LoginGuard
+	accountCfg      *config.AccountConfig
 }
 
 func NewService(userRepo Repository,
 	tokenService TokenService,
 	passwordManager PasswordManager,
 	mailer email.MailerService,
+	securityLog SecurityLog,
+	loginGuard LoginGuard,
+	accountCfg *config.AccountConfig,
 ) *Service {
 	return &Service{
 		userRepository:  userRepo,
 		tokenService:    tokenService,
 		passwordManager: passwordManager,
 		mailer:          mailer,
+		securityLog:     securityLog,
+		loginGuard:      loginGuard,
+		accountCfg:      accountCfg,
 	}
 }
 
@@ -59,13 +114,11 @@ func NewService(userRepo Repository,
 func (us *Service) CreateUser(
 	ctx context.Context,
 	user User,
-	fingerprint string,
+	client entity.ClientInfo,
 ) (string, string, error) {
 	hash, err := us.passwordManager.Hash(user.Password)
 	if err != nil {
-		err := apperror.ErrFailedToGeneratePassword
-		err.WrappedErr = fmt.Errorf("failed to generate password %w", err)
-		return "", "", err
+		return "", "", passwordError(err, "failed to generate password")
 	}
 	user.Password = hash
 
@@ -74,54 +127,106 @@ func (us *Service) CreateUser(
 		return "", "", err
 	}
 
-	accessToken, refreshToken, err := us.tokenService.GenerateTokens(ctx, fingerprint, id)
+	accessToken, refreshToken, err := us.tokenService.GenerateTokens(ctx, client.Fingerprint, id, entity.RoleUser)
 	if err != nil {
 		return "", "", err
 	}
+	refreshToken.SetClient(client)
 
 	if err = us.tokenService.InsertToken(ctx, refreshToken); err != nil {
 		return "", "", err
 	}
 
-	us.mailer.Registered(user.Name, user.Email)
+	verificationToken, err := us.issueActionToken(ctx, id, entity.TokenPurposeVerifyEmail,
+		us.accountCfg.VerificationTokenTTL)
+	if err != nil {
+		return "", "", err
+	}
+
+	us.mailer.Registered(user.Name, verificationToken, user.Email)
 
 	return accessToken, refreshToken.UUID.String(), nil
 }
 
 // Authenticate аутентифицирует пользователя по email и паролю, создавая новые токены.
+// Если у пользователя подключен второй фактор, вместо токенов возвращается одноразовый
+// токен проверки, который вместе с кодом обменивается на токены в VerifyMFA.
 func (us *Service) Authenticate(
 	ctx context.Context,
 	email,
-	password,
-	fingerprint string,
-) (string, string, error) {
+	password string,
+	client entity.ClientInfo,
+) (LoginResult, error) {
+	if err := us.loginGuard.Check(ctx, email, client.IP); err != nil {
+		return LoginResult{}, err
+	}
+
 	user, err := us.userRepository.GetUser(ctx, email)
 	if err != nil {
-		return "", "", apperror.ErrUserNotFound
+		if errors.Is(err, apperror.ErrUserNotFound) {
+			if err = us.loginFailed(ctx, email, client.IP, nil); err != nil {
+				return LoginResult{}, err
+			}
+		}
+		return LoginResult{}, apperror.ErrUserNotFound
 	}
 
-	compared, err := us.passwordManager.Compare(password, user.Password)
+	compared, err := us.comparePassword(password, user.Password)
 	if err != nil {
-		return "", "", err
+		return LoginResult{}, err
 	}
 
 	if !compared {
-		return "", "", apperror.ErrIncorrectPassword
+		if err = us.loginFailed(ctx, email, client.IP, &user); err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{}, apperror.ErrIncorrectPassword
+	}
+
+	if user.BannedAt != nil {
+		return LoginResult{}, apperror.ErrUserBanned
+	}
+
+	us.rehashPassword(ctx, user, password)
+
+	// счетчик неудачных попыток сбрасывается только после второго фактора,
+	// иначе знающий пароль мог бы перебирать коды без ограничений
+	if user.MFAEnabled() {
+		return us.mfaChallenge(ctx, user)
 	}
 
+	if err = us.loginGuard.Reset(ctx, email); err != nil {
+		return LoginResult{}, err
+	}
+
+	accessToken, refreshToken, err := us.startSession(ctx, user, client)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	return LoginResult{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// startSession завершает прежние сессии пользователя и открывает новую
+func (us *Service) startSession(
+	ctx context.Context,
+	user entity.User,
+	client entity.ClientInfo,
+) (string, string, error) {
 	if err := us.tokenService.RevokeActivesByUserID(ctx, user.ID); err != nil {
 		return "", "", err
 	}
 
-	err = us.tokenService.CleanUpExpiredByUserID(ctx, user.ID)
+	err := us.tokenService.CleanUpExpiredByUserID(ctx, user.ID)
 	if err != nil {
 		return "", "", err
 	}
 
-	accessToken, refreshToken, err := us.tokenService.GenerateTokens(ctx, fingerprint, user.ID)
+	accessToken, refreshToken, err := us.tokenService.GenerateTokens(ctx, client.Fingerprint, user.ID, user.Role)
 	if err != nil {
 		return "", "", err
 	}
+	refreshToken.SetClient(client)
 
 	if err = us.tokenService.InsertToken(ctx, refreshToken); err != nil {
 		return "", "", err
@@ -131,53 +236,98 @@ func (us *Service) Authenticate(
 }
 
 // Refresh обновляет пару токенов аутентификации.
+// Старый refresh токен обменивается на новый той же семьи. Повторное предъявление
+// уже обмененного токена считается кражей: вся семья отзывается, событие пишется в аудит.
 func (us *Service) Refresh(
 	ctx context.Context,
-	refreshToken,
-	fingerprint string,
+	refreshToken string,
+	client entity.ClientInfo,
 ) (string, string, error) {
 	refresh, err := us.tokenService.GetByUUID(ctx, refreshToken)
 	if err != nil {
 		return "", "", err
 	}
 
+	if refresh.IsRotated() {
+		return "", "", us.handleTokenReuse(ctx, refresh, client)
+	}
+
 	if !refresh.IsValid() {
 		return "", "", apperror.ErrInvalidToken
 	}
 
-	if refresh.Fingerprint != fingerprint {
+	if refresh.Fingerprint != client.Fingerprint {
 		return "", "", apperror.ErrInvalidFingerprint
 	}
 
-	now := time.Now()
-	refresh.RevokedAt = &now
-
-	refresh, err = us.tokenService.Update(ctx, refresh)
+	user, err := us.userRepository.GetUserByID(ctx, refresh.UserID)
 	if err != nil {
 		return "", "", err
 	}
 
-	user, err := us.userRepository.GetUserByID(ctx, refresh.UserID)
-	if err != nil {
-		return "", "", err
+	if user.BannedAt != nil {
+		return "", "", apperror.ErrUserBanned
 	}
 
-	access, refresh, err := us.tokenService.GenerateTokens(ctx, fingerprint, user.ID)
+	access, newRefresh, err := us.tokenService.GenerateTokens(ctx, client.Fingerprint, user.ID, user.Role)
 	if err != nil {
 		return "", "", err
 	}
+	newRefresh.FamilyID = refresh.FamilyID
+	newRefresh.SetClient(client)
 
 	err = us.tokenService.CleanUpExpiredByUserID(ctx, user.ID)
 	if err != nil {
 		return "", "", err
 	}
 
-	err = us.tokenService.InsertToken(ctx, refresh)
+	err = us.tokenService.Rotate(ctx, refresh.UUID, newRefresh)
+	if errors.Is(err, apperror.ErrRefreshTokenReused) {
+		return "", "", us.handleTokenReuse(ctx, refresh, client)
+	}
 	if err != nil {
 		return "", "", err
 	}
 
-	return access, refresh.UUID.String(), nil
+	return access, newRefresh.UUID.String(), nil
+}
+
+// handleTokenReuse отзывает семью повторно предъявленного токена, пишет событие в аудит
+// и, если включено, предупреждает пользователя письмом
+func (us *Service) handleTokenReuse(ctx context.Context, refresh entity.RefreshToken, client entity.ClientInfo) error {
+	if err := us.tokenService.RevokeFamily(ctx, refresh.FamilyID); err != nil {
+		return err
+	}
+
+	role := entity.RoleUser
+	user, err := us.userRepository.GetUserByID(ctx, refresh.UserID)
+	if err == nil {
+		role = user.Role
+	}
+
+	err = us.securityLog.Log([]entity.AuditEntry{{
+		Method:     entity.AuditMethodSecurity,
+		Url:        entity.SecurityEventRefreshTokenReuse,
+		RespStatus: http.StatusUnauthorized,
+		UserID:     refresh.UserID,
+		IP:         client.IP,
+		UserRole:   string(role),
+		ReceivedAt: time.Now(),
+		ReqBody: map[string]interface{}{
+			"family_id":  refresh.FamilyID.String(),
+			"user_agent": client.UserAgent,
+		},
+		RespBody: map[string]interface{}{},
+	}})
+	if err != nil {
+		return apperror.New(apperror.InternalError, "failed to log security event", err)
+	}
+
+	if us.accountCfg.NotifyTokenReuse && user.Email != "" {
+		us.mailer.RefreshTokenReused(client.IP, user.Email)
+	}
+
+	return apperror.ErrRefreshTokenReused
 }
 
 func (us *Service) Logout(
@@ -198,17 +348,317 @@ func (us *Service) Logout(
 		return apperror.ErrInvalidFingerprint
 	}
 
-	now := time.Now()
-	refresh.RevokedAt = &now
-
-	_, err = us.tokenService.Update(ctx, refresh)
-	if err != nil {
+	// вместе с сессией гасятся и выданные в ней access токены
+	if err = us.tokenService.RevokeFamily(ctx, refresh.FamilyID); err != nil {
 		return fmt.Errorf("failed to revoke refresh token: %w", err)
 	}
 
 	return nil
 }
 
+// ListSessions возвращает активные сессии пользователя, последние использованные первыми
+func (us *Service) ListSessions(ctx context.Context, userID uint) ([]entity.RefreshToken, error) {
+	return us.tokenService.GetActivesTokenByUserID(ctx, userID)
+}
+
+// RevokeSession завершает одну сессию пользователя. Чужие и уже завершенные сессии не находятся.
+func (us *Service) RevokeSession(ctx context.Context, userID uint, sessionUUID string) error {
+	refresh, err := us.tokenService.GetByUUID(ctx, sessionUUID)
+	if err != nil {
+		return err
+	}
+
+	if refresh.UserID != userID || !refresh.IsValid() {
+		return apperror.ErrTokenNotFound
+	}
+
+	return us.tokenService.RevokeFamily(ctx, refresh.FamilyID)
+}
+
+// RevokeAllSessions завершает все сессии пользователя на всех устройствах
+func (us *Service) RevokeAllSessions(ctx context.Context, userID uint) error {
+	return us.tokenService.RevokeAllByUserID(ctx, userID)
+}
+
+// BanUser блокирует пользователя: вход и обновление токенов запрещаются,
+// все его сессии и выданные access токены отзываются. Администратора заблокировать нельзя.
+func (us *Service) BanUser(ctx context.Context, userID uint) error {
+	user, err := us.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == entity.RoleAdmin {
+		return apperror.New(apperror.Forbidden, "administrators cannot be banned", nil)
+	}
+
+	if err = us.userRepository.SetBanned(ctx, userID, true); err != nil {
+		return err
+	}
+
+	return us.tokenService.RevokeAllByUserID(ctx, userID)
+}
+
+// UnbanUser снимает блокировку. Отозванные сессии не восстанавливаются.
+func (us *Service) UnbanUser(ctx context.Context, userID uint) error {
+	return us.userRepository.SetBanned(ctx, userID, false)
+}
+
 func (us *Service) GetUserByID(ctx context.Context, id uint) (entity.User, error) {
 	return us.userRepository.GetUserByID(ctx, id)
 }
+
+// VerifyEmail подтверждает почту по токену из письма
+func (us *Service) VerifyEmail(ctx context.Context, token string) error {
+	userID, err := us.userRepository.UseActionToken(ctx, security.HashOpaqueToken(token), entity.TokenPurposeVerifyEmail)
+	if err != nil {
+		return err
+	}
+
+	return us.userRepository.MarkEmailVerified(ctx, userID)
+}
+
+// ResendVerification отправляет новый токен подтверждения почты
+func (us *Service) ResendVerification(ctx context.Context, userID uint) error {
+	user, err := us.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return apperror.New(apperror.Conflict, "email is already verified", nil)
+	}
+
+	token, err := us.issueActionToken(ctx, user.ID, entity.TokenPurposeVerifyEmail, us.accountCfg.VerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	us.mailer.VerificationRequested(token, user.Email)
+
+	return nil
+}
+
+// ForgotPassword отправляет токен сброса пароля. Для неизвестной почты ошибка не возвращается,
+// чтобы по ответу нельзя было узнать, зарегистрирован ли пользователь.
+func (us *Service) ForgotPassword(ctx context.Context, email string) error {
+	user, err := us.userRepository.GetUser(ctx, email)
+	if err != nil {
+		if errors.Is(err, apperror.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := us.issueActionToken(ctx, user.ID, entity.TokenPurposeResetPassword, us.accountCfg.ResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	us.mailer.PasswordResetRequested(token, user.Email)
+
+	return nil
+}
+
+// ResetPassword меняет пароль по токену из письма и завершает все сессии пользователя
+func (us *Service) ResetPassword(ctx context.Context, token, password string) error {
+	userID, err := us.userRepository.UseActionToken(ctx, security.HashOpaqueToken(token),
+		entity.TokenPurposeResetPassword)
+	if err != nil {
+		return err
+	}
+
+	hash, err := us.passwordManager.Hash(password)
+	if err != nil {
+		return passwordError(err, "failed to generate password")
+	}
+
+	if err = us.userRepository.UpdatePassword(ctx, userID, hash); err != nil {
+		return err
+	}
+
+	return us.tokenService.RevokeAllByUserID(ctx, userID)
+}
+
+// UnlockAccount снимает блокировку входа по токену из письма о блокировке
+func (us *Service) UnlockAccount(ctx context.Context, token string) error {
+	userID, err := us.userRepository.UseActionToken(ctx, security.HashOpaqueToken(token),
+		entity.TokenPurposeUnlockAccount)
+	if err != nil {
+		return err
+	}
+
+	user, err := us.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return us.loginGuard.Reset(ctx, user.Email)
+}
+
+// UpdateProfile меняет имя и телефон пользователя
+func (us *Service) UpdateProfile(ctx context.Context, userID uint, name, phone *string) error {
+	if name == nil && phone == nil {
+		return apperror.New(apperror.BadRequest, "nothing to update", nil)
+	}
+
+	return us.userRepository.UpdateProfile(ctx, userID, name, phone)
+}
+
+// ChangePassword меняет пароль после проверки текущего и завершает все сессии пользователя
+func (us *Service) ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) error {
+	if _, err := us.checkPassword(ctx, userID, currentPassword); err != nil {
+		return err
+	}
+
+	hash, err := us.passwordManager.Hash(newPassword)
+	if err != nil {
+		return passwordError(err, "failed to generate password")
+	}
+
+	if err = us.userRepository.UpdatePassword(ctx, userID, hash); err != nil {
+		return err
+	}
+
+	return us.tokenService.RevokeAllByUserID(ctx, userID)
+}
+
+// ChangeEmail меняет почту после проверки пароля. Новую почту нужно подтвердить заново.
+func (us *Service) ChangeEmail(ctx context.Context, userID uint, password, newEmail string) error {
+	user, err := us.checkPassword(ctx, userID, password)
+	if err != nil {
+		return err
+	}
+	if user.Email == newEmail {
+		return apperror.New(apperror.BadRequest, "new email matches the current one", nil)
+	}
+
+	if err = us.userRepository.UpdateEmail(ctx, userID, newEmail); err != nil {
+		return err
+	}
+
+	token, err := us.issueActionToken(ctx, userID, entity.TokenPurposeVerifyEmail, us.accountCfg.VerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	us.mailer.VerificationRequested(token, newEmail)
+
+	return nil
+}
+
+// DeleteAccount после проверки пароля завершает все сессии и обезличивает аккаунт.
+// Сессии отзываются до обезличивания: оно удаляет refresh токены, по которым
+// находятся еще действующие access токены.
+func (us *Service) DeleteAccount(ctx context.Context, userID uint, password string) error {
+	if _, err := us.checkPassword(ctx, userID, password); err != nil {
+		return err
+	}
+
+	if err := us.tokenService.RevokeAllByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	return us.userRepository.AnonymizeUser(ctx, userID)
+}
+
+func (us *Service) checkPassword(ctx context.Context, userID uint, password string) (entity.User, error) {
+	user, err := us.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	compared, err := us.comparePassword(password, user.Password)
+	if err != nil {
+		return entity.User{}, err
+	}
+	if !compared {
+		return entity.User{}, apperror.ErrIncorrectPassword
+	}
+
+	return user, nil
+}
+
+// rehashPassword пересчитывает хэш пароля, вычисленный с устаревшими параметрами или без перца.
+// Ошибки не прерывают вход: хэш будет пересчитан при следующем входе.
+func (us *Service) rehashPassword(ctx context.Context, user entity.User, password string) {
+	if !us.passwordManager.NeedsRehash(user.Password) {
+		return
+	}
+
+	hash, err := us.passwordManager.Hash(password)
+	if err != nil {
+		return
+	}
+
+	_ = us.userRepository.ReplacePasswordHash(ctx, user.ID, user.Password, hash)
+}
+
+// comparePassword сверяет пароль с хэшем. У пользователей, пришедших через внешнего провайдера,
+// пароля нет, пока они не зададут его через сброс пароля, и никакой пароль для них не подходит.
+func (us *Service) comparePassword(password, hash string) (bool, error) {
+	if hash == "" {
+		return false, nil
+	}
+
+	compared, err := us.passwordManager.Compare(password, hash)
+	if err != nil {
+		return false, passwordError(err, "failed to compare password")
+	}
+
+	return compared, nil
+}
+
+// loginFailed учитывает неудачный вход. Если учетная запись при этом заблокирована,
+// владельцу отправляется письмо с токеном разблокировки.
+func (us *Service) loginFailed(ctx context.Context, email, ip string, user *entity.User) error {
+	locked, err := us.loginGuard.Fail(ctx, email, ip)
+	if err != nil {
+		return err
+	}
+
+	if !locked || user == nil {
+		return nil
+	}
+
+	token, err := us.issueActionToken(ctx, user.ID, entity.TokenPurposeUnlockAccount, us.accountCfg.UnlockTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	us.mailer.AccountLocked(token, user.Email)
+
+	return nil
+}
+
+// passwordError переводит ошибку вычисления хэша пароля в ошибку приложения.
+// Если все слоты вычисления заняты, клиенту предлагается повторить запрос позже.
+func passwordError(err error, message string) error {
+	if errors.Is(err, security.ErrHashingBusy) {
+		return apperror.NewRetry(apperror.TooManyRequests, "server is busy, retry later", time.Second)
+	}
+	return apperror.New(apperror.InternalError, message, err)
+}
+
+func (us *Service) issueActionToken(
+	ctx context.Context,
+	userID uint,
+	purpose string,
+	ttl time.Duration,
+) (string, error) {
+	token, tokenHash, err := security.GenerateOpaqueToken()
+	if err != nil {
+		return "", apperror.New(apperror.InternalError, "failed to generate token", err)
+	}
+
+	err = us.userRepository.InsertActionToken(ctx, entity.UserActionToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"time"
 
+	"github.com/EM-Stawberry/Stawberry/config"
 	"github.com/EM-Stawberry/Stawberry/pkg/email/mock_email"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
@@ -22,6 +24,8 @@ var _ = Describe("UserService", func() {
 		mockTokenService    *MockTokenService
 		mockPasswordManager *MockPasswordManager
 		mockEmailService    *mock_email.MockMailerService
+		mockSecurityLog     *MockSecurityLog
+		mockLoginGuard      *MockLoginGuard
 		userService         *Service
 		ctx                 context.Context
 	)
@@ -32,7 +36,15 @@ var _ = Describe("UserService", func() {
 		mockTokenService = NewMockTokenService(ctrl)
 		mockPasswordManager = NewMockPasswordManager(ctrl)
 		mockEmailService = mock_email.NewMockMailerService(ctrl)
-		userService = NewService(mockRepo, mockTokenService, mockPasswordManager, mockEmailService)
+		mockSecurityLog = NewMockSecurityLog(ctrl)
+		mockLoginGuard = NewMockLoginGuard(ctrl)
+		userService = NewService(mockRepo, mockTokenService, mockPasswordManager, mockEmailService, mockSecurityLog,
+			mockLoginGuard, &config.AccountConfig{
+				VerificationTokenTTL: time.Hour,
+				ResetTokenTTL:        time.Hour,
+				UnlockTokenTTL:       time.Hour,
+				NotifyTokenReuse:     true,
+			})
 		ctx = context.Background()
 	})
 
@@ -45,6 +57,7 @@ var _ = Describe("UserService", func() {
 			testUser       User
 			hashedPassword string
 			fingerprint    string
+			client         entity.ClientInfo
 		)
 
 		BeforeEach(func() {
@@ -55,6 +68,7 @@ var _ = Describe("UserService", func() {
 			}
 			hashedPassword = "hashed-password"
 			fingerprint = "test-fingerprint"
+			client = entity.ClientInfo{Fingerprint: fingerprint}
 		})
 
 		Context("when user creation is successful", func() {
@@ -62,12 +76,13 @@ var _ = Describe("UserService", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
 				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), nil)
 				mockTokenService.EXPECT().
-					GenerateTokens(ctx, fingerprint, uint(1)).
+					GenerateTokens(ctx, fingerprint, uint(1), entity.RoleUser).
 					Return("access-token", entity.RefreshToken{UUID: uuid.New()}, nil)
 				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
-				mockEmailService.EXPECT().Registered(testUser.Name, testUser.Email)
+				mockRepo.EXPECT().InsertActionToken(ctx, gomock.Any()).Return(nil)
+				mockEmailService.EXPECT().Registered(testUser.Name, gomock.Any(), testUser.Email)
 
-				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
+				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, client)
 
 				Expect(err).ToNot(HaveOccurred())
 				Expect(accessToken).ToNot(BeEmpty())
@@ -79,7 +94,7 @@ var _ = Describe("UserService", func() {
 			It("should return error", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return("", errors.New("failed to generate password"))
 
-				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
+				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
@@ -92,7 +107,7 @@ var _ = Describe("UserService", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
 				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(0), errors.New("db error"))
 
-				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
+				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
@@ -105,10 +120,10 @@ var _ = Describe("UserService", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
 				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), nil)
 				mockTokenService.EXPECT().
-					GenerateTokens(ctx, fingerprint, uint(1)).
+					GenerateTokens(ctx, fingerprint, uint(1), entity.RoleUser).
 					Return("", entity.RefreshToken{}, errors.New("token generation error"))
 
-				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
+				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
@@ -121,13 +136,13 @@ var _ = Describe("UserService", func() {
 				mockPasswordManager.EXPECT().Hash(testUser.Password).Return(hashedPassword, nil)
 				mockRepo.EXPECT().InsertUser(ctx, gomock.Any()).Return(uint(1), nil)
 				mockTokenService.EXPECT().
-					GenerateTokens(ctx, fingerprint, uint(1)).
+					GenerateTokens(ctx, fingerprint, uint(1), entity.RoleUser).
 					Return("access-token", entity.RefreshToken{}, nil)
 				mockTokenService.EXPECT().
 					InsertToken(ctx, gomock.Any()).
 					Return(errors.New("token insertion error"))
 
-				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, fingerprint)
+				accessToken, refreshToken, err := userService.CreateUser(ctx, testUser, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
@@ -141,6 +156,7 @@ var _ = Describe("UserService", func() {
 			email          string
 			password       string
 			fingerprint    string
+			client         entity.ClientInfo
 			hashedPassword string
 			testUser       entity.User
 		)
@@ -149,6 +165,7 @@ var _ = Describe("UserService", func() {
 			email = "test@example.com"
 			password = "password123"
 			fingerprint = "test-fingerprint"
+			client = entity.ClientInfo{Fingerprint: fingerprint}
 			hashedPassword = "hashed-password"
 			testUser = entity.User{
 				ID:       1,
@@ -159,128 +176,248 @@ var _ = Describe("UserService", func() {
 
 		Context("when authentication is successful", func() {
 			It("should authenticate user and return tokens", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
 				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
 				mockPasswordManager.EXPECT().Compare(password, hashedPassword).Return(true, nil)
+				mockPasswordManager.EXPECT().NeedsRehash(hashedPassword).Return(false)
+				mockLoginGuard.EXPECT().Reset(ctx, email).Return(nil)
 				mockTokenService.EXPECT().RevokeActivesByUserID(ctx, testUser.ID).Return(nil)
 				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, testUser.ID).Return(nil)
 				mockTokenService.EXPECT().
-					GenerateTokens(ctx, fingerprint, testUser.ID).
+					GenerateTokens(ctx, fingerprint, testUser.ID, gomock.Any()).
 					Return("access-token", entity.RefreshToken{UUID: uuid.New()}, nil)
 				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
 
-				accessToken, refreshToken, err := userService.Authenticate(ctx, email, password, fingerprint)
+				result, err := userService.Authenticate(ctx, email, password, client)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.AccessToken).ToNot(BeEmpty())
+				Expect(result.RefreshToken).ToNot(BeEmpty())
+			})
+		})
+
+		Context("when password hash is outdated", func() {
+			It("should replace the hash and log in", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
+				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
+				mockPasswordManager.EXPECT().Compare(password, hashedPassword).Return(true, nil)
+				mockPasswordManager.EXPECT().NeedsRehash(hashedPassword).Return(true)
+				mockPasswordManager.EXPECT().Hash(password).Return("new-hash", nil)
+				mockRepo.EXPECT().ReplacePasswordHash(ctx, testUser.ID, hashedPassword, "new-hash").Return(nil)
+				mockLoginGuard.EXPECT().Reset(ctx, email).Return(nil)
+				mockTokenService.EXPECT().RevokeActivesByUserID(ctx, testUser.ID).Return(nil)
+				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, testUser.ID).Return(nil)
+				mockTokenService.EXPECT().
+					GenerateTokens(ctx, fingerprint, testUser.ID, gomock.Any()).
+					Return("access-token", entity.RefreshToken{UUID: uuid.New()}, nil)
+				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+
+				result, err := userService.Authenticate(ctx, email, password, client)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.AccessToken).ToNot(BeEmpty())
+			})
+
+			It("should log in even if the new hash cannot be computed", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
+				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
+				mockPasswordManager.EXPECT().Compare(password, hashedPassword).Return(true, nil)
+				mockPasswordManager.EXPECT().NeedsRehash(hashedPassword).Return(true)
+				mockPasswordManager.EXPECT().Hash(password).Return("", security.ErrHashingBusy)
+				mockLoginGuard.EXPECT().Reset(ctx, email).Return(nil)
+				mockTokenService.EXPECT().RevokeActivesByUserID(ctx, testUser.ID).Return(nil)
+				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, testUser.ID).Return(nil)
+				mockTokenService.EXPECT().
+					GenerateTokens(ctx, fingerprint, testUser.ID, gomock.Any()).
+					Return("access-token", entity.RefreshToken{UUID: uuid.New()}, nil)
+				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+
+				_, err := userService.Authenticate(ctx, email, password, client)
 
 				Expect(err).ToNot(HaveOccurred())
-				Expect(accessToken).ToNot(BeEmpty())
-				Expect(refreshToken).ToNot(BeEmpty())
 			})
 		})
 
 		Context("when user is not found", func() {
 			It("should return user not found error", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
 				mockRepo.EXPECT().GetUser(ctx, email).Return(entity.User{}, apperror.ErrUserNotFound)
+				mockLoginGuard.EXPECT().Fail(ctx, email, "").Return(false, nil)
 
-				accessToken, refreshToken, err := userService.Authenticate(ctx, email, password, fingerprint)
+				result, err := userService.Authenticate(ctx, email, password, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(Equal(apperror.ErrUserNotFound))
-				Expect(accessToken).To(BeEmpty())
-				Expect(refreshToken).To(BeEmpty())
+				Expect(result.AccessToken).To(BeEmpty())
+				Expect(result.RefreshToken).To(BeEmpty())
 			})
 		})
 
 		Context("when password is incorrect", func() {
 			It("should return incorrect password error", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
 				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
 				mockPasswordManager.EXPECT().Compare("wrong_password", hashedPassword).Return(false, nil)
+				mockLoginGuard.EXPECT().Fail(ctx, email, "").Return(false, nil)
 
-				accessToken, refreshToken, err := userService.Authenticate(ctx, email, "wrong_password", fingerprint)
+				result, err := userService.Authenticate(ctx, email, "wrong_password", client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(Equal(apperror.ErrIncorrectPassword))
-				Expect(accessToken).To(BeEmpty())
-				Expect(refreshToken).To(BeEmpty())
+				Expect(result.AccessToken).To(BeEmpty())
+				Expect(result.RefreshToken).To(BeEmpty())
+			})
+		})
+
+		Context("when the failed attempt locks the account", func() {
+			It("should send an unlock email", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
+				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
+				mockPasswordManager.EXPECT().Compare("wrong_password", hashedPassword).Return(false, nil)
+				mockLoginGuard.EXPECT().Fail(ctx, email, "").Return(true, nil)
+				mockRepo.EXPECT().InsertActionToken(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, token entity.UserActionToken) error {
+						Expect(token.Purpose).To(Equal(entity.TokenPurposeUnlockAccount))
+						return nil
+					})
+				mockEmailService.EXPECT().AccountLocked(gomock.Any(), email)
+
+				_, err := userService.Authenticate(ctx, email, "wrong_password", client)
+
+				Expect(err).To(Equal(apperror.ErrIncorrectPassword))
+			})
+		})
+
+		Context("when login is throttled", func() {
+			It("should not check the password", func() {
+				throttled := apperror.NewRetry(apperror.TooManyRequests, "too many failed login attempts", time.Minute)
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(throttled)
+
+				_, err := userService.Authenticate(ctx, email, password, client)
+
+				Expect(err).To(Equal(throttled))
+			})
+		})
+
+		Context("when password hashing is busy", func() {
+			It("should ask to retry later", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
+				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
+				mockPasswordManager.EXPECT().Compare(password, hashedPassword).Return(false, security.ErrHashingBusy)
+
+				_, err := userService.Authenticate(ctx, email, password, client)
+
+				var appErr apperror.AppError
+				Expect(errors.As(err, &appErr)).To(BeTrue())
+				Expect(appErr.Code()).To(Equal(apperror.TooManyRequests))
+			})
+		})
+
+		Context("when user is banned", func() {
+			It("should return user banned error", func() {
+				bannedAt := time.Now()
+				testUser.BannedAt = &bannedAt
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
+				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
+				mockPasswordManager.EXPECT().Compare(password, hashedPassword).Return(true, nil)
+
+				_, err := userService.Authenticate(ctx, email, password, client)
+
+				Expect(err).To(Equal(apperror.ErrUserBanned))
 			})
 		})
 
 		Context("when password validation fails", func() {
 			It("should return error", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
 				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
 				mockPasswordManager.EXPECT().Compare(password, hashedPassword).Return(false, errors.New("invalid password"))
 
-				accessToken, refreshToken, err := userService.Authenticate(ctx, email, password, fingerprint)
+				result, err := userService.Authenticate(ctx, email, password, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("invalid password"))
-				Expect(accessToken).To(BeEmpty())
-				Expect(refreshToken).To(BeEmpty())
+				Expect(result.AccessToken).To(BeEmpty())
+				Expect(result.RefreshToken).To(BeEmpty())
 			})
 		})
 
 		Context("when revoking active tokens fails", func() {
 			It("should return error", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
 				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
 				mockPasswordManager.EXPECT().Compare(password, hashedPassword).Return(true, nil)
+				mockPasswordManager.EXPECT().NeedsRehash(hashedPassword).Return(false)
+				mockLoginGuard.EXPECT().Reset(ctx, email).Return(nil)
 				mockTokenService.EXPECT().RevokeActivesByUserID(ctx, testUser.ID).Return(errors.New("revoke error"))
 
-				accessToken, refreshToken, err := userService.Authenticate(ctx, email, password, fingerprint)
+				result, err := userService.Authenticate(ctx, email, password, client)
 
 				Expect(err).To(HaveOccurred())
-				Expect(accessToken).To(BeEmpty())
-				Expect(refreshToken).To(BeEmpty())
+				Expect(result.AccessToken).To(BeEmpty())
+				Expect(result.RefreshToken).To(BeEmpty())
 			})
 		})
 
 		Context("when cleaning up expired tokens fails", func() {
 			It("should return error", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
 				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
 				mockPasswordManager.EXPECT().Compare(password, hashedPassword).Return(true, nil)
+				mockPasswordManager.EXPECT().NeedsRehash(hashedPassword).Return(false)
+				mockLoginGuard.EXPECT().Reset(ctx, email).Return(nil)
 				mockTokenService.EXPECT().RevokeActivesByUserID(ctx, testUser.ID).Return(nil)
 				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, testUser.ID).Return(errors.New("cleanup error"))
 
-				accessToken, refreshToken, err := userService.Authenticate(ctx, email, password, fingerprint)
+				result, err := userService.Authenticate(ctx, email, password, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("cleanup error"))
-				Expect(accessToken).To(BeEmpty())
-				Expect(refreshToken).To(BeEmpty())
+				Expect(result.AccessToken).To(BeEmpty())
+				Expect(result.RefreshToken).To(BeEmpty())
 			})
 		})
 
 		Context("when token generation fails", func() {
 			It("should return error", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
 				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
 				mockPasswordManager.EXPECT().Compare(password, hashedPassword).Return(true, nil)
+				mockPasswordManager.EXPECT().NeedsRehash(hashedPassword).Return(false)
+				mockLoginGuard.EXPECT().Reset(ctx, email).Return(nil)
 				mockTokenService.EXPECT().RevokeActivesByUserID(ctx, testUser.ID).Return(nil)
 				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, testUser.ID).Return(nil)
 				mockTokenService.EXPECT().
-					GenerateTokens(ctx, fingerprint, testUser.ID).
+					GenerateTokens(ctx, fingerprint, testUser.ID, gomock.Any()).
 					Return("", entity.RefreshToken{}, errors.New("token generation error"))
 
-				accessToken, refreshToken, err := userService.Authenticate(ctx, email, password, fingerprint)
+				result, err := userService.Authenticate(ctx, email, password, client)
 
 				Expect(err).To(HaveOccurred())
-				Expect(accessToken).To(BeEmpty())
-				Expect(refreshToken).To(BeEmpty())
+				Expect(result.AccessToken).To(BeEmpty())
+				Expect(result.RefreshToken).To(BeEmpty())
 			})
 		})
 
 		Context("when token insertion fails", func() {
 			It("should return error", func() {
+				mockLoginGuard.EXPECT().Check(ctx, email, "").Return(nil)
 				mockRepo.EXPECT().GetUser(ctx, email).Return(testUser, nil)
 				mockPasswordManager.EXPECT().Compare(password, hashedPassword).Return(true, nil)
+				mockPasswordManager.EXPECT().NeedsRehash(hashedPassword).Return(false)
+				mockLoginGuard.EXPECT().Reset(ctx, email).Return(nil)
 				mockTokenService.EXPECT().RevokeActivesByUserID(ctx, testUser.ID).Return(nil)
 				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, testUser.ID).Return(nil)
 				mockTokenService.EXPECT().
-					GenerateTokens(ctx, fingerprint, testUser.ID).
+					GenerateTokens(ctx, fingerprint, testUser.ID, gomock.Any()).
 					Return("access-token", entity.RefreshToken{}, nil)
 				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(errors.New("insert error"))
 
-				accessToken, refreshToken, err := userService.Authenticate(ctx, email, password, fingerprint)
+				result, err := userService.Authenticate(ctx, email, password, client)
 
 				Expect(err).To(HaveOccurred())
-				Expect(accessToken).To(BeEmpty())
-				Expect(refreshToken).To(BeEmpty())
+				Expect(result.AccessToken).To(BeEmpty())
+				Expect(result.RefreshToken).To(BeEmpty())
 			})
 		})
 	})
@@ -289,6 +426,7 @@ var _ = Describe("UserService", func() {
 		var (
 			refreshTokenStr   string
 			fingerprint       string
+			client            entity.ClientInfo
 			userID            uint
 			validRefreshToken entity.RefreshToken
 		)
@@ -296,27 +434,32 @@ var _ = Describe("UserService", func() {
 		BeforeEach(func() {
 			refreshTokenStr = uuid.New().String()
 			fingerprint = "test-fingerprint"
+			client = entity.ClientInfo{Fingerprint: fingerprint}
 			userID = uint(1)
 			validRefreshToken = entity.RefreshToken{
 				UUID:        uuid.New(),
 				ExpiresAt:   time.Now().Add(time.Hour),
 				Fingerprint: fingerprint,
 				UserID:      userID,
+				FamilyID:    uuid.New(),
 			}
 		})
 
 		Context("when token refresh is successful", func() {
 			It("should refresh tokens and return new ones", func() {
 				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(validRefreshToken, nil)
-				mockTokenService.EXPECT().Update(ctx, gomock.Any()).Return(validRefreshToken, nil)
 				mockRepo.EXPECT().GetUserByID(ctx, userID).Return(entity.User{ID: userID}, nil)
 				mockTokenService.EXPECT().
-					GenerateTokens(ctx, fingerprint, userID).
+					GenerateTokens(ctx, fingerprint, userID, gomock.Any()).
 					Return("new-access-token", entity.RefreshToken{UUID: uuid.New()}, nil)
 				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, userID).Return(nil)
-				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(nil)
+				mockTokenService.EXPECT().Rotate(ctx, validRefreshToken.UUID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, token entity.RefreshToken) error {
+						Expect(token.FamilyID).To(Equal(validRefreshToken.FamilyID))
+						return nil
+					})
 
-				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, fingerprint)
+				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, client)
 
 				Expect(err).ToNot(HaveOccurred())
 				Expect(accessToken).ToNot(BeEmpty())
@@ -331,7 +474,7 @@ var _ = Describe("UserService", func() {
 
 				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(invalidToken, nil)
 
-				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, fingerprint)
+				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(Equal(apperror.ErrInvalidToken))
@@ -343,8 +486,9 @@ var _ = Describe("UserService", func() {
 		Context("when fingerprint is invalid", func() {
 			It("should return invalid fingerprint error", func() {
 				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(validRefreshToken, nil)
+				wrongClient := entity.ClientInfo{Fingerprint: "wrong-fingerprint"}
 
-				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, "wrong-fingerprint")
+				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, wrongClient)
 
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(Equal(apperror.ErrInvalidFingerprint))
@@ -359,20 +503,7 @@ var _ = Describe("UserService", func() {
 					GetByUUID(ctx, refreshTokenStr).
 					Return(entity.RefreshToken{}, errors.New("database error"))
 
-				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, fingerprint)
-
-				Expect(err).To(HaveOccurred())
-				Expect(accessToken).To(BeEmpty())
-				Expect(newRefreshToken).To(BeEmpty())
-			})
-		})
-
-		Context("when updating refresh token fails", func() {
-			It("should return error", func() {
-				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(validRefreshToken, nil)
-				mockTokenService.EXPECT().Update(ctx, gomock.Any()).Return(entity.RefreshToken{}, errors.New("update error"))
-
-				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, fingerprint)
+				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
@@ -383,10 +514,9 @@ var _ = Describe("UserService", func() {
 		Context("when getting user by ID fails", func() {
 			It("should return error", func() {
 				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(validRefreshToken, nil)
-				mockTokenService.EXPECT().Update(ctx, gomock.Any()).Return(validRefreshToken, nil)
 				mockRepo.EXPECT().GetUserByID(ctx, userID).Return(entity.User{}, errors.New("user not found"))
 
-				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, fingerprint)
+				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
@@ -397,13 +527,12 @@ var _ = Describe("UserService", func() {
 		Context("when generating new tokens fails", func() {
 			It("should return error", func() {
 				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(validRefreshToken, nil)
-				mockTokenService.EXPECT().Update(ctx, gomock.Any()).Return(validRefreshToken, nil)
 				mockRepo.EXPECT().GetUserByID(ctx, userID).Return(entity.User{ID: userID}, nil)
 				mockTokenService.EXPECT().
-					GenerateTokens(ctx, fingerprint, userID).
+					GenerateTokens(ctx, fingerprint, userID, gomock.Any()).
 					Return("", entity.RefreshToken{}, errors.New("token generation error"))
 
-				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, fingerprint)
+				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
@@ -414,14 +543,13 @@ var _ = Describe("UserService", func() {
 		Context("when cleaning up expired tokens fails", func() {
 			It("should return error", func() {
 				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(validRefreshToken, nil)
-				mockTokenService.EXPECT().Update(ctx, gomock.Any()).Return(validRefreshToken, nil)
 				mockRepo.EXPECT().GetUserByID(ctx, userID).Return(entity.User{ID: userID}, nil)
 				mockTokenService.EXPECT().
-					GenerateTokens(ctx, fingerprint, userID).
+					GenerateTokens(ctx, fingerprint, userID, gomock.Any()).
 					Return("new-access-token", entity.RefreshToken{}, nil)
 				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, userID).Return(errors.New("cleanup error"))
 
-				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, fingerprint)
+				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("cleanup error"))
@@ -430,24 +558,72 @@ var _ = Describe("UserService", func() {
 			})
 		})
 
-		Context("when inserting new refresh token fails", func() {
+		Context("when rotating refresh token fails", func() {
 			It("should return error", func() {
 				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(validRefreshToken, nil)
-				mockTokenService.EXPECT().Update(ctx, gomock.Any()).Return(validRefreshToken, nil)
 				mockRepo.EXPECT().GetUserByID(ctx, userID).Return(entity.User{ID: userID}, nil)
 				mockTokenService.EXPECT().
-					GenerateTokens(ctx, fingerprint, userID).
+					GenerateTokens(ctx, fingerprint, userID, gomock.Any()).
 					Return("new-access-token", entity.RefreshToken{}, nil)
 				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, userID).Return(nil)
-				mockTokenService.EXPECT().InsertToken(ctx, gomock.Any()).Return(errors.New("insert error"))
+				mockTokenService.EXPECT().Rotate(ctx, validRefreshToken.UUID, gomock.Any()).
+					Return(errors.New("rotate error"))
 
-				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, fingerprint)
+				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, client)
 
 				Expect(err).To(HaveOccurred())
 				Expect(accessToken).To(BeEmpty())
 				Expect(newRefreshToken).To(BeEmpty())
 			})
 		})
+
+		Context("when rotated refresh token is presented again", func() {
+			It("should revoke the whole family, log security event and notify user", func() {
+				replacedBy := uuid.New()
+				revokedAt := time.Now().Add(-time.Minute)
+				stolen := validRefreshToken
+				stolen.ReplacedBy = &replacedBy
+				stolen.RevokedAt = &revokedAt
+
+				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(stolen, nil)
+				mockTokenService.EXPECT().RevokeFamily(ctx, stolen.FamilyID).Return(nil)
+				mockRepo.EXPECT().GetUserByID(ctx, userID).
+					Return(entity.User{ID: userID, Email: "a@b.c", Role: entity.RoleUser}, nil)
+				mockSecurityLog.EXPECT().Log(gomock.Any()).
+					DoAndReturn(func(entries []entity.AuditEntry) error {
+						Expect(entries).To(HaveLen(1))
+						Expect(entries[0].Url).To(Equal(entity.SecurityEventRefreshTokenReuse))
+						Expect(entries[0].UserID).To(Equal(userID))
+						return nil
+					})
+				mockEmailService.EXPECT().RefreshTokenReused(gomock.Any(), "a@b.c")
+
+				accessToken, newRefreshToken, err := userService.Refresh(ctx, refreshTokenStr, client)
+
+				Expect(err).To(MatchError(apperror.ErrRefreshTokenReused))
+				Expect(accessToken).To(BeEmpty())
+				Expect(newRefreshToken).To(BeEmpty())
+			})
+		})
+
+		Context("when refresh token is rotated concurrently", func() {
+			It("should treat it as reuse", func() {
+				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(validRefreshToken, nil)
+				mockRepo.EXPECT().GetUserByID(ctx, userID).Return(entity.User{ID: userID}, nil).Times(2)
+				mockTokenService.EXPECT().
+					GenerateTokens(ctx, fingerprint, userID, gomock.Any()).
+					Return("new-access-token", entity.RefreshToken{UUID: uuid.New()}, nil)
+				mockTokenService.EXPECT().CleanUpExpiredByUserID(ctx, userID).Return(nil)
+				mockTokenService.EXPECT().Rotate(ctx, validRefreshToken.UUID, gomock.Any()).
+					Return(apperror.ErrRefreshTokenReused)
+				mockTokenService.EXPECT().RevokeFamily(ctx, validRefreshToken.FamilyID).Return(nil)
+				mockSecurityLog.EXPECT().Log(gomock.Any()).Return(nil)
+
+				_, _, err := userService.Refresh(ctx, refreshTokenStr, client)
+
+				Expect(err).To(MatchError(apperror.ErrRefreshTokenReused))
+			})
+		})
 	})
 
 	Describe("Logout", func() {
@@ -464,13 +640,14 @@ var _ = Describe("UserService", func() {
 				UUID:        uuid.New(),
 				ExpiresAt:   time.Now().Add(time.Hour),
 				Fingerprint: fingerprint,
+				FamilyID:    uuid.New(),
 			}
 		})
 
 		Context("when logout is successful", func() {
 			It("should logout user successfully", func() {
 				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(validRefreshToken, nil)
-				mockTokenService.EXPECT().Update(ctx, gomock.Any()).Return(entity.RefreshToken{}, nil)
+				mockTokenService.EXPECT().RevokeFamily(ctx, validRefreshToken.FamilyID).Return(nil)
 
 				err := userService.Logout(ctx, refreshTokenStr, fingerprint)
 
@@ -536,10 +713,10 @@ var _ = Describe("UserService", func() {
 			})
 		})
 
-		Context("when token update fails", func() {
+		Context("when token revocation fails", func() {
 			It("should return error", func() {
 				mockTokenService.EXPECT().GetByUUID(ctx, refreshTokenStr).Return(validRefreshToken, nil)
-				mockTokenService.EXPECT().Update(ctx, gomock.Any()).Return(entity.RefreshToken{}, errors.New("update error"))
+				mockTokenService.EXPECT().RevokeFamily(ctx, validRefreshToken.FamilyID).Return(errors.New("revoke error"))
 
 				err := userService.Logout(ctx, refreshTokenStr, fingerprint)
 
@@ -586,4 +763,220 @@ var _ = Describe("UserService", func() {
 			})
 		})
 	})
+
+	Describe("VerifyEmail", func() {
+		It("should use token and mark email as verified", func() {
+			mockRepo.EXPECT().
+				UseActionToken(ctx, security.HashOpaqueToken("token"), entity.TokenPurposeVerifyEmail).
+				Return(uint(1), nil)
+			mockRepo.EXPECT().MarkEmailVerified(ctx, uint(1)).Return(nil)
+
+			Expect(userService.VerifyEmail(ctx, "token")).To(Succeed())
+		})
+
+		It("should return error for invalid token", func() {
+			mockRepo.EXPECT().UseActionToken(ctx, gomock.Any(), entity.TokenPurposeVerifyEmail).
+				Return(uint(0), apperror.ErrInvalidActionToken)
+
+			Expect(userService.VerifyEmail(ctx, "token")).To(MatchError(apperror.ErrInvalidActionToken))
+		})
+	})
+
+	Describe("ResendVerification", func() {
+		It("should refuse when email is already verified", func() {
+			verifiedAt := time.Now()
+			mockRepo.EXPECT().GetUserByID(ctx, uint(1)).
+				Return(entity.User{ID: 1, EmailVerifiedAt: &verifiedAt}, nil)
+
+			err := userService.ResendVerification(ctx, 1)
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should send a new token", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(1)).Return(entity.User{ID: 1, Email: "a@b.c"}, nil)
+			mockRepo.EXPECT().InsertActionToken(ctx, gomock.Any()).Return(nil)
+			mockEmailService.EXPECT().VerificationRequested(gomock.Any(), "a@b.c")
+
+			Expect(userService.ResendVerification(ctx, 1)).To(Succeed())
+		})
+	})
+
+	Describe("ForgotPassword", func() {
+		It("should store token hash and mail the token", func() {
+			var stored entity.UserActionToken
+			var mailed string
+
+			mockRepo.EXPECT().GetUser(ctx, "a@b.c").Return(entity.User{ID: 1, Email: "a@b.c"}, nil)
+			mockRepo.EXPECT().InsertActionToken(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, token entity.UserActionToken) error {
+					stored = token
+					return nil
+				})
+			mockEmailService.EXPECT().PasswordResetRequested(gomock.Any(), "a@b.c").
+				Do(func(token, _ string) { mailed = token })
+
+			Expect(userService.ForgotPassword(ctx, "a@b.c")).To(Succeed())
+			Expect(stored.Purpose).To(Equal(entity.TokenPurposeResetPassword))
+			Expect(stored.TokenHash).To(Equal(security.HashOpaqueToken(mailed)))
+		})
+
+		It("should not reveal unknown email", func() {
+			mockRepo.EXPECT().GetUser(ctx, "unknown@b.c").Return(entity.User{}, apperror.ErrUserNotFound)
+
+			Expect(userService.ForgotPassword(ctx, "unknown@b.c")).To(Succeed())
+		})
+	})
+
+	Describe("ResetPassword", func() {
+		It("should update password and revoke all sessions", func() {
+			mockRepo.EXPECT().UseActionToken(ctx, gomock.Any(), entity.TokenPurposeResetPassword).Return(uint(1), nil)
+			mockPasswordManager.EXPECT().Hash("new-password").Return("new-hash", nil)
+			mockRepo.EXPECT().UpdatePassword(ctx, uint(1), "new-hash").Return(nil)
+			mockTokenService.EXPECT().RevokeAllByUserID(ctx, uint(1)).Return(nil)
+
+			Expect(userService.ResetPassword(ctx, "token", "new-password")).To(Succeed())
+		})
+
+		It("should not change password for invalid token", func() {
+			mockRepo.EXPECT().UseActionToken(ctx, gomock.Any(), entity.TokenPurposeResetPassword).
+				Return(uint(0), apperror.ErrInvalidActionToken)
+
+			Expect(userService.ResetPassword(ctx, "token", "new-password")).To(MatchError(apperror.ErrInvalidActionToken))
+		})
+	})
+
+	Describe("UpdateProfile", func() {
+		It("should refuse empty update", func() {
+			Expect(userService.UpdateProfile(ctx, 1, nil, nil)).To(HaveOccurred())
+		})
+
+		It("should update provided fields", func() {
+			name := "New Name"
+			mockRepo.EXPECT().UpdateProfile(ctx, uint(1), &name, nil).Return(nil)
+
+			Expect(userService.UpdateProfile(ctx, 1, &name, nil)).To(Succeed())
+		})
+	})
+
+	Describe("ChangePassword", func() {
+		It("should refuse wrong current password", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(1)).Return(entity.User{ID: 1, Password: "hash"}, nil)
+			mockPasswordManager.EXPECT().Compare("wrong", "hash").Return(false, nil)
+
+			err := userService.ChangePassword(ctx, 1, "wrong", "new-password")
+
+			Expect(err).To(MatchError(apperror.ErrIncorrectPassword))
+		})
+
+		It("should update password and revoke all sessions", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(1)).Return(entity.User{ID: 1, Password: "hash"}, nil)
+			mockPasswordManager.EXPECT().Compare("current", "hash").Return(true, nil)
+			mockPasswordManager.EXPECT().Hash("new-password").Return("new-hash", nil)
+			mockRepo.EXPECT().UpdatePassword(ctx, uint(1), "new-hash").Return(nil)
+			mockTokenService.EXPECT().RevokeAllByUserID(ctx, uint(1)).Return(nil)
+
+			Expect(userService.ChangePassword(ctx, 1, "current", "new-password")).To(Succeed())
+		})
+	})
+
+	Describe("ChangeEmail", func() {
+		It("should update email and send verification to the new address", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(1)).
+				Return(entity.User{ID: 1, Email: "old@b.c", Password: "hash"}, nil)
+			mockPasswordManager.EXPECT().Compare("current", "hash").Return(true, nil)
+			mockRepo.EXPECT().UpdateEmail(ctx, uint(1), "new@b.c").Return(nil)
+			mockRepo.EXPECT().InsertActionToken(ctx, gomock.Any()).Return(nil)
+			mockEmailService.EXPECT().VerificationRequested(gomock.Any(), "new@b.c")
+
+			Expect(userService.ChangeEmail(ctx, 1, "current", "new@b.c")).To(Succeed())
+		})
+
+		It("should refuse the same email", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(1)).
+				Return(entity.User{ID: 1, Email: "old@b.c", Password: "hash"}, nil)
+			mockPasswordManager.EXPECT().Compare("current", "hash").Return(true, nil)
+
+			Expect(userService.ChangeEmail(ctx, 1, "current", "old@b.c")).To(HaveOccurred())
+		})
+	})
+
+	Describe("DeleteAccount", func() {
+		It("should anonymize user after password check", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(1)).Return(entity.User{ID: 1, Password: "hash"}, nil)
+			mockPasswordManager.EXPECT().Compare("current", "hash").Return(true, nil)
+			gomock.InOrder(
+				mockTokenService.EXPECT().RevokeAllByUserID(ctx, uint(1)).Return(nil),
+				mockRepo.EXPECT().AnonymizeUser(ctx, uint(1)).Return(nil),
+			)
+
+			Expect(userService.DeleteAccount(ctx, 1, "current")).To(Succeed())
+		})
+
+		It("should not anonymize user when sessions cannot be revoked", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(1)).Return(entity.User{ID: 1, Password: "hash"}, nil)
+			mockPasswordManager.EXPECT().Compare("current", "hash").Return(true, nil)
+			mockTokenService.EXPECT().RevokeAllByUserID(ctx, uint(1)).Return(errors.New("db down"))
+
+			Expect(userService.DeleteAccount(ctx, 1, "current")).To(HaveOccurred())
+		})
+
+		It("should not delete account with wrong password", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(1)).Return(entity.User{ID: 1, Password: "hash"}, nil)
+			mockPasswordManager.EXPECT().Compare("wrong", "hash").Return(false, nil)
+
+			Expect(userService.DeleteAccount(ctx, 1, "wrong")).To(MatchError(apperror.ErrIncorrectPassword))
+		})
+	})
+
+	Describe("BanUser", func() {
+		It("should ban the user and revoke all sessions", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(2)).Return(entity.User{ID: 2, Role: entity.RoleUser}, nil)
+			mockRepo.EXPECT().SetBanned(ctx, uint(2), true).Return(nil)
+			mockTokenService.EXPECT().RevokeAllByUserID(ctx, uint(2)).Return(nil)
+
+			Expect(userService.BanUser(ctx, 2)).To(Succeed())
+		})
+
+		It("should refuse to ban an administrator", func() {
+			mockRepo.EXPECT().GetUserByID(ctx, uint(2)).Return(entity.User{ID: 2, Role: entity.RoleAdmin}, nil)
+
+			err := userService.BanUser(ctx, 2)
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.Forbidden))
+		})
+	})
+
+	Describe("RevokeSession", func() {
+		var sessionUUID uuid.UUID
+
+		BeforeEach(func() {
+			sessionUUID = uuid.New()
+		})
+
+		It("should revoke own active session", func() {
+			familyID := uuid.New()
+			mockTokenService.EXPECT().GetByUUID(ctx, sessionUUID.String()).
+				Return(entity.RefreshToken{
+					UUID:      sessionUUID,
+					UserID:    1,
+					ExpiresAt: time.Now().Add(time.Hour),
+					FamilyID:  familyID,
+				}, nil)
+			mockTokenService.EXPECT().RevokeFamily(ctx, familyID).Return(nil)
+
+			Expect(userService.RevokeSession(ctx, 1, sessionUUID.String())).To(Succeed())
+		})
+
+		It("should not reveal session of another user", func() {
+			mockTokenService.EXPECT().GetByUUID(ctx, sessionUUID.String()).
+				Return(entity.RefreshToken{UUID: sessionUUID, UserID: 2, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+			err := userService.RevokeSession(ctx, 1, sessionUUID.String())
+
+			Expect(err).To(MatchError(apperror.ErrTokenNotFound))
+		})
+	})
 })
@@ -0,0 +1,219 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
+	"go.uber.org/zap"
+)
+
+const (
+	// responseBodyLimit ограничивает часть ответа получателя, которая сохраняется в журнал
+	responseBodyLimit = 1024
+	// errorLimit ограничивает длину сохраняемой ошибки
+	errorLimit = 500
+)
+
+var errPrivateTarget = errors.New("webhook target resolves to a private address")
+
+// Dispatcher периодически отправляет доставки из очереди. Неудачные попытки повторяются
+// с экспоненциально растущей задержкой, после cfg.MaxAttempts доставка помечается dead.
+type Dispatcher struct {
+	repository Repository
+	client     *http.Client
+	cfg        *config.WebhookConfig
+	log        *zap.Logger
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+func NewDispatcher(
+	repository Repository,
+	client *http.Client,
+	cfg *config.WebhookConfig,
+	log *zap.Logger,
+) *Dispatcher {
+	d := &Dispatcher{
+		repository: repository,
+		client:     client,
+		cfg:        cfg,
+		log:        log,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	go d.run(cfg.PollInterval)
+
+	return d
+}
+
+// NewHTTPClient возвращает клиента для доставки вебхуков. Адрес задает магазин, поэтому клиент
+// не подключается к внутренним сетям, не ходит через прокси и не следует редиректам.
+func NewHTTPClient(cfg *config.WebhookConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateTargets {
+		dialer.Control = denyPrivateTargets
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// denyPrivateTargets проверяет адрес уже после разрешения имени, поэтому DNS не помогает его обойти
+func denyPrivateTargets(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return errPrivateTarget
+	}
+
+	return nil
+}
+
+// Close останавливает отправку и дожидается завершения текущего прохода
+func (d *Dispatcher) Close() {
+	close(d.stop)
+	<-d.done
+}
+
+func (d *Dispatcher) run(interval time.Duration) {
+	defer close(d.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.dispatch(context.Background())
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// dispatch отправляет одну пачку доставок параллельно. Доставки откладываются на время
+// запроса с запасом, чтобы другой экземпляр приложения не отправил их одновременно.
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	jobs, err := d.repository.ClaimWebhookDeliveries(ctx, d.cfg.BatchSize, d.cfg.Timeout+time.Minute)
+	if err != nil {
+		d.log.Error("Failed to claim webhook deliveries", zap.Error(err))
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, job)
+		}()
+	}
+	wg.Wait()
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, job entity.WebhookJob) {
+	delivery := job.Delivery
+	attempt := d.send(ctx, job)
+
+	delivery.Attempts++
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = attempt.Error
+
+	switch {
+	case attempt.Error == "":
+		delivery.Status = entity.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &attempt.AttemptedAt
+	case delivery.Attempts >= d.cfg.MaxAttempts:
+		delivery.Status = entity.WebhookDeliveryDead
+	default:
+		delivery.NextAttemptAt = attempt.AttemptedAt.Add(d.backoff(delivery.Attempts))
+	}
+
+	if err := d.repository.FinishWebhookAttempt(ctx, delivery, attempt); err != nil {
+		d.log.Error("Failed to save webhook attempt", zap.Uint("delivery_id", delivery.ID), zap.Error(err))
+		return
+	}
+
+	if delivery.Status == entity.WebhookDeliveryDead {
+		d.log.Warn("Webhook delivery is dead",
+			zap.Uint("delivery_id", delivery.ID), zap.Uint("endpoint_id", delivery.EndpointID))
+	}
+}
+
+// send выполняет одну попытку. Успехом считается только ответ 2xx.
+func (d *Dispatcher) send(ctx context.Context, job entity.WebhookJob) entity.WebhookAttempt {
+	attempt := entity.WebhookAttempt{DeliveryID: job.Delivery.ID, AttemptedAt: time.Now()}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(job.Delivery.Payload))
+	if err != nil {
+		attempt.Error = truncate(err.Error(), errorLimit)
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Stawberry-Webhooks/1.0")
+	req.Header.Set("X-Stawberry-Event", string(job.Delivery.Event))
+	req.Header.Set("X-Stawberry-Event-ID", job.Delivery.EventID)
+	req.Header.Set("X-Stawberry-Signature",
+		security.SignWebhook(job.Secret, attempt.AttemptedAt.Unix(), job.Delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	attempt.Duration = time.Since(attempt.AttemptedAt)
+	if err != nil {
+		attempt.Error = truncate(err.Error(), errorLimit)
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+	attempt.ResponseBody = truncate(string(body), responseBodyLimit)
+	attempt.StatusCode = &resp.StatusCode
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = "unexpected response status " + resp.Status
+	}
+
+	return attempt
+}
+
+// backoff возвращает задержку после attempts неудачных попыток
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.RetryBaseDelay
+	for i := 1; i < attempts && delay < d.cfg.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, d.cfg.RetryMaxDelay)
+}
+
+// truncate обрезает строку для журнала и убирает из нее то, что Postgres не примет в TEXT
+func truncate(s string, limit int) string {
+	if len(s) > limit {
+		s = s[:limit]
+	}
+	return strings.ReplaceAll(strings.ToValidUTF8(s, ""), "\x00", "")
+}
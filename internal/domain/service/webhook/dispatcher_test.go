@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("Dispatcher", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockRepo   *MockRepository
+		cfg        *config.WebhookConfig
+		dispatcher *Dispatcher
+		server     *httptest.Server
+		status     int
+		received   *http.Request
+		body       []byte
+		ctx        context.Context
+	)
+
+	payload := []byte(`{"id":"e1","event":"offer.created"}`)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		cfg = &config.WebhookConfig{
+			BatchSize:           10,
+			Timeout:             time.Second,
+			MaxAttempts:         3,
+			RetryBaseDelay:      time.Minute,
+			RetryMaxDelay:       time.Hour,
+			AllowPrivateTargets: true,
+		}
+		status = http.StatusOK
+		received = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte("ok"))
+		}))
+		// Без запуска фонового цикла, проходы вызываются напрямую
+		dispatcher = &Dispatcher{
+			repository: mockRepo,
+			client:     NewHTTPClient(cfg),
+			cfg:        cfg,
+			log:        zap.NewNop(),
+		}
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		server.Close()
+		ctrl.Finish()
+	})
+
+	job := func(attempts int) entity.WebhookJob {
+		return entity.WebhookJob{
+			Delivery: entity.WebhookDelivery{
+				ID:       50,
+				EventID:  "e1",
+				Event:    entity.WebhookOfferCreated,
+				Payload:  payload,
+				Status:   entity.WebhookDeliveryPending,
+				Attempts: attempts,
+			},
+			URL:    server.URL,
+			Secret: "whsec_test",
+		}
+	}
+
+	It("should send a signed payload and mark the delivery delivered", func() {
+		mockRepo.EXPECT().ClaimWebhookDeliveries(ctx, 10, gomock.Any()).Return([]entity.WebhookJob{job(0)}, nil)
+		mockRepo.EXPECT().FinishWebhookAttempt(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, d entity.WebhookDelivery, a entity.WebhookAttempt) error {
+				Expect(d.Status).To(Equal(entity.WebhookDeliveryDelivered))
+				Expect(d.Attempts).To(Equal(1))
+				Expect(d.DeliveredAt).NotTo(BeNil())
+				Expect(*a.StatusCode).To(Equal(http.StatusOK))
+				Expect(a.ResponseBody).To(Equal("ok"))
+				Expect(a.Error).To(BeEmpty())
+				return nil
+			})
+
+		dispatcher.dispatch(ctx)
+
+		Expect(body).To(Equal(payload))
+		Expect(received.Header.Get("X-Stawberry-Event")).To(Equal("offer.created"))
+		Expect(received.Header.Get("X-Stawberry-Event-ID")).To(Equal("e1"))
+
+		signature := received.Header.Get("X-Stawberry-Signature")
+		Expect(signature).To(HavePrefix("t="))
+		var ts int64
+		_, err := fmt.Sscanf(signature, "t=%d,", &ts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(signature).To(Equal(security.SignWebhook("whsec_test", ts, payload)))
+	})
+
+	It("should schedule a retry with exponential backoff on failure", func() {
+		status = http.StatusInternalServerError
+		mockRepo.EXPECT().FinishWebhookAttempt(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, d entity.WebhookDelivery, a entity.WebhookAttempt) error {
+				Expect(d.Status).To(Equal(entity.WebhookDeliveryPending))
+				Expect(d.Attempts).To(Equal(2))
+				Expect(*d.LastStatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(d.NextAttemptAt).To(BeTemporally("~", a.AttemptedAt.Add(2*time.Minute), time.Second))
+				return nil
+			})
+
+		dispatcher.deliver(ctx, job(1))
+	})
+
+	It("should mark the delivery dead after the last attempt", func() {
+		status = http.StatusGone
+		mockRepo.EXPECT().FinishWebhookAttempt(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, d entity.WebhookDelivery, _ entity.WebhookAttempt) error {
+				Expect(d.Status).To(Equal(entity.WebhookDeliveryDead))
+				Expect(d.Attempts).To(Equal(3))
+				return nil
+			})
+
+		dispatcher.deliver(ctx, job(2))
+	})
+
+	It("should refuse private addresses unless allowed", func() {
+		cfg.AllowPrivateTargets = false
+		dispatcher.client = NewHTTPClient(cfg)
+		mockRepo.EXPECT().FinishWebhookAttempt(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, d entity.WebhookDelivery, a entity.WebhookAttempt) error {
+				Expect(a.StatusCode).To(BeNil())
+				Expect(a.Error).To(ContainSubstring(errPrivateTarget.Error()))
+				Expect(d.Status).To(Equal(entity.WebhookDeliveryPending))
+				return nil
+			})
+
+		dispatcher.deliver(ctx, job(0))
+
+		Expect(received).To(BeNil())
+	})
+
+	It("should cap the retry delay", func() {
+		Expect(dispatcher.backoff(1)).To(Equal(time.Minute))
+		Expect(dispatcher.backoff(3)).To(Equal(4 * time.Minute))
+		Expect(dispatcher.backoff(30)).To(Equal(time.Hour))
+	})
+})
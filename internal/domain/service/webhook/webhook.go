@@ -0,0 +1,302 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/pkg/security"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=webhook_mock_test.go -package=webhook Repository ShopMembers
+
+type Repository interface {
+	InsertWebhookEndpoint(ctx context.Context, endpoint entity.WebhookEndpoint) (uint, error)
+	SelectWebhookEndpoints(ctx context.Context, shopID uint) ([]entity.WebhookEndpoint, error)
+	GetWebhookEndpoint(ctx context.Context, shopID, endpointID uint) (entity.WebhookEndpoint, error)
+	DeleteWebhookEndpoint(ctx context.Context, shopID, endpointID uint) error
+	InsertWebhookDeliveries(ctx context.Context, deliveries []entity.WebhookDelivery) error
+	SelectWebhookDeliveries(ctx context.Context, endpointID uint, limit, offset int) ([]entity.WebhookDelivery, int, error)
+	GetWebhookDelivery(ctx context.Context, endpointID, deliveryID uint) (entity.WebhookDelivery, error)
+	SelectWebhookAttempts(ctx context.Context, deliveryID uint) ([]entity.WebhookAttempt, error)
+	RequeueWebhookDelivery(ctx context.Context, deliveryID uint) error
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookJob, error)
+	FinishWebhookAttempt(ctx context.Context, delivery entity.WebhookDelivery, attempt entity.WebhookAttempt) error
+}
+
+// ShopMembers возвращает роль пользователя в магазине
+type ShopMembers interface {
+	GetMemberRole(ctx context.Context, shopID, userID uint) (entity.ShopRole, error)
+}
+
+// Payload это тело вебхука. ID события одинаков во всех доставках и повторах,
+// по нему получатель отбрасывает дубликаты.
+type Payload struct {
+	ID        string              `json:"id"`
+	Event     entity.WebhookEvent `json:"event"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      any                 `json:"data"`
+}
+
+type offerData struct {
+	ID        uint      `json:"id"`
+	Price     float64   `json:"price"`
+	Currency  string    `json:"currency"`
+	Status    string    `json:"status"`
+	ShopID    uint      `json:"shop_id"`
+	ProductID uint      `json:"product_id"`
+	UserID    uint      `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type Service struct {
+	webhookRepository Repository
+	shopMembers       ShopMembers
+	cfg               *config.WebhookConfig
+}
+
+func NewService(
+	webhookRepository Repository,
+	shopMembers ShopMembers,
+	cfg *config.WebhookConfig,
+) *Service {
+	return &Service{
+		webhookRepository: webhookRepository,
+		shopMembers:       shopMembers,
+		cfg:               cfg,
+	}
+}
+
+// CreateEndpoint регистрирует адрес магазина и возвращает его вместе с секретом подписи.
+// Секрет показывается только при создании.
+func (s *Service) CreateEndpoint(
+	ctx context.Context,
+	shopID, actorID uint,
+	rawURL string,
+	events []entity.WebhookEvent,
+) (entity.WebhookEndpoint, error) {
+	if err := s.checkManager(ctx, shopID, actorID); err != nil {
+		return entity.WebhookEndpoint{}, err
+	}
+
+	if err := s.validateURL(rawURL); err != nil {
+		return entity.WebhookEndpoint{}, err
+	}
+
+	if len(events) == 0 {
+		return entity.WebhookEndpoint{}, apperror.New(apperror.BadRequest, "at least one event is required", nil)
+	}
+	for _, event := range events {
+		if !slices.Contains(entity.WebhookEvents, event) {
+			return entity.WebhookEndpoint{}, apperror.New(apperror.BadRequest, "unknown event "+string(event), nil)
+		}
+	}
+
+	secret, err := security.GenerateWebhookSecret()
+	if err != nil {
+		return entity.WebhookEndpoint{}, apperror.New(apperror.InternalError, "failed to generate webhook secret", err)
+	}
+
+	endpoint := entity.WebhookEndpoint{
+		ShopID:    shopID,
+		CreatedBy: actorID,
+		URL:       rawURL,
+		Secret:    secret,
+		Events:    slices.Compact(slices.Sorted(slices.Values(events))),
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+
+	endpoint.ID, err = s.webhookRepository.InsertWebhookEndpoint(ctx, endpoint)
+	if err != nil {
+		return entity.WebhookEndpoint{}, err
+	}
+
+	return endpoint, nil
+}
+
+// GetEndpoints возвращает адреса магазина
+func (s *Service) GetEndpoints(ctx context.Context, shopID, actorID uint) ([]entity.WebhookEndpoint, error) {
+	if err := s.checkManager(ctx, shopID, actorID); err != nil {
+		return nil, err
+	}
+
+	return s.webhookRepository.SelectWebhookEndpoints(ctx, shopID)
+}
+
+// DeleteEndpoint удаляет адрес, неотправленные доставки на него отменяются
+func (s *Service) DeleteEndpoint(ctx context.Context, shopID, actorID, endpointID uint) error {
+	if err := s.checkManager(ctx, shopID, actorID); err != nil {
+		return err
+	}
+
+	return s.webhookRepository.DeleteWebhookEndpoint(ctx, shopID, endpointID)
+}
+
+// GetDeliveries возвращает журнал доставок адреса, новые первыми
+func (s *Service) GetDeliveries(
+	ctx context.Context,
+	shopID, actorID, endpointID uint,
+	page, limit int,
+) ([]entity.WebhookDelivery, int, error) {
+	if _, err := s.endpoint(ctx, shopID, actorID, endpointID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.webhookRepository.SelectWebhookDeliveries(ctx, endpointID, limit, (page-1)*limit)
+}
+
+// GetDelivery возвращает доставку вместе с журналом ее попыток
+func (s *Service) GetDelivery(
+	ctx context.Context,
+	shopID, actorID, endpointID, deliveryID uint,
+) (entity.WebhookDelivery, []entity.WebhookAttempt, error) {
+	if _, err := s.endpoint(ctx, shopID, actorID, endpointID); err != nil {
+		return entity.WebhookDelivery{}, nil, err
+	}
+
+	delivery, err := s.webhookRepository.GetWebhookDelivery(ctx, endpointID, deliveryID)
+	if err != nil {
+		return entity.WebhookDelivery{}, nil, err
+	}
+
+	attempts, err := s.webhookRepository.SelectWebhookAttempts(ctx, deliveryID)
+	if err != nil {
+		return entity.WebhookDelivery{}, nil, err
+	}
+
+	return delivery, attempts, nil
+}
+
+// Redeliver возвращает доставленную или исчерпавшую попытки доставку в очередь.
+// Событие отправляется с тем же ID, поэтому получатель может распознать повтор.
+func (s *Service) Redeliver(ctx context.Context, shopID, actorID, endpointID, deliveryID uint) error {
+	if _, err := s.endpoint(ctx, shopID, actorID, endpointID); err != nil {
+		return err
+	}
+
+	delivery, err := s.webhookRepository.GetWebhookDelivery(ctx, endpointID, deliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery.Status == entity.WebhookDeliveryPending {
+		return apperror.New(apperror.Conflict, "webhook delivery is already queued", nil)
+	}
+
+	return s.webhookRepository.RequeueWebhookDelivery(ctx, deliveryID)
+}
+
+// HandleOfferEvent ставит в очередь доставку события по заявке на все подписанные адреса магазина.
+// ID события в теле вебхука совпадает с UID события outbox, поэтому повторная обработка
+// дает получателю дубликат, который он может распознать.
+func (s *Service) HandleOfferEvent(ctx context.Context, event entity.Event) error {
+	var payload entity.OfferEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	offer := payload.Offer
+	return s.publish(ctx, offer.ShopID, entity.WebhookEvent(event.Type), Payload{
+		ID:        event.UID,
+		Event:     entity.WebhookEvent(event.Type),
+		CreatedAt: event.CreatedAt,
+		Data: offerData{
+			ID:        offer.ID,
+			Price:     offer.Price,
+			Currency:  offer.Currency,
+			Status:    offer.Status,
+			ShopID:    offer.ShopID,
+			ProductID: offer.ProductID,
+			UserID:    offer.UserID,
+			CreatedAt: offer.CreatedAt,
+			UpdatedAt: offer.UpdatedAt,
+			ExpiresAt: offer.ExpiresAt,
+		},
+	})
+}
+
+func (s *Service) publish(ctx context.Context, shopID uint, event entity.WebhookEvent, payload Payload) error {
+	endpoints, err := s.webhookRepository.SelectWebhookEndpoints(ctx, shopID)
+	if err != nil {
+		return err
+	}
+
+	endpoints = slices.DeleteFunc(endpoints, func(e entity.WebhookEndpoint) bool {
+		return !e.Subscribed(event)
+	})
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]entity.WebhookDelivery, len(endpoints))
+	for i, endpoint := range endpoints {
+		deliveries[i] = entity.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			EventID:       payload.ID,
+			Event:         event,
+			Payload:       body,
+			Status:        entity.WebhookDeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+	}
+
+	return s.webhookRepository.InsertWebhookDeliveries(ctx, deliveries)
+}
+
+// endpoint проверяет права на управление вебхуками и возвращает адрес магазина
+func (s *Service) endpoint(
+	ctx context.Context,
+	shopID, actorID, endpointID uint,
+) (entity.WebhookEndpoint, error) {
+	if err := s.checkManager(ctx, shopID, actorID); err != nil {
+		return entity.WebhookEndpoint{}, err
+	}
+
+	return s.webhookRepository.GetWebhookEndpoint(ctx, shopID, endpointID)
+}
+
+// checkManager проверяет, что сотрудник может управлять вебхуками магазина
+func (s *Service) checkManager(ctx context.Context, shopID, actorID uint) error {
+	role, err := s.shopMembers.GetMemberRole(ctx, shopID, actorID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrShopMemberNotFound) {
+			return apperror.New(apperror.Forbidden, "not a member of the shop", err)
+		}
+		return err
+	}
+	if role != entity.ShopRoleOwner && role != entity.ShopRoleManager {
+		return apperror.New(apperror.Forbidden, "only owner and manager can manage webhooks", nil)
+	}
+	return nil
+}
+
+// validateURL пропускает только абсолютные https адреса. Адреса во внутренних сетях
+// дополнительно отсекаются при подключении, так как имя может указывать куда угодно.
+func (s *Service) validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return apperror.New(apperror.BadRequest, "webhook url must be an absolute url", err)
+	}
+	if u.User != nil {
+		return apperror.New(apperror.BadRequest, "webhook url must not contain credentials", nil)
+	}
+	if u.Scheme != "https" && (u.Scheme != "http" || !s.cfg.AllowPrivateTargets) {
+		return apperror.New(apperror.BadRequest, "webhook url must use https", nil)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webhook.go
+//
+// Generated by this command:
+//
+//	mockgen -source=webhook.go -destination=webhook_mock_test.go -package=webhook Repository ShopMembers
+//
+
+// Package webhook is a generated GoMock package.
+package webhook
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockRepository) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]entity.WebhookJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) ClaimWebhookDeliveries(ctx, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).ClaimWebhookDeliveries), ctx, limit, lease)
+}
+
+// DeleteWebhookEndpoint mocks base method.
+func (m *MockRepository) DeleteWebhookEndpoint(ctx context.Context, shopID, endpointID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpoint", ctx, shopID, endpointID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookEndpoint indicates an expected call of DeleteWebhookEndpoint.
+func (mr *MockRepositoryMockRecorder) DeleteWebhookEndpoint(ctx, shopID, endpointID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpoint", reflect.TypeOf((*MockRepository)(nil).DeleteWebhookEndpoint), ctx, shopID, endpointID)
+}
+
+// FinishWebhookAttempt mocks base method.
+func (m *MockRepository) FinishWebhookAttempt(ctx context.Context, delivery entity.WebhookDelivery, attempt entity.WebhookAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishWebhookAttempt", ctx, delivery, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishWebhookAttempt indicates an expected call of FinishWebhookAttempt.
+func (mr *MockRepositoryMockRecorder) FinishWebhookAttempt(ctx, delivery, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishWebhookAttempt", reflect.TypeOf((*MockRepository)(nil).FinishWebhookAttempt), ctx, delivery, attempt)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockRepository) GetWebhookDelivery(ctx context.Context, endpointID, deliveryID uint) (entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", ctx, endpointID, deliveryID)
+	ret0, _ := ret[0].(entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockRepositoryMockRecorder) GetWebhookDelivery(ctx, endpointID, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockRepository)(nil).GetWebhookDelivery), ctx, endpointID, deliveryID)
+}
+
+// GetWebhookEndpoint mocks base method.
+func (m *MockRepository) GetWebhookEndpoint(ctx context.Context, shopID, endpointID uint) (entity.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpoint", ctx, shopID, endpointID)
+	ret0, _ := ret[0].(entity.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpoint indicates an expected call of GetWebhookEndpoint.
+func (mr *MockRepositoryMockRecorder) GetWebhookEndpoint(ctx, shopID, endpointID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpoint", reflect.TypeOf((*MockRepository)(nil).GetWebhookEndpoint), ctx, shopID, endpointID)
+}
+
+// InsertWebhookDeliveries mocks base method.
+func (m *MockRepository) InsertWebhookDeliveries(ctx context.Context, deliveries []entity.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookDeliveries", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWebhookDeliveries indicates an expected call of InsertWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) InsertWebhookDeliveries(ctx, deliveries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).InsertWebhookDeliveries), ctx, deliveries)
+}
+
+// InsertWebhookEndpoint mocks base method.
+func (m *MockRepository) InsertWebhookEndpoint(ctx context.Context, endpoint entity.WebhookEndpoint) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhookEndpoint", ctx, endpoint)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhookEndpoint indicates an expected call of InsertWebhookEndpoint.
+func (mr *MockRepositoryMockRecorder) InsertWebhookEndpoint(ctx, endpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhookEndpoint", reflect.TypeOf((*MockRepository)(nil).InsertWebhookEndpoint), ctx, endpoint)
+}
+
+// RequeueWebhookDelivery mocks base method.
+func (m *MockRepository) RequeueWebhookDelivery(ctx context.Context, deliveryID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueWebhookDelivery", ctx, deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueWebhookDelivery indicates an expected call of RequeueWebhookDelivery.
+func (mr *MockRepositoryMockRecorder) RequeueWebhookDelivery(ctx, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueWebhookDelivery", reflect.TypeOf((*MockRepository)(nil).RequeueWebhookDelivery), ctx, deliveryID)
+}
+
+// SelectWebhookAttempts mocks base method.
+func (m *MockRepository) SelectWebhookAttempts(ctx context.Context, deliveryID uint) ([]entity.WebhookAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectWebhookAttempts", ctx, deliveryID)
+	ret0, _ := ret[0].([]entity.WebhookAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectWebhookAttempts indicates an expected call of SelectWebhookAttempts.
+func (mr *MockRepositoryMockRecorder) SelectWebhookAttempts(ctx, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectWebhookAttempts", reflect.TypeOf((*MockRepository)(nil).SelectWebhookAttempts), ctx, deliveryID)
+}
+
+// SelectWebhookDeliveries mocks base method.
+func (m *MockRepository) SelectWebhookDeliveries(ctx context.Context, endpointID uint, limit, offset int) ([]entity.WebhookDelivery, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectWebhookDeliveries", ctx, endpointID, limit, offset)
+	ret0, _ := ret[0].([]entity.WebhookDelivery)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectWebhookDeliveries indicates an expected call of SelectWebhookDeliveries.
+func (mr *MockRepositoryMockRecorder) SelectWebhookDeliveries(ctx, endpointID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectWebhookDeliveries", reflect.TypeOf((*MockRepository)(nil).SelectWebhookDeliveries), ctx, endpointID, limit, offset)
+}
+
+// SelectWebhookEndpoints mocks base method.
+func (m *MockRepository) SelectWebhookEndpoints(ctx context.Context, shopID uint) ([]entity.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectWebhookEndpoints", ctx, shopID)
+	ret0, _ := ret[0].([]entity.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectWebhookEndpoints indicates an expected call of SelectWebhookEndpoints.
+func (mr *MockRepositoryMockRecorder) SelectWebhookEndpoints(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectWebhookEndpoints", reflect.TypeOf((*MockRepository)(nil).SelectWebhookEndpoints), ctx, shopID)
+}
+
+// MockShopMembers is a mock of ShopMembers interface.
+type MockShopMembers struct {
+	ctrl     *gomock.Controller
+	recorder *MockShopMembersMockRecorder
+	isgomock struct{}
+}
+
+// MockShopMembersMockRecorder is the mock recorder for MockShopMembers.
+type MockShopMembersMockRecorder struct {
+	mock *MockShopMembers
+}
+
+// NewMockShopMembers creates a new mock instance.
+func NewMockShopMembers(ctrl *gomock.Controller) *MockShopMembers {
+	mock := &MockShopMembers{ctrl: ctrl}
+	mock.recorder = &MockShopMembersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShopMembers) EXPECT() *MockShopMembersMockRecorder {
+	return m.recorder
+}
+
+// GetMemberRole mocks base method.
+func (m *MockShopMembers) GetMemberRole(ctx context.Context, shopID, userID uint) (entity.ShopRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMemberRole", ctx, shopID, userID)
+	ret0, _ := ret[0].(entity.ShopRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMemberRole indicates an expected call of GetMemberRole.
+func (mr *MockShopMembersMockRecorder) GetMemberRole(ctx, shopID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMemberRole", reflect.TypeOf((*MockShopMembers)(nil).GetMemberRole), ctx, shopID, userID)
+}
@@ -0,0 +1,209 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+func expectAppErrorCode(err error, code string) {
+	var appErr *apperror.Error
+	ExpectWithOffset(1, errors.As(err, &appErr)).To(BeTrue())
+	ExpectWithOffset(1, appErr.Code()).To(Equal(code))
+}
+
+var _ = Describe("WebhookService", func() {
+	var (
+		ctrl        *gomock.Controller
+		mockRepo    *MockRepository
+		mockMembers *MockShopMembers
+		cfg         *config.WebhookConfig
+		service     *Service
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockMembers = NewMockShopMembers(ctrl)
+		cfg = &config.WebhookConfig{}
+		service = NewService(mockRepo, mockMembers, cfg)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateEndpoint", func() {
+		It("should store the endpoint with a signing secret", func() {
+			var stored entity.WebhookEndpoint
+			mockMembers.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleOwner, nil)
+			mockRepo.EXPECT().InsertWebhookEndpoint(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, endpoint entity.WebhookEndpoint) (uint, error) {
+					stored = endpoint
+					return 3, nil
+				})
+
+			endpoint, err := service.CreateEndpoint(ctx, 1, 10, "https://erp.example.com/hooks", []entity.WebhookEvent{
+				entity.WebhookOfferExpired, entity.WebhookOfferCreated, entity.WebhookOfferExpired,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(endpoint.ID).To(Equal(uint(3)))
+			Expect(endpoint.Secret).To(HavePrefix("whsec_"))
+			Expect(stored.Secret).To(Equal(endpoint.Secret))
+			Expect(stored.Active).To(BeTrue())
+			Expect(stored.Events).To(Equal([]entity.WebhookEvent{
+				entity.WebhookOfferCreated, entity.WebhookOfferExpired,
+			}))
+		})
+
+		It("should reject plain http unless private targets are allowed", func() {
+			mockMembers.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleOwner, nil).Times(2)
+
+			_, err := service.CreateEndpoint(ctx, 1, 10, "http://erp.example.com/hooks",
+				[]entity.WebhookEvent{entity.WebhookOfferCreated})
+			expectAppErrorCode(err, apperror.BadRequest)
+
+			cfg.AllowPrivateTargets = true
+			mockRepo.EXPECT().InsertWebhookEndpoint(ctx, gomock.Any()).Return(uint(3), nil)
+
+			_, err = service.CreateEndpoint(ctx, 1, 10, "http://localhost:9000/hooks",
+				[]entity.WebhookEvent{entity.WebhookOfferCreated})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject unknown events", func() {
+			mockMembers.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleManager, nil)
+
+			_, err := service.CreateEndpoint(ctx, 1, 10, "https://erp.example.com/hooks",
+				[]entity.WebhookEvent{"offer.countered"})
+
+			expectAppErrorCode(err, apperror.BadRequest)
+		})
+
+		It("should forbid agents to register endpoints", func() {
+			mockMembers.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleAgent, nil)
+
+			_, err := service.CreateEndpoint(ctx, 1, 10, "https://erp.example.com/hooks",
+				[]entity.WebhookEvent{entity.WebhookOfferCreated})
+
+			expectAppErrorCode(err, apperror.Forbidden)
+		})
+	})
+
+	Describe("HandleOfferEvent", func() {
+		offerEvent := func(eventType entity.EventType, offer entity.Offer) entity.Event {
+			payload, err := json.Marshal(entity.OfferEvent{Offer: offer})
+			Expect(err).NotTo(HaveOccurred())
+			return entity.Event{ID: 9, UID: "0b7c8a52-5f0e-4a5e-9a55-6a1f2d1c3b4e", Type: eventType, Payload: payload}
+		}
+
+		It("should enqueue one delivery per subscribed active endpoint", func() {
+			offer := entity.Offer{ID: 7, ShopID: 1, UserID: 20, ProductID: 30, Price: 99.5, Currency: "USD",
+				Status: "pending"}
+			mockRepo.EXPECT().SelectWebhookEndpoints(ctx, uint(1)).Return([]entity.WebhookEndpoint{
+				{ID: 1, Active: true, Events: []entity.WebhookEvent{entity.WebhookOfferCreated}},
+				{ID: 2, Active: true, Events: []entity.WebhookEvent{entity.WebhookOfferExpired}},
+				{ID: 3, Active: false, Events: []entity.WebhookEvent{entity.WebhookOfferCreated}},
+				{ID: 4, Active: true, Events: entity.WebhookEvents},
+			}, nil)
+
+			var queued []entity.WebhookDelivery
+			mockRepo.EXPECT().InsertWebhookDeliveries(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, deliveries []entity.WebhookDelivery) error {
+					queued = deliveries
+					return nil
+				})
+
+			Expect(service.HandleOfferEvent(ctx, offerEvent(entity.EventOfferCreated, offer))).To(Succeed())
+
+			Expect(queued).To(HaveLen(2))
+			Expect(queued[0].EndpointID).To(Equal(uint(1)))
+			Expect(queued[1].EndpointID).To(Equal(uint(4)))
+			Expect(queued[0].EventID).To(Equal("0b7c8a52-5f0e-4a5e-9a55-6a1f2d1c3b4e"))
+			Expect(queued[1].EventID).To(Equal(queued[0].EventID))
+			Expect(queued[0].Status).To(Equal(entity.WebhookDeliveryPending))
+
+			var payload struct {
+				ID    string `json:"id"`
+				Event string `json:"event"`
+				Data  struct {
+					ID     uint   `json:"id"`
+					ShopID uint   `json:"shop_id"`
+					Status string `json:"status"`
+				} `json:"data"`
+			}
+			Expect(json.Unmarshal(queued[0].Payload, &payload)).To(Succeed())
+			Expect(payload.ID).To(Equal(queued[0].EventID))
+			Expect(payload.Event).To(Equal("offer.created"))
+			Expect(payload.Data.ID).To(Equal(uint(7)))
+			Expect(payload.Data.ShopID).To(Equal(uint(1)))
+			Expect(payload.Data.Status).To(Equal("pending"))
+		})
+
+		It("should not enqueue anything without subscribers", func() {
+			mockRepo.EXPECT().SelectWebhookEndpoints(ctx, uint(1)).Return([]entity.WebhookEndpoint{
+				{ID: 1, Active: true, Events: []entity.WebhookEvent{entity.WebhookOfferExpired}},
+			}, nil)
+
+			Expect(service.HandleOfferEvent(ctx,
+				offerEvent(entity.EventOfferCreated, entity.Offer{ID: 7, ShopID: 1}))).To(Succeed())
+		})
+
+		It("should return the queue error so the event is retried", func() {
+			mockRepo.EXPECT().SelectWebhookEndpoints(ctx, uint(1)).Return([]entity.WebhookEndpoint{
+				{ID: 1, Active: true, Events: []entity.WebhookEvent{entity.WebhookOfferAccepted}},
+			}, nil)
+			mockRepo.EXPECT().InsertWebhookDeliveries(ctx, gomock.Any()).Return(errors.New("db is down"))
+
+			err := service.HandleOfferEvent(ctx, offerEvent(entity.EventOfferAccepted, entity.Offer{ID: 7, ShopID: 1}))
+
+			Expect(err).To(MatchError("db is down"))
+		})
+	})
+
+	Describe("Redeliver", func() {
+		BeforeEach(func() {
+			mockMembers.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleOwner, nil)
+			mockRepo.EXPECT().GetWebhookEndpoint(ctx, uint(1), uint(3)).Return(entity.WebhookEndpoint{ID: 3}, nil)
+		})
+
+		It("should requeue a dead delivery", func() {
+			mockRepo.EXPECT().GetWebhookDelivery(ctx, uint(3), uint(50)).
+				Return(entity.WebhookDelivery{ID: 50, Status: entity.WebhookDeliveryDead}, nil)
+			mockRepo.EXPECT().RequeueWebhookDelivery(ctx, uint(50)).Return(nil)
+
+			Expect(service.Redeliver(ctx, 1, 10, 3, 50)).To(Succeed())
+		})
+
+		It("should not requeue a delivery that is still queued", func() {
+			mockRepo.EXPECT().GetWebhookDelivery(ctx, uint(3), uint(50)).
+				Return(entity.WebhookDelivery{ID: 50, Status: entity.WebhookDeliveryPending}, nil)
+
+			err := service.Redeliver(ctx, 1, 10, 3, 50)
+
+			expectAppErrorCode(err, apperror.Conflict)
+		})
+	})
+
+	Describe("GetDeliveries", func() {
+		It("should not show deliveries of another shop's endpoint", func() {
+			mockMembers.EXPECT().GetMemberRole(ctx, uint(1), uint(10)).Return(entity.ShopRoleOwner, nil)
+			mockRepo.EXPECT().GetWebhookEndpoint(ctx, uint(1), uint(3)).
+				Return(entity.WebhookEndpoint{}, apperror.ErrWebhookNotFound)
+
+			_, _, err := service.GetDeliveries(ctx, 1, 10, 3, 1, 20)
+
+			Expect(err).To(MatchError(apperror.ErrWebhookNotFound))
+		})
+	})
+})
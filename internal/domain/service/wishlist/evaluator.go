@@ -0,0 +1,181 @@
+package wishlist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"go.uber.org/zap"
+)
+
+// searchMatchLimit ограничивает количество новых продуктов, обрабатываемых за один проход по поиску.
+// Остальные попадут в следующий проход.
+const searchMatchLimit = 50
+
+//go:generate mockgen -source=$GOFILE -destination=evaluator_mock_test.go -package=wishlist Notifier ProductFinder
+
+type Notifier interface {
+	Notify(ctx context.Context, notification entity.Notification) error
+}
+
+type ProductFinder interface {
+	GetProductsAfterID(ctx context.Context, filter model.ProductFilter, afterID, limit int) ([]entity.Product, error)
+}
+
+// Evaluator периодически проверяет списки желаемого и сохраненные поиски
+// и уведомляет пользователей о снижении цены и новых подходящих продуктах
+type Evaluator struct {
+	repository Repository
+	products   ProductFinder
+	notifier   Notifier
+	log        *zap.Logger
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+func NewEvaluator(
+	repository Repository,
+	products ProductFinder,
+	notifier Notifier,
+	interval time.Duration,
+	log *zap.Logger,
+) *Evaluator {
+	e := &Evaluator{
+		repository: repository,
+		products:   products,
+		notifier:   notifier,
+		log:        log,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	go e.run(interval)
+
+	return e
+}
+
+// Close останавливает проверки и дожидается завершения текущего прохода
+func (e *Evaluator) Close() {
+	close(e.stop)
+	<-e.done
+}
+
+func (e *Evaluator) run(interval time.Duration) {
+	defer close(e.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.evaluate(context.Background())
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+func (e *Evaluator) evaluate(ctx context.Context) {
+	if err := e.evaluatePriceDrops(ctx); err != nil {
+		e.log.Error("Failed to evaluate wishlist price drops", zap.Error(err))
+	}
+	if err := e.evaluateSavedSearches(ctx); err != nil {
+		e.log.Error("Failed to evaluate saved searches", zap.Error(err))
+	}
+}
+
+func (e *Evaluator) evaluatePriceDrops(ctx context.Context) error {
+	if err := e.repository.ResetRecoveredPrices(ctx); err != nil {
+		return err
+	}
+
+	drops, err := e.repository.SelectPriceDrops(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, drop := range drops {
+		// Снижение забирается до уведомления: при нескольких экземплярах уведомит только один
+		claimed, err := e.repository.SwapNotifiedPrice(ctx, drop.UserID, drop.ProductID, drop.NotifiedPrice, &drop.Price)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		message := fmt.Sprintf("The price of %s dropped to %d.%02d", drop.ProductName, drop.Price/100, drop.Price%100)
+		err = e.notifier.Notify(ctx, entity.Notification{
+			UserID:  drop.UserID,
+			Type:    entity.NotificationPriceDropped,
+			Message: message,
+			Link:    fmt.Sprintf("/products/%d", drop.ProductID),
+		})
+		if err != nil {
+			e.log.Error("Failed to notify about price drop",
+				zap.Uint("user_id", drop.UserID), zap.Int("product_id", drop.ProductID), zap.Error(err))
+			// Возвращаем прежнюю цену, чтобы повторить уведомление в следующем проходе
+			_, err = e.repository.SwapNotifiedPrice(ctx, drop.UserID, drop.ProductID, &drop.Price, drop.NotifiedPrice)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *Evaluator) evaluateSavedSearches(ctx context.Context) error {
+	searches, err := e.repository.SelectAllSavedSearches(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, search := range searches {
+		var filter model.ProductFilter
+		if err = json.Unmarshal(search.Filter, &filter); err != nil {
+			e.log.Error("Failed to decode saved search filter", zap.Uint("search_id", search.ID), zap.Error(err))
+			continue
+		}
+
+		products, err := e.products.GetProductsAfterID(ctx, filter, search.LastProductID, searchMatchLimit)
+		if err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			continue
+		}
+
+		// Продукты забираются сдвигом курсора до уведомления: при нескольких экземплярах уведомит только один
+		lastProductID := products[len(products)-1].ID
+		claimed, err := e.repository.SwapSavedSearchCursor(ctx, search.ID, search.LastProductID, lastProductID)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		message := fmt.Sprintf("%d new product(s) match your saved search \"%s\"", len(products), search.Name)
+		err = e.notifier.Notify(ctx, entity.Notification{
+			UserID:  search.UserID,
+			Type:    entity.NotificationSearchMatched,
+			Message: message,
+			Link:    "/me/saved-searches",
+		})
+		if err != nil {
+			e.log.Error("Failed to notify about saved search matches",
+				zap.Uint("user_id", search.UserID), zap.Uint("search_id", search.ID), zap.Error(err))
+			// Возвращаем курсор, чтобы повторить уведомление в следующем проходе
+			if _, err = e.repository.SwapSavedSearchCursor(ctx, search.ID, lastProductID, search.LastProductID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
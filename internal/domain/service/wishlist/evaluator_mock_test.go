@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: evaluator.go
+//
+// Generated by this command:
+//
+//	mockgen -source=evaluator.go -destination=evaluator_mock_test.go -package=wishlist
+//
+
+// Package wishlist is a generated GoMock package.
+package wishlist
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	model "github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, notification entity.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, notification any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, notification)
+}
+
+// MockProductFinder is a mock of ProductFinder interface.
+type MockProductFinder struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductFinderMockRecorder
+	isgomock struct{}
+}
+
+// MockProductFinderMockRecorder is the mock recorder for MockProductFinder.
+type MockProductFinderMockRecorder struct {
+	mock *MockProductFinder
+}
+
+// NewMockProductFinder creates a new mock instance.
+func NewMockProductFinder(ctrl *gomock.Controller) *MockProductFinder {
+	mock := &MockProductFinder{ctrl: ctrl}
+	mock.recorder = &MockProductFinderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductFinder) EXPECT() *MockProductFinderMockRecorder {
+	return m.recorder
+}
+
+// GetProductsAfterID mocks base method.
+func (m *MockProductFinder) GetProductsAfterID(ctx context.Context, filter model.ProductFilter, afterID, limit int) ([]entity.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductsAfterID", ctx, filter, afterID, limit)
+	ret0, _ := ret[0].([]entity.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductsAfterID indicates an expected call of GetProductsAfterID.
+func (mr *MockProductFinderMockRecorder) GetProductsAfterID(ctx, filter, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductsAfterID", reflect.TypeOf((*MockProductFinder)(nil).GetProductsAfterID), ctx, filter, afterID, limit)
+}
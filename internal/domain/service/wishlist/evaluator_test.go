@@ -0,0 +1,139 @@
+package wishlist
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	. "github.com/onsi/ginkgo/v2"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+var _ = Describe("Evaluator", func() {
+	var (
+		ctrl         *gomock.Controller
+		mockRepo     *MockRepository
+		mockProducts *MockProductFinder
+		mockNotifier *MockNotifier
+		evaluator    *Evaluator
+		ctx          context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		mockProducts = NewMockProductFinder(ctrl)
+		mockNotifier = NewMockNotifier(ctrl)
+		// Без запуска фонового цикла, проходы вызываются напрямую
+		evaluator = &Evaluator{
+			repository: mockRepo,
+			products:   mockProducts,
+			notifier:   mockNotifier,
+			log:        zap.NewNop(),
+		}
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("price drops", func() {
+		It("should notify and remember notified price", func() {
+			mockRepo.EXPECT().ResetRecoveredPrices(ctx).Return(nil)
+			mockRepo.EXPECT().SelectPriceDrops(ctx).Return([]entity.PriceDrop{
+				{UserID: 1, UserEmail: "a@b.c", ProductID: 5, ProductName: "Phone", Price: 99950, TargetPrice: 100000},
+			}, nil)
+			price := 99950
+			mockRepo.EXPECT().SwapNotifiedPrice(ctx, uint(1), 5, (*int)(nil), &price).Return(true, nil)
+			mockNotifier.EXPECT().Notify(ctx, entity.Notification{
+				UserID:  1,
+				Type:    entity.NotificationPriceDropped,
+				Message: "The price of Phone dropped to 999.50",
+				Link:    "/products/5",
+			}).Return(nil)
+			mockRepo.EXPECT().SelectAllSavedSearches(ctx).Return(nil, nil)
+
+			evaluator.evaluate(ctx)
+		})
+
+		It("should release the claim to retry later if notification fails", func() {
+			notified, price := 150, 100
+			mockRepo.EXPECT().ResetRecoveredPrices(ctx).Return(nil)
+			mockRepo.EXPECT().SelectPriceDrops(ctx).Return([]entity.PriceDrop{
+				{UserID: 1, ProductID: 5, Price: 100, NotifiedPrice: &notified},
+			}, nil)
+			mockRepo.EXPECT().SwapNotifiedPrice(ctx, uint(1), 5, &notified, &price).Return(true, nil)
+			mockNotifier.EXPECT().Notify(ctx, gomock.Any()).Return(errors.New("db error"))
+			mockRepo.EXPECT().SwapNotifiedPrice(ctx, uint(1), 5, &price, &notified).Return(true, nil)
+			mockRepo.EXPECT().SelectAllSavedSearches(ctx).Return(nil, nil)
+
+			evaluator.evaluate(ctx)
+		})
+
+		It("should skip drops claimed by another instance", func() {
+			mockRepo.EXPECT().ResetRecoveredPrices(ctx).Return(nil)
+			mockRepo.EXPECT().SelectPriceDrops(ctx).Return([]entity.PriceDrop{
+				{UserID: 1, ProductID: 5, Price: 100},
+			}, nil)
+			mockRepo.EXPECT().SwapNotifiedPrice(ctx, uint(1), 5, gomock.Any(), gomock.Any()).Return(false, nil)
+			mockRepo.EXPECT().SelectAllSavedSearches(ctx).Return(nil, nil)
+
+			evaluator.evaluate(ctx)
+		})
+	})
+
+	Describe("saved searches", func() {
+		It("should notify about new products and move cursor", func() {
+			categoryID := 3
+			mockRepo.EXPECT().ResetRecoveredPrices(ctx).Return(nil)
+			mockRepo.EXPECT().SelectPriceDrops(ctx).Return(nil, nil)
+			mockRepo.EXPECT().SelectAllSavedSearches(ctx).Return([]entity.SavedSearch{
+				{ID: 2, UserID: 1, UserEmail: "a@b.c", Name: "Phones", Filter: []byte(`{"category_id":3}`), LastProductID: 10},
+				{ID: 3, UserID: 1, Name: "Laptops", Filter: []byte(`{}`), LastProductID: 20},
+			}, nil)
+			mockProducts.EXPECT().GetProductsAfterID(ctx, model.ProductFilter{CategoryID: &categoryID}, 10, searchMatchLimit).
+				Return([]entity.Product{{ID: 11}, {ID: 15}}, nil)
+			mockRepo.EXPECT().SwapSavedSearchCursor(ctx, uint(2), 10, 15).Return(true, nil)
+			mockNotifier.EXPECT().Notify(ctx, entity.Notification{
+				UserID:  1,
+				Type:    entity.NotificationSearchMatched,
+				Message: `2 new product(s) match your saved search "Phones"`,
+				Link:    "/me/saved-searches",
+			}).Return(nil)
+			mockProducts.EXPECT().GetProductsAfterID(ctx, model.ProductFilter{}, 20, searchMatchLimit).Return(nil, nil)
+
+			evaluator.evaluate(ctx)
+		})
+
+		It("should skip matches claimed by another instance", func() {
+			mockRepo.EXPECT().ResetRecoveredPrices(ctx).Return(nil)
+			mockRepo.EXPECT().SelectPriceDrops(ctx).Return(nil, nil)
+			mockRepo.EXPECT().SelectAllSavedSearches(ctx).Return([]entity.SavedSearch{
+				{ID: 2, UserID: 1, Name: "Phones", Filter: []byte(`{}`), LastProductID: 10},
+			}, nil)
+			mockProducts.EXPECT().GetProductsAfterID(ctx, model.ProductFilter{}, 10, searchMatchLimit).
+				Return([]entity.Product{{ID: 11}}, nil)
+			mockRepo.EXPECT().SwapSavedSearchCursor(ctx, uint(2), 10, 11).Return(false, nil)
+
+			evaluator.evaluate(ctx)
+		})
+
+		It("should move cursor back if notification fails", func() {
+			mockRepo.EXPECT().ResetRecoveredPrices(ctx).Return(nil)
+			mockRepo.EXPECT().SelectPriceDrops(ctx).Return(nil, nil)
+			mockRepo.EXPECT().SelectAllSavedSearches(ctx).Return([]entity.SavedSearch{
+				{ID: 2, UserID: 1, Name: "Phones", Filter: []byte(`{}`), LastProductID: 10},
+			}, nil)
+			mockProducts.EXPECT().GetProductsAfterID(ctx, model.ProductFilter{}, 10, searchMatchLimit).
+				Return([]entity.Product{{ID: 11}}, nil)
+			mockRepo.EXPECT().SwapSavedSearchCursor(ctx, uint(2), 10, 11).Return(true, nil)
+			mockNotifier.EXPECT().Notify(ctx, gomock.Any()).Return(errors.New("db error"))
+			mockRepo.EXPECT().SwapSavedSearchCursor(ctx, uint(2), 11, 10).Return(true, nil)
+
+			evaluator.evaluate(ctx)
+		})
+	})
+})
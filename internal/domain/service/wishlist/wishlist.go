@@ -0,0 +1,86 @@
+package wishlist
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=wishlist_mock_test.go -package=wishlist Repository
+
+type Repository interface {
+	SelectWishlist(ctx context.Context, userID uint) ([]entity.WishlistItem, error)
+	UpsertWishlistItem(ctx context.Context, userID uint, productID int, targetPrice *int) error
+	DeleteWishlistItem(ctx context.Context, userID uint, productID int) error
+	SelectSavedSearches(ctx context.Context, userID uint) ([]entity.SavedSearch, error)
+	InsertSavedSearch(ctx context.Context, search entity.SavedSearch) (uint, error)
+	DeleteSavedSearch(ctx context.Context, userID, searchID uint) error
+
+	SelectPriceDrops(ctx context.Context) ([]entity.PriceDrop, error)
+	SwapNotifiedPrice(ctx context.Context, userID uint, productID int, from, to *int) (bool, error)
+	ResetRecoveredPrices(ctx context.Context) error
+	SelectAllSavedSearches(ctx context.Context) ([]entity.SavedSearch, error)
+	SwapSavedSearchCursor(ctx context.Context, searchID uint, from, to int) (bool, error)
+}
+
+type Service struct {
+	wishlistRepository Repository
+}
+
+func NewService(wishlistRepository Repository) *Service {
+	return &Service{wishlistRepository: wishlistRepository}
+}
+
+func (s *Service) GetWishlist(ctx context.Context, userID uint) ([]entity.WishlistItem, error) {
+	return s.wishlistRepository.SelectWishlist(ctx, userID)
+}
+
+// SaveWishlistItem добавляет продукт в список желаемого. Если задана целевая цена,
+// пользователь получит уведомление, когда минимальная цена на продукт опустится до неё.
+func (s *Service) SaveWishlistItem(ctx context.Context, userID uint, productID int, targetPrice *int) error {
+	if targetPrice != nil && *targetPrice <= 0 {
+		return apperror.New(apperror.BadRequest, "target price must be positive", nil)
+	}
+
+	return s.wishlistRepository.UpsertWishlistItem(ctx, userID, productID, targetPrice)
+}
+
+func (s *Service) RemoveWishlistItem(ctx context.Context, userID uint, productID int) error {
+	return s.wishlistRepository.DeleteWishlistItem(ctx, userID, productID)
+}
+
+func (s *Service) GetSavedSearches(ctx context.Context, userID uint) ([]entity.SavedSearch, error) {
+	return s.wishlistRepository.SelectSavedSearches(ctx, userID)
+}
+
+// CreateSavedSearch сохраняет фильтр каталога, о новых подходящих продуктах придут уведомления
+func (s *Service) CreateSavedSearch(
+	ctx context.Context,
+	userID uint,
+	name string,
+	filter model.ProductFilter,
+) (uint, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, apperror.New(apperror.BadRequest, "search name must not be empty", nil)
+	}
+
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return 0, apperror.New(apperror.InternalError, "failed to serialize search filter", err)
+	}
+
+	return s.wishlistRepository.InsertSavedSearch(ctx, entity.SavedSearch{
+		UserID: userID,
+		Name:   name,
+		Filter: data,
+	})
+}
+
+func (s *Service) DeleteSavedSearch(ctx context.Context, userID, searchID uint) error {
+	return s.wishlistRepository.DeleteSavedSearch(ctx, userID, searchID)
+}
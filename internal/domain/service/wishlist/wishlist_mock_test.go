@@ -0,0 +1,203 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: wishlist.go
+//
+// Generated by this command:
+//
+//	mockgen -source=wishlist.go -destination=wishlist_mock_test.go -package=wishlist
+//
+
+// Package wishlist is a generated GoMock package.
+package wishlist
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteSavedSearch mocks base method.
+func (m *MockRepository) DeleteSavedSearch(ctx context.Context, userID, searchID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSavedSearch", ctx, userID, searchID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSavedSearch indicates an expected call of DeleteSavedSearch.
+func (mr *MockRepositoryMockRecorder) DeleteSavedSearch(ctx, userID, searchID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavedSearch", reflect.TypeOf((*MockRepository)(nil).DeleteSavedSearch), ctx, userID, searchID)
+}
+
+// DeleteWishlistItem mocks base method.
+func (m *MockRepository) DeleteWishlistItem(ctx context.Context, userID uint, productID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWishlistItem", ctx, userID, productID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWishlistItem indicates an expected call of DeleteWishlistItem.
+func (mr *MockRepositoryMockRecorder) DeleteWishlistItem(ctx, userID, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWishlistItem", reflect.TypeOf((*MockRepository)(nil).DeleteWishlistItem), ctx, userID, productID)
+}
+
+// InsertSavedSearch mocks base method.
+func (m *MockRepository) InsertSavedSearch(ctx context.Context, search entity.SavedSearch) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSavedSearch", ctx, search)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertSavedSearch indicates an expected call of InsertSavedSearch.
+func (mr *MockRepositoryMockRecorder) InsertSavedSearch(ctx, search any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSavedSearch", reflect.TypeOf((*MockRepository)(nil).InsertSavedSearch), ctx, search)
+}
+
+// ResetRecoveredPrices mocks base method.
+func (m *MockRepository) ResetRecoveredPrices(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetRecoveredPrices", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetRecoveredPrices indicates an expected call of ResetRecoveredPrices.
+func (mr *MockRepositoryMockRecorder) ResetRecoveredPrices(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetRecoveredPrices", reflect.TypeOf((*MockRepository)(nil).ResetRecoveredPrices), ctx)
+}
+
+// SelectAllSavedSearches mocks base method.
+func (m *MockRepository) SelectAllSavedSearches(ctx context.Context) ([]entity.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAllSavedSearches", ctx)
+	ret0, _ := ret[0].([]entity.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAllSavedSearches indicates an expected call of SelectAllSavedSearches.
+func (mr *MockRepositoryMockRecorder) SelectAllSavedSearches(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAllSavedSearches", reflect.TypeOf((*MockRepository)(nil).SelectAllSavedSearches), ctx)
+}
+
+// SelectPriceDrops mocks base method.
+func (m *MockRepository) SelectPriceDrops(ctx context.Context) ([]entity.PriceDrop, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectPriceDrops", ctx)
+	ret0, _ := ret[0].([]entity.PriceDrop)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectPriceDrops indicates an expected call of SelectPriceDrops.
+func (mr *MockRepositoryMockRecorder) SelectPriceDrops(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectPriceDrops", reflect.TypeOf((*MockRepository)(nil).SelectPriceDrops), ctx)
+}
+
+// SelectSavedSearches mocks base method.
+func (m *MockRepository) SelectSavedSearches(ctx context.Context, userID uint) ([]entity.SavedSearch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectSavedSearches", ctx, userID)
+	ret0, _ := ret[0].([]entity.SavedSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectSavedSearches indicates an expected call of SelectSavedSearches.
+func (mr *MockRepositoryMockRecorder) SelectSavedSearches(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectSavedSearches", reflect.TypeOf((*MockRepository)(nil).SelectSavedSearches), ctx, userID)
+}
+
+// SelectWishlist mocks base method.
+func (m *MockRepository) SelectWishlist(ctx context.Context, userID uint) ([]entity.WishlistItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectWishlist", ctx, userID)
+	ret0, _ := ret[0].([]entity.WishlistItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectWishlist indicates an expected call of SelectWishlist.
+func (mr *MockRepositoryMockRecorder) SelectWishlist(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectWishlist", reflect.TypeOf((*MockRepository)(nil).SelectWishlist), ctx, userID)
+}
+
+// SwapNotifiedPrice mocks base method.
+func (m *MockRepository) SwapNotifiedPrice(ctx context.Context, userID uint, productID int, from, to *int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwapNotifiedPrice", ctx, userID, productID, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SwapNotifiedPrice indicates an expected call of SwapNotifiedPrice.
+func (mr *MockRepositoryMockRecorder) SwapNotifiedPrice(ctx, userID, productID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwapNotifiedPrice", reflect.TypeOf((*MockRepository)(nil).SwapNotifiedPrice), ctx, userID, productID, from, to)
+}
+
+// SwapSavedSearchCursor mocks base method.
+func (m *MockRepository) SwapSavedSearchCursor(ctx context.Context, searchID uint, from, to int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwapSavedSearchCursor", ctx, searchID, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SwapSavedSearchCursor indicates an expected call of SwapSavedSearchCursor.
+func (mr *MockRepositoryMockRecorder) SwapSavedSearchCursor(ctx, searchID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwapSavedSearchCursor", reflect.TypeOf((*MockRepository)(nil).SwapSavedSearchCursor), ctx, searchID, from, to)
+}
+
+// UpsertWishlistItem mocks base method.
+func (m *MockRepository) UpsertWishlistItem(ctx context.Context, userID uint, productID int, targetPrice *int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWishlistItem", ctx, userID, productID, targetPrice)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWishlistItem indicates an expected call of UpsertWishlistItem.
+func (mr *MockRepositoryMockRecorder) UpsertWishlistItem(ctx, userID, productID, targetPrice any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWishlistItem", reflect.TypeOf((*MockRepository)(nil).UpsertWishlistItem), ctx, userID, productID, targetPrice)
+}
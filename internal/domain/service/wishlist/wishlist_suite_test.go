@@ -0,0 +1,13 @@
+package wishlist
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestWishlist(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Wishlist Service Suite")
+}
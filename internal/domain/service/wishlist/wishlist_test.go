@@ -0,0 +1,89 @@
+package wishlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("WishlistService", func() {
+	var (
+		ctrl     *gomock.Controller
+		mockRepo *MockRepository
+		service  *Service
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockRepo = NewMockRepository(ctrl)
+		service = NewService(mockRepo)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("SaveWishlistItem", func() {
+		It("should save item with target price", func() {
+			target := 10000
+			mockRepo.EXPECT().UpsertWishlistItem(ctx, uint(1), 5, &target).Return(nil)
+
+			Expect(service.SaveWishlistItem(ctx, 1, 5, &target)).To(Succeed())
+		})
+
+		It("should reject non-positive target price", func() {
+			target := 0
+
+			err := service.SaveWishlistItem(ctx, 1, 5, &target)
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+	})
+
+	Describe("CreateSavedSearch", func() {
+		It("should persist serialized filter", func() {
+			categoryID := 3
+			name := "phone"
+			filter := model.ProductFilter{
+				CategoryID: &categoryID,
+				Name:       &name,
+				Attributes: map[string]string{"color": "black"},
+			}
+
+			mockRepo.EXPECT().InsertSavedSearch(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, search entity.SavedSearch) (uint, error) {
+					Expect(search.UserID).To(Equal(uint(1)))
+					Expect(search.Name).To(Equal("Phones"))
+
+					var saved model.ProductFilter
+					Expect(json.Unmarshal(search.Filter, &saved)).To(Succeed())
+					Expect(saved).To(Equal(filter))
+					return 7, nil
+				})
+
+			id, err := service.CreateSavedSearch(ctx, 1, "  Phones ", filter)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(id).To(Equal(uint(7)))
+		})
+
+		It("should reject empty name", func() {
+			_, err := service.CreateSavedSearch(ctx, 1, " ", model.ProductFilter{})
+
+			var appErr apperror.AppError
+			Expect(errors.As(err, &appErr)).To(BeTrue())
+			Expect(appErr.Code()).To(Equal(apperror.BadRequest))
+		})
+	})
+})
@@ -5,16 +5,23 @@
 // @in header
 // @name Authorization
 // @description Bearer token for authentication. Format: "Bearer <token>"
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description Shop API key for integrations. Format: "ApiKey <key>"
 
 package handler
 
 import (
+	"fmt"
+
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"golang.org/x/text/currency"
 
 	// Импорт сваггер-генератора
 	"github.com/EM-Stawberry/Stawberry/docs"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/reviews"
 	"github.com/EM-Stawberry/Stawberry/pkg/database"
@@ -43,13 +50,32 @@ func SetupRouter(
 	guestOfferH *guesthandler.Handler,
 	userS middleware.UserGetter,
 	tokenS middleware.TokenValidator,
+	apiKeyS middleware.APIKeyValidator,
 	basePath string,
 	logger *zap.Logger,
 	auditMiddleware *middleware.AuditMiddleware,
 	auditH *AuditHandler,
-) *gin.Engine {
+	categoryH *CategoryHandler,
+	imageH *ImageHandler,
+	wishlistH *WishlistHandler,
+	permissionH *PermissionHandler,
+	permissionS middleware.PermissionGetter,
+	shopMemberH *ShopMemberHandler,
+	jwksH *JWKSHandler,
+	socialLoginH *SocialLoginHandler,
+	apiKeyH *APIKeyHandler,
+	webhookH *WebhookHandler,
+	requireVerifiedEmail bool,
+	trustedProxies []string,
+) (*gin.Engine, error) {
 	router := gin.New()
 
+	// Без списка доверенных прокси gin верит X-Forwarded-For от любого клиента,
+	// и ограничения по IP можно обойти, подставляя заголовок
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
 	// Добавляет кастомные валидаторы для использования в json-тегах
 	setupValitators()
 
@@ -71,7 +97,19 @@ func SetupRouter(
 	public := base.Group(basePath)
 
 	// secured это эндпойнты, которые не сработают без авторизационного токера
-	secured := public.Group("/").Use(middleware.AuthMiddleware(userS, tokenS))
+	secured := public.Group("/").Use(middleware.AuthMiddleware(userS, tokenS, nil))
+
+	// integration это эндпойнты, доступные и по access токену, и по API ключу магазина.
+	// Каждый из них должен требовать область ключа через RequireScope.
+	integration := public.Group("/").Use(middleware.AuthMiddleware(userS, tokenS, apiKeyS))
+
+	// stream это долгие потоки событий. Браузерные клиенты не умеют передавать в них заголовки,
+	// поэтому access token принимается и в параметре запроса.
+	stream := public.Group("/", middleware.AccessTokenFromQuery(), middleware.AuthMiddleware(userS, tokenS, nil))
+
+	// admin это эндпойнты, доступные только администраторам
+	admin := public.Group("/admin", middleware.AuthMiddleware(userS, tokenS, nil),
+		middleware.RequireRole(entity.RoleAdmin))
 
 	// healtcheck эндпойнты
 	{
@@ -80,18 +118,54 @@ func SetupRouter(
 		secured.GET("/auth_required", healthH.authCheck)
 	}
 
+	// открытые ключи для проверки access токенов другими сервисами
+	base.GET("/.well-known/jwks.json", jwksH.GetJWKS)
+
 	// эндпойнты регистрации-авторизации
 	auth := public.Group("/auth")
 	{
 		auth.POST("/reg", userH.Registration)
 		auth.POST("/login", userH.Login)
+		auth.POST("/login/mfa", userH.VerifyMFA)
 		auth.POST("/logout", userH.Logout)
 		auth.POST("/refresh", userH.Refresh)
+		auth.POST("/verify", userH.VerifyEmail)
+		auth.POST("/unlock", userH.UnlockAccount)
+		auth.POST("/forgot", userH.ForgotPassword)
+		auth.POST("/reset", userH.ResetPassword)
+		auth.GET("/oidc", socialLoginH.GetOIDCProviders)
+		auth.GET("/oidc/:provider", socialLoginH.GetOIDCAuthURL)
+		auth.POST("/oidc/:provider/callback", socialLoginH.PostOIDCCallback)
+		secured.POST("/auth/verify/resend", userH.ResendVerification)
+		secured.GET("/auth/sessions", userH.GetSessions)
+		secured.DELETE("/auth/sessions", userH.DeleteSessions)
+		secured.DELETE("/auth/sessions/:uuid", userH.DeleteSession)
 	}
 	// эндпойнты для продуктов
 	{
 		public.GET("/products", productH.GetProducts)
+		public.GET("/products/compare", productH.CompareProducts)
 		public.GET("/products/:id", productH.GetProductByID)
+		public.GET("/products/:id/offers-from-shops", productH.GetProductShopPrices)
+		public.GET("/products/:id/price-history", productH.GetPriceHistory)
+	}
+
+	// эндпойнты изображений продуктов
+	{
+		public.GET("/products/:id/images", imageH.GetProductImages)
+		secured.POST("/products/:id/images", imageH.PostProductImage)
+		secured.DELETE("/products/:id/images/:name", imageH.DeleteProductImage)
+		public.GET("/images/*key", imageH.ServeLocalImage)
+	}
+
+	// эндпойнты категорий
+	{
+		public.GET("/categories", categoryH.GetCategoryTree)
+		public.GET("/categories/:id", categoryH.GetCategorySubtree)
+		public.GET("/categories/:id/breadcrumbs", categoryH.GetCategoryBreadcrumbs)
+		admin.POST("/categories", categoryH.PostCategory)
+		admin.PATCH("/categories/:id", categoryH.MoveCategory)
+		admin.DELETE("/categories/:id", categoryH.DeleteCategory)
 	}
 
 	// эндпойнты для гостевых заявок
@@ -101,9 +175,10 @@ func SetupRouter(
 
 	// эндпойнты запросов на покупку
 	{
-		secured.PATCH("offers/:offerID", offerH.PatchOfferStatus)
+		integration.PATCH("offers/:offerID", middleware.RequireScope(entity.PermissionOffersRespond),
+			offerH.PatchOfferStatus)
 		secured.GET("offers", offerH.GetUserOffers)
-		secured.POST("offers", offerH.PostOffer)
+		secured.POST("offers", middleware.RequireVerifiedEmail(requireVerifiedEmail), offerH.PostOffer)
 	}
 
 	// эндпойнты отзывов
@@ -114,21 +189,93 @@ func SetupRouter(
 		secured.POST("/sellers/:id/reviews", sellerReviewH.AddReview)
 	}
 
-	// заглушка эндпоинта админа
-	// admin := secured.Group("/admin", middleware.Admin)
-	secured.GET("/audit", auditH.DisplayLogs)
+	// эндпойнты уведомлений
+	{
+		secured.GET("/notifications", notificationH.GetNotifications)
+		secured.GET("/notifications/unread-count", notificationH.GetUnreadNotifications)
+		secured.POST("/notifications/read-all", notificationH.PostNotificationsReadAll)
+		secured.POST("/notifications/:id/read", notificationH.PostNotificationRead)
+		secured.GET("/notifications/preferences", notificationH.GetNotificationPreferences)
+		secured.PUT("/notifications/preferences", notificationH.PutNotificationPreferences)
+		stream.GET("/notifications/stream", notificationH.GetNotificationStream)
+		stream.GET("/notifications/ws", notificationH.GetNotificationSocket)
+	}
+
+	// эндпойнты профиля
+	{
+		secured.GET("/me", userH.GetMe)
+		secured.PATCH("/me", userH.PatchMe)
+		secured.DELETE("/me", userH.DeleteMe)
+		secured.PUT("/me/password", userH.ChangePassword)
+		secured.PUT("/me/email", userH.ChangeEmail)
+		secured.POST("/me/mfa/totp", userH.PostTOTP)
+		secured.POST("/me/mfa/totp/confirm", userH.ConfirmTOTP)
+		secured.DELETE("/me/mfa/totp", userH.DeleteTOTP)
+		secured.POST("/me/mfa/recovery-codes", userH.PostRecoveryCodes)
+	}
+
+	// эндпойнты списка желаемого и сохраненных поисков
+	{
+		secured.GET("/me/wishlist", wishlistH.GetWishlist)
+		secured.PUT("/me/wishlist/:productID", wishlistH.PutWishlistItem)
+		secured.DELETE("/me/wishlist/:productID", wishlistH.DeleteWishlistItem)
+		secured.GET("/me/saved-searches", wishlistH.GetSavedSearches)
+		secured.POST("/me/saved-searches", wishlistH.PostSavedSearch)
+		secured.DELETE("/me/saved-searches/:id", wishlistH.DeleteSavedSearch)
+	}
+
+	// эндпойнты сотрудников магазинов
+	{
+		secured.GET("/shops/:id/members", shopMemberH.GetShopMembers)
+		secured.DELETE("/shops/:id/members/:userID", shopMemberH.DeleteShopMember)
+		secured.POST("/shops/:id/invitations", shopMemberH.PostShopInvitation)
+		secured.POST("/shop-invitations/accept", shopMemberH.AcceptShopInvitation)
+	}
+
+	// эндпойнты API ключей магазинов
+	{
+		secured.GET("/shops/:id/api-keys", apiKeyH.GetAPIKeys)
+		secured.POST("/shops/:id/api-keys", apiKeyH.PostAPIKey)
+		secured.DELETE("/shops/:id/api-keys/:keyID", apiKeyH.DeleteAPIKey)
+	}
+
+	// эндпойнты вебхуков магазинов
+	{
+		secured.GET("/shops/:id/webhooks", webhookH.GetWebhooks)
+		secured.POST("/shops/:id/webhooks", webhookH.PostWebhook)
+		secured.DELETE("/shops/:id/webhooks/:webhookID", webhookH.DeleteWebhook)
+		secured.GET("/shops/:id/webhooks/:webhookID/deliveries", webhookH.GetWebhookDeliveries)
+		secured.GET("/shops/:id/webhooks/:webhookID/deliveries/:deliveryID", webhookH.GetWebhookDelivery)
+		secured.POST("/shops/:id/webhooks/:webhookID/deliveries/:deliveryID/redeliver",
+			webhookH.PostWebhookRedelivery)
+	}
+
+	// эндпойнты управления правами
+	{
+		admin.GET("/permissions", permissionH.GetPermissions)
+		admin.GET("/users/:id/permissions", permissionH.GetUserPermissions)
+		admin.POST("/users/:id/permissions", permissionH.PostUserPermission)
+		admin.DELETE("/users/:id/permissions/:permission", permissionH.DeleteUserPermission)
+	}
+
+	// эндпойнты блокировки пользователей
+	{
+		admin.POST("/users/:id/ban", userH.BanUser)
+		admin.DELETE("/users/:id/ban", userH.UnbanUser)
+	}
+
+	secured.GET("/audit", middleware.RequirePermission(permissionS, entity.PermissionAuditRead), auditH.DisplayLogs)
 
 	// Эндпоинты для бд
 	{
-		secured.POST("/dev/seed-db", seedDB)
-		secured.POST("/dev/clear-db", clearDB)
+		secured.POST("/dev/seed-db", middleware.RequireRole(entity.RoleAdmin), seedDB)
+		secured.POST("/dev/clear-db", middleware.RequireRole(entity.RoleAdmin), clearDB)
 	}
 
 	// Эти заглушки можно убрать после реализации соответствующих хендлеров
 	_ = productH
-	_ = notificationH
 
-	return router
+	return router, nil
 }
 
 func setupValitators() {
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyService interface {
+	CreateKey(
+		ctx context.Context,
+		shopID, actorID uint,
+		name string,
+		scopes []entity.Permission,
+		expiresAt *time.Time,
+	) (entity.APIKey, string, error)
+	GetKeys(ctx context.Context, shopID, actorID uint) ([]entity.APIKey, error)
+	RevokeKey(ctx context.Context, shopID, actorID, keyID uint) error
+}
+
+type APIKeyHandler struct {
+	apiKeyService APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// GetAPIKeys godoc
+// @Summary      Получить API ключи магазина
+// @Description  Список доступен владельцу и менеджерам магазина, секреты ключей не возвращаются
+// @Tags         shops
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID магазина"
+// @Success      200  {array}   dto.APIKeyResp
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /shops/{id}/api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	actorID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	shopID, err := parseShopID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	keys, err := h.apiKeyService.GetKeys(c.Request.Context(), shopID, actorID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormAPIKeys(keys))
+}
+
+// PostAPIKey godoc
+// @Summary      Выпустить API ключ магазина
+// @Description  Ключ для интеграций магазина передается в заголовке Authorization: ApiKey <ключ>
+// @Description  и действует от имени создавшего его сотрудника. Ключ показывается только в этом ответе.
+// @Description  Доступные области: offers:respond, inventory:write, в пределах прав роли сотрудника.
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                true  "ID магазина"
+// @Param        body  body      dto.PostAPIKeyReq  true  "Название, области и срок действия ключа"
+// @Success      201   {object}  dto.PostAPIKeyResp
+// @Failure      400   {object}  apperror.Error
+// @Failure      401   {object}  apperror.Error
+// @Failure      403   {object}  apperror.Error
+// @Failure      500   {object}  apperror.Error
+// @Router       /shops/{id}/api-keys [post]
+func (h *APIKeyHandler) PostAPIKey(c *gin.Context) {
+	actorID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	shopID, err := parseShopID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.PostAPIKeyReq
+	if err = c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid api key data", err))
+		return
+	}
+
+	key, rawKey, err := h.apiKeyService.CreateKey(c.Request.Context(), shopID, actorID, req.Name,
+		req.ConvertScopes(), req.ExpiresAt)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.PostAPIKeyResp{APIKeyResp: dto.FormAPIKey(key), APIKey: rawKey})
+}
+
+// DeleteAPIKey godoc
+// @Summary      Отозвать API ключ магазина
+// @Description  Ключ перестает работать сразу. Отзывать ключи могут владелец и менеджеры магазина.
+// @Tags         shops
+// @Security     BearerAuth
+// @Param        id     path  int  true  "ID магазина"
+// @Param        keyID  path  int  true  "ID ключа"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /shops/{id}/api-keys/{keyID} [delete]
+func (h *APIKeyHandler) DeleteAPIKey(c *gin.Context) {
+	actorID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	shopID, err := parseShopID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	keyID, err := strconv.ParseUint(c.Param("keyID"), 10, 32)
+	if err != nil || keyID == 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "key id must be a positive number", err))
+		return
+	}
+
+	if err = h.apiKeyService.RevokeKey(c.Request.Context(), shopID, actorID, uint(keyID)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
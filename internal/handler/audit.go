@@ -48,6 +48,7 @@ func NewAuditHandler(as AuditService) *AuditHandler {
 // @Param page query integer false "Page number (default 1)" minimum(1)
 // @Success 200 {object} map[string]interface{} "Returns paginated audit logs"
 // @Failure 400 {object} apperror.AppError "Invalid request parameters"
+// @Failure 403 {object} apperror.AppError "Requires audit:read permission or admin role"
 // @Failure 500 {object} apperror.AppError "Internal server error"
 // @Router /audit/logs [get]
 func (h *AuditHandler) DisplayLogs(c *gin.Context) {
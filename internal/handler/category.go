@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryService interface {
+	GetTree(ctx context.Context) ([]entity.Category, error)
+	GetSubtree(ctx context.Context, id uint) (entity.Category, error)
+	GetBreadcrumbs(ctx context.Context, id uint) ([]entity.Category, error)
+	CreateCategory(ctx context.Context, name string, parentID *uint) (uint, error)
+	MoveCategory(ctx context.Context, id uint, parentID *uint) error
+	DeleteCategory(ctx context.Context, id uint) error
+}
+
+type CategoryHandler struct {
+	categoryService CategoryService
+}
+
+func NewCategoryHandler(categoryService CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+// GetCategoryTree godoc
+// @Summary      Получить дерево категорий
+// @Description  Возвращает все категории в виде дерева с количеством продуктов в каждом узле
+// @Tags         categories
+// @Produce      json
+// @Success      200  {array}   dto.CategoryResp
+// @Failure      500  {object}  apperror.Error
+// @Router       /categories [get]
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.categoryService.GetTree(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormCategoryTree(tree))
+}
+
+// GetCategorySubtree godoc
+// @Summary      Получить поддерево категории
+// @Description  Возвращает категорию со всеми подкатегориями и количеством продуктов
+// @Tags         categories
+// @Produce      json
+// @Param        id   path      int  true  "ID категории"
+// @Success      200  {object}  dto.CategoryResp
+// @Failure      400  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /categories/{id} [get]
+func (h *CategoryHandler) GetCategorySubtree(c *gin.Context) {
+	id, err := parseCategoryID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	subtree, err := h.categoryService.GetSubtree(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormCategory(subtree))
+}
+
+// GetCategoryBreadcrumbs godoc
+// @Summary      Получить путь до категории
+// @Description  Возвращает цепочку категорий от корня до указанной включительно
+// @Tags         categories
+// @Produce      json
+// @Param        id   path      int  true  "ID категории"
+// @Success      200  {array}   dto.BreadcrumbResp
+// @Failure      400  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /categories/{id}/breadcrumbs [get]
+func (h *CategoryHandler) GetCategoryBreadcrumbs(c *gin.Context) {
+	id, err := parseCategoryID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	path, err := h.categoryService.GetBreadcrumbs(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormBreadcrumbs(path))
+}
+
+// PostCategory godoc
+// @Summary      Создать категорию
+// @Description  Добавляет категорию последним потомком родителя или новым корнем
+// @Tags         categories
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.PostCategoryReq  true  "Данные категории"
+// @Success      201   {object}  dto.PostCategoryResp
+// @Failure      400   {object}  apperror.Error
+// @Failure      403   {object}  apperror.Error
+// @Failure      404   {object}  apperror.Error
+// @Failure      409   {object}  apperror.Error
+// @Failure      500   {object}  apperror.Error
+// @Router       /admin/categories [post]
+func (h *CategoryHandler) PostCategory(c *gin.Context) {
+	var req dto.PostCategoryReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid category data", err))
+		return
+	}
+
+	id, err := h.categoryService.CreateCategory(c.Request.Context(), req.Name, req.ParentID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.PostCategoryResp{ID: id})
+}
+
+// MoveCategory godoc
+// @Summary      Переместить категорию
+// @Description  Переносит категорию вместе с подкатегориями к новому родителю, без parent_id - в корень
+// @Tags         categories
+// @Accept       json
+// @Security     BearerAuth
+// @Param        id    path  int                  true  "ID категории"
+// @Param        body  body  dto.MoveCategoryReq  true  "Новый родитель"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /admin/categories/{id} [patch]
+func (h *CategoryHandler) MoveCategory(c *gin.Context) {
+	id, err := parseCategoryID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.MoveCategoryReq
+	if err = c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid move data", err))
+		return
+	}
+
+	if err = h.categoryService.MoveCategory(c.Request.Context(), id, req.ParentID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteCategory godoc
+// @Summary      Удалить категорию
+// @Description  Удаляет категорию вместе с подкатегориями, если в них нет продуктов
+// @Tags         categories
+// @Security     BearerAuth
+// @Param        id   path  int  true  "ID категории"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      409  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /admin/categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, err := parseCategoryID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.categoryService.DeleteCategory(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseCategoryID(c *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		return 0, apperror.New(apperror.BadRequest, "category id must be a positive number", err)
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type PostAPIKeyReq struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r PostAPIKeyReq) ConvertScopes() []entity.Permission {
+	scopes := make([]entity.Permission, len(r.Scopes))
+	for i, scope := range r.Scopes {
+		scopes[i] = entity.Permission(scope)
+	}
+	return scopes
+}
+
+type APIKeyResp struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  uint       `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// PostAPIKeyResp содержит сам ключ, он показывается только при создании
+type PostAPIKeyResp struct {
+	APIKeyResp
+	APIKey string `json:"api_key"`
+}
+
+func FormAPIKey(key entity.APIKey) APIKeyResp {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+	return APIKeyResp{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		CreatedBy:  key.CreatedBy,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func FormAPIKeys(keys []entity.APIKey) []APIKeyResp {
+	resp := make([]APIKeyResp, len(keys))
+	for i, key := range keys {
+		resp[i] = FormAPIKey(key)
+	}
+	return resp
+}
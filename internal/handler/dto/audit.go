@@ -11,6 +11,7 @@ type AuditEntry struct {
 	Url        string                 `json:"url"`
 	RespStatus int                    `json:"resp_status"`
 	UserID     uint                   `json:"user_id"`
+	APIKeyID   uint                   `json:"api_key_id,omitempty"`
 	IP         string                 `json:"user_ip"`
 	UserRole   string                 `json:"user_role"`
 	ReceivedAt time.Time              `json:"received_at"`
@@ -26,6 +27,7 @@ func FormResponse(entities []entity.AuditEntry) []AuditEntry {
 			Url:        e.Url,
 			RespStatus: e.RespStatus,
 			UserID:     e.UserID,
+			APIKeyID:   e.APIKeyID,
 			IP:         e.IP,
 			UserRole:   e.UserRole,
 			ReceivedAt: e.ReceivedAt,
@@ -0,0 +1,64 @@
+package dto
+
+import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+
+type CategoryResp struct {
+	ID                uint           `json:"id"`
+	Name              string         `json:"name"`
+	ParentID          *uint          `json:"parent_id"`
+	Depth             int            `json:"depth"`
+	ProductCount      int            `json:"product_count"`
+	TotalProductCount int            `json:"total_product_count"`
+	Children          []CategoryResp `json:"children"`
+}
+
+type BreadcrumbResp struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type PostCategoryReq struct {
+	Name     string `json:"name" binding:"required,max=255"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+type PostCategoryResp struct {
+	ID uint `json:"id"`
+}
+
+type MoveCategoryReq struct {
+	ParentID *uint `json:"parent_id"`
+}
+
+func FormCategory(c entity.Category) CategoryResp {
+	children := make([]CategoryResp, 0, len(c.Children))
+	for _, child := range c.Children {
+		children = append(children, FormCategory(child))
+	}
+
+	return CategoryResp{
+		ID:                c.ID,
+		Name:              c.Name,
+		ParentID:          c.ParentID,
+		Depth:             c.Depth,
+		ProductCount:      c.ProductCount,
+		TotalProductCount: c.TotalProductCount,
+		Children:          children,
+	}
+}
+
+func FormCategoryTree(tree []entity.Category) []CategoryResp {
+	resp := make([]CategoryResp, 0, len(tree))
+	for _, c := range tree {
+		resp = append(resp, FormCategory(c))
+	}
+	return resp
+}
+
+func FormBreadcrumbs(path []entity.Category) []BreadcrumbResp {
+	resp := make([]BreadcrumbResp, 0, len(path))
+	for _, c := range path {
+		resp = append(resp, BreadcrumbResp{ID: c.ID, Name: c.Name})
+	}
+	return resp
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+// JWK это открытый ключ в формате RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSResp struct {
+	Keys []JWK `json:"keys"`
+}
+
+func FormJWKS(keys []entity.PublicKey) JWKSResp {
+	resp := JWKSResp{Keys: make([]JWK, 0, len(keys))}
+	for _, k := range keys {
+		jwk := JWK{
+			Use: "sig",
+			Kid: k.KID,
+			Alg: k.Algorithm,
+		}
+
+		switch pub := k.Key.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+
+		resp.Keys = append(resp.Keys, jwk)
+	}
+	return resp
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+
+type VerifyMFAReq struct {
+	Challenge   string `json:"mfa_challenge" binding:"required"`
+	Code        string `json:"code" binding:"required"`
+	Fingerprint string `json:"fingerprint" binding:"required"`
+}
+
+type TOTPEnrollmentResp struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+func FormTOTPEnrollment(e entity.TOTPEnrollment) TOTPEnrollmentResp {
+	return TOTPEnrollmentResp{
+		Secret: e.Secret,
+		URI:    e.URI,
+	}
+}
+
+type ConfirmTOTPReq struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFAPasswordReq struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type RecoveryCodesResp struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -1,10 +1,133 @@
 package dto
 
-// пока не нужно, но может понадобиться
-//type GetNotificationReq struct{}
-//
-//type GetNotificationResp struct{}
-//
-//func (gn *GetNotificationReq) ConvertToSvc() notification.Notification {
-//	return notification.Notification{}
-//}
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type NotificationResp struct {
+	ID      uint       `json:"id"`
+	Type    string     `json:"type"`
+	Message string     `json:"message"`
+	Link    string     `json:"link,omitempty"`
+	Read    bool       `json:"read"`
+	ReadAt  *time.Time `json:"read_at,omitempty"`
+	SentAt  time.Time  `json:"sent_at"`
+}
+
+type NotificationsResp struct {
+	Data []NotificationResp `json:"data"`
+	Meta struct {
+		CurrentPage int `json:"current_page"`
+		PerPage     int `json:"per_page"`
+		TotalItems  int `json:"total_items"`
+		TotalPages  int `json:"total_pages"`
+		UnreadCount int `json:"unread_count"`
+	} `json:"meta"`
+}
+
+type UnreadNotificationsResp struct {
+	Count int `json:"count"`
+}
+
+type MarkAllNotificationsReadResp struct {
+	Updated int64 `json:"updated"`
+}
+
+// RealtimeMessageResp это сообщение потока уведомлений через WebSocket
+type RealtimeMessageResp struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
+
+func FormNotification(notification entity.Notification) NotificationResp {
+	return NotificationResp{
+		ID:      notification.ID,
+		Type:    string(notification.Type),
+		Message: notification.Message,
+		Link:    notification.Link,
+		Read:    notification.ReadAt != nil,
+		ReadAt:  notification.ReadAt,
+		SentAt:  notification.SentAt,
+	}
+}
+
+func FormNotifications(notifications []entity.Notification, page, limit, total, unread int) NotificationsResp {
+	var resp NotificationsResp
+	resp.Data = make([]NotificationResp, len(notifications))
+	for i, notification := range notifications {
+		resp.Data[i] = FormNotification(notification)
+	}
+	resp.Meta.CurrentPage = page
+	resp.Meta.PerPage = limit
+	resp.Meta.TotalItems = total
+	resp.Meta.TotalPages = (total + limit - 1) / limit
+	resp.Meta.UnreadCount = unread
+	return resp
+}
+
+// QuietHoursDTO это тихие часы во времени пользователя, могут переходить через полночь
+type QuietHoursDTO struct {
+	Start string `json:"start" binding:"required,datetime=15:04" example:"22:00"`
+	End   string `json:"end" binding:"required,datetime=15:04" example:"08:00"`
+}
+
+type NotificationPreferencesDTO struct {
+	// Channels задает канал для вида уведомления: in_app, email или none.
+	// Для не указанных видов действует канал по умолчанию.
+	Channels   map[string]string `json:"channels" example:"offer_received:email"`
+	QuietHours *QuietHoursDTO    `json:"quiet_hours"`
+	Timezone   string            `json:"timezone" binding:"required" example:"Europe/Moscow"`
+	Digest     string            `json:"digest" binding:"required,oneof=off hourly daily"`
+}
+
+func (p *NotificationPreferencesDTO) ConvertToEntity(userID uint) entity.NotificationPreferences {
+	preferences := entity.NotificationPreferences{
+		UserID:   userID,
+		Channels: make(map[entity.NotificationType]entity.NotificationChannel, len(p.Channels)),
+		Timezone: p.Timezone,
+		Digest:   entity.DigestFrequency(p.Digest),
+	}
+	for t, channel := range p.Channels {
+		preferences.Channels[entity.NotificationType(t)] = entity.NotificationChannel(channel)
+	}
+	if p.QuietHours != nil {
+		preferences.QuietHours = &entity.QuietHours{
+			Start: minuteOfDay(p.QuietHours.Start),
+			End:   minuteOfDay(p.QuietHours.End),
+		}
+	}
+	return preferences
+}
+
+// FormNotificationPreferences возвращает каналы для всех видов уведомлений
+func FormNotificationPreferences(p entity.NotificationPreferences) NotificationPreferencesDTO {
+	resp := NotificationPreferencesDTO{
+		Channels: make(map[string]string, len(entity.NotificationTypes)),
+		Timezone: p.Timezone,
+		Digest:   string(p.Digest),
+	}
+	for _, t := range entity.NotificationTypes {
+		resp.Channels[string(t)] = string(p.Channel(t))
+	}
+	if p.QuietHours != nil {
+		resp.QuietHours = &QuietHoursDTO{
+			Start: formatMinuteOfDay(p.QuietHours.Start),
+			End:   formatMinuteOfDay(p.QuietHours.End),
+		}
+	}
+	return resp
+}
+
+// minuteOfDay переводит проверенное при привязке время HH:MM в минуты от полуночи
+func minuteOfDay(s string) int {
+	t, _ := time.Parse("15:04", s)
+	return t.Hour()*60 + t.Minute()
+}
+
+func formatMinuteOfDay(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
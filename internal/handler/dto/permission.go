@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"sort"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type PermissionResp struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type PostUserPermissionReq struct {
+	Permission string `json:"permission" binding:"required"`
+}
+
+func FormPermissions(registry map[entity.Permission]string) []PermissionResp {
+	resp := make([]PermissionResp, 0, len(registry))
+	for name, description := range registry {
+		resp = append(resp, PermissionResp{Name: string(name), Description: description})
+	}
+	sort.Slice(resp, func(i, j int) bool { return resp[i].Name < resp[j].Name })
+	return resp
+}
+
+func FormUserPermissions(permissions []entity.Permission) []string {
+	resp := make([]string, len(permissions))
+	for i, permission := range permissions {
+		resp[i] = string(permission)
+	}
+	return resp
+}
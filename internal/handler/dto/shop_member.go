@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type ShopMemberResp struct {
+	UserID    uint      `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PostShopInvitationReq struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=manager agent"`
+}
+
+type PostShopInvitationResp struct {
+	ID uint `json:"id"`
+}
+
+type AcceptShopInvitationReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func FormShopMembers(members []entity.ShopMember) []ShopMemberResp {
+	resp := make([]ShopMemberResp, len(members))
+	for i, member := range members {
+		resp[i] = ShopMemberResp{
+			UserID:    member.UserID,
+			Name:      member.UserName,
+			Email:     member.UserEmail,
+			Role:      string(member.Role),
+			CreatedAt: member.CreatedAt,
+		}
+	}
+	return resp
+}
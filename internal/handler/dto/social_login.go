@@ -0,0 +1,15 @@
+package dto
+
+type OIDCProvidersResp struct {
+	Providers []string `json:"providers"`
+}
+
+type OIDCAuthURLResp struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type OIDCCallbackReq struct {
+	Code        string `json:"code" binding:"required"`
+	State       string `json:"state" binding:"required"`
+	Fingerprint string `json:"fingerprint" binding:"required"`
+}
@@ -1,6 +1,9 @@
 package dto
 
 import (
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
 )
 
@@ -42,12 +45,104 @@ type RefreshResp struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// LoginUserResp содержит токены либо, если у пользователя подключен второй фактор,
+// только mfa_challenge для /auth/login/mfa
 type LoginUserResp struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required"`
+	MFAChallenge string `json:"mfa_challenge,omitempty"`
 }
 
 type LogoutReq struct {
 	RefreshToken string `json:"refresh_token,omitempty"`
 	Fingerprint  string `json:"fingerprint" validate:"required"`
 }
+
+type VerifyEmailReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type UnlockAccountReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ForgotPasswordReq struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordReq struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type UserProfileResp struct {
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	Phone         string `json:"phone"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"email_verified"`
+	MFAEnabled    bool   `json:"mfa_enabled"`
+}
+
+func FormUserProfile(u entity.User) UserProfileResp {
+	return UserProfileResp{
+		ID:            u.ID,
+		Name:          u.Name,
+		Email:         u.Email,
+		Phone:         u.Phone,
+		Role:          string(u.Role),
+		EmailVerified: u.EmailVerifiedAt != nil,
+		MFAEnabled:    u.MFAEnabled(),
+	}
+}
+
+type UpdateProfileReq struct {
+	Name  *string `json:"name" binding:"omitempty,min=1,max=255"`
+	Phone *string `json:"phone" binding:"omitempty,min=1,max=20"`
+}
+
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+type ChangeEmailReq struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type DeleteAccountReq struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type SessionResp struct {
+	UUID       string    `json:"uuid"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	LastIP     string    `json:"last_ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// FormSessions собирает список сессий. current это refresh токен из cookie запроса, если он есть.
+func FormSessions(tokens []entity.RefreshToken, current string) []SessionResp {
+	sessions := make([]SessionResp, 0, len(tokens))
+	for _, t := range tokens {
+		uuid := t.UUID.String()
+		sessions = append(sessions, SessionResp{
+			UUID:       uuid,
+			Device:     t.DeviceLabel(),
+			UserAgent:  t.UserAgent,
+			LastIP:     t.LastIP,
+			CreatedAt:  t.CreatedAt,
+			LastUsedAt: t.LastUsedAt,
+			ExpiresAt:  t.ExpiresAt,
+			Current:    uuid == current,
+		})
+	}
+	return sessions
+}
@@ -0,0 +1,150 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type PostWebhookReq struct {
+	URL    string   `json:"url" binding:"required,max=2000"`
+	Events []string `json:"events" binding:"required,min=1"`
+}
+
+func (r PostWebhookReq) ConvertEvents() []entity.WebhookEvent {
+	events := make([]entity.WebhookEvent, len(r.Events))
+	for i, event := range r.Events {
+		events[i] = entity.WebhookEvent(event)
+	}
+	return events
+}
+
+type WebhookResp struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PostWebhookResp содержит секрет подписи, он показывается только при создании
+type PostWebhookResp struct {
+	WebhookResp
+	Secret string `json:"secret"`
+}
+
+func FormWebhook(endpoint entity.WebhookEndpoint) WebhookResp {
+	events := make([]string, len(endpoint.Events))
+	for i, event := range endpoint.Events {
+		events[i] = string(event)
+	}
+	return WebhookResp{
+		ID:        endpoint.ID,
+		URL:       endpoint.URL,
+		Events:    events,
+		Active:    endpoint.Active,
+		CreatedBy: endpoint.CreatedBy,
+		CreatedAt: endpoint.CreatedAt,
+	}
+}
+
+func FormWebhooks(endpoints []entity.WebhookEndpoint) []WebhookResp {
+	resp := make([]WebhookResp, len(endpoints))
+	for i, endpoint := range endpoints {
+		resp[i] = FormWebhook(endpoint)
+	}
+	return resp
+}
+
+type WebhookDeliveryResp struct {
+	ID             uint       `json:"id"`
+	EventID        string     `json:"event_id"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int       `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type WebhookAttemptResp struct {
+	StatusCode   *int      `json:"status_code,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"`
+	DurationMS   int64     `json:"duration_ms"`
+	AttemptedAt  time.Time `json:"attempted_at"`
+}
+
+// WebhookDeliveryDetailsResp содержит тело события и журнал попыток доставки
+type WebhookDeliveryDetailsResp struct {
+	WebhookDeliveryResp
+	Payload    json.RawMessage      `json:"payload" swaggertype:"object"`
+	AttemptLog []WebhookAttemptResp `json:"attempt_log"`
+}
+
+type WebhookDeliveriesResp struct {
+	Data []WebhookDeliveryResp `json:"data"`
+	Meta struct {
+		CurrentPage int `json:"current_page"`
+		PerPage     int `json:"per_page"`
+		TotalItems  int `json:"total_items"`
+		TotalPages  int `json:"total_pages"`
+	} `json:"meta"`
+}
+
+func FormWebhookDelivery(delivery entity.WebhookDelivery) WebhookDeliveryResp {
+	resp := WebhookDeliveryResp{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		Event:          string(delivery.Event),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	// Время следующей попытки имеет смысл только для доставок в очереди
+	if delivery.Status == entity.WebhookDeliveryPending {
+		resp.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return resp
+}
+
+func FormWebhookDeliveries(deliveries []entity.WebhookDelivery, page, limit, total int) WebhookDeliveriesResp {
+	var resp WebhookDeliveriesResp
+	resp.Data = make([]WebhookDeliveryResp, len(deliveries))
+	for i, delivery := range deliveries {
+		resp.Data[i] = FormWebhookDelivery(delivery)
+	}
+	resp.Meta.CurrentPage = page
+	resp.Meta.PerPage = limit
+	resp.Meta.TotalItems = total
+	resp.Meta.TotalPages = (total + limit - 1) / limit
+	return resp
+}
+
+func FormWebhookDeliveryDetails(
+	delivery entity.WebhookDelivery,
+	attempts []entity.WebhookAttempt,
+) WebhookDeliveryDetailsResp {
+	resp := WebhookDeliveryDetailsResp{
+		WebhookDeliveryResp: FormWebhookDelivery(delivery),
+		Payload:             delivery.Payload,
+		AttemptLog:          make([]WebhookAttemptResp, len(attempts)),
+	}
+	for i, attempt := range attempts {
+		resp.AttemptLog[i] = WebhookAttemptResp{
+			StatusCode:   attempt.StatusCode,
+			Error:        attempt.Error,
+			ResponseBody: attempt.ResponseBody,
+			DurationMS:   attempt.Duration.Milliseconds(),
+			AttemptedAt:  attempt.AttemptedAt,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+)
+
+type WishlistItemResp struct {
+	ProductID    int       `json:"product_id"`
+	ProductName  string    `json:"product_name"`
+	TargetPrice  *int      `json:"target_price"`
+	MinimalPrice int       `json:"minimal_price"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PutWishlistItemReq struct {
+	TargetPrice *int `json:"target_price" binding:"omitempty,gt=0"`
+}
+
+type SavedSearchResp struct {
+	ID        uint            `json:"id"`
+	Name      string          `json:"name"`
+	Filter    json.RawMessage `json:"filter" swaggertype:"object"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type PostSavedSearchReq struct {
+	Name   string              `json:"name" binding:"required,max=255"`
+	Filter model.ProductFilter `json:"filter"`
+}
+
+type PostSavedSearchResp struct {
+	ID uint `json:"id"`
+}
+
+func FormWishlist(items []entity.WishlistItem) []WishlistItemResp {
+	resp := make([]WishlistItemResp, len(items))
+	for i, item := range items {
+		resp[i] = WishlistItemResp{
+			ProductID:    item.ProductID,
+			ProductName:  item.ProductName,
+			TargetPrice:  item.TargetPrice,
+			MinimalPrice: item.MinimalPrice,
+			CreatedAt:    item.CreatedAt,
+		}
+	}
+	return resp
+}
+
+func FormSavedSearches(searches []entity.SavedSearch) []SavedSearchResp {
+	resp := make([]SavedSearchResp, len(searches))
+	for i, search := range searches {
+		resp[i] = SavedSearchResp{
+			ID:        search.ID,
+			Name:      search.Name,
+			Filter:    search.Filter,
+			CreatedAt: search.CreatedAt,
+		}
+	}
+	return resp
+}
@@ -1,13 +1,22 @@
 package helpers
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/gin-gonic/gin"
+)
 
 const (
-	UserIDKey      = "userID"
-	UserIsStoreKey = "userIsStore"
-	UserIsAdminKey = "userIsAdmin"
-	UserName       = "userName"
-	UserEmail      = "userEmail"
+	UserIDKey          = "userID"
+	UserRoleKey        = "userRole"
+	UserPermissionsKey = "userPermissions"
+	UserIsStoreKey     = "userIsStore"
+	UserIsAdminKey     = "userIsAdmin"
+	UserName           = "userName"
+	UserEmail          = "userEmail"
+	UserEmailVerified  = "userEmailVerified"
+	APIKeyIDKey        = "apiKeyID"
+	APIKeyShopIDKey    = "apiKeyShopID"
+	APIKeyScopesKey    = "apiKeyScopes"
 )
 
 func UserIDContext(c *gin.Context) (uint, bool) {
@@ -22,6 +31,30 @@ func UserIDContext(c *gin.Context) (uint, bool) {
 	return idValue, true
 }
 
+func UserRoleContext(c *gin.Context) (entity.Role, bool) {
+	role, exists := c.Get(UserRoleKey)
+	if !exists {
+		return "", false
+	}
+	roleValue, ok := role.(entity.Role)
+	if !ok {
+		return "", false
+	}
+	return roleValue, true
+}
+
+func UserPermissionsContext(c *gin.Context) ([]entity.Permission, bool) {
+	permissions, exists := c.Get(UserPermissionsKey)
+	if !exists {
+		return nil, false
+	}
+	permissionsValue, ok := permissions.([]entity.Permission)
+	if !ok {
+		return nil, false
+	}
+	return permissionsValue, true
+}
+
 func UserIsAdminContext(c *gin.Context) (bool, bool) {
 	isAdmin, exists := c.Get(UserIsAdminKey)
 	if !exists {
@@ -69,3 +102,32 @@ func UserEmailContext(c *gin.Context) (string, bool) {
 	}
 	return emailValue, true
 }
+
+// APIKeyIDContext возвращает ID API ключа, если запрос выполнен по ключу магазина
+func APIKeyIDContext(c *gin.Context) (uint, bool) {
+	id, exists := c.Get(APIKeyIDKey)
+	if !exists {
+		return 0, false
+	}
+	idValue, ok := id.(uint)
+	return idValue, ok
+}
+
+// APIKeyShopIDContext возвращает магазин, в пределах которого действует API ключ запроса
+func APIKeyShopIDContext(c *gin.Context) (uint, bool) {
+	id, exists := c.Get(APIKeyShopIDKey)
+	if !exists {
+		return 0, false
+	}
+	idValue, ok := id.(uint)
+	return idValue, ok
+}
+
+func APIKeyScopesContext(c *gin.Context) ([]entity.Permission, bool) {
+	scopes, exists := c.Get(APIKeyScopesKey)
+	if !exists {
+		return nil, false
+	}
+	scopesValue, ok := scopes.([]entity.Permission)
+	return scopesValue, ok
+}
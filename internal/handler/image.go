@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead запас на заголовки и границы multipart-запроса сверх размера самого файла
+const multipartOverhead = 64 * 1024
+
+type ImageService interface {
+	UploadProductImage(ctx context.Context, productID int, userID uint, isAdmin bool,
+		data []byte) (entity.ProductImage, error)
+	GetProductImages(ctx context.Context, productID int) ([]entity.ProductImage, error)
+	DeleteProductImage(ctx context.Context, productID int, fileName string, userID uint, isAdmin bool) error
+}
+
+// LocalFiles отдает файлы локального хранилища по подписанным ссылкам
+type LocalFiles interface {
+	Open(key, expires, signature string) (*os.File, error)
+}
+
+type ImageHandler struct {
+	imageService ImageService
+	maxSize      int64
+	localFiles   LocalFiles
+}
+
+// NewImageHandler создает хендлер изображений, localFiles передается только при локальном хранилище
+func NewImageHandler(imageService ImageService, maxSize int64, localFiles LocalFiles) *ImageHandler {
+	return &ImageHandler{
+		imageService: imageService,
+		maxSize:      maxSize,
+		localFiles:   localFiles,
+	}
+}
+
+// PostProductImage godoc
+// @Summary      Загрузить изображение продукта
+// @Description  Принимает JPEG, PNG, GIF или WebP, сохраняет оригинал и превью в хранилище.
+// @Description  Доступно администраторам и продавцам, у которых продукт есть в ассортименте.
+// @Tags         images
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      int   true  "ID продукта"
+// @Param        image  formData  file  true  "Файл изображения"
+// @Success      201    {object}  entity.ProductImage
+// @Failure      400    {object}  apperror.Error
+// @Failure      401    {object}  apperror.Error
+// @Failure      403    {object}  apperror.Error
+// @Failure      404    {object}  apperror.Error
+// @Failure      500    {object}  apperror.Error
+// @Router       /products/{id}/images [post]
+func (h *ImageHandler) PostProductImage(c *gin.Context) {
+	productID, err := parseProductID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+	isAdmin, _ := helpers.UserIsAdminContext(c)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+multipartOverhead)
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "image file is required", err))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "failed to read image file", err))
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	// Читаем на байт больше лимита, чтобы сервис мог отличить слишком большой файл
+	data, err := io.ReadAll(io.LimitReader(file, h.maxSize+1))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "failed to read image file", err))
+		return
+	}
+
+	img, err := h.imageService.UploadProductImage(c.Request.Context(), productID, userID, isAdmin, data)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, img)
+}
+
+// GetProductImages godoc
+// @Summary      Получить изображения продукта
+// @Description  Возвращает изображения продукта с временными подписанными ссылками на оригинал и превью
+// @Tags         images
+// @Produce      json
+// @Param        id   path      int  true  "ID продукта"
+// @Success      200  {array}   entity.ProductImage
+// @Failure      400  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /products/{id}/images [get]
+func (h *ImageHandler) GetProductImages(c *gin.Context) {
+	productID, err := parseProductID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	images, err := h.imageService.GetProductImages(c.Request.Context(), productID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if images == nil {
+		images = []entity.ProductImage{}
+	}
+
+	c.JSON(http.StatusOK, images)
+}
+
+// DeleteProductImage godoc
+// @Summary      Удалить изображение продукта
+// @Description  Удаляет оригинал и превью из хранилища. Имя - последняя часть ключа изображения.
+// @Tags         images
+// @Security     BearerAuth
+// @Param        id    path  int     true  "ID продукта"
+// @Param        name  path  string  true  "Имя файла изображения"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /products/{id}/images/{name} [delete]
+func (h *ImageHandler) DeleteProductImage(c *gin.Context) {
+	productID, err := parseProductID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+	isAdmin, _ := helpers.UserIsAdminContext(c)
+
+	err = h.imageService.DeleteProductImage(c.Request.Context(), productID, c.Param("name"), userID, isAdmin)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ServeLocalImage godoc
+// @Summary      Получить файл изображения
+// @Description  Отдает файл из локального хранилища по подписанной ссылке. При хранении в S3 ссылки ведут в бакет.
+// @Tags         images
+// @Produce      image/jpeg,image/png,image/gif,image/webp
+// @Param        key        path   string  true  "Ключ изображения"
+// @Param        expires    query  int     true  "Время истечения ссылки (unix)"
+// @Param        signature  query  string  true  "Подпись ссылки"
+// @Success      200
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Router       /images/{key} [get]
+func (h *ImageHandler) ServeLocalImage(c *gin.Context) {
+	if h.localFiles == nil {
+		_ = c.Error(apperror.ErrImageNotFound)
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	file, err := h.localFiles.Open(key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			_ = c.Error(apperror.ErrImageNotFound)
+			return
+		}
+		_ = c.Error(apperror.New(apperror.Forbidden, "invalid image link", err))
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.InternalError, "failed to read image", err))
+		return
+	}
+
+	http.ServeContent(c.Writer, c.Request, stat.Name(), stat.ModTime(), file)
+}
+
+func parseProductID(c *gin.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		return 0, apperror.New(apperror.BadRequest, "product id must be a positive number", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type KeyProvider interface {
+	PublicKeys() []entity.PublicKey
+}
+
+// JWKSHandler публикует открытые ключи, которыми другие сервисы проверяют наши access токены
+type JWKSHandler struct {
+	keyProvider KeyProvider
+}
+
+func NewJWKSHandler(kp KeyProvider) *JWKSHandler {
+	return &JWKSHandler{
+		keyProvider: kp,
+	}
+}
+
+// GetJWKS godoc
+//
+//	@Summary		Открытые ключи подписи токенов
+//	@Description	Возвращает JWK Set с ключами проверки access токенов, включая ключи, выведенные из ротации
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	dto.JWKSResp
+//	@Router			/.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, dto.FormJWKS(h.keyProvider.PublicKeys()))
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyMFA godoc
+//
+//	@Summary		Второй шаг входа
+//	@Description	Обменивает mfa_challenge из /auth/login и код TOTP или резервный код на токены access/refresh.
+//	@Description	mfa_challenge одноразовый: после неверного кода вход начинается заново.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			mfa	body		dto.VerifyMFAReq	true	"Токен проверки и код"
+//	@Success		200	{object}	dto.LoginUserResp
+//	@Failure		400	{object}	apperror.AppError
+//	@Failure		401	{object}	apperror.AppError
+//	@Failure		429	{object}	apperror.AppError
+//	@Router			/auth/login/mfa [post]
+func (h *UserHandler) VerifyMFA(c *gin.Context) {
+	var req dto.VerifyMFAReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid two-factor data", err))
+		return
+	}
+
+	accessToken, refreshToken, err := h.userService.VerifyMFA(
+		c.Request.Context(),
+		req.Challenge,
+		req.Code,
+		clientInfo(c, req.Fingerprint),
+	)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	setRefreshCookie(c, refreshToken, h.basePath, h.domain, h.refreshLife)
+
+	c.JSON(http.StatusOK, dto.LoginUserResp{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	})
+}
+
+// PostTOTP godoc
+//
+//	@Summary		Подключение приложения-аутентификатора
+//	@Description	Создает секрет TOTP и otpauth URI для QR-кода. Второй фактор включается после подтверждения кодом.
+//	@Tags			profile
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.TOTPEnrollmentResp
+//	@Failure		401	{object}	apperror.AppError
+//	@Failure		409	{object}	apperror.AppError
+//	@Router			/me/mfa/totp [post]
+func (h *UserHandler) PostTOTP(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	enrollment, err := h.userService.EnrollTOTP(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormTOTPEnrollment(enrollment))
+}
+
+// ConfirmTOTP godoc
+//
+//	@Summary		Подтверждение приложения-аутентификатора
+//	@Description	Включает второй фактор по коду из приложения и возвращает резервные коды. Они показываются один раз.
+//	@Tags			profile
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			code	body		dto.ConfirmTOTPReq	true	"Код из приложения"
+//	@Success		200		{object}	dto.RecoveryCodesResp
+//	@Failure		400		{object}	apperror.AppError
+//	@Failure		401		{object}	apperror.AppError
+//	@Failure		409		{object}	apperror.AppError
+//	@Router			/me/mfa/totp/confirm [post]
+func (h *UserHandler) ConfirmTOTP(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	var req dto.ConfirmTOTPReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid two-factor data", err))
+		return
+	}
+
+	codes, err := h.userService.ConfirmTOTP(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RecoveryCodesResp{RecoveryCodes: codes})
+}
+
+// DeleteTOTP godoc
+//
+//	@Summary		Отключение второго фактора
+//	@Description	Отключает второй фактор после проверки пароля и удаляет резервные коды
+//	@Tags			profile
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Param			password	body	dto.MFAPasswordReq	true	"Текущий пароль"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Failure		401	{object}	apperror.AppError
+//	@Failure		409	{object}	apperror.AppError
+//	@Router			/me/mfa/totp [delete]
+func (h *UserHandler) DeleteTOTP(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	var req dto.MFAPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid password data", err))
+		return
+	}
+
+	if err := h.userService.DisableTOTP(c.Request.Context(), userID, req.Password); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PostRecoveryCodes godoc
+//
+//	@Summary		Новые резервные коды
+//	@Description	После проверки пароля заменяет резервные коды новыми. Старые коды перестают действовать.
+//	@Tags			profile
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			password	body		dto.MFAPasswordReq	true	"Текущий пароль"
+//	@Success		200			{object}	dto.RecoveryCodesResp
+//	@Failure		400			{object}	apperror.AppError
+//	@Failure		401			{object}	apperror.AppError
+//	@Failure		409			{object}	apperror.AppError
+//	@Router			/me/mfa/recovery-codes [post]
+func (h *UserHandler) PostRecoveryCodes(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	var req dto.MFAPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid password data", err))
+		return
+	}
+
+	codes, err := h.userService.RegenerateRecoveryCodes(c.Request.Context(), userID, req.Password)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RecoveryCodesResp{RecoveryCodes: codes})
+}
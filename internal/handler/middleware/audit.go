@@ -16,6 +16,7 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"go.uber.org/zap"
 )
 
@@ -168,19 +169,23 @@ func (am *AuditMiddleware) Middleware() gin.HandlerFunc {
 
 		receivedAt := time.Now()
 
-		bodyBytes, _ := c.GetRawData()
-		if len(bodyBytes) > maxBodySize {
-			bodyBytes = append(bodyBytes[:maxBodySize], []byte("... [TRUNCATED]")...)
+		// Тело multipart-запросов (загрузка файлов) не логируется и не вычитывается целиком в память
+		var bodyBytes []byte
+		if c.ContentType() != binding.MIMEMultipartPOSTForm {
+			bodyBytes, _ = c.GetRawData()
+			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+			if len(bodyBytes) > maxBodySize {
+				bodyBytes = append(bodyBytes[:maxBodySize:maxBodySize], []byte("... [TRUNCATED]")...)
+			}
 		}
 
-		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
 		blw := &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
 		c.Writer = blw
 
 		c.Next()
 
 		usrID, _ := helpers.UserIDContext(c)
+		apiKeyID, _ := helpers.APIKeyIDContext(c)
 
 		reqBody := make(map[string]interface{})
 		if len(bodyBytes) > 0 {
@@ -206,6 +211,7 @@ func (am *AuditMiddleware) Middleware() gin.HandlerFunc {
 			Url:        c.Request.URL.Path,
 			RespStatus: c.Writer.Status(),
 			UserID:     usrID,
+			APIKeyID:   apiKeyID,
 			IP:         c.ClientIP(),
 			UserRole:   getRole(c),
 			ReceivedAt: receivedAt,
@@ -224,22 +230,24 @@ func (am *AuditMiddleware) Middleware() gin.HandlerFunc {
 }
 
 func getRole(c *gin.Context) string {
-	isStore, _ := helpers.UserIsStoreContext(c)
-	if isStore {
-		return "shop"
+	role, ok := helpers.UserRoleContext(c)
+	if !ok {
+		return string(entity.RoleUser)
 	}
-	isAdmin, _ := helpers.UserIsAdminContext(c)
-	if isAdmin {
-		return "admin"
-	}
-	return "user"
+	return string(role)
 }
 
 func sanitizeSensitiveData(data map[string]interface{}) {
 	if data == nil {
 		return
 	}
-	sensitiveFields := []string{"password", "fingerprint", "refresh_token", "access_token"}
+	sensitiveFields := []string{
+		"password", "current_password", "new_password", "fingerprint", "refresh_token", "access_token", "api_key",
+		// второй фактор: секрет TOTP, резервные коды и одноразовые коды входа
+		"otpauth_uri", "recovery_codes", "mfa_challenge", "code",
+		// секрет TOTP и секрет подписи вебхуков, которым можно подделать X-Stawberry-Signature
+		"secret",
+	}
 	for _, field := range sensitiveFields {
 		if _, ok := data[field]; ok {
 			data[field] = "[REDACTED]"
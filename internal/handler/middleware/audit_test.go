@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"encoding/json"
+
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("sanitizeSensitiveData", func() {
+	DescribeTable("should redact second factor secrets",
+		func(field string, value interface{}) {
+			data := map[string]interface{}{field: value, "name": "kept"}
+
+			sanitizeSensitiveData(data)
+
+			Expect(data).To(HaveKeyWithValue(field, "[REDACTED]"))
+			Expect(data).To(HaveKeyWithValue("name", "kept"))
+		},
+		Entry("TOTP secret", "secret", "JBSWY3DPEHPK3PXP"),
+		Entry("TOTP provisioning URI", "otpauth_uri", "otpauth://totp/Stawberry:a@b.c?secret=JBSWY3DPEHPK3PXP"),
+		Entry("recovery codes", "recovery_codes", []interface{}{"aaaa-bbbb", "cccc-dddd"}),
+		Entry("login challenge", "mfa_challenge", "challenge"),
+		Entry("one-time code", "code", "123456"),
+	)
+
+	It("should redact the webhook signing secret returned on creation", func() {
+		raw, err := json.Marshal(dto.PostWebhookResp{
+			WebhookResp: dto.WebhookResp{ID: 1, URL: "https://shop.example/hook"},
+			Secret:      "whsec_signing_secret",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		var data map[string]interface{}
+		Expect(json.Unmarshal(raw, &data)).To(Succeed())
+		sanitizeSensitiveData(data)
+
+		Expect(data).To(HaveKeyWithValue("secret", "[REDACTED]"))
+		Expect(data).To(HaveKeyWithValue("url", "https://shop.example/hook"))
+	})
+
+	It("should tolerate a missing body", func() {
+		Expect(func() { sanitizeSensitiveData(nil) }).NotTo(Panic())
+	})
+})
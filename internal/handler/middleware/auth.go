@@ -19,14 +19,23 @@ type TokenValidator interface {
 	ValidateToken(ctx context.Context, token string) (entity.AccessToken, error)
 }
 
+type APIKeyValidator interface {
+	Authenticate(ctx context.Context, rawKey string) (entity.APIKey, error)
+}
+
 const (
 	authorizationHeader = "Authorization"
 	bearerSchema        = "Bearer"
+	apiKeySchema        = "ApiKey"
+	accessTokenParam    = "access_token"
 )
 
-// AuthMiddleware валидирует access token,
-// достает из него userID и проверяет существование пользователя
-func AuthMiddleware(userGetter UserGetter, validator TokenValidator) gin.HandlerFunc {
+// AuthMiddleware валидирует access token, в том числе по списку отозванных,
+// достает из него userID и проверяет, что пользователь существует и не заблокирован.
+// Если передан keyValidator, вместо access токена принимается API ключ магазина со схемой ApiKey:
+// запрос выполняется от имени создавшего ключ сотрудника, а маршрут должен разрешить область ключа
+// через RequireScope. Без keyValidator схема ApiKey отклоняется.
+func AuthMiddleware(userGetter UserGetter, validator TokenValidator, keyValidator APIKeyValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHead := c.GetHeader(authorizationHeader)
 		if authHead == "" {
@@ -36,32 +45,66 @@ func AuthMiddleware(userGetter UserGetter, validator TokenValidator) gin.Handler
 		}
 
 		parts := strings.Split(authHead, " ")
-		if len(parts) != 2 || parts[0] != bearerSchema {
+		if len(parts) != 2 || (parts[0] != bearerSchema && (parts[0] != apiKeySchema || keyValidator == nil)) {
 			_ = c.Error(apperror.New(apperror.Unauthorized, "Invalid authorization format", nil))
 			c.Abort()
 			return
 		}
 
-		access, err := validator.ValidateToken(c.Request.Context(), parts[1])
+		var userID uint
+		if parts[0] == apiKeySchema {
+			key, err := keyValidator.Authenticate(c.Request.Context(), parts[1])
+			if err != nil {
+				_ = c.Error(apperror.New(apperror.Unauthorized, "Invalid, expired or revoked api key", err))
+				c.Abort()
+				return
+			}
+			userID = key.CreatedBy
+			c.Set(helpers.APIKeyIDKey, key.ID)
+			c.Set(helpers.APIKeyShopIDKey, key.ShopID)
+			c.Set(helpers.APIKeyScopesKey, key.Scopes)
+		} else {
+			access, err := validator.ValidateToken(c.Request.Context(), parts[1])
+			if err != nil {
+				_ = c.Error(apperror.New(apperror.Unauthorized, "Invalid or expired token", err))
+				c.Abort()
+				return
+			}
+			userID = access.UserID
+		}
+
+		user, err := userGetter.GetUserByID(c.Request.Context(), userID)
 		if err != nil {
-			_ = c.Error(apperror.New(apperror.Unauthorized, "Invalid or expired token", err))
+			_ = c.Error(apperror.New(apperror.Unauthorized, "User not found", err))
 			c.Abort()
 			return
 		}
 
-		user, err := userGetter.GetUserByID(c.Request.Context(), access.UserID)
-		if err != nil {
-			_ = c.Error(apperror.New(apperror.Unauthorized, "User not found", err))
+		if user.BannedAt != nil {
+			_ = c.Error(apperror.ErrUserBanned)
 			c.Abort()
 			return
 		}
 
 		c.Set(helpers.UserIDKey, user.ID)
-		c.Set(helpers.UserIsStoreKey, user.IsStore)
+		c.Set(helpers.UserRoleKey, user.Role)
+		c.Set(helpers.UserIsStoreKey, user.Role == entity.RoleShop)
+		c.Set(helpers.UserIsAdminKey, user.Role == entity.RoleAdmin)
 		c.Set(helpers.UserName, user.Name)
 		c.Set(helpers.UserEmail, user.Email)
+		c.Set(helpers.UserEmailVerified, user.EmailVerifiedAt != nil)
+		c.Next()
+	}
+}
 
-		c.Set(helpers.UserIsAdminKey, false)
+// AccessTokenFromQuery принимает access token из параметра access_token, если нет заголовка Authorization.
+// Нужен только для потоков: браузерные EventSource и WebSocket не умеют передавать заголовки.
+// Ставится перед AuthMiddleware.
+func AccessTokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.Query(accessTokenParam); token != "" && c.GetHeader(authorizationHeader) == "" {
+			c.Request.Header.Set(authorizationHeader, bearerSchema+" "+token)
+		}
 		c.Next()
 	}
 }
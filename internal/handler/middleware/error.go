@@ -3,7 +3,9 @@ package middleware
 import (
 	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/gin-gonic/gin"
@@ -26,6 +28,10 @@ func Errors() gin.HandlerFunc {
 				if appErr.Code() == apperror.BadRequest {
 					resp["details"] = appErr.Error()
 				}
+				var retryErr *apperror.RetryError
+				if errors.As(err, &retryErr) {
+					c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryErr.RetryAfter.Seconds()))))
+				}
 				c.AbortWithStatusJSON(statusCode, resp)
 				return
 			}
@@ -57,6 +63,8 @@ func errorStatus(code string) int {
 		return http.StatusConflict
 	case apperror.Forbidden:
 		return http.StatusForbidden
+	case apperror.TooManyRequests:
+		return http.StatusTooManyRequests
 	case apperror.InternalError:
 		fallthrough
 
@@ -150,7 +150,7 @@ func ZapLogger(logger *zap.Logger) gin.HandlerFunc {
 		errorMessage := c.Errors.ByType(gin.ErrorTypePrivate).String()
 
 		if len(query) > 0 {
-			path = path + "?" + query
+			path = path + "?" + redactQuery(query)
 		}
 
 		message := fmt.Sprintf("%s %s %s%s%s %s",
@@ -196,3 +196,10 @@ func ZapRecovery(logger *zap.Logger) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+var accessTokenQuery = regexp.MustCompile(`(^|&)(access_token=)[^&]*`)
+
+// redactQuery hides access tokens passed in the query string by stream clients
+func redactQuery(query string) string {
+	return accessTokenQuery.ReplaceAllString(query, "${1}${2}REDACTED")
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"slices"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+type PermissionGetter interface {
+	GetUserPermissions(ctx context.Context, userID uint) ([]entity.Permission, error)
+}
+
+// RequirePermission пропускает дальше администраторов и пользователей с выданным правом,
+// должен стоять после AuthMiddleware. Права загружаются один раз за запрос и кешируются в контексте.
+func RequirePermission(getter PermissionGetter, permission entity.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role, _ := helpers.UserRoleContext(c); role == entity.RoleAdmin {
+			c.Next()
+			return
+		}
+
+		permissions, ok := helpers.UserPermissionsContext(c)
+		if !ok {
+			userID, ok := helpers.UserIDContext(c)
+			if !ok {
+				_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+				c.Abort()
+				return
+			}
+
+			var err error
+			permissions, err = getter.GetUserPermissions(c.Request.Context(), userID)
+			if err != nil {
+				_ = c.Error(err)
+				c.Abort()
+				return
+			}
+			c.Set(helpers.UserPermissionsKey, permissions)
+		}
+
+		if !slices.Contains(permissions, permission) {
+			_ = c.Error(apperror.New(apperror.Forbidden, "permission "+string(permission)+" required", nil))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope разрешает маршрут запросам по API ключу магазина с областью scope,
+// должен стоять после AuthMiddleware. Запросы по access токену проходят без проверки.
+func RequireScope(scope entity.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, ok := helpers.APIKeyScopesContext(c)
+		if ok && !slices.Contains(scopes, scope) {
+			_ = c.Error(apperror.New(apperror.Forbidden, "api key scope "+string(scope)+" required", nil))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole пропускает дальше только пользователей с одной из перечисленных ролей,
+// должен стоять после AuthMiddleware
+func RequireRole(roles ...entity.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, ok := helpers.UserRoleContext(c)
+		if !ok || !slices.Contains(roles, role) {
+			_ = c.Error(apperror.New(apperror.Forbidden, "insufficient privileges", nil))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,8 +12,14 @@ import (
 // change this to change request timeout
 const to = 10 * time.Second
 
+// Timeout ограничивает время обработки запроса. Потоки событий живут долго и не ограничиваются.
 func Timeout() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if IsStreamRequest(c) {
+			c.Next()
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), to)
 		defer cancel()
 
@@ -32,3 +39,8 @@ func Timeout() gin.HandlerFunc {
 		}
 	}
 }
+
+// IsStreamRequest проверяет, что клиент открывает поток: Server-Sent Events или WebSocket
+func IsStreamRequest(c *gin.Context) bool {
+	return c.IsWebsocket() || strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+}
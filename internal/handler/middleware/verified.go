@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireVerifiedEmail пропускает дальше только пользователей с подтвержденной почтой,
+// если проверка включена. Должен стоять после AuthMiddleware.
+func RequireVerifiedEmail(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled {
+			c.Next()
+			return
+		}
+
+		if verified := c.GetBool(helpers.UserEmailVerified); !verified {
+			_ = c.Error(apperror.New(apperror.Forbidden, "email is not verified", nil))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,73 +1,385 @@
 package handler
 
 import (
-	"math"
+	"context"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
 )
 
 type NotificationService interface {
-	GetNotification(id string, offset int, limit int) ([]entity.Notification, int, error)
+	GetNotifications(
+		ctx context.Context,
+		userID uint,
+		unreadOnly bool,
+		page, limit int,
+	) ([]entity.Notification, int, error)
+	CountUnread(ctx context.Context, userID uint) (int, error)
+	MarkRead(ctx context.Context, userID, notificationID uint) error
+	MarkAllRead(ctx context.Context, userID uint) (int64, error)
+	GetPreferences(ctx context.Context, userID uint) (entity.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, preferences entity.NotificationPreferences) error
+}
+
+// NotificationStream открывает поток сообщений пользователя
+type NotificationStream interface {
+	Subscribe(userID uint) (<-chan entity.RealtimeMessage, func())
 }
 
 type NotificationHandler struct {
-	offerService NotificationService
+	notificationService NotificationService
+	stream              NotificationStream
+	cfg                 *config.RealtimeConfig
 }
 
-func NewNotificationHandler(notificationService NotificationService) *NotificationHandler {
-	return &NotificationHandler{offerService: notificationService}
+func NewNotificationHandler(
+	notificationService NotificationService,
+	stream NotificationStream,
+	cfg *config.RealtimeConfig,
+) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService, stream: stream, cfg: cfg}
 }
 
-// GetNotification обработчик уведомлений
-// получает все уведомления (одобрение или неодобрение заявки) авторизированного пользователя
-func (h *NotificationHandler) GetNotification(c *gin.Context) {
-	// сделать user, ok := c.Get("user")
-	// сейчас в контекст кладется userID, в будущем структура user
-	userID, ok := c.Get("userID")
+// GetNotifications godoc
+// @Summary      Получить уведомления
+// @Description  Уведомления пользователя от новых к старым. В meta возвращается число непрочитанных.
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        unread  query     bool  false  "Только непрочитанные"
+// @Param        page    query     int   false  "Номер страницы"  default(1)
+// @Param        limit   query     int   false  "Размер страницы"  default(20)
+// @Success      200     {object}  dto.NotificationsResp
+// @Failure      400     {object}  apperror.Error
+// @Failure      401     {object}  apperror.Error
+// @Failure      500     {object}  apperror.Error
+// @Router       /notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid userID from context"})
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	unreadOnly, err := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "unread must be true or false", err))
 		return
 	}
 
 	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 	if err != nil || page < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid page number", err))
 		return
 	}
 
-	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	if err != nil || limit < 1 || limit > 100 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit value"})
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid limit value (must be 1-100)", err))
+		return
+	}
+
+	notifications, total, err := h.notificationService.GetNotifications(c.Request.Context(), userID,
+		unreadOnly, page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	unread, err := h.notificationService.CountUnread(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	offset := (page - 1) * limit
+	c.JSON(http.StatusOK, dto.FormNotifications(notifications, page, limit, total, unread))
+}
 
-	// исправить вызов h.offerService.GetNotification()
-	// сейчас в контекст кладется userID, в будущем структура user
-	uid, ok := userID.(uint)
+// GetUnreadNotifications godoc
+// @Summary      Получить число непрочитанных уведомлений
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.UnreadNotificationsResp
+// @Failure      401  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadNotifications(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
 	if !ok {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
 		return
 	}
-	notifications, total, err := h.offerService.GetNotification(strconv.FormatUint(uint64(uid), 10), offset, limit)
+
+	count, err := h.notificationService.CountUnread(c.Request.Context(), userID)
 	if err != nil {
 		_ = c.Error(err)
 		return
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	c.JSON(http.StatusOK, dto.UnreadNotificationsResp{Count: count})
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": notifications,
-		"meta": gin.H{
-			"current_page": page,
-			"per_page":     limit,
-			"total_items":  total,
-			"total_pages":  totalPages,
-		},
+// PostNotificationRead godoc
+// @Summary      Отметить уведомление прочитанным
+// @Tags         notifications
+// @Security     BearerAuth
+// @Param        id   path  int  true  "ID уведомления"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /notifications/{id}/read [post]
+func (h *NotificationHandler) PostNotificationRead(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	notificationID, err := parsePathID(c, "id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.notificationService.MarkRead(c.Request.Context(), userID, notificationID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PostNotificationsReadAll godoc
+// @Summary      Отметить все уведомления прочитанными
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.MarkAllNotificationsReadResp
+// @Failure      401  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /notifications/read-all [post]
+func (h *NotificationHandler) PostNotificationsReadAll(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	updated, err := h.notificationService.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MarkAllNotificationsReadResp{Updated: updated})
+}
+
+// GetNotificationPreferences godoc
+// @Summary      Получить настройки уведомлений
+// @Description  Канал для каждого вида уведомлений, тихие часы и частоту дайджеста
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.NotificationPreferencesDTO
+// @Failure      401  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /notifications/preferences [get]
+func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	preferences, err := h.notificationService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormNotificationPreferences(preferences))
+}
+
+// PutNotificationPreferences godoc
+// @Summary      Изменить настройки уведомлений
+// @Description  Заменяет настройки целиком. Канал in_app оставляет уведомление только в приложении,
+// @Description  email дополнительно отправляет его письмом, none отключает уведомления этого вида.
+// @Description  Письма, пришедшие на тихие часы или при включенном дайджесте (hourly, daily),
+// @Description  отправляются одним письмом позже. Тихие часы задаются в зоне timezone.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        preferences  body      dto.NotificationPreferencesDTO  true  "Настройки уведомлений"
+// @Success      200          {object}  dto.NotificationPreferencesDTO
+// @Failure      400          {object}  apperror.Error
+// @Failure      401          {object}  apperror.Error
+// @Failure      500          {object}  apperror.Error
+// @Router       /notifications/preferences [put]
+func (h *NotificationHandler) PutNotificationPreferences(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	var req dto.NotificationPreferencesDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid notification preferences", err))
+		return
+	}
+
+	preferences := req.ConvertToEntity(userID)
+	if err := h.notificationService.UpdatePreferences(c.Request.Context(), preferences); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormNotificationPreferences(preferences))
+}
+
+// GetNotificationStream godoc
+// @Summary      Поток уведомлений (Server-Sent Events)
+// @Description  Присылает новые уведомления (событие notification) и изменения заявок пользователя
+// @Description  (offer.created, offer.accepted, offer.declined, offer.cancelled, offer.expired).
+// @Description  Запрос должен содержать заголовок Accept: text/event-stream, EventSource передает его сам.
+// @Description  Браузерный EventSource может передать access token в параметре access_token.
+// @Description  Сервер периодически закрывает поток, клиент должен переподключиться и перечитать уведомления.
+// @Tags         notifications
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        access_token  query  string  false  "Access token, если нельзя передать заголовок"
+// @Success      200
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Router       /notifications/stream [get]
+func (h *NotificationHandler) GetNotificationStream(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	if !middleware.IsStreamRequest(c) {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Accept: text/event-stream header is required", nil))
+		return
+	}
+
+	messages, unsubscribe := h.stream.Subscribe(userID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Отключает буферизацию ответа в nginx
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	_, _ = io.WriteString(c.Writer, ": connected\n\n")
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.cfg.HeartbeatInterval)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(h.cfg.MaxConnectionTime)
+	defer deadline.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return false
+			}
+			c.SSEvent(message.Type, message.Data)
+			return true
+		case <-heartbeat.C:
+			_, _ = io.WriteString(w, ": ping\n\n")
+			return true
+		case <-deadline.C:
+			return false
+		case <-c.Request.Context().Done():
+			return false
+		}
 	})
 }
+
+// GetNotificationSocket godoc
+// @Summary      Поток уведомлений (WebSocket)
+// @Description  То же, что /notifications/stream, но через WebSocket. Каждое сообщение это JSON
+// @Description  с полями type и data, сообщения ping отправляются для поддержания соединения.
+// @Description  Access token можно передать в параметре access_token.
+// @Tags         notifications
+// @Security     BearerAuth
+// @Param        access_token  query  string  false  "Access token, если нельзя передать заголовок"
+// @Success      101
+// @Failure      401  {object}  apperror.Error
+// @Router       /notifications/ws [get]
+func (h *NotificationHandler) GetNotificationSocket(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	server := websocket.Server{
+		// Доступ дает только access token, cookie не используются, поэтому Origin не проверяется
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			h.serveSocket(ws, userID)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *NotificationHandler) serveSocket(ws *websocket.Conn, userID uint) {
+	defer ws.Close()
+
+	messages, unsubscribe := h.stream.Subscribe(userID)
+	defer unsubscribe()
+
+	// Клиент ничего не присылает, чтение нужно только чтобы заметить закрытие соединения
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard []byte
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+	}()
+
+	heartbeat := time.NewTicker(h.cfg.HeartbeatInterval)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(h.cfg.MaxConnectionTime)
+	defer deadline.Stop()
+
+	for {
+		var out dto.RealtimeMessageResp
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			out = dto.RealtimeMessageResp{Type: message.Type, Data: message.Data}
+		case <-heartbeat.C:
+			out = dto.RealtimeMessageResp{Type: "ping"}
+		case <-deadline.C:
+			return
+		case <-closed:
+			return
+		}
+
+		if err := websocket.JSON.Send(ws, out); err != nil {
+			return
+		}
+	}
+}
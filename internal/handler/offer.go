@@ -18,7 +18,7 @@ import (
 )
 
 type OfferService interface {
-	CreateOffer(ctx context.Context, offer entity.Offer, usr entity.User) (uint, error)
+	CreateOffer(ctx context.Context, offer entity.Offer) (uint, error)
 	GetUserOffers(ctx context.Context, userID uint, page, limit int) ([]entity.Offer, int, error)
 	GetOffer(ctx context.Context, offerID uint) (entity.Offer, error)
 	UpdateOfferStatus(ctx context.Context, offer entity.Offer, userID uint, isStore bool) (entity.Offer, error)
@@ -68,25 +68,10 @@ func (h *OfferHandler) PostOffer(c *gin.Context) {
 		return
 	}
 
-	var usr entity.User
-	usr.Name, ok = helpers.UserNameContext(c)
-	if !ok {
-		_ = c.Error(apperror.New(apperror.InternalError,
-			"user name key not found in ctx", nil))
-		return
-	}
-
-	usr.Email, ok = helpers.UserEmailContext(c)
-	if !ok {
-		_ = c.Error(apperror.New(apperror.InternalError,
-			"user email key not found in ctx", nil))
-		return
-	}
-
 	offerEnt := offerPost.ConvertToEntity()
 	offerEnt.UserID = userID
 
-	offerID, err := h.offerService.CreateOffer(c.Request.Context(), offerEnt, usr)
+	offerID, err := h.offerService.CreateOffer(c.Request.Context(), offerEnt)
 	if err != nil {
 		_ = c.Error(apperror.New(apperror.InternalError, "Failed to create offer", err))
 		return
@@ -162,6 +147,8 @@ func (h *OfferHandler) GetOffer(c *gin.Context) {
 // @produce	json
 // @param		id		path		int						true	"Offer ID"
 // @param		body	body		dto.PatchOfferStatusReq	true	"Offer status update request"
+// @security	BearerAuth
+// @security	ApiKeyAuth
 // @success	200		{object}	dto.PatchOfferStatusResp
 // @failure	400		{object}	apperror.Error
 // @failure	401		{object}	apperror.Error
@@ -198,6 +185,19 @@ func (h *OfferHandler) PatchOfferStatus(c *gin.Context) {
 			"user isstore key not found in ctx", nil))
 	}
 
+	// API ключ действует только в пределах своего магазина
+	if keyShopID, ok := helpers.APIKeyShopIDContext(c); ok {
+		offer, err := h.offerService.GetOffer(c.Request.Context(), uint(id))
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		if offer.ShopID != keyShopID {
+			_ = c.Error(apperror.New(apperror.Forbidden, "offer is addressed to another shop", nil))
+			return
+		}
+	}
+
 	offerEntity := req.ConvertToEntity()
 	offerEntity.ID = uint(id)
 
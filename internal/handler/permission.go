@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+type PermissionService interface {
+	GetUserPermissions(ctx context.Context, userID uint) ([]entity.Permission, error)
+	GrantPermission(ctx context.Context, userID uint, permission entity.Permission, grantedBy uint) error
+	RevokePermission(ctx context.Context, userID uint, permission entity.Permission) error
+}
+
+type PermissionHandler struct {
+	permissionService PermissionService
+}
+
+func NewPermissionHandler(permissionService PermissionService) *PermissionHandler {
+	return &PermissionHandler{permissionService: permissionService}
+}
+
+// GetPermissions godoc
+// @Summary      Получить реестр прав
+// @Description  Возвращает все права, которые можно выдать пользователям
+// @Tags         permissions
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   dto.PermissionResp
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Router       /admin/permissions [get]
+func (h *PermissionHandler) GetPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.FormPermissions(entity.Permissions))
+}
+
+// GetUserPermissions godoc
+// @Summary      Получить права пользователя
+// @Tags         permissions
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID пользователя"
+// @Success      200  {array}   string
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /admin/users/{id}/permissions [get]
+func (h *PermissionHandler) GetUserPermissions(c *gin.Context) {
+	userID, err := parseUserID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	permissions, err := h.permissionService.GetUserPermissions(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormUserPermissions(permissions))
+}
+
+// PostUserPermission godoc
+// @Summary      Выдать право пользователю
+// @Tags         permissions
+// @Accept       json
+// @Security     BearerAuth
+// @Param        id    path  int                        true  "ID пользователя"
+// @Param        body  body  dto.PostUserPermissionReq  true  "Право из реестра"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /admin/users/{id}/permissions [post]
+func (h *PermissionHandler) PostUserPermission(c *gin.Context) {
+	adminID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	userID, err := parseUserID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.PostUserPermissionReq
+	if err = c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid permission data", err))
+		return
+	}
+
+	err = h.permissionService.GrantPermission(c.Request.Context(), userID, entity.Permission(req.Permission), adminID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteUserPermission godoc
+// @Summary      Отозвать право у пользователя
+// @Tags         permissions
+// @Security     BearerAuth
+// @Param        id          path  int     true  "ID пользователя"
+// @Param        permission  path  string  true  "Право, например audit:read"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /admin/users/{id}/permissions/{permission} [delete]
+func (h *PermissionHandler) DeleteUserPermission(c *gin.Context) {
+	userID, err := parseUserID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	permission := entity.Permission(c.Param("permission"))
+	if err = h.permissionService.RevokePermission(c.Request.Context(), userID, permission); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseUserID(c *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		return 0, apperror.New(apperror.BadRequest, "user id must be a positive number", err)
+	}
+	return uint(id), nil
+}
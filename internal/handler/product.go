@@ -3,9 +3,12 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 
@@ -19,8 +22,18 @@ import (
 type ProductService interface {
 	GetFilteredProducts(ctx context.Context, filter model.ProductFilter, limit, offset int) ([]entity.Product, int, error)
 	GetProductByID(ctx context.Context, id string) (entity.Product, error)
+	CompareProducts(ctx context.Context, ids []int) (entity.ProductComparison, error)
+	GetProductShopPrices(ctx context.Context, id int) ([]entity.ShopPrice, error)
+	GetPriceHistory(ctx context.Context, productID int, shopID *int, from, to time.Time,
+		window string) ([]entity.ShopPriceHistory, error)
 }
 
+// defaultPriceHistoryPeriod период истории цен, если from не указан
+const defaultPriceHistoryPeriod = 30 * 24 * time.Hour
+
+// maxCompareProducts ограничивает количество продуктов в одном сравнении
+const maxCompareProducts = 10
+
 type ProductHandler struct {
 	productService ProductService
 }
@@ -135,3 +148,147 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 		},
 	})
 }
+
+// GetProductShopPrices godoc
+// @Summary      Получить предложения продукта в магазинах
+// @Description  Возвращает цену, валюту и наличие продукта в каждом магазине вместе с рейтингом продавца.
+// @Description  Сначала идут доступные предложения, затем по возрастанию цены.
+// @Tags         products
+// @Produce      json
+// @Param        id   path      int  true  "ID продукта"
+// @Success      200  {array}   entity.ShopPrice
+// @Failure      400  {object}  apperror.Error "Некорректный ID"
+// @Failure      404  {object}  apperror.Error "Продукт не найден"
+// @Failure      500  {object}  apperror.Error "Ошибка сервера при получении предложений"
+// @Router       /products/{id}/offers-from-shops [get]
+func (h *ProductHandler) GetProductShopPrices(c *gin.Context) {
+	id, err := parseProductID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	prices, err := h.productService.GetProductShopPrices(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if prices == nil {
+		prices = []entity.ShopPrice{}
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
+
+// GetPriceHistory godoc
+// @Summary      Получить историю цен продукта
+// @Description  Возвращает изменения цены продукта по магазинам и статистику min/avg/max по окнам.
+// @Description  Среднее взвешено по времени действия цены. Цены в копейках.
+// @Tags         products
+// @Produce      json
+// @Param        id       path      int     true   "ID продукта"
+// @Param        shop_id  query     int     false  "ID магазина"
+// @Param        from     query     string  false  "Начало периода в RFC3339 (по умолчанию 30 дней назад)"
+// @Param        to       query     string  false  "Конец периода в RFC3339 (по умолчанию сейчас)"
+// @Param        window   query     string  false  "Размер окна: hour, day, week, month (по умолчанию day)"
+// @Success      200  {array}   entity.ShopPriceHistory
+// @Failure      400  {object}  apperror.Error "Некорректные параметры"
+// @Failure      404  {object}  apperror.Error "Продукт не найден"
+// @Failure      500  {object}  apperror.Error "Ошибка сервера при получении истории цен"
+// @Router       /products/{id}/price-history [get]
+func (h *ProductHandler) GetPriceHistory(c *gin.Context) {
+	id, err := parseProductID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var shopID *int
+	if raw := c.Query("shop_id"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			_ = c.Error(apperror.New(apperror.BadRequest, "shop_id must be a positive number", err))
+			return
+		}
+		shopID = &value
+	}
+
+	to := time.Now()
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.Parse(time.RFC3339, raw); err != nil {
+			_ = c.Error(apperror.New(apperror.BadRequest, "to must be in RFC3339 format", err))
+			return
+		}
+	}
+
+	from := to.Add(-defaultPriceHistoryPeriod)
+	if raw := c.Query("from"); raw != "" {
+		if from, err = time.Parse(time.RFC3339, raw); err != nil {
+			_ = c.Error(apperror.New(apperror.BadRequest, "from must be in RFC3339 format", err))
+			return
+		}
+	}
+
+	history, err := h.productService.GetPriceHistory(c.Request.Context(), id, shopID, from, to,
+		c.DefaultQuery("window", "day"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// CompareProducts godoc
+// @Summary      Сравнить продукты
+// @Description  Возвращает продукты с ценами по магазинам и матрицу атрибутов по объединению ключей.
+// @Description  Атрибуты, значения которых у продуктов различаются, помечаются флагом differs.
+// @Tags         products
+// @Produce      json
+// @Param        ids  query     string  true  "ID продуктов через запятую (от 2 до 10)"
+// @Success      200  {object}  entity.ProductComparison
+// @Failure      400  {object}  apperror.Error "Некорректный список ID"
+// @Failure      404  {object}  apperror.Error "Продукт не найден"
+// @Failure      500  {object}  apperror.Error "Ошибка сервера при сравнении продуктов"
+// @Router       /products/compare [get]
+func (h *ProductHandler) CompareProducts(c *gin.Context) {
+	ids, err := parseCompareIDs(c.QueryArray("ids"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	comparison, err := h.productService.CompareProducts(c.Request.Context(), ids)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// parseCompareIDs разбирает ID как из ids=1,2, так и из ids=1&ids=2, убирая повторы
+func parseCompareIDs(params []string) ([]int, error) {
+	seen := make(map[int]struct{})
+	var ids []int
+	for _, param := range params {
+		for _, raw := range strings.Split(param, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil || id < 1 {
+				return nil, apperror.New(apperror.BadRequest, "ids must be positive numbers", err)
+			}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) < 2 || len(ids) > maxCompareProducts {
+		return nil, apperror.New(apperror.BadRequest,
+			fmt.Sprintf("from 2 to %d different products can be compared", maxCompareProducts), nil)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+type ShopMemberService interface {
+	GetMembers(ctx context.Context, shopID, actorID uint) ([]entity.ShopMember, error)
+	InviteMember(ctx context.Context, shopID, actorID uint, userMail string, role entity.ShopRole) (uint, error)
+	AcceptInvitation(ctx context.Context, token string, userID uint, userMail string) error
+	RemoveMember(ctx context.Context, shopID, actorID, userID uint) error
+}
+
+type ShopMemberHandler struct {
+	shopMemberService ShopMemberService
+}
+
+func NewShopMemberHandler(shopMemberService ShopMemberService) *ShopMemberHandler {
+	return &ShopMemberHandler{shopMemberService: shopMemberService}
+}
+
+// GetShopMembers godoc
+// @Summary      Получить сотрудников магазина
+// @Description  Список доступен только сотрудникам магазина
+// @Tags         shops
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID магазина"
+// @Success      200  {array}   dto.ShopMemberResp
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /shops/{id}/members [get]
+func (h *ShopMemberHandler) GetShopMembers(c *gin.Context) {
+	actorID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	shopID, err := parseShopID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	members, err := h.shopMemberService.GetMembers(c.Request.Context(), shopID, actorID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormShopMembers(members))
+}
+
+// PostShopInvitation godoc
+// @Summary      Пригласить сотрудника
+// @Description  Отправляет приглашение на почту. Владелец приглашает менеджеров и агентов, менеджер только агентов.
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                        true  "ID магазина"
+// @Param        body  body      dto.PostShopInvitationReq  true  "Почта и роль сотрудника"
+// @Success      201   {object}  dto.PostShopInvitationResp
+// @Failure      400   {object}  apperror.Error
+// @Failure      401   {object}  apperror.Error
+// @Failure      403   {object}  apperror.Error
+// @Failure      404   {object}  apperror.Error
+// @Failure      500   {object}  apperror.Error
+// @Router       /shops/{id}/invitations [post]
+func (h *ShopMemberHandler) PostShopInvitation(c *gin.Context) {
+	actorID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	shopID, err := parseShopID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	var req dto.PostShopInvitationReq
+	if err = c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid invitation data", err))
+		return
+	}
+
+	id, err := h.shopMemberService.InviteMember(c.Request.Context(), shopID, actorID, req.Email,
+		entity.ShopRole(req.Role))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.PostShopInvitationResp{ID: id})
+}
+
+// AcceptShopInvitation godoc
+// @Summary      Принять приглашение в магазин
+// @Description  Принять приглашение может только пользователь с почтой, на которую оно отправлено
+// @Tags         shops
+// @Accept       json
+// @Security     BearerAuth
+// @Param        body  body  dto.AcceptShopInvitationReq  true  "Токен из письма"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      409  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /shop-invitations/accept [post]
+func (h *ShopMemberHandler) AcceptShopInvitation(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+	userMail, ok := helpers.UserEmailContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	var req dto.AcceptShopInvitationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid invitation token", err))
+		return
+	}
+
+	if err := h.shopMemberService.AcceptInvitation(c.Request.Context(), req.Token, userID, userMail); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteShopMember godoc
+// @Summary      Убрать сотрудника из магазина
+// @Description  Сотрудник может уйти сам. Владелец убирает менеджеров и агентов, менеджер только агентов.
+// @Tags         shops
+// @Security     BearerAuth
+// @Param        id      path  int  true  "ID магазина"
+// @Param        userID  path  int  true  "ID сотрудника"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /shops/{id}/members/{userID} [delete]
+func (h *ShopMemberHandler) DeleteShopMember(c *gin.Context) {
+	actorID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	shopID, err := parseShopID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("userID"), 10, 32)
+	if err != nil || userID == 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "user id must be a positive number", err))
+		return
+	}
+
+	if err = h.shopMemberService.RemoveMember(c.Request.Context(), shopID, actorID, uint(userID)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseShopID(c *gin.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		return 0, apperror.New(apperror.BadRequest, "shop id must be a positive number", err)
+	}
+	return uint(id), nil
+}
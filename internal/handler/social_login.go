@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/gin-gonic/gin"
+)
+
+//go:generate mockgen -source=$GOFILE -destination=social_login_mock_test.go -package=handler SocialLoginService
+
+type SocialLoginService interface {
+	Providers() []string
+	Begin(ctx context.Context, providerName, fingerprint string) (string, error)
+	Complete(
+		ctx context.Context,
+		providerName, state, code string,
+		client entity.ClientInfo,
+	) (user.LoginResult, error)
+}
+
+// SocialLoginHandler обслуживает вход через внешних OIDC провайдеров
+type SocialLoginHandler struct {
+	socialLoginService SocialLoginService
+	refreshLife        int
+	basePath           string
+	domain             string
+}
+
+func NewSocialLoginHandler(
+	cfg *config.Config,
+	socialLoginService SocialLoginService,
+) *SocialLoginHandler {
+	return &SocialLoginHandler{
+		socialLoginService: socialLoginService,
+		refreshLife:        int(cfg.Token.RefreshTokenDuration),
+		domain:             cfg.Server.Domain,
+	}
+}
+
+// GetOIDCProviders godoc
+//
+//	@Summary		Внешние провайдеры входа
+//	@Description	Возвращает имена настроенных OIDC провайдеров
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	dto.OIDCProvidersResp
+//	@Router			/auth/oidc [get]
+func (h *SocialLoginHandler) GetOIDCProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.OIDCProvidersResp{Providers: h.socialLoginService.Providers()})
+}
+
+// GetOIDCAuthURL godoc
+//
+//	@Summary		Начало входа через внешнего провайдера
+//	@Description	Возвращает адрес страницы входа провайдера. После входа провайдер перенаправит
+//	@Description	пользователя на redirect_uri с code и state, их нужно передать в callback.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			provider	path		string	true	"Имя провайдера"
+//	@Param			fingerprint	query		string	true	"Отпечаток клиента"
+//	@Success		200			{object}	dto.OIDCAuthURLResp
+//	@Failure		400			{object}	apperror.AppError
+//	@Failure		404			{object}	apperror.AppError
+//	@Router			/auth/oidc/{provider} [get]
+func (h *SocialLoginHandler) GetOIDCAuthURL(c *gin.Context) {
+	fingerprint := c.Query("fingerprint")
+	if fingerprint == "" {
+		_ = c.Error(apperror.New(apperror.BadRequest, "fingerprint is required", nil))
+		return
+	}
+
+	authURL, err := h.socialLoginService.Begin(c.Request.Context(), c.Param("provider"), fingerprint)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.OIDCAuthURLResp{AuthorizationURL: authURL})
+}
+
+// PostOIDCCallback godoc
+//
+//	@Summary		Завершение входа через внешнего провайдера
+//	@Description	Обменивает code и state из перенаправления провайдера на токены access/refresh.
+//	@Description	Если подключен второй фактор, возвращается mfa_challenge для /auth/login/mfa.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider	path		string				true	"Имя провайдера"
+//	@Param			callback	body		dto.OIDCCallbackReq	true	"Параметры перенаправления провайдера"
+//	@Success		200			{object}	dto.LoginUserResp
+//	@Failure		400			{object}	apperror.AppError
+//	@Failure		401			{object}	apperror.AppError
+//	@Failure		404			{object}	apperror.AppError
+//	@Failure		409			{object}	apperror.AppError
+//	@Router			/auth/oidc/{provider}/callback [post]
+func (h *SocialLoginHandler) PostOIDCCallback(c *gin.Context) {
+	var req dto.OIDCCallbackReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid callback data", err))
+		return
+	}
+
+	result, err := h.socialLoginService.Complete(
+		c.Request.Context(),
+		c.Param("provider"),
+		req.State,
+		req.Code,
+		clientInfo(c, req.Fingerprint),
+	)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if result.MFAChallenge != "" {
+		c.JSON(http.StatusOK, dto.LoginUserResp{
+			MFARequired:  true,
+			MFAChallenge: result.MFAChallenge,
+		})
+		return
+	}
+
+	setRefreshCookie(c, result.RefreshToken, h.basePath, h.domain, h.refreshLife)
+
+	c.JSON(http.StatusOK, dto.LoginUserResp{
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
+	})
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: social_login.go
+//
+// Generated by this command:
+//
+//	mockgen -source=social_login.go -destination=social_login_mock_test.go -package=handler SocialLoginService
+//
+
+// Package handler is a generated GoMock package.
+package handler
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	user "github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSocialLoginService is a mock of SocialLoginService interface.
+type MockSocialLoginService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSocialLoginServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockSocialLoginServiceMockRecorder is the mock recorder for MockSocialLoginService.
+type MockSocialLoginServiceMockRecorder struct {
+	mock *MockSocialLoginService
+}
+
+// NewMockSocialLoginService creates a new mock instance.
+func NewMockSocialLoginService(ctrl *gomock.Controller) *MockSocialLoginService {
+	mock := &MockSocialLoginService{ctrl: ctrl}
+	mock.recorder = &MockSocialLoginServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSocialLoginService) EXPECT() *MockSocialLoginServiceMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockSocialLoginService) Begin(ctx context.Context, providerName, fingerprint string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, providerName, fingerprint)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockSocialLoginServiceMockRecorder) Begin(ctx, providerName, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockSocialLoginService)(nil).Begin), ctx, providerName, fingerprint)
+}
+
+// Complete mocks base method.
+func (m *MockSocialLoginService) Complete(ctx context.Context, providerName, state, code string, client entity.ClientInfo) (user.LoginResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, providerName, state, code, client)
+	ret0, _ := ret[0].(user.LoginResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockSocialLoginServiceMockRecorder) Complete(ctx, providerName, state, code, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockSocialLoginService)(nil).Complete), ctx, providerName, state, code, client)
+}
+
+// Providers mocks base method.
+func (m *MockSocialLoginService) Providers() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Providers")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Providers indicates an expected call of Providers.
+func (mr *MockSocialLoginServiceMockRecorder) Providers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Providers", reflect.TypeOf((*MockSocialLoginService)(nil).Providers))
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/EM-Stawberry/Stawberry/config"
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("SocialLoginHandler", func() {
+	var (
+		ctrl        *gomock.Controller
+		mockService *MockSocialLoginService
+		handler     *SocialLoginHandler
+		router      *gin.Engine
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockService = NewMockSocialLoginService(ctrl)
+		handler = NewSocialLoginHandler(&config.Config{}, mockService)
+
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		router.Use(middleware.Errors())
+		router.GET("/auth/oidc/:provider", handler.GetOIDCAuthURL)
+		router.POST("/auth/oidc/:provider/callback", handler.PostOIDCCallback)
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("GetOIDCAuthURL", func() {
+		It("should return the provider login page", func() {
+			mockService.EXPECT().Begin(gomock.Any(), "local", "fp123").Return("https://idp/authorize", nil)
+
+			req := httptest.NewRequest("GET", "/auth/oidc/local?fingerprint=fp123", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response dto.OIDCAuthURLResp
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.AuthorizationURL).To(Equal("https://idp/authorize"))
+		})
+
+		It("should return not found for an unknown provider", func() {
+			mockService.EXPECT().Begin(gomock.Any(), "unknown", "fp123").
+				Return("", apperror.ErrIdentityProviderNotFound)
+
+			req := httptest.NewRequest("GET", "/auth/oidc/unknown?fingerprint=fp123", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should require a fingerprint", func() {
+			req := httptest.NewRequest("GET", "/auth/oidc/local", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("PostOIDCCallback", func() {
+		input := dto.OIDCCallbackReq{Code: "code", State: "state", Fingerprint: "fp123"}
+
+		It("should return tokens and set the refresh cookie", func() {
+			mockService.EXPECT().Complete(gomock.Any(), "local", "state", "code", testClient).
+				Return(user.LoginResult{AccessToken: "access_token", RefreshToken: "refresh_token"}, nil)
+
+			jsonData, _ := json.Marshal(input)
+			req := httptest.NewRequest("POST", "/auth/oidc/local/callback", bytes.NewBuffer(jsonData))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response dto.LoginUserResp
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.AccessToken).To(Equal("access_token"))
+			Expect(w.Header().Get("Set-Cookie")).To(ContainSubstring("refresh_token=refresh_token"))
+		})
+
+		It("should return a challenge when second factor is enabled", func() {
+			mockService.EXPECT().Complete(gomock.Any(), "local", "state", "code", testClient).
+				Return(user.LoginResult{MFAChallenge: "challenge"}, nil)
+
+			jsonData, _ := json.Marshal(input)
+			req := httptest.NewRequest("POST", "/auth/oidc/local/callback", bytes.NewBuffer(jsonData))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			var response dto.LoginUserResp
+			Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+			Expect(response.MFARequired).To(BeTrue())
+			Expect(response.AccessToken).To(BeEmpty())
+			Expect(w.Header().Get("Set-Cookie")).To(BeEmpty())
+		})
+
+		It("should reject an invalid state", func() {
+			mockService.EXPECT().Complete(gomock.Any(), "local", "state", "code", testClient).
+				Return(user.LoginResult{}, apperror.ErrInvalidLoginState)
+
+			jsonData, _ := json.Marshal(input)
+			req := httptest.NewRequest("POST", "/auth/oidc/local/callback", bytes.NewBuffer(jsonData))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
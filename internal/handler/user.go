@@ -9,17 +9,38 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 //go:generate mockgen -source=$GOFILE -destination=user_mock_test.go -package=handler UserService
 
 type UserService interface {
-	CreateUser(ctx context.Context, user user.User, fingerprint string) (string, string, error)
-	Authenticate(ctx context.Context, email, password, fingerprint string) (string, string, error)
-	Refresh(ctx context.Context, refreshToken, fingerprint string) (string, string, error)
+	CreateUser(ctx context.Context, user user.User, client entity.ClientInfo) (string, string, error)
+	Authenticate(ctx context.Context, email, password string, client entity.ClientInfo) (user.LoginResult, error)
+	VerifyMFA(ctx context.Context, challenge, code string, client entity.ClientInfo) (string, string, error)
+	Refresh(ctx context.Context, refreshToken string, client entity.ClientInfo) (string, string, error)
 	Logout(ctx context.Context, refreshToken, fingerprint string) error
 	GetUserByID(ctx context.Context, id uint) (entity.User, error)
+	VerifyEmail(ctx context.Context, token string) error
+	UnlockAccount(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID uint) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+	UpdateProfile(ctx context.Context, userID uint, name, phone *string) error
+	ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) error
+	ChangeEmail(ctx context.Context, userID uint, password, newEmail string) error
+	DeleteAccount(ctx context.Context, userID uint, password string) error
+	ListSessions(ctx context.Context, userID uint) ([]entity.RefreshToken, error)
+	RevokeSession(ctx context.Context, userID uint, sessionUUID string) error
+	RevokeAllSessions(ctx context.Context, userID uint) error
+	BanUser(ctx context.Context, userID uint) error
+	UnbanUser(ctx context.Context, userID uint) error
+	EnrollTOTP(ctx context.Context, userID uint) (entity.TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID uint, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID uint, password string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uint, password string) ([]string, error)
 }
 
 type UserHandler struct {
@@ -61,7 +82,7 @@ func (h *UserHandler) Registration(c *gin.Context) {
 	accessToken, refreshToken, err := h.userService.CreateUser(
 		c.Request.Context(),
 		regUserDTO.ConvertToSvc(),
-		regUserDTO.Fingerprint,
+		clientInfo(c, regUserDTO.Fingerprint),
 	)
 	if err != nil {
 		_ = c.Error(err)
@@ -80,7 +101,8 @@ func (h *UserHandler) Registration(c *gin.Context) {
 // Login godoc
 //
 //	@Summary		Аутентификация пользователя
-//	@Description	Аутентифицирует пользователя и возвращает токены access/refresh
+//	@Description	Аутентифицирует пользователя и возвращает токены access/refresh.
+//	@Description	Если подключен второй фактор, возвращается mfa_challenge для /auth/login/mfa.
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
@@ -95,11 +117,11 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	accessToken, refreshToken, err := h.userService.Authenticate(
+	result, err := h.userService.Authenticate(
 		c.Request.Context(),
 		loginUserDTO.Email,
 		loginUserDTO.Password,
-		loginUserDTO.Fingerprint,
+		clientInfo(c, loginUserDTO.Fingerprint),
 	)
 
 	if err != nil {
@@ -107,12 +129,20 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
+	if result.MFAChallenge != "" {
+		c.JSON(http.StatusOK, dto.LoginUserResp{
+			MFARequired:  true,
+			MFAChallenge: result.MFAChallenge,
+		})
+		return
+	}
+
 	response := dto.LoginUserResp{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
 	}
 
-	setRefreshCookie(c, refreshToken, h.basePath, h.domain, h.refreshLife)
+	setRefreshCookie(c, result.RefreshToken, h.basePath, h.domain, h.refreshLife)
 
 	c.JSON(http.StatusOK, response)
 }
@@ -147,7 +177,7 @@ func (h *UserHandler) Refresh(c *gin.Context) {
 	accessToken, refreshToken, err := h.userService.Refresh(
 		c.Request.Context(),
 		refreshDTO.RefreshToken,
-		refreshDTO.Fingerprint,
+		clientInfo(c, refreshDTO.Fingerprint),
 	)
 	if err != nil {
 		_ = c.Error(err)
@@ -203,6 +233,438 @@ func (h *UserHandler) Logout(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// VerifyEmail godoc
+//
+//	@Summary		Подтверждение почты
+//	@Description	Подтверждает почту по одноразовому токену из письма
+//	@Tags			auth
+//	@Accept			json
+//	@Param			verify	body	dto.VerifyEmailReq	true	"Токен из письма"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Router			/auth/verify [post]
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid verification data", err))
+		return
+	}
+
+	if err := h.userService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UnlockAccount godoc
+//
+//	@Summary		Разблокировка учетной записи
+//	@Description	Снимает блокировку входа после неудачных попыток по одноразовому токену из письма
+//	@Tags			auth
+//	@Accept			json
+//	@Param			unlock	body	dto.UnlockAccountReq	true	"Токен из письма"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Router			/auth/unlock [post]
+func (h *UserHandler) UnlockAccount(c *gin.Context) {
+	var req dto.UnlockAccountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid unlock data", err))
+		return
+	}
+
+	if err := h.userService.UnlockAccount(c.Request.Context(), req.Token); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResendVerification godoc
+//
+//	@Summary		Повторная отправка письма подтверждения
+//	@Description	Отправляет новый токен подтверждения почты
+//	@Tags			auth
+//	@Security		BearerAuth
+//	@Success		204
+//	@Failure		401	{object}	apperror.AppError
+//	@Failure		409	{object}	apperror.AppError
+//	@Router			/auth/verify/resend [post]
+func (h *UserHandler) ResendVerification(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	if err := h.userService.ResendVerification(c.Request.Context(), userID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ForgotPassword godoc
+//
+//	@Summary		Запрос сброса пароля
+//	@Description	Отправляет токен сброса пароля на почту. Ответ не зависит от того, зарегистрирована ли почта.
+//	@Tags			auth
+//	@Accept			json
+//	@Param			forgot	body	dto.ForgotPasswordReq	true	"Почта пользователя"
+//	@Success		202
+//	@Failure		400	{object}	apperror.AppError
+//	@Router			/auth/forgot [post]
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid email", err))
+		return
+	}
+
+	if err := h.userService.ForgotPassword(c.Request.Context(), req.Email); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Сброс пароля
+//	@Description	Устанавливает новый пароль по токену из письма и завершает все сессии пользователя
+//	@Tags			auth
+//	@Accept			json
+//	@Param			reset	body	dto.ResetPasswordReq	true	"Токен из письма и новый пароль"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Router			/auth/reset [post]
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid reset data", err))
+		return
+	}
+
+	if err := h.userService.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetMe godoc
+//
+//	@Summary		Профиль текущего пользователя
+//	@Tags			profile
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	dto.UserProfileResp
+//	@Failure		401	{object}	apperror.AppError
+//	@Router			/me [get]
+func (h *UserHandler) GetMe(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormUserProfile(user))
+}
+
+// PatchMe godoc
+//
+//	@Summary		Изменение профиля
+//	@Description	Меняет имя и/или телефон. Незаданные поля не меняются.
+//	@Tags			profile
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Param			profile	body	dto.UpdateProfileReq	true	"Новые данные профиля"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Failure		401	{object}	apperror.AppError
+//	@Router			/me [patch]
+func (h *UserHandler) PatchMe(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	var req dto.UpdateProfileReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid profile data", err))
+		return
+	}
+
+	if err := h.userService.UpdateProfile(c.Request.Context(), userID, req.Name, req.Phone); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Смена пароля
+//	@Description	Меняет пароль после проверки текущего. Все сессии пользователя завершаются.
+//	@Tags			profile
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Param			password	body	dto.ChangePasswordReq	true	"Текущий и новый пароль"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Failure		401	{object}	apperror.AppError
+//	@Router			/me/password [put]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	var req dto.ChangePasswordReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid password data", err))
+		return
+	}
+
+	err := h.userService.ChangePassword(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangeEmail godoc
+//
+//	@Summary		Смена почты
+//	@Description	Меняет почту после проверки пароля. На новую почту приходит токен подтверждения.
+//	@Tags			profile
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Param			email	body	dto.ChangeEmailReq	true	"Новая почта и текущий пароль"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Failure		401	{object}	apperror.AppError
+//	@Failure		409	{object}	apperror.AppError
+//	@Router			/me/email [put]
+func (h *UserHandler) ChangeEmail(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	var req dto.ChangeEmailReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid email data", err))
+		return
+	}
+
+	if err := h.userService.ChangeEmail(c.Request.Context(), userID, req.Password, req.Email); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteMe godoc
+//
+//	@Summary		Удаление аккаунта
+//	@Description	Обезличивает аккаунт после проверки пароля: заявки и отзывы остаются, персональные данные стираются.
+//	@Description	Владелец магазина не может удалить аккаунт, пока у него есть магазины.
+//	@Tags			profile
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Param			account	body	dto.DeleteAccountReq	true	"Текущий пароль"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Failure		401	{object}	apperror.AppError
+//	@Failure		409	{object}	apperror.AppError
+//	@Router			/me [delete]
+func (h *UserHandler) DeleteMe(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	var req dto.DeleteAccountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "Invalid account data", err))
+		return
+	}
+
+	if err := h.userService.DeleteAccount(c.Request.Context(), userID, req.Password); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSessions godoc
+//
+//	@Summary		Активные сессии
+//	@Description	Возвращает активные сессии пользователя, последние использованные первыми
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		dto.SessionResp
+//	@Failure		401	{object}	apperror.AppError
+//	@Router			/auth/sessions [get]
+func (h *UserHandler) GetSessions(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	sessions, err := h.userService.ListSessions(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	current, _ := c.Cookie("refresh_token")
+
+	c.JSON(http.StatusOK, dto.FormSessions(sessions, current))
+}
+
+// DeleteSession godoc
+//
+//	@Summary		Завершение сессии
+//	@Description	Отзывает refresh токен сессии. Выданный ей access токен действует до истечения срока.
+//	@Tags			auth
+//	@Security		BearerAuth
+//	@Param			uuid	path	string	true	"UUID сессии"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Failure		401	{object}	apperror.AppError
+//	@Failure		404	{object}	apperror.AppError
+//	@Router			/auth/sessions/{uuid} [delete]
+func (h *UserHandler) DeleteSession(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	sessionUUID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "session id must be a valid uuid", err))
+		return
+	}
+
+	if err = h.userService.RevokeSession(c.Request.Context(), userID, sessionUUID.String()); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteSessions godoc
+//
+//	@Summary		Выход на всех устройствах
+//	@Description	Отзывает refresh токены всех сессий пользователя, включая текущую
+//	@Tags			auth
+//	@Security		BearerAuth
+//	@Success		204
+//	@Failure		401	{object}	apperror.AppError
+//	@Router			/auth/sessions [delete]
+func (h *UserHandler) DeleteSessions(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	if err := h.userService.RevokeAllSessions(c.Request.Context(), userID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// BanUser godoc
+//
+//	@Summary		Заблокировать пользователя
+//	@Description	Запрещает вход и отзывает все сессии и access токены пользователя
+//	@Tags			admin
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"ID пользователя"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Failure		403	{object}	apperror.AppError
+//	@Failure		404	{object}	apperror.AppError
+//	@Router			/admin/users/{id}/ban [post]
+func (h *UserHandler) BanUser(c *gin.Context) {
+	userID, err := parseUserID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.userService.BanUser(c.Request.Context(), userID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UnbanUser godoc
+//
+//	@Summary		Разблокировать пользователя
+//	@Tags			admin
+//	@Security		BearerAuth
+//	@Param			id	path	int	true	"ID пользователя"
+//	@Success		204
+//	@Failure		400	{object}	apperror.AppError
+//	@Failure		404	{object}	apperror.AppError
+//	@Router			/admin/users/{id}/ban [delete]
+func (h *UserHandler) UnbanUser(c *gin.Context) {
+	userID, err := parseUserID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.userService.UnbanUser(c.Request.Context(), userID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// clientInfo собирает данные клиента, которые сохраняются в сессии
+func clientInfo(c *gin.Context, fingerprint string) entity.ClientInfo {
+	return entity.ClientInfo{
+		Fingerprint: fingerprint,
+		IP:          c.ClientIP(),
+		UserAgent:   c.Request.UserAgent(),
+	}
+}
+
 func setRefreshCookie(c *gin.Context, refreshToken, basePath, domain string, maxAge int) {
 	jwtCookie := http.Cookie{
 		Name:     "refresh_token",
@@ -5,18 +5,28 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/EM-Stawberry/Stawberry/config"
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
 	"github.com/EM-Stawberry/Stawberry/internal/handler/middleware"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
 )
 
+// testClient это данные клиента, которые httptest подставляет в запросы
+var testClient = entity.ClientInfo{Fingerprint: "fp123", IP: "192.0.2.1"}
+
 var _ = Describe("UserHandler", func() {
 	var (
 		ctrl        *gomock.Controller
@@ -57,7 +67,7 @@ var _ = Describe("UserHandler", func() {
 				}
 
 				mockService.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any(), "fp123").
+					CreateUser(gomock.Any(), gomock.Any(), testClient).
 					Return("access_token", "refresh_token", nil)
 
 				jsonData, _ := json.Marshal(input)
@@ -105,7 +115,7 @@ var _ = Describe("UserHandler", func() {
 				}
 
 				mockService.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any(), "fp123").
+					CreateUser(gomock.Any(), gomock.Any(), testClient).
 					Return("", "", apperror.New(apperror.InternalError, "service error", nil))
 
 				jsonData, _ := json.Marshal(input)
@@ -145,8 +155,8 @@ var _ = Describe("UserHandler", func() {
 				}
 
 				mockService.EXPECT().
-					Authenticate(gomock.Any(), "test@example.com", "password123", "fp123").
-					Return("access_token", "refresh_token", nil)
+					Authenticate(gomock.Any(), "test@example.com", "password123", testClient).
+					Return(user.LoginResult{AccessToken: "access_token", RefreshToken: "refresh_token"}, nil)
 
 				jsonData, _ := json.Marshal(input)
 				req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(jsonData))
@@ -164,6 +174,36 @@ var _ = Describe("UserHandler", func() {
 			})
 		})
 
+		Context("when second factor is enabled", func() {
+			It("should return a challenge without tokens", func() {
+				input := dto.LoginUserReq{
+					Email:       "test@example.com",
+					Password:    "password123",
+					Fingerprint: "fp123",
+				}
+
+				mockService.EXPECT().
+					Authenticate(gomock.Any(), "test@example.com", "password123", testClient).
+					Return(user.LoginResult{MFAChallenge: "challenge"}, nil)
+
+				jsonData, _ := json.Marshal(input)
+				req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(w.Result().Cookies()).To(BeEmpty())
+
+				var response dto.LoginUserResp
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.MFARequired).To(BeTrue())
+				Expect(response.MFAChallenge).To(Equal("challenge"))
+				Expect(response.AccessToken).To(BeEmpty())
+			})
+		})
+
 		Context("when authentication fails", func() {
 			It("should return unauthorized", func() {
 				input := dto.LoginUserReq{
@@ -173,8 +213,8 @@ var _ = Describe("UserHandler", func() {
 				}
 
 				mockService.EXPECT().
-					Authenticate(gomock.Any(), "test@example.com", "wrong_password", "fp123").
-					Return("", "", apperror.ErrIncorrectPassword)
+					Authenticate(gomock.Any(), "test@example.com", "wrong_password", testClient).
+					Return(user.LoginResult{}, apperror.ErrIncorrectPassword)
 
 				jsonData, _ := json.Marshal(input)
 				req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(jsonData))
@@ -186,6 +226,30 @@ var _ = Describe("UserHandler", func() {
 			})
 		})
 
+		Context("when login is throttled", func() {
+			It("should return too many requests with retry after", func() {
+				input := dto.LoginUserReq{
+					Email:       "test@example.com",
+					Password:    "password123",
+					Fingerprint: "fp123",
+				}
+
+				mockService.EXPECT().
+					Authenticate(gomock.Any(), "test@example.com", "password123", testClient).
+					Return(user.LoginResult{}, apperror.NewRetry(apperror.TooManyRequests, "too many failed login attempts",
+						1500*time.Millisecond))
+
+				jsonData, _ := json.Marshal(input)
+				req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusTooManyRequests))
+				Expect(w.Header().Get("Retry-After")).To(Equal("2"))
+			})
+		})
+
 		Context("when JSON is invalid", func() {
 			It("should return bad request", func() {
 				jsonData := []byte(`{"invalid json"`)
@@ -212,7 +276,7 @@ var _ = Describe("UserHandler", func() {
 				}
 
 				mockService.EXPECT().
-					Refresh(gomock.Any(), "old_refresh_token", "fp123").
+					Refresh(gomock.Any(), "old_refresh_token", testClient).
 					Return("new_access_token", "new_refresh_token", nil)
 
 				jsonData, _ := json.Marshal(input)
@@ -239,7 +303,7 @@ var _ = Describe("UserHandler", func() {
 				}
 
 				mockService.EXPECT().
-					Refresh(gomock.Any(), "cookie_refresh_token", "fp123").
+					Refresh(gomock.Any(), "cookie_refresh_token", testClient).
 					Return("new_access_token", "new_refresh_token", nil)
 
 				jsonData, _ := json.Marshal(input)
@@ -288,7 +352,7 @@ var _ = Describe("UserHandler", func() {
 				}
 
 				mockService.EXPECT().
-					Refresh(gomock.Any(), "invalid_token", "fp123").
+					Refresh(gomock.Any(), "invalid_token", testClient).
 					Return("", "", apperror.New(apperror.InternalError, "invalid refresh token", nil))
 
 				jsonData, _ := json.Marshal(input)
@@ -416,8 +480,382 @@ var _ = Describe("UserHandler", func() {
 			})
 		})
 	})
+
+	Describe("VerifyEmail", func() {
+		BeforeEach(func() {
+			router.POST("/auth/verify", handler.VerifyEmail)
+		})
+
+		Context("when token is valid", func() {
+			It("should return no content", func() {
+				mockService.EXPECT().VerifyEmail(gomock.Any(), "token").Return(nil)
+
+				jsonData, _ := json.Marshal(dto.VerifyEmailReq{Token: "token"})
+				req := httptest.NewRequest("POST", "/auth/verify", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("when token is invalid", func() {
+			It("should return bad request", func() {
+				mockService.EXPECT().VerifyEmail(gomock.Any(), "token").Return(apperror.ErrInvalidActionToken)
+
+				jsonData, _ := json.Marshal(dto.VerifyEmailReq{Token: "token"})
+				req := httptest.NewRequest("POST", "/auth/verify", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("VerifyMFA", func() {
+		BeforeEach(func() {
+			router.POST("/auth/login/mfa", handler.VerifyMFA)
+		})
+
+		Context("when code is valid", func() {
+			It("should return tokens", func() {
+				mockService.EXPECT().
+					VerifyMFA(gomock.Any(), "challenge", "123456", testClient).
+					Return("access_token", "refresh_token", nil)
+
+				jsonData, _ := json.Marshal(dto.VerifyMFAReq{
+					Challenge:   "challenge",
+					Code:        "123456",
+					Fingerprint: "fp123",
+				})
+				req := httptest.NewRequest("POST", "/auth/login/mfa", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var response dto.LoginUserResp
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.AccessToken).To(Equal("access_token"))
+				Expect(response.RefreshToken).To(Equal("refresh_token"))
+			})
+		})
+
+		Context("when code is invalid", func() {
+			It("should return unauthorized", func() {
+				mockService.EXPECT().
+					VerifyMFA(gomock.Any(), "challenge", "000000", testClient).
+					Return("", "", apperror.ErrInvalidMFACode)
+
+				jsonData, _ := json.Marshal(dto.VerifyMFAReq{
+					Challenge:   "challenge",
+					Code:        "000000",
+					Fingerprint: "fp123",
+				})
+				req := httptest.NewRequest("POST", "/auth/login/mfa", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+	})
+
+	Describe("UnlockAccount", func() {
+		BeforeEach(func() {
+			router.POST("/auth/unlock", handler.UnlockAccount)
+		})
+
+		Context("when token is valid", func() {
+			It("should return no content", func() {
+				mockService.EXPECT().UnlockAccount(gomock.Any(), "token").Return(nil)
+
+				jsonData, _ := json.Marshal(dto.UnlockAccountReq{Token: "token"})
+				req := httptest.NewRequest("POST", "/auth/unlock", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("when token is missing", func() {
+			It("should return bad request", func() {
+				req := httptest.NewRequest("POST", "/auth/unlock", bytes.NewBufferString("{}"))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("ForgotPassword", func() {
+		BeforeEach(func() {
+			router.POST("/auth/forgot", handler.ForgotPassword)
+		})
+
+		Context("when email is valid", func() {
+			It("should return accepted", func() {
+				mockService.EXPECT().ForgotPassword(gomock.Any(), "test@example.com").Return(nil)
+
+				jsonData, _ := json.Marshal(dto.ForgotPasswordReq{Email: "test@example.com"})
+				req := httptest.NewRequest("POST", "/auth/forgot", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusAccepted))
+			})
+		})
+
+		Context("when email is malformed", func() {
+			It("should return bad request", func() {
+				jsonData, _ := json.Marshal(dto.ForgotPasswordReq{Email: "not-an-email"})
+				req := httptest.NewRequest("POST", "/auth/forgot", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("ResetPassword", func() {
+		BeforeEach(func() {
+			router.POST("/auth/reset", handler.ResetPassword)
+		})
+
+		Context("when reset is successful", func() {
+			It("should return no content", func() {
+				mockService.EXPECT().ResetPassword(gomock.Any(), "token", "new_password").Return(nil)
+
+				jsonData, _ := json.Marshal(dto.ResetPasswordReq{Token: "token", Password: "new_password"})
+				req := httptest.NewRequest("POST", "/auth/reset", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("when password is missing", func() {
+			It("should return bad request", func() {
+				jsonData, _ := json.Marshal(dto.ResetPasswordReq{Token: "token"})
+				req := httptest.NewRequest("POST", "/auth/reset", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("Profile", func() {
+		BeforeEach(func() {
+			router.Use(func(c *gin.Context) {
+				c.Set(helpers.UserIDKey, uint(1))
+				c.Next()
+			})
+			router.GET("/me", handler.GetMe)
+			router.PUT("/me/password", handler.ChangePassword)
+			router.DELETE("/me", handler.DeleteMe)
+		})
+
+		Context("when profile is requested", func() {
+			It("should return current user", func() {
+				mockService.EXPECT().GetUserByID(gomock.Any(), uint(1)).
+					Return(entity.User{ID: 1, Name: "Test User", Email: "test@example.com", Role: entity.RoleUser}, nil)
+
+				req := httptest.NewRequest("GET", "/me", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var response dto.UserProfileResp
+				Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+				Expect(response.Email).To(Equal("test@example.com"))
+				Expect(response.EmailVerified).To(BeFalse())
+			})
+		})
+
+		Context("when current password is wrong", func() {
+			It("should return unauthorized", func() {
+				mockService.EXPECT().ChangePassword(gomock.Any(), uint(1), "wrong", "new_password").
+					Return(apperror.ErrIncorrectPassword)
+
+				jsonData, _ := json.Marshal(dto.ChangePasswordReq{CurrentPassword: "wrong", NewPassword: "new_password"})
+				req := httptest.NewRequest("PUT", "/me/password", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		Context("when account owner still has shops", func() {
+			It("should return conflict", func() {
+				mockService.EXPECT().DeleteAccount(gomock.Any(), uint(1), "password").
+					Return(apperror.New(apperror.Conflict, "user owns shops", nil))
+
+				jsonData, _ := json.Marshal(dto.DeleteAccountReq{Password: "password"})
+				req := httptest.NewRequest("DELETE", "/me", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusConflict))
+			})
+		})
+	})
+
+	Describe("Audit", func() {
+		var (
+			auditService *recordingAuditService
+			audit        *middleware.AuditMiddleware
+		)
+
+		BeforeEach(func() {
+			auditService = &recordingAuditService{}
+			audit = middleware.NewAuditMiddleware(
+				&config.AuditConfig{WorkerPoolSize: 1, QueueSize: 10, BatchSize: 10}, auditService, zap.NewNop())
+			router.Use(audit.Middleware(), func(c *gin.Context) {
+				c.Set(helpers.UserIDKey, uint(1))
+				c.Next()
+			})
+			router.PUT("/me/password", handler.ChangePassword)
+		})
+
+		Context("when password is changed", func() {
+			It("should not store either password in the audit log", func() {
+				mockService.EXPECT().ChangePassword(gomock.Any(), uint(1), "old_secret_pw", "new_secret_pw").Return(nil)
+
+				jsonData, _ := json.Marshal(dto.ChangePasswordReq{CurrentPassword: "old_secret_pw", NewPassword: "new_secret_pw"})
+				req := httptest.NewRequest("PUT", "/me/password", bytes.NewBuffer(jsonData))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				audit.Close()
+
+				Expect(w.Code).To(BeNumerically("<", http.StatusBadRequest))
+				entries := auditService.Entries()
+				Expect(entries).To(HaveLen(1))
+				Expect(entries[0].ReqBody).To(HaveKeyWithValue("current_password", "[REDACTED]"))
+				Expect(entries[0].ReqBody).To(HaveKeyWithValue("new_password", "[REDACTED]"))
+			})
+		})
+	})
+
+	Describe("Sessions", func() {
+		BeforeEach(func() {
+			router.Use(func(c *gin.Context) {
+				c.Set(helpers.UserIDKey, uint(1))
+				c.Next()
+			})
+			router.GET("/auth/sessions", handler.GetSessions)
+			router.DELETE("/auth/sessions/:uuid", handler.DeleteSession)
+		})
+
+		Context("when sessions are listed with refresh cookie", func() {
+			It("should mark the current session", func() {
+				current, other := uuid.New(), uuid.New()
+				mockService.EXPECT().ListSessions(gomock.Any(), uint(1)).
+					Return([]entity.RefreshToken{
+						{UUID: current, Fingerprint: "fp123"},
+						{UUID: other, Fingerprint: "fp456"},
+					}, nil)
+
+				req := httptest.NewRequest("GET", "/auth/sessions", nil)
+				req.AddCookie(&http.Cookie{Name: "refresh_token", Value: current.String()})
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+
+				var response []dto.SessionResp
+				Expect(json.Unmarshal(w.Body.Bytes(), &response)).To(Succeed())
+				Expect(response).To(HaveLen(2))
+				Expect(response[0].Current).To(BeTrue())
+				Expect(response[1].Current).To(BeFalse())
+				Expect(response[0].Device).ToNot(Equal(response[1].Device))
+			})
+		})
+
+		Context("when session id is not a uuid", func() {
+			It("should return bad request", func() {
+				req := httptest.NewRequest("DELETE", "/auth/sessions/abc", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("Ban", func() {
+		BeforeEach(func() {
+			router.POST("/admin/users/:id/ban", handler.BanUser)
+			router.DELETE("/admin/users/:id/ban", handler.UnbanUser)
+		})
+
+		Context("when user is banned", func() {
+			It("should return no content", func() {
+				mockService.EXPECT().BanUser(gomock.Any(), uint(2)).Return(nil)
+
+				req := httptest.NewRequest("POST", "/admin/users/2/ban", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("when ban is lifted for unknown user", func() {
+			It("should return not found", func() {
+				mockService.EXPECT().UnbanUser(gomock.Any(), uint(2)).Return(apperror.ErrUserNotFound)
+
+				req := httptest.NewRequest("DELETE", "/admin/users/2/ban", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
 })
 
+// recordingAuditService запоминает записи аудита, которые middleware отправил на сохранение
+type recordingAuditService struct {
+	mu      sync.Mutex
+	entries []entity.AuditEntry
+}
+
+func (s *recordingAuditService) Log(entries []entity.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func (s *recordingAuditService) Entries() []entity.AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]entity.AuditEntry(nil), s.entries...)
+}
+
 func TestUserHandler(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "UserHandler Suite")
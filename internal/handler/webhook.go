@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookService interface {
+	CreateEndpoint(
+		ctx context.Context,
+		shopID, actorID uint,
+		url string,
+		events []entity.WebhookEvent,
+	) (entity.WebhookEndpoint, error)
+	GetEndpoints(ctx context.Context, shopID, actorID uint) ([]entity.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, shopID, actorID, endpointID uint) error
+	GetDeliveries(
+		ctx context.Context,
+		shopID, actorID, endpointID uint,
+		page, limit int,
+	) ([]entity.WebhookDelivery, int, error)
+	GetDelivery(
+		ctx context.Context,
+		shopID, actorID, endpointID, deliveryID uint,
+	) (entity.WebhookDelivery, []entity.WebhookAttempt, error)
+	Redeliver(ctx context.Context, shopID, actorID, endpointID, deliveryID uint) error
+}
+
+type WebhookHandler struct {
+	webhookService WebhookService
+}
+
+func NewWebhookHandler(webhookService WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// GetWebhooks godoc
+// @Summary      Получить вебхуки магазина
+// @Description  Список доступен владельцу и менеджерам магазина, секреты подписи не возвращаются
+// @Tags         shops
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID магазина"
+// @Success      200  {array}   dto.WebhookResp
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /shops/{id}/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	actorID, shopID, ok := webhookActor(c)
+	if !ok {
+		return
+	}
+
+	endpoints, err := h.webhookService.GetEndpoints(c.Request.Context(), shopID, actorID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormWebhooks(endpoints))
+}
+
+// PostWebhook godoc
+// @Summary      Зарегистрировать вебхук магазина
+// @Description  На адрес отправляются POST запросы с JSON телом события. Заголовок X-Stawberry-Signature
+// @Description  имеет вид t=<unix время>,v1=<hex HMAC-SHA256 от "<unix время>.<тело>" на секрете вебхука>.
+// @Description  Секрет показывается только в этом ответе. Доступные события: offer.created, offer.accepted,
+// @Description  offer.declined, offer.cancelled, offer.expired.
+// @Tags         shops
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                 true  "ID магазина"
+// @Param        body  body      dto.PostWebhookReq  true  "Адрес и события"
+// @Success      201   {object}  dto.PostWebhookResp
+// @Failure      400   {object}  apperror.Error
+// @Failure      401   {object}  apperror.Error
+// @Failure      403   {object}  apperror.Error
+// @Failure      500   {object}  apperror.Error
+// @Router       /shops/{id}/webhooks [post]
+func (h *WebhookHandler) PostWebhook(c *gin.Context) {
+	actorID, shopID, ok := webhookActor(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PostWebhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid webhook data", err))
+		return
+	}
+
+	endpoint, err := h.webhookService.CreateEndpoint(c.Request.Context(), shopID, actorID,
+		req.URL, req.ConvertEvents())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.PostWebhookResp{WebhookResp: dto.FormWebhook(endpoint), Secret: endpoint.Secret})
+}
+
+// DeleteWebhook godoc
+// @Summary      Удалить вебхук магазина
+// @Description  Неотправленные доставки на этот адрес отменяются, журнал доставок удаляется
+// @Tags         shops
+// @Security     BearerAuth
+// @Param        id         path  int  true  "ID магазина"
+// @Param        webhookID  path  int  true  "ID вебхука"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /shops/{id}/webhooks/{webhookID} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	actorID, shopID, ok := webhookActor(c)
+	if !ok {
+		return
+	}
+
+	webhookID, err := parsePathID(c, "webhookID")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.webhookService.DeleteEndpoint(c.Request.Context(), shopID, actorID, webhookID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary      Получить журнал доставок вебхука
+// @Description  Доставки со статусами pending, delivered и dead, новые первыми
+// @Tags         shops
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      int  true   "ID магазина"
+// @Param        webhookID  path      int  true   "ID вебхука"
+// @Param        page       query     int  false  "Номер страницы"  default(1)
+// @Param        limit      query     int  false  "Размер страницы (1-100)"  default(20)
+// @Success      200        {object}  dto.WebhookDeliveriesResp
+// @Failure      400        {object}  apperror.Error
+// @Failure      401        {object}  apperror.Error
+// @Failure      403        {object}  apperror.Error
+// @Failure      404        {object}  apperror.Error
+// @Failure      500        {object}  apperror.Error
+// @Router       /shops/{id}/webhooks/{webhookID}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	actorID, shopID, ok := webhookActor(c)
+	if !ok {
+		return
+	}
+
+	webhookID, err := parsePathID(c, "webhookID")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid page number", err))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid limit value (must be 1-100)", err))
+		return
+	}
+
+	deliveries, total, err := h.webhookService.GetDeliveries(c.Request.Context(), shopID, actorID,
+		webhookID, page, limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormWebhookDeliveries(deliveries, page, limit, total))
+}
+
+// GetWebhookDelivery godoc
+// @Summary      Получить доставку вебхука
+// @Description  Тело события и журнал всех попыток доставки с кодами и ответами получателя
+// @Tags         shops
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path      int  true  "ID магазина"
+// @Param        webhookID   path      int  true  "ID вебхука"
+// @Param        deliveryID  path      int  true  "ID доставки"
+// @Success      200         {object}  dto.WebhookDeliveryDetailsResp
+// @Failure      400         {object}  apperror.Error
+// @Failure      401         {object}  apperror.Error
+// @Failure      403         {object}  apperror.Error
+// @Failure      404         {object}  apperror.Error
+// @Failure      500         {object}  apperror.Error
+// @Router       /shops/{id}/webhooks/{webhookID}/deliveries/{deliveryID} [get]
+func (h *WebhookHandler) GetWebhookDelivery(c *gin.Context) {
+	actorID, shopID, ok := webhookActor(c)
+	if !ok {
+		return
+	}
+
+	webhookID, deliveryID, err := parseDeliveryPath(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	delivery, attempts, err := h.webhookService.GetDelivery(c.Request.Context(), shopID, actorID,
+		webhookID, deliveryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormWebhookDeliveryDetails(delivery, attempts))
+}
+
+// PostWebhookRedelivery godoc
+// @Summary      Повторить доставку вебхука
+// @Description  Возвращает доставленную или исчерпавшую попытки доставку в очередь с тем же ID события
+// @Tags         shops
+// @Security     BearerAuth
+// @Param        id          path  int  true  "ID магазина"
+// @Param        webhookID   path  int  true  "ID вебхука"
+// @Param        deliveryID  path  int  true  "ID доставки"
+// @Success      202
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      403  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      409  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /shops/{id}/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver [post]
+func (h *WebhookHandler) PostWebhookRedelivery(c *gin.Context) {
+	actorID, shopID, ok := webhookActor(c)
+	if !ok {
+		return
+	}
+
+	webhookID, deliveryID, err := parseDeliveryPath(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err = h.webhookService.Redeliver(c.Request.Context(), shopID, actorID, webhookID, deliveryID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// webhookActor достает пользователя и магазин из запроса, при ошибке она уже записана в контекст
+func webhookActor(c *gin.Context) (uint, uint, bool) {
+	actorID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return 0, 0, false
+	}
+
+	shopID, err := parseShopID(c)
+	if err != nil {
+		_ = c.Error(err)
+		return 0, 0, false
+	}
+
+	return actorID, shopID, true
+}
+
+func parseDeliveryPath(c *gin.Context) (uint, uint, error) {
+	webhookID, err := parsePathID(c, "webhookID")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	deliveryID, err := parsePathID(c, "deliveryID")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return webhookID, deliveryID, nil
+}
+
+func parsePathID(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil || id == 0 {
+		return 0, apperror.New(apperror.BadRequest, name+" must be a positive number", err)
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/dto"
+	"github.com/EM-Stawberry/Stawberry/internal/handler/helpers"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	"github.com/gin-gonic/gin"
+)
+
+type WishlistService interface {
+	GetWishlist(ctx context.Context, userID uint) ([]entity.WishlistItem, error)
+	SaveWishlistItem(ctx context.Context, userID uint, productID int, targetPrice *int) error
+	RemoveWishlistItem(ctx context.Context, userID uint, productID int) error
+	GetSavedSearches(ctx context.Context, userID uint) ([]entity.SavedSearch, error)
+	CreateSavedSearch(ctx context.Context, userID uint, name string, filter model.ProductFilter) (uint, error)
+	DeleteSavedSearch(ctx context.Context, userID, searchID uint) error
+}
+
+type WishlistHandler struct {
+	wishlistService WishlistService
+}
+
+func NewWishlistHandler(wishlistService WishlistService) *WishlistHandler {
+	return &WishlistHandler{wishlistService: wishlistService}
+}
+
+// GetWishlist godoc
+// @Summary      Получить список желаемого
+// @Description  Возвращает продукты из списка желаемого с целевой и текущей минимальной ценой (в копейках)
+// @Tags         wishlist
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   dto.WishlistItemResp
+// @Failure      401  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /me/wishlist [get]
+func (h *WishlistHandler) GetWishlist(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	items, err := h.wishlistService.GetWishlist(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormWishlist(items))
+}
+
+// PutWishlistItem godoc
+// @Summary      Добавить продукт в список желаемого
+// @Description  Добавляет продукт или меняет целевую цену. Когда минимальная цена опустится до целевой,
+// @Description  придет уведомление и письмо. Тело запроса необязательно.
+// @Tags         wishlist
+// @Accept       json
+// @Security     BearerAuth
+// @Param        productID  path  int                     true   "ID продукта"
+// @Param        body       body  dto.PutWishlistItemReq  false  "Целевая цена в копейках"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /me/wishlist/{productID} [put]
+func (h *WishlistHandler) PutWishlistItem(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Param("productID"))
+	if err != nil || productID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "product id must be a positive number", err))
+		return
+	}
+
+	var req dto.PutWishlistItemReq
+	if err = c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid wishlist item data", err))
+		return
+	}
+
+	if err = h.wishlistService.SaveWishlistItem(c.Request.Context(), userID, productID, req.TargetPrice); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteWishlistItem godoc
+// @Summary      Убрать продукт из списка желаемого
+// @Tags         wishlist
+// @Security     BearerAuth
+// @Param        productID  path  int  true  "ID продукта"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /me/wishlist/{productID} [delete]
+func (h *WishlistHandler) DeleteWishlistItem(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Param("productID"))
+	if err != nil || productID < 1 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "product id must be a positive number", err))
+		return
+	}
+
+	if err = h.wishlistService.RemoveWishlistItem(c.Request.Context(), userID, productID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSavedSearches godoc
+// @Summary      Получить сохраненные поиски
+// @Tags         wishlist
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {array}   dto.SavedSearchResp
+// @Failure      401  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /me/saved-searches [get]
+func (h *WishlistHandler) GetSavedSearches(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	searches, err := h.wishlistService.GetSavedSearches(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FormSavedSearches(searches))
+}
+
+// PostSavedSearch godoc
+// @Summary      Сохранить поиск
+// @Description  Сохраняет фильтр каталога. О новых продуктах, подходящих под фильтр, придут уведомление и письмо.
+// @Tags         wishlist
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.PostSavedSearchReq  true  "Название и фильтр поиска"
+// @Success      201   {object}  dto.PostSavedSearchResp
+// @Failure      400   {object}  apperror.Error
+// @Failure      401   {object}  apperror.Error
+// @Failure      500   {object}  apperror.Error
+// @Router       /me/saved-searches [post]
+func (h *WishlistHandler) PostSavedSearch(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	var req dto.PostSavedSearchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(apperror.New(apperror.BadRequest, "invalid saved search data", err))
+		return
+	}
+
+	id, err := h.wishlistService.CreateSavedSearch(c.Request.Context(), userID, req.Name, req.Filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.PostSavedSearchResp{ID: id})
+}
+
+// DeleteSavedSearch godoc
+// @Summary      Удалить сохраненный поиск
+// @Tags         wishlist
+// @Security     BearerAuth
+// @Param        id   path  int  true  "ID поиска"
+// @Success      204
+// @Failure      400  {object}  apperror.Error
+// @Failure      401  {object}  apperror.Error
+// @Failure      404  {object}  apperror.Error
+// @Failure      500  {object}  apperror.Error
+// @Router       /me/saved-searches/{id} [delete]
+func (h *WishlistHandler) DeleteSavedSearch(c *gin.Context) {
+	userID, ok := helpers.UserIDContext(c)
+	if !ok {
+		_ = c.Error(apperror.New(apperror.Unauthorized, "invalid credentials", nil))
+		return
+	}
+
+	searchID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || searchID == 0 {
+		_ = c.Error(apperror.New(apperror.BadRequest, "search id must be a positive number", err))
+		return
+	}
+
+	if err = h.wishlistService.DeleteSavedSearch(c.Request.Context(), userID, uint(searchID)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// apiKeyTouchInterval ограничивает запись last_used_at, чтобы каждый запрос интеграции не обновлял строку
+const apiKeyTouchInterval = time.Minute
+
+var apiKeyColumns = []string{
+	"id",
+	"shop_id",
+	"created_by",
+	"name",
+	"prefix",
+	"secret_hash",
+	"scopes",
+	"expires_at",
+	"last_used_at",
+	"revoked_at",
+	"created_at",
+}
+
+type APIKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) InsertAPIKey(ctx context.Context, key entity.APIKey) (uint, error) {
+	query, args := sq.Insert("shop_api_keys").
+		Columns("shop_id", "created_by", "name", "prefix", "secret_hash", "scopes", "expires_at").
+		Values(key.ShopID, key.CreatedBy, key.Name, key.Prefix, key.SecretHash,
+			model.JoinScopes(key.Scopes), key.ExpiresAt).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var id uint
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to insert api key", err)
+	}
+
+	return id, nil
+}
+
+// SelectAPIKeys возвращает ключи магазина, включая отозванные, новые первыми
+func (r *APIKeyRepository) SelectAPIKeys(ctx context.Context, shopID uint) ([]entity.APIKey, error) {
+	query, args := sq.Select(apiKeyColumns...).
+		From("shop_api_keys").
+		Where(sq.Eq{"shop_id": shopID}).
+		OrderBy("created_at DESC", "id DESC").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var rows []model.APIKey
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch api keys", err)
+	}
+
+	keys := make([]entity.APIKey, len(rows))
+	for i, row := range rows {
+		keys[i] = model.ConvertAPIKeyToEntity(row)
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (entity.APIKey, error) {
+	query, args := sq.Select(apiKeyColumns...).
+		From("shop_api_keys").
+		Where(sq.Eq{"prefix": prefix}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var row model.APIKey
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.APIKey{}, apperror.ErrAPIKeyNotFound
+		}
+		return entity.APIKey{}, apperror.New(apperror.DatabaseError, "failed to fetch api key", err)
+	}
+
+	return model.ConvertAPIKeyToEntity(row), nil
+}
+
+// RevokeAPIKey отзывает ключ магазина. Уже отозванный ключ считается ненайденным.
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, shopID, keyID uint) error {
+	query, args := sq.Update("shop_api_keys").
+		Set("revoked_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": keyID, "shop_id": shopID, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to revoke api key", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to revoke api key", err)
+	}
+	if affected == 0 {
+		return apperror.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// TouchAPIKey отмечает использование ключа не чаще раза в apiKeyTouchInterval
+func (r *APIKeyRepository) TouchAPIKey(ctx context.Context, keyID uint) error {
+	query, args := sq.Update("shop_api_keys").
+		Set("last_used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": keyID}).
+		Where(sq.Or{
+			sq.Eq{"last_used_at": nil},
+			sq.Lt{"last_used_at": time.Now().Add(-apiKeyTouchInterval)},
+		}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update api key usage", err)
+	}
+
+	return nil
+}
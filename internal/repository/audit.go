@@ -28,6 +28,7 @@ func (ar *AuditRepository) LogStore(ae []entity.AuditEntry) error {
 		"resp_status",
 		"user_ip",
 		"user_id",
+		"api_key_id",
 		"user_role",
 		"received_at",
 		"req_body",
@@ -41,6 +42,7 @@ func (ar *AuditRepository) LogStore(ae []entity.AuditEntry) error {
 			m.RespStatus,
 			m.IP,
 			m.UserID,
+			m.APIKeyID,
 			m.UserRole,
 			m.ReceivedAt,
 			m.ReqBody,
@@ -78,6 +80,7 @@ func (ar *AuditRepository) GetLogs(
 		"resp_status",
 		"user_ip",
 		"user_id",
+		"api_key_id",
 		"user_role",
 		"received_at",
 		"req_body",
@@ -0,0 +1,360 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+// categoryColumns колонки категории c с глубиной и количеством продуктов,
+// посчитанными по nested set
+var categoryColumns = []string{
+	"c.id",
+	"c.name",
+	"c.parent_id",
+	"c.lft",
+	"c.rgt",
+	"(SELECT COUNT(*) FROM categories a WHERE a.lft < c.lft AND a.rgt > c.rgt) AS depth",
+	"(SELECT COUNT(*) FROM products p WHERE p.category_id = c.id) AS product_count",
+	"(SELECT COUNT(*) FROM products p JOIN categories d ON d.id = p.category_id " +
+		"WHERE d.lft BETWEEN c.lft AND c.rgt) AS total_product_count",
+}
+
+type CategoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewCategoryRepository(db *sqlx.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// GetCategories возвращает все категории, упорядоченные по lft
+func (r *CategoryRepository) GetCategories(ctx context.Context) ([]entity.Category, error) {
+	query, args := sq.Select(categoryColumns...).
+		From("categories c").
+		OrderBy("c.lft").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var categories []model.Category
+	if err := r.db.SelectContext(ctx, &categories, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch categories", err)
+	}
+
+	return model.ConvertCategoriesToEntity(categories), nil
+}
+
+// GetSubtree возвращает категорию и всех её потомков, упорядоченных по lft
+func (r *CategoryRepository) GetSubtree(ctx context.Context, id uint) ([]entity.Category, error) {
+	query, args := sq.Select(categoryColumns...).
+		From("categories c").
+		Join("categories root ON c.lft BETWEEN root.lft AND root.rgt").
+		Where(sq.Eq{"root.id": id}).
+		OrderBy("c.lft").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var categories []model.Category
+	if err := r.db.SelectContext(ctx, &categories, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch category subtree", err)
+	}
+
+	if len(categories) == 0 {
+		return nil, apperror.ErrCategoryNotFound
+	}
+
+	return model.ConvertCategoriesToEntity(categories), nil
+}
+
+// GetBreadcrumbs возвращает путь от корня дерева до категории включительно
+func (r *CategoryRepository) GetBreadcrumbs(ctx context.Context, id uint) ([]entity.Category, error) {
+	query, args := sq.Select(categoryColumns...).
+		From("categories node").
+		Join("categories c ON c.lft <= node.lft AND c.rgt >= node.rgt").
+		Where(sq.Eq{"node.id": id}).
+		OrderBy("c.lft").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var categories []model.Category
+	if err := r.db.SelectContext(ctx, &categories, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch category breadcrumbs", err)
+	}
+
+	if len(categories) == 0 {
+		return nil, apperror.ErrCategoryNotFound
+	}
+
+	return model.ConvertCategoriesToEntity(categories), nil
+}
+
+// InsertCategory добавляет категорию последним потомком parentID
+// или новым корнем, если parentID не указан
+func (r *CategoryRepository) InsertCategory(
+	ctx context.Context,
+	name string,
+	parentID *uint,
+) (uint, error) {
+	tx, err := r.beginStructureTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	pos, err := insertPosition(ctx, tx, parentID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = shiftCategories(ctx, tx, pos, 2); err != nil {
+		return 0, err
+	}
+
+	query, args := sq.Insert("categories").
+		Columns("name", "lft", "rgt", "parent_id").
+		Values(name, pos, pos+1, parentID).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var id uint
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return 0, apperror.New(apperror.DuplicateError, "category with this name already exists", err)
+		}
+		return 0, apperror.New(apperror.DatabaseError, "failed to insert category", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return id, nil
+}
+
+// MoveCategory переносит категорию вместе с поддеревом последним потомком parentID
+// или в корень, если parentID не указан
+func (r *CategoryRepository) MoveCategory(
+	ctx context.Context,
+	id uint,
+	parentID *uint,
+) error {
+	tx, err := r.beginStructureTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	node, err := getCategoryBounds(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if parentID != nil {
+		parent, err := getCategoryBounds(ctx, tx, *parentID)
+		if err != nil {
+			return err
+		}
+		if parent.Lft >= node.Lft && parent.Lft <= node.Rgt {
+			return apperror.New(apperror.BadRequest, "category cannot be moved into its own subtree", nil)
+		}
+	}
+
+	width := node.Rgt - node.Lft + 1
+
+	// Поддерево временно выводится из нумерации отрицательными значениями,
+	// чтобы сдвиги остальных узлов его не задевали
+	query, args := sq.Update("categories").
+		Set("lft", sq.Expr("-lft")).
+		Set("rgt", sq.Expr("-rgt")).
+		Where(sq.GtOrEq{"lft": node.Lft}).
+		Where(sq.LtOrEq{"rgt": node.Rgt}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to detach category subtree", err)
+	}
+
+	if err = shiftCategories(ctx, tx, node.Rgt+1, -width); err != nil {
+		return err
+	}
+
+	pos, err := insertPosition(ctx, tx, parentID)
+	if err != nil {
+		return err
+	}
+
+	if err = shiftCategories(ctx, tx, pos, width); err != nil {
+		return err
+	}
+
+	offset := pos - node.Lft
+	query, args = sq.Update("categories").
+		Set("lft", sq.Expr("-lft + ?", offset)).
+		Set("rgt", sq.Expr("-rgt + ?", offset)).
+		Where(sq.Lt{"lft": 0}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to attach category subtree", err)
+	}
+
+	query, args = sq.Update("categories").
+		Set("parent_id", parentID).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update category parent", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// DeleteCategory удаляет категорию вместе с поддеревом, если к нему не привязаны продукты
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, id uint) error {
+	tx, err := r.beginStructureTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	node, err := getCategoryBounds(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	query, args := sq.Select("COUNT(*)").
+		From("products p").
+		Join("categories d ON d.id = p.category_id").
+		Where("d.lft BETWEEN ? AND ?", node.Lft, node.Rgt).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var products int
+	if err = tx.GetContext(ctx, &products, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to count category products", err)
+	}
+	if products > 0 {
+		return apperror.New(apperror.Conflict, "category or its subcategories still contain products", nil)
+	}
+
+	query, args = sq.Delete("categories").
+		Where("lft BETWEEN ? AND ?", node.Lft, node.Rgt).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to delete category subtree", err)
+	}
+
+	if err = shiftCategories(ctx, tx, node.Rgt+1, -(node.Rgt - node.Lft + 1)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// beginStructureTx открывает транзакцию и блокирует таблицу категорий от параллельных
+// изменений структуры, иначе конкурентные сдвиги lft/rgt могут испортить дерево
+func (r *CategoryRepository) beginStructureTx(ctx context.Context) (*sqlx.Tx, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		_ = tx.Rollback()
+		return nil, apperror.New(apperror.DatabaseError, "failed to lock categories table", err)
+	}
+
+	return tx, nil
+}
+
+func getCategoryBounds(ctx context.Context, tx *sqlx.Tx, id uint) (model.Category, error) {
+	query, args := sq.Select("id", "name", "parent_id", "lft", "rgt").
+		From("categories").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var category model.Category
+	if err := tx.GetContext(ctx, &category, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Category{}, apperror.ErrCategoryNotFound
+		}
+		return model.Category{}, apperror.New(apperror.DatabaseError, "failed to fetch category", err)
+	}
+
+	return category, nil
+}
+
+// insertPosition возвращает lft для нового последнего потомка parentID,
+// либо позицию после последнего корня
+func insertPosition(ctx context.Context, tx *sqlx.Tx, parentID *uint) (int, error) {
+	if parentID != nil {
+		parent, err := getCategoryBounds(ctx, tx, *parentID)
+		if err != nil {
+			return 0, err
+		}
+		return parent.Rgt, nil
+	}
+
+	query, args := sq.Select("COALESCE(MAX(rgt), 0) + 1").
+		From("categories").
+		Where(sq.Gt{"rgt": 0}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var pos int
+	if err := tx.GetContext(ctx, &pos, query, args...); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to calculate category position", err)
+	}
+
+	return pos, nil
+}
+
+// shiftCategories сдвигает на delta все границы, которые больше или равны from
+func shiftCategories(ctx context.Context, tx *sqlx.Tx, from, delta int) error {
+	query, args := sq.Update("categories").
+		Set("rgt", sq.Expr("rgt + ?", delta)).
+		Where(sq.GtOrEq{"rgt": from}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to shift categories", err)
+	}
+
+	query, args = sq.Update("categories").
+		Set("lft", sq.Expr("lft + ?", delta)).
+		Where(sq.GtOrEq{"lft": from}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to shift categories", err)
+	}
+
+	return nil
+}
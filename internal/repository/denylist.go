@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// DenylistRepository хранит отозванные access токены в Postgres,
+// поэтому отзыв виден всем экземплярам приложения
+type DenylistRepository struct {
+	db *sqlx.DB
+}
+
+func NewDenylistRepository(db *sqlx.DB) *DenylistRepository {
+	return &DenylistRepository{db: db}
+}
+
+// Add добавляет токены в список. Заодно удаляются записи об уже истекших токенах.
+func (r *DenylistRepository) Add(ctx context.Context, tokens ...entity.RevokedAccessToken) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args := sq.Delete("revoked_access_tokens").
+		Where("expires_at <= NOW()").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to clean revoked access tokens", err)
+	}
+
+	stmt := sq.Insert("revoked_access_tokens").
+		Columns("jti", "expires_at")
+	for _, t := range tokens {
+		stmt = stmt.Values(t.JTI, t.ExpiresAt)
+	}
+
+	query, args = stmt.
+		Suffix("ON CONFLICT (jti) DO UPDATE " +
+			"SET expires_at = GREATEST(revoked_access_tokens.expires_at, EXCLUDED.expires_at)").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to revoke access tokens", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// Contains проверяет, отозван ли токен с указанным jti
+func (r *DenylistRepository) Contains(ctx context.Context, jti string) (bool, error) {
+	query, args := sq.Select("1").
+		Prefix("SELECT EXISTS (").
+		From("revoked_access_tokens").
+		Where(sq.Eq{"jti": jti}).
+		Where("expires_at > NOW()").
+		Suffix(")").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var revoked bool
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&revoked); err != nil {
+		return false, apperror.New(apperror.DatabaseError, "failed to check access token", err)
+	}
+
+	return revoked, nil
+}
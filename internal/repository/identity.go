@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+// TouchIdentity отмечает вход через внешнюю учетную запись и возвращает ID связанного пользователя
+func (r *UserRepository) TouchIdentity(ctx context.Context, identity entity.ExternalIdentity) (uint, error) {
+	query, args := sq.Update("identities").
+		Set("last_login_at", sq.Expr("NOW()")).
+		Set("email", identity.Email).
+		Where(sq.Eq{"provider": identity.Provider, "subject": identity.Subject}).
+		Suffix("RETURNING user_id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var userID uint
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, apperror.ErrUserNotFound
+		}
+		return 0, apperror.New(apperror.DatabaseError, "failed to fetch identity", err)
+	}
+
+	return userID, nil
+}
+
+// LinkIdentity связывает внешнюю учетную запись с существующим пользователем
+func (r *UserRepository) LinkIdentity(ctx context.Context, userID uint, identity entity.ExternalIdentity) error {
+	return insertIdentity(ctx, r.db, userID, identity)
+}
+
+// InsertExternalUser создает пользователя по внешней учетной записи. Пароля у него нет,
+// почта считается подтвержденной провайдером.
+func (r *UserRepository) InsertExternalUser(ctx context.Context, identity entity.ExternalIdentity) (uint, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args := sq.Insert("users").
+		Columns("name", "email", "phone_number", "password_hash", "email_verified_at").
+		Values(identity.Name, identity.Email, "", "", sq.Expr("NOW()")).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var userID uint
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&userID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return 0, apperror.New(apperror.DuplicateError, "user with this email already exists", err)
+		}
+		return 0, apperror.New(apperror.DatabaseError, "failed to create user", err)
+	}
+
+	if err = insertIdentity(ctx, tx, userID, identity); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return userID, nil
+}
+
+func insertIdentity(ctx context.Context, db sqlx.ExecerContext, userID uint, identity entity.ExternalIdentity) error {
+	query, args := sq.Insert("identities").
+		Columns("user_id", "provider", "subject", "email").
+		Values(userID, identity.Provider, identity.Subject, identity.Email).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return apperror.New(apperror.Conflict, "external account is already linked", err)
+		}
+		return apperror.New(apperror.DatabaseError, "failed to link external account", err)
+	}
+
+	return nil
+}
+
+// OIDCStateRepository хранит начатые входы через внешних провайдеров
+type OIDCStateRepository struct {
+	db *sqlx.DB
+}
+
+func NewOIDCStateRepository(db *sqlx.DB) *OIDCStateRepository {
+	return &OIDCStateRepository{db: db}
+}
+
+// InsertState сохраняет начатый вход. Заодно удаляются просроченные.
+func (r *OIDCStateRepository) InsertState(ctx context.Context, state entity.OIDCLoginState) error {
+	query, args := sq.Delete("oidc_login_states").
+		Where("expires_at <= NOW()").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to clean login states", err)
+	}
+
+	query, args = sq.Insert("oidc_login_states").
+		Columns("state_hash", "provider", "nonce", "code_verifier", "fingerprint", "expires_at").
+		Values(state.StateHash, state.Provider, state.Nonce, state.CodeVerifier, state.Fingerprint, state.ExpiresAt).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save login state", err)
+	}
+
+	return nil
+}
+
+// UseState гасит начатый вход и возвращает его. Просроченный или уже использованный state не находится.
+func (r *OIDCStateRepository) UseState(ctx context.Context, stateHash, provider string) (entity.OIDCLoginState, error) {
+	query, args := sq.Delete("oidc_login_states").
+		Where(sq.Eq{"state_hash": stateHash, "provider": provider}).
+		Where("expires_at > NOW()").
+		Suffix("RETURNING state_hash, provider, nonce, code_verifier, fingerprint, expires_at").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var state entity.OIDCLoginState
+	err := r.db.QueryRowxContext(ctx, query, args...).Scan(
+		&state.StateHash,
+		&state.Provider,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.Fingerprint,
+		&state.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.OIDCLoginState{}, apperror.ErrInvalidLoginState
+		}
+		return entity.OIDCLoginState{}, apperror.New(apperror.DatabaseError, "failed to use login state", err)
+	}
+
+	return state, nil
+}
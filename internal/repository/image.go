@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+var imageColumns = []string{"image_key", "thumbnail_key", "product_id", "content_type", "size", "created_at"}
+
+type ImageRepository struct {
+	db *sqlx.DB
+}
+
+func NewImageRepository(db *sqlx.DB) *ImageRepository {
+	return &ImageRepository{db: db}
+}
+
+// InsertImage сохраняет ключи загруженного изображения продукта
+func (r *ImageRepository) InsertImage(ctx context.Context, img entity.ProductImage) error {
+	query, args := sq.Insert("image_keys").
+		Columns("image_key", "thumbnail_key", "product_id", "content_type", "size", "created_at").
+		Values(img.Key, img.ThumbnailKey, img.ProductID, img.ContentType, img.Size, img.CreatedAt).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case pgerrcode.ForeignKeyViolation:
+				return apperror.ErrProductNotFound
+			case pgerrcode.UniqueViolation:
+				return apperror.New(apperror.DuplicateError, "image with this key already exists", err)
+			}
+		}
+		return apperror.New(apperror.DatabaseError, "failed to insert image", err)
+	}
+
+	return nil
+}
+
+// SelectImagesByProductIDs возвращает изображения продуктов в порядке загрузки
+func (r *ImageRepository) SelectImagesByProductIDs(
+	ctx context.Context,
+	productIDs []int,
+) ([]entity.ProductImage, error) {
+	query, args := sq.Select(imageColumns...).
+		From("image_keys").
+		Where(sq.Eq{"product_id": productIDs}).
+		OrderBy("product_id", "created_at").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var images []model.ProductImage
+	if err := r.db.SelectContext(ctx, &images, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch product images", err)
+	}
+
+	result := make([]entity.ProductImage, len(images))
+	for i, img := range images {
+		result[i] = model.ConvertProductImageToEntity(img)
+	}
+
+	return result, nil
+}
+
+// GetImage находит изображение продукта по ключу
+func (r *ImageRepository) GetImage(ctx context.Context, productID int, key string) (entity.ProductImage, error) {
+	query, args := sq.Select(imageColumns...).
+		From("image_keys").
+		Where(sq.Eq{"product_id": productID, "image_key": key}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var img model.ProductImage
+	if err := r.db.GetContext(ctx, &img, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ProductImage{}, apperror.ErrImageNotFound
+		}
+		return entity.ProductImage{}, apperror.New(apperror.DatabaseError, "failed to fetch product image", err)
+	}
+
+	return model.ConvertProductImageToEntity(img), nil
+}
+
+func (r *ImageRepository) DeleteImage(ctx context.Context, productID int, key string) error {
+	query, args := sq.Delete("image_keys").
+		Where(sq.Eq{"product_id": productID, "image_key": key}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to delete product image", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return apperror.ErrImageNotFound
+	}
+
+	return nil
+}
+
+// IsProductSoldByUser проверяет, что продукт есть в ассортименте одного из магазинов пользователя
+func (r *ImageRepository) IsProductSoldByUser(ctx context.Context, productID int, userID uint) (bool, error) {
+	subquery := sq.Select("1").
+		From("shop_inventory si").
+		Join("shops s ON s.id = si.shop_id").
+		Where(sq.Eq{"si.product_id": productID, "s.user_id": userID})
+
+	query, args := sq.Select().
+		Column(sq.Expr("EXISTS (?)", subquery)).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var sold bool
+	if err := r.db.GetContext(ctx, &sold, query, args...); err != nil {
+		return false, apperror.New(apperror.DatabaseError, "failed to check product ownership", err)
+	}
+
+	return sold, nil
+}
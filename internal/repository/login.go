@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var loginAttemptsColumns = []string{"key", "failures", "last_failed_at", "blocked_until"}
+
+type LoginAttemptRepository struct {
+	db *sqlx.DB
+}
+
+func NewLoginAttemptRepository(db *sqlx.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// Get возвращает счетчики по ключам. Ключей без неудачных попыток в результате нет.
+func (r *LoginAttemptRepository) Get(ctx context.Context, keys []string) ([]entity.LoginAttempts, error) {
+	query, args := sq.Select(loginAttemptsColumns...).
+		From("login_attempts").
+		Where(sq.Eq{"key": keys}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var attempts []model.LoginAttempts
+	if err := r.db.SelectContext(ctx, &attempts, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch login attempts", err)
+	}
+
+	result := make([]entity.LoginAttempts, len(attempts))
+	for i, a := range attempts {
+		result[i] = model.ConvertLoginAttemptsToEntity(a)
+	}
+
+	return result, nil
+}
+
+// RegisterFailure атомарно увеличивает счетчик неудачных попыток.
+// Если с последней неудачи прошло больше window, счет начинается заново.
+func (r *LoginAttemptRepository) RegisterFailure(
+	ctx context.Context,
+	key string,
+	window time.Duration,
+) (entity.LoginAttempts, error) {
+	query, args := sq.Insert("login_attempts").
+		Columns("key", "failures", "last_failed_at").
+		Values(key, 1, sq.Expr("NOW()")).
+		Suffix("ON CONFLICT (key) DO UPDATE SET "+
+			"failures = CASE WHEN login_attempts.last_failed_at < NOW() - ? * INTERVAL '1 second' "+
+			"THEN 1 ELSE login_attempts.failures + 1 END, "+
+			"last_failed_at = NOW()", int64(window.Seconds())).
+		Suffix("RETURNING key, failures, last_failed_at, blocked_until").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var attempts model.LoginAttempts
+	if err := r.db.QueryRowxContext(ctx, query, args...).StructScan(&attempts); err != nil {
+		return entity.LoginAttempts{}, apperror.New(apperror.DatabaseError, "failed to register login failure", err)
+	}
+
+	return model.ConvertLoginAttemptsToEntity(attempts), nil
+}
+
+// Block запрещает попытки входа по ключу до until.
+// При resetFailures счетчик обнуляется, так после блокировки учетной записи отсчет начинается заново.
+func (r *LoginAttemptRepository) Block(ctx context.Context, key string, until time.Time, resetFailures bool) error {
+	stmt := sq.Update("login_attempts").
+		Set("blocked_until", until).
+		Where(sq.Eq{"key": key})
+	if resetFailures {
+		stmt = stmt.Set("failures", 0)
+	}
+
+	query, args := stmt.PlaceholderFormat(sq.Dollar).MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to block login", err)
+	}
+
+	return nil
+}
+
+// Reset снимает ограничения по ключу
+func (r *LoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	query, args := sq.Delete("login_attempts").
+		Where(sq.Eq{"key": key}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to reset login attempts", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// SetTOTPSecret сохраняет секрет TOTP, ожидающий подтверждения. Предыдущий неподтвержденный секрет заменяется.
+func (r *UserRepository) SetTOTPSecret(ctx context.Context, userID uint, secret string) error {
+	query, args := sq.Update("users").
+		Set("totp_secret", secret).
+		Set("totp_last_step", 0).
+		Where(sq.Eq{"id": userID, "deleted_at": nil, "totp_enabled_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save totp secret", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return apperror.ErrMFAAlreadyEnabled
+	}
+
+	return nil
+}
+
+// EnableTOTP включает двухфакторную аутентификацию и сохраняет хэши резервных кодов
+func (r *UserRepository) EnableTOTP(ctx context.Context, userID uint, step int64, codeHashes []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args := sq.Update("users").
+		Set("totp_enabled_at", sq.Expr("NOW()")).
+		Set("totp_last_step", step).
+		Where(sq.Eq{"id": userID, "totp_enabled_at": nil}).
+		Where(sq.NotEq{"totp_secret": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to enable totp", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return apperror.ErrMFAAlreadyEnabled
+	}
+
+	if err = replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// DisableTOTP отключает двухфакторную аутентификацию и удаляет резервные коды
+func (r *UserRepository) DisableTOTP(ctx context.Context, userID uint) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args := sq.Update("users").
+		Set("totp_secret", nil).
+		Set("totp_enabled_at", nil).
+		Set("totp_last_step", 0).
+		Where(sq.Eq{"id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to disable totp", err)
+	}
+
+	if err = replaceRecoveryCodes(ctx, tx, userID, nil); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// UseTOTPStep запоминает принятый шаг TOTP. Если этот или более поздний шаг уже принят,
+// например при параллельном входе с тем же кодом, возвращается ErrInvalidMFACode.
+func (r *UserRepository) UseTOTPStep(ctx context.Context, userID uint, step int64) error {
+	query, args := sq.Update("users").
+		Set("totp_last_step", step).
+		Where(sq.Eq{"id": userID}).
+		Where(sq.Lt{"totp_last_step": step}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save totp step", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return apperror.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// GetRecoveryCodes возвращает неиспользованные резервные коды пользователя
+func (r *UserRepository) GetRecoveryCodes(ctx context.Context, userID uint) ([]entity.RecoveryCode, error) {
+	query, args := sq.Select("id", "code_hash").
+		From("user_recovery_codes").
+		Where(sq.Eq{"user_id": userID, "used_at": nil}).
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch recovery codes", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	codes := make([]entity.RecoveryCode, 0)
+	for rows.Next() {
+		var code entity.RecoveryCode
+		if err = rows.Scan(&code.ID, &code.Hash); err != nil {
+			return nil, apperror.New(apperror.DatabaseError, "failed to fetch recovery codes", err)
+		}
+		codes = append(codes, code)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch recovery codes", err)
+	}
+
+	return codes, nil
+}
+
+// UseRecoveryCode гасит резервный код. Уже использованный код не принимается повторно.
+func (r *UserRepository) UseRecoveryCode(ctx context.Context, codeID uint) error {
+	query, args := sq.Update("user_recovery_codes").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": codeID, "used_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to use recovery code", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return apperror.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes заменяет все резервные коды пользователя новыми
+func (r *UserRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err = replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sqlx.Tx, userID uint, codeHashes []string) error {
+	query, args := sq.Delete("user_recovery_codes").
+		Where(sq.Eq{"user_id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to delete recovery codes", err)
+	}
+
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	stmt := sq.Insert("user_recovery_codes").Columns("user_id", "code_hash")
+	for _, hash := range codeHashes {
+		stmt = stmt.Values(userID, hash)
+	}
+	query, args = stmt.PlaceholderFormat(sq.Dollar).MustSql()
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save recovery codes", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type APIKey struct {
+	ID         uint         `db:"id"`
+	ShopID     uint         `db:"shop_id"`
+	CreatedBy  uint         `db:"created_by"`
+	Name       string       `db:"name"`
+	Prefix     string       `db:"prefix"`
+	SecretHash string       `db:"secret_hash"`
+	Scopes     string       `db:"scopes"`
+	ExpiresAt  sql.NullTime `db:"expires_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+	CreatedAt  time.Time    `db:"created_at"`
+}
+
+func ConvertAPIKeyToEntity(k APIKey) entity.APIKey {
+	key := entity.APIKey{
+		ID:         k.ID,
+		ShopID:     k.ShopID,
+		CreatedBy:  k.CreatedBy,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		SecretHash: k.SecretHash,
+		CreatedAt:  k.CreatedAt,
+	}
+	for _, scope := range strings.Fields(k.Scopes) {
+		key.Scopes = append(key.Scopes, entity.Permission(scope))
+	}
+	if k.ExpiresAt.Valid {
+		key.ExpiresAt = &k.ExpiresAt.Time
+	}
+	if k.LastUsedAt.Valid {
+		key.LastUsedAt = &k.LastUsedAt.Time
+	}
+	if k.RevokedAt.Valid {
+		key.RevokedAt = &k.RevokedAt.Time
+	}
+	return key
+}
+
+// JoinScopes записывает области ключа через пробел
+func JoinScopes(scopes []entity.Permission) string {
+	parts := make([]string, len(scopes))
+	for i, scope := range scopes {
+		parts[i] = string(scope)
+	}
+	return strings.Join(parts, " ")
+}
@@ -30,6 +30,7 @@ type AuditEntry struct {
 	Url        string    `db:"url"`
 	RespStatus int       `db:"resp_status"`
 	UserID     uint      `db:"user_id"`
+	APIKeyID   *uint     `db:"api_key_id"`
 	IP         string    `db:"user_ip"`
 	UserRole   string    `db:"user_role"`
 	ReceivedAt time.Time `db:"received_at"`
@@ -45,6 +46,7 @@ func ConvertEntityToAuditEntries(entries []entity.AuditEntry) []AuditEntry {
 			Url:        entry.Url,
 			RespStatus: entry.RespStatus,
 			UserID:     entry.UserID,
+			APIKeyID:   nullableID(entry.APIKeyID),
 			IP:         entry.IP,
 			UserRole:   entry.UserRole,
 			ReceivedAt: entry.ReceivedAt,
@@ -72,6 +74,7 @@ func ConvertAuditEntriesToEntity(entries []AuditEntryMeta) ([]entity.AuditEntry,
 			Url:        entry.Url,
 			RespStatus: entry.RespStatus,
 			UserID:     entry.UserID,
+			APIKeyID:   valueOrZero(entry.APIKeyID),
 			IP:         entry.IP,
 			UserRole:   entry.UserRole,
 			ReceivedAt: entry.ReceivedAt,
@@ -81,3 +84,17 @@ func ConvertAuditEntriesToEntity(entries []AuditEntryMeta) ([]entity.AuditEntry,
 	}
 	return entities, entries[0].TotalCount
 }
+
+func nullableID(id uint) *uint {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func valueOrZero(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"database/sql"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type Category struct {
+	ID                uint          `db:"id"`
+	Name              string        `db:"name"`
+	ParentID          sql.NullInt64 `db:"parent_id"`
+	Lft               int           `db:"lft"`
+	Rgt               int           `db:"rgt"`
+	Depth             int           `db:"depth"`
+	ProductCount      int           `db:"product_count"`
+	TotalProductCount int           `db:"total_product_count"`
+}
+
+func ConvertCategoryToEntity(c Category) entity.Category {
+	var parentID *uint
+	if c.ParentID.Valid {
+		id := uint(c.ParentID.Int64)
+		parentID = &id
+	}
+
+	return entity.Category{
+		ID:                c.ID,
+		Name:              c.Name,
+		ParentID:          parentID,
+		Lft:               c.Lft,
+		Rgt:               c.Rgt,
+		Depth:             c.Depth,
+		ProductCount:      c.ProductCount,
+		TotalProductCount: c.TotalProductCount,
+	}
+}
+
+func ConvertCategoriesToEntity(cs []Category) []entity.Category {
+	categories := make([]entity.Category, len(cs))
+	for i, c := range cs {
+		categories[i] = ConvertCategoryToEntity(c)
+	}
+	return categories
+}
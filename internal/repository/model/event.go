@@ -0,0 +1,44 @@
+package model
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type Event struct {
+	ID            uint           `db:"id"`
+	UID           string         `db:"uid"`
+	Type          string         `db:"event_type"`
+	ActorID       sql.NullInt64  `db:"actor_id"`
+	Payload       []byte         `db:"payload"`
+	Status        string         `db:"status"`
+	Completed     string         `db:"completed"`
+	Attempts      int            `db:"attempts"`
+	NextAttemptAt time.Time      `db:"next_attempt_at"`
+	LastError     sql.NullString `db:"last_error"`
+	ProcessedAt   sql.NullTime   `db:"processed_at"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
+
+func ConvertEventToEntity(e Event) entity.Event {
+	event := entity.Event{
+		ID:            e.ID,
+		UID:           e.UID,
+		Type:          entity.EventType(e.Type),
+		ActorID:       uint(e.ActorID.Int64),
+		Payload:       e.Payload,
+		Status:        entity.EventStatus(e.Status),
+		Completed:     strings.Fields(e.Completed),
+		Attempts:      e.Attempts,
+		NextAttemptAt: e.NextAttemptAt,
+		LastError:     e.LastError.String,
+		CreatedAt:     e.CreatedAt,
+	}
+	if e.ProcessedAt.Valid {
+		event.ProcessedAt = &e.ProcessedAt.Time
+	}
+	return event
+}
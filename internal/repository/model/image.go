@@ -0,0 +1,28 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type ProductImage struct {
+	Key          string         `db:"image_key"`
+	ThumbnailKey sql.NullString `db:"thumbnail_key"`
+	ProductID    int            `db:"product_id"`
+	ContentType  string         `db:"content_type"`
+	Size         int64          `db:"size"`
+	CreatedAt    time.Time      `db:"created_at"`
+}
+
+func ConvertProductImageToEntity(i ProductImage) entity.ProductImage {
+	return entity.ProductImage{
+		Key:          i.Key,
+		ThumbnailKey: i.ThumbnailKey.String,
+		ProductID:    i.ProductID,
+		ContentType:  i.ContentType,
+		Size:         i.Size,
+		CreatedAt:    i.CreatedAt,
+	}
+}
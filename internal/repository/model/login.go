@@ -0,0 +1,27 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type LoginAttempts struct {
+	Key          string       `db:"key"`
+	Failures     int          `db:"failures"`
+	LastFailedAt time.Time    `db:"last_failed_at"`
+	BlockedUntil sql.NullTime `db:"blocked_until"`
+}
+
+func ConvertLoginAttemptsToEntity(a LoginAttempts) entity.LoginAttempts {
+	result := entity.LoginAttempts{
+		Key:          a.Key,
+		Failures:     a.Failures,
+		LastFailedAt: a.LastFailedAt,
+	}
+	if a.BlockedUntil.Valid {
+		result.BlockedUntil = a.BlockedUntil.Time
+	}
+	return result
+}
@@ -1,10 +1,73 @@
 package model
 
-import "time"
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
 
 type Notification struct {
-	ID      uint      `gorm:"column:id;primary_key"`
-	UserID  uint      `gorm:"column:user_id"`
-	Message string    `gorm:"column:message"`
-	SentAt  time.Time `gorm:"column:sent_at"`
+	ID           uint         `db:"id"`
+	UserID       uint         `db:"user_id"`
+	Type         string       `db:"type"`
+	Message      string       `db:"message"`
+	Link         string       `db:"link"`
+	ReadAt       sql.NullTime `db:"read_at"`
+	SentAt       time.Time    `db:"sent_at"`
+	EmailPending bool         `db:"email_pending"`
+}
+
+type NotificationWithCount struct {
+	Notification
+	TotalCount int `db:"total_count"`
+}
+
+func ConvertNotificationToEntity(n Notification) entity.Notification {
+	notification := entity.Notification{
+		ID:           n.ID,
+		UserID:       n.UserID,
+		Type:         entity.NotificationType(n.Type),
+		Message:      n.Message,
+		Link:         n.Link,
+		SentAt:       n.SentAt,
+		EmailPending: n.EmailPending,
+	}
+	if n.ReadAt.Valid {
+		notification.ReadAt = &n.ReadAt.Time
+	}
+	return notification
+}
+
+type NotificationPreferences struct {
+	UserID          uint          `db:"user_id"`
+	Channels        []byte        `db:"channels"`
+	QuietHoursStart sql.NullInt16 `db:"quiet_hours_start"`
+	QuietHoursEnd   sql.NullInt16 `db:"quiet_hours_end"`
+	Timezone        string        `db:"timezone"`
+	Digest          string        `db:"digest"`
+	LastDigestAt    sql.NullTime  `db:"last_digest_at"`
+}
+
+func ConvertNotificationPreferencesToEntity(p NotificationPreferences) (entity.NotificationPreferences, error) {
+	preferences := entity.NotificationPreferences{
+		UserID:   p.UserID,
+		Channels: map[entity.NotificationType]entity.NotificationChannel{},
+		Timezone: p.Timezone,
+		Digest:   entity.DigestFrequency(p.Digest),
+	}
+	if err := json.Unmarshal(p.Channels, &preferences.Channels); err != nil {
+		return entity.NotificationPreferences{}, err
+	}
+	if p.QuietHoursStart.Valid && p.QuietHoursEnd.Valid {
+		preferences.QuietHours = &entity.QuietHours{
+			Start: int(p.QuietHoursStart.Int16),
+			End:   int(p.QuietHoursEnd.Int16),
+		}
+	}
+	if p.LastDigestAt.Valid {
+		preferences.LastDigestAt = &p.LastDigestAt.Time
+	}
+	return preferences, nil
 }
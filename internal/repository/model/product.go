@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 )
 
@@ -12,12 +14,12 @@ type Product struct {
 }
 
 type ProductFilter struct {
-	CategoryID *int    `form:"category_id"`
-	ShopID     *int    `form:"shop_id"`
-	MinPrice   *int    `form:"min_price"`
-	MaxPrice   *int    `form:"max_price"`
-	Name       *string `form:"name"`
-	Attributes map[string]string
+	CategoryID *int              `form:"category_id" json:"category_id,omitempty"`
+	ShopID     *int              `form:"shop_id" json:"shop_id,omitempty"`
+	MinPrice   *int              `form:"min_price" json:"min_price,omitempty"`
+	MaxPrice   *int              `form:"max_price" json:"max_price,omitempty"`
+	Name       *string           `form:"name" json:"name,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 func ConvertProductToEntity(p Product) entity.Product {
@@ -31,3 +33,61 @@ func ConvertProductToEntity(p Product) entity.Product {
 		Attributes:   make(map[string]interface{}),
 	}
 }
+
+// PriceRange это минимальная и максимальная цена продукта в копейках, 0 если предложений нет
+type PriceRange struct {
+	Min int
+	Max int
+}
+
+// ReviewStats это средняя оценка продукта и количество отзывов
+type ReviewStats struct {
+	Average float64
+	Count   int
+}
+
+type ShopPrice struct {
+	ProductID          int     `db:"product_id"`
+	ShopID             int     `db:"shop_id"`
+	ShopName           string  `db:"shop_name"`
+	SellerID           int     `db:"seller_id"`
+	Price              int     `db:"price"`
+	Currency           string  `db:"currency"`
+	IsAvailable        bool    `db:"is_available"`
+	SellerRating       float64 `db:"seller_rating"`
+	SellerReviewsCount int     `db:"seller_reviews_count"`
+}
+
+func ConvertShopPriceToEntity(sp ShopPrice) entity.ShopPrice {
+	return entity.ShopPrice{
+		ProductID:          sp.ProductID,
+		ShopID:             sp.ShopID,
+		ShopName:           sp.ShopName,
+		SellerID:           sp.SellerID,
+		Price:              sp.Price,
+		Currency:           sp.Currency,
+		IsAvailable:        sp.IsAvailable,
+		SellerRating:       sp.SellerRating,
+		SellerReviewsCount: sp.SellerReviewsCount,
+	}
+}
+
+type PricePoint struct {
+	ProductID int       `db:"product_id"`
+	ShopID    int       `db:"shop_id"`
+	ShopName  string    `db:"shop_name"`
+	Price     int       `db:"price"`
+	Currency  string    `db:"currency"`
+	ChangedAt time.Time `db:"changed_at"`
+}
+
+func ConvertPricePointToEntity(pp PricePoint) entity.PricePoint {
+	return entity.PricePoint{
+		ProductID: pp.ProductID,
+		ShopID:    pp.ShopID,
+		ShopName:  pp.ShopName,
+		Price:     pp.Price,
+		Currency:  pp.Currency,
+		ChangedAt: pp.ChangedAt,
+	}
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type ShopMember struct {
+	ShopID    uint      `db:"shop_id"`
+	UserID    uint      `db:"user_id"`
+	UserName  string    `db:"user_name"`
+	UserEmail string    `db:"user_email"`
+	Role      string    `db:"role"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func ConvertShopMemberToEntity(m ShopMember) entity.ShopMember {
+	return entity.ShopMember{
+		ShopID:    m.ShopID,
+		UserID:    m.UserID,
+		UserName:  m.UserName,
+		UserEmail: m.UserEmail,
+		Role:      entity.ShopRole(m.Role),
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+type ShopInvitation struct {
+	ID         uint          `db:"id"`
+	ShopID     uint          `db:"shop_id"`
+	Email      string        `db:"email"`
+	Role       string        `db:"role"`
+	TokenHash  string        `db:"token_hash"`
+	InvitedBy  sql.NullInt64 `db:"invited_by"`
+	ExpiresAt  time.Time     `db:"expires_at"`
+	AcceptedAt sql.NullTime  `db:"accepted_at"`
+	CreatedAt  time.Time     `db:"created_at"`
+}
+
+func ConvertShopInvitationToEntity(i ShopInvitation) entity.ShopInvitation {
+	invitation := entity.ShopInvitation{
+		ID:        i.ID,
+		ShopID:    i.ShopID,
+		Email:     i.Email,
+		Role:      entity.ShopRole(i.Role),
+		TokenHash: i.TokenHash,
+		InvitedBy: uint(i.InvitedBy.Int64),
+		ExpiresAt: i.ExpiresAt,
+		CreatedAt: i.CreatedAt,
+	}
+	if i.AcceptedAt.Valid {
+		invitation.AcceptedAt = &i.AcceptedAt.Time
+	}
+	return invitation
+}
@@ -14,6 +14,12 @@ type RefreshToken struct {
 	RevokedAt   *time.Time `db:"revoked_at"`
 	Fingerprint string     `db:"fingerprint"`
 	UserID      uint       `db:"user_id"`
+	FamilyID    uuid.UUID  `db:"family_id"`
+	ReplacedBy  *uuid.UUID `db:"replaced_by"`
+	LastUsedAt  time.Time  `db:"last_used_at"`
+	LastIP      string     `db:"last_ip"`
+	UserAgent   string     `db:"user_agent"`
+	AccessJTI   string     `db:"access_jti"`
 }
 
 func ConvertTokenFromEntity(t entity.RefreshToken) RefreshToken {
@@ -24,6 +30,12 @@ func ConvertTokenFromEntity(t entity.RefreshToken) RefreshToken {
 		RevokedAt:   t.RevokedAt,
 		Fingerprint: t.Fingerprint,
 		UserID:      t.UserID,
+		FamilyID:    t.FamilyID,
+		ReplacedBy:  t.ReplacedBy,
+		LastUsedAt:  t.LastUsedAt,
+		LastIP:      t.LastIP,
+		UserAgent:   t.UserAgent,
+		AccessJTI:   t.AccessJTI,
 	}
 }
 
@@ -35,5 +47,11 @@ func ConvertTokenToEntity(t RefreshToken) entity.RefreshToken {
 		RevokedAt:   t.RevokedAt,
 		Fingerprint: t.Fingerprint,
 		UserID:      t.UserID,
+		FamilyID:    t.FamilyID,
+		ReplacedBy:  t.ReplacedBy,
+		LastUsedAt:  t.LastUsedAt,
+		LastIP:      t.LastIP,
+		UserAgent:   t.UserAgent,
+		AccessJTI:   t.AccessJTI,
 	}
 }
@@ -1,17 +1,24 @@
 package model
 
 import (
+	"database/sql"
+
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/service/user"
 )
 
 type User struct {
-	ID            uint   `db:"id"`
-	Name          string `db:"name"`
-	Email         string `db:"email"`
-	Phone         string `db:"phone_number"`
-	Password      string `db:"password_hash"`
-	IsStore       bool   `db:"is_store"`
+	ID            uint           `db:"id"`
+	Name          string         `db:"name"`
+	Email         string         `db:"email"`
+	Phone         string         `db:"phone_number"`
+	Password      string         `db:"password_hash"`
+	Role          string         `db:"role"`
+	EmailVerified sql.NullTime   `db:"email_verified_at"`
+	BannedAt      sql.NullTime   `db:"banned_at"`
+	TOTPSecret    sql.NullString `db:"totp_secret"`
+	TOTPEnabledAt sql.NullTime   `db:"totp_enabled_at"`
+	TOTPLastStep  int64          `db:"totp_last_step"`
 	Notifications []Notification
 }
 
@@ -21,17 +28,28 @@ func ConvertUserFromSvc(u user.User) User {
 		Email:    u.Email,
 		Phone:    u.Phone,
 		Password: u.Password,
-		IsStore:  u.IsStore,
 	}
 }
 
 func ConvertUserToEntity(u User) entity.User {
-	return entity.User{
+	result := entity.User{
 		ID:       u.ID,
 		Name:     u.Name,
 		Email:    u.Email,
 		Phone:    u.Phone,
 		Password: u.Password,
-		IsStore:  u.IsStore,
+		Role:     entity.Role(u.Role),
+	}
+	if u.EmailVerified.Valid {
+		result.EmailVerifiedAt = &u.EmailVerified.Time
+	}
+	if u.BannedAt.Valid {
+		result.BannedAt = &u.BannedAt.Time
+	}
+	result.TOTPSecret = u.TOTPSecret.String
+	result.TOTPLastStep = u.TOTPLastStep
+	if u.TOTPEnabledAt.Valid {
+		result.TOTPEnabledAt = &u.TOTPEnabledAt.Time
 	}
+	return result
 }
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type WebhookEndpoint struct {
+	ID        uint      `db:"id"`
+	ShopID    uint      `db:"shop_id"`
+	CreatedBy uint      `db:"created_by"`
+	URL       string    `db:"url"`
+	Secret    string    `db:"secret"`
+	Events    string    `db:"events"`
+	Active    bool      `db:"active"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func ConvertWebhookEndpointToEntity(e WebhookEndpoint) entity.WebhookEndpoint {
+	endpoint := entity.WebhookEndpoint{
+		ID:        e.ID,
+		ShopID:    e.ShopID,
+		CreatedBy: e.CreatedBy,
+		URL:       e.URL,
+		Secret:    e.Secret,
+		Active:    e.Active,
+		CreatedAt: e.CreatedAt,
+	}
+	for _, event := range strings.Fields(e.Events) {
+		endpoint.Events = append(endpoint.Events, entity.WebhookEvent(event))
+	}
+	return endpoint
+}
+
+// JoinWebhookEvents записывает события адреса через пробел
+func JoinWebhookEvents(events []entity.WebhookEvent) string {
+	parts := make([]string, len(events))
+	for i, event := range events {
+		parts[i] = string(event)
+	}
+	return strings.Join(parts, " ")
+}
+
+type WebhookDelivery struct {
+	ID             uint           `db:"id"`
+	EndpointID     uint           `db:"endpoint_id"`
+	EventID        string         `db:"event_id"`
+	Event          string         `db:"event"`
+	Payload        []byte         `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	LastStatusCode sql.NullInt32  `db:"last_status_code"`
+	LastError      sql.NullString `db:"last_error"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+}
+
+type WebhookDeliveryWithCount struct {
+	WebhookDelivery
+	TotalCount int `db:"total_count"`
+}
+
+// WebhookJob это доставка вместе с адресом и секретом, выбранными одним запросом
+type WebhookJob struct {
+	WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+func ConvertWebhookDeliveryToEntity(d WebhookDelivery) entity.WebhookDelivery {
+	delivery := entity.WebhookDelivery{
+		ID:            d.ID,
+		EndpointID:    d.EndpointID,
+		EventID:       d.EventID,
+		Event:         entity.WebhookEvent(d.Event),
+		Payload:       d.Payload,
+		Status:        entity.WebhookDeliveryStatus(d.Status),
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		LastError:     d.LastError.String,
+		CreatedAt:     d.CreatedAt,
+	}
+	if d.LastStatusCode.Valid {
+		code := int(d.LastStatusCode.Int32)
+		delivery.LastStatusCode = &code
+	}
+	if d.DeliveredAt.Valid {
+		delivery.DeliveredAt = &d.DeliveredAt.Time
+	}
+	return delivery
+}
+
+type WebhookAttempt struct {
+	ID           uint           `db:"id"`
+	DeliveryID   uint           `db:"delivery_id"`
+	StatusCode   sql.NullInt32  `db:"status_code"`
+	Error        sql.NullString `db:"error"`
+	ResponseBody sql.NullString `db:"response_body"`
+	DurationMS   int64          `db:"duration_ms"`
+	AttemptedAt  time.Time      `db:"attempted_at"`
+}
+
+func ConvertWebhookAttemptToEntity(a WebhookAttempt) entity.WebhookAttempt {
+	attempt := entity.WebhookAttempt{
+		ID:           a.ID,
+		DeliveryID:   a.DeliveryID,
+		Error:        a.Error.String,
+		ResponseBody: a.ResponseBody.String,
+		Duration:     time.Duration(a.DurationMS) * time.Millisecond,
+		AttemptedAt:  a.AttemptedAt,
+	}
+	if a.StatusCode.Valid {
+		code := int(a.StatusCode.Int32)
+		attempt.StatusCode = &code
+	}
+	return attempt
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+)
+
+type WishlistItem struct {
+	UserID       uint          `db:"user_id"`
+	ProductID    int           `db:"product_id"`
+	ProductName  string        `db:"product_name"`
+	TargetPrice  sql.NullInt64 `db:"target_price"`
+	MinimalPrice int           `db:"minimal_price"`
+	CreatedAt    time.Time     `db:"created_at"`
+}
+
+func ConvertWishlistItemToEntity(w WishlistItem) entity.WishlistItem {
+	item := entity.WishlistItem{
+		UserID:       w.UserID,
+		ProductID:    w.ProductID,
+		ProductName:  w.ProductName,
+		MinimalPrice: w.MinimalPrice,
+		CreatedAt:    w.CreatedAt,
+	}
+	if w.TargetPrice.Valid {
+		target := int(w.TargetPrice.Int64)
+		item.TargetPrice = &target
+	}
+	return item
+}
+
+type PriceDrop struct {
+	UserID      uint   `db:"user_id"`
+	UserEmail   string `db:"user_email"`
+	ProductID   int    `db:"product_id"`
+	ProductName string `db:"product_name"`
+	Price       int    `db:"price"`
+	TargetPrice int    `db:"target_price"`
+
+	NotifiedPrice *int `db:"notified_price"`
+}
+
+func ConvertPriceDropToEntity(p PriceDrop) entity.PriceDrop {
+	return entity.PriceDrop{
+		UserID:      p.UserID,
+		UserEmail:   p.UserEmail,
+		ProductID:   p.ProductID,
+		ProductName: p.ProductName,
+		Price:       p.Price,
+		TargetPrice: p.TargetPrice,
+
+		NotifiedPrice: p.NotifiedPrice,
+	}
+}
+
+type SavedSearch struct {
+	ID            uint      `db:"id"`
+	UserID        uint      `db:"user_id"`
+	UserEmail     string    `db:"user_email"`
+	Name          string    `db:"name"`
+	Filter        []byte    `db:"filter"`
+	LastProductID int       `db:"last_product_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+func ConvertSavedSearchToEntity(s SavedSearch) entity.SavedSearch {
+	return entity.SavedSearch{
+		ID:            s.ID,
+		UserID:        s.UserID,
+		UserEmail:     s.UserEmail,
+		Name:          s.Name,
+		Filter:        s.Filter,
+		LastProductID: s.LastProductID,
+		CreatedAt:     s.CreatedAt,
+	}
+}
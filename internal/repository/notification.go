@@ -1,11 +1,42 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 )
 
+var notificationColumns = []string{
+	"id",
+	"user_id",
+	"type",
+	"message",
+	"link",
+	"read_at",
+	"sent_at",
+	"email_pending",
+}
+
+var notificationPreferenceColumns = []string{
+	"user_id",
+	"channels",
+	"quiet_hours_start",
+	"quiet_hours_end",
+	"timezone",
+	"digest",
+	"last_digest_at",
+}
+
 type NotificationRepository struct {
 	db *sqlx.DB
 }
@@ -14,15 +45,283 @@ func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
 	return &NotificationRepository{db: db}
 }
 
+// SelectUserNotifications возвращает уведомления пользователя от новых к старым.
+// При unreadOnly возвращаются только непрочитанные.
 func (r *NotificationRepository) SelectUserNotifications(
-	id string,
-	offset, limit int,
+	ctx context.Context,
+	userID uint,
+	unreadOnly bool,
+	limit, offset int,
 ) ([]entity.Notification, int, error) {
-	var total int64
+	builder := sq.Select(append(notificationColumns, "COUNT(*) OVER() AS total_count")...).
+		From("notifications").
+		Where(sq.Eq{"user_id": userID})
+	if unreadOnly {
+		builder = builder.Where(sq.Eq{"read_at": nil})
+	}
+
+	query, args := builder.
+		OrderBy("id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var rows []model.NotificationWithCount
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "failed to fetch notifications", err)
+	}
+
+	if len(rows) == 0 {
+		return []entity.Notification{}, 0, nil
+	}
+
+	notifications := make([]entity.Notification, len(rows))
+	for i, row := range rows {
+		notifications[i] = model.ConvertNotificationToEntity(row.Notification)
+	}
+
+	return notifications, rows[0].TotalCount, nil
+}
+
+func (r *NotificationRepository) CountUnreadNotifications(ctx context.Context, userID uint) (int, error) {
+	query, args := sq.Select("COUNT(*)").
+		From("notifications").
+		Where(sq.Eq{"user_id": userID, "read_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to count unread notifications", err)
+	}
+
+	return count, nil
+}
+
+// InsertNotifications сохраняет уведомления одним запросом и возвращает их с ID и временем отправки.
+// Уведомления повторно доставленного события пропускаются и не возвращаются.
+func (r *NotificationRepository) InsertNotifications(
+	ctx context.Context,
+	notifications []entity.Notification,
+) ([]entity.Notification, error) {
+	if len(notifications) == 0 {
+		return nil, nil
+	}
+
+	builder := sq.Insert("notifications").
+		Columns("user_id", "type", "message", "link", "email_pending", "event_uid")
+	for _, n := range notifications {
+		eventUID := sql.NullString{String: n.EventUID, Valid: n.EventUID != ""}
+		builder = builder.Values(n.UserID, n.Type, n.Message, n.Link, n.EmailPending, eventUID)
+	}
+
+	query, args := builder.
+		Suffix("ON CONFLICT (event_uid, user_id) DO NOTHING RETURNING " + strings.Join(notificationColumns, ", ")).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var rows []model.Notification
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to insert notifications", err)
+	}
+
+	inserted := make([]entity.Notification, len(rows))
+	for i, row := range rows {
+		inserted[i] = model.ConvertNotificationToEntity(row)
+	}
+
+	return inserted, nil
+}
+
+// MarkNotificationRead отмечает уведомление прочитанным. Повторная отметка не меняет время прочтения.
+func (r *NotificationRepository) MarkNotificationRead(ctx context.Context, userID, notificationID uint) error {
+	query, args := sq.Update("notifications").
+		Set("read_at", sq.Expr("COALESCE(read_at, ?)", time.Now())).
+		Where(sq.Eq{"id": notificationID, "user_id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to mark notification read", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to mark notification read", err)
+	}
+	if affected == 0 {
+		return apperror.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// MarkAllNotificationsRead отмечает прочитанными все уведомления пользователя и возвращает их количество
+func (r *NotificationRepository) MarkAllNotificationsRead(ctx context.Context, userID uint) (int64, error) {
+	query, args := sq.Update("notifications").
+		Set("read_at", time.Now()).
+		Where(sq.Eq{"user_id": userID, "read_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to mark notifications read", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to mark notifications read", err)
+	}
+
+	return affected, nil
+}
+
+// SelectNotificationPreferences возвращает сохраненные настройки пользователей.
+// Пользователей, которые не меняли настройки, в результате нет.
+func (r *NotificationRepository) SelectNotificationPreferences(
+	ctx context.Context,
+	userIDs []uint,
+) ([]entity.NotificationPreferences, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args := sq.Select(notificationPreferenceColumns...).
+		From("notification_preferences").
+		Where(sq.Eq{"user_id": userIDs}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var rows []model.NotificationPreferences
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch notification preferences", err)
+	}
+
+	preferences := make([]entity.NotificationPreferences, len(rows))
+	for i, row := range rows {
+		p, err := model.ConvertNotificationPreferencesToEntity(row)
+		if err != nil {
+			return nil, apperror.New(apperror.DatabaseError, "failed to decode notification preferences", err)
+		}
+		preferences[i] = p
+	}
+
+	return preferences, nil
+}
+
+// UpsertNotificationPreferences сохраняет настройки пользователя. Время последнего дайджеста не меняется.
+func (r *NotificationRepository) UpsertNotificationPreferences(
+	ctx context.Context,
+	preferences entity.NotificationPreferences,
+) error {
+	channels, err := json.Marshal(preferences.Channels)
+	if err != nil {
+		return apperror.New(apperror.InternalError, "failed to encode notification channels", err)
+	}
+
+	var start, end sql.NullInt16
+	if preferences.QuietHours != nil {
+		start = sql.NullInt16{Int16: int16(preferences.QuietHours.Start), Valid: true}
+		end = sql.NullInt16{Int16: int16(preferences.QuietHours.End), Valid: true}
+	}
+
+	query, args := sq.Insert("notification_preferences").
+		Columns("user_id", "channels", "quiet_hours_start", "quiet_hours_end", "timezone", "digest").
+		Values(preferences.UserID, channels, start, end, preferences.Timezone, preferences.Digest).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET " +
+			"channels = EXCLUDED.channels, " +
+			"quiet_hours_start = EXCLUDED.quiet_hours_start, " +
+			"quiet_hours_end = EXCLUDED.quiet_hours_end, " +
+			"timezone = EXCLUDED.timezone, " +
+			"digest = EXCLUDED.digest, " +
+			"updated_at = NOW()").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save notification preferences", err)
+	}
+
+	return nil
+}
+
+// SelectPendingEmailUserIDs возвращает пользователей, у которых есть отложенные письма
+func (r *NotificationRepository) SelectPendingEmailUserIDs(ctx context.Context) ([]uint, error) {
+	query, args := sq.Select("DISTINCT user_id").
+		From("notifications").
+		Where(sq.Eq{"email_pending": true}).
+		Where(sq.NotEq{"user_id": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var userIDs []uint
+	if err := r.db.SelectContext(ctx, &userIDs, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch pending notification emails", err)
+	}
+
+	return userIDs, nil
+}
+
+// ClaimNotificationDigest забирает отложенные уведомления пользователя для дайджеста
+// и запоминает время дайджеста. Строки, которые уже забирает другой экземпляр, пропускаются,
+// поэтому одно уведомление попадает только в одно письмо. Уведомления возвращаются от старых к новым.
+func (r *NotificationRepository) ClaimNotificationDigest(
+	ctx context.Context,
+	userID uint,
+	sentAt time.Time,
+) ([]entity.Notification, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	pending := sq.Select("id").
+		From("notifications").
+		Where(sq.Eq{"user_id": userID, "email_pending": true}).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args := sq.Update("notifications").
+		Set("email_pending", false).
+		Where(sq.Expr("id IN (?)", pending)).
+		Suffix("RETURNING " + strings.Join(notificationColumns, ", ")).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var rows []model.Notification
+	if err = tx.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to claim notification digest", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	query, args = sq.Insert("notification_preferences").
+		Columns("user_id", "last_digest_at").
+		Values(userID, sentAt).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET last_digest_at = EXCLUDED.last_digest_at").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to claim notification digest", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
 
-	_ = id
-	_ = offset
-	_ = limit
+	notifications := make([]entity.Notification, len(rows))
+	for i, row := range rows {
+		notifications[i] = model.ConvertNotificationToEntity(row)
+	}
 
-	return nil, int(total), nil
+	return notifications, nil
 }
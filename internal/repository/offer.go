@@ -15,6 +15,9 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 )
 
+const offerColumns = "id, offer_price, currency, status, " +
+	"created_at, updated_at, expires_at, shop_id, product_id, user_id"
+
 type OfferRepository struct {
 	db *sqlx.DB
 }
@@ -35,6 +38,7 @@ func (r *OfferRepository) InsertOffer(
 			"product_id": offerModel.ProductID,
 			"shop_id":    offerModel.ShopID,
 			"user_id":    offerModel.UserID}).
+		Where(squirrel.Gt{"expires_at": time.Now()}).
 		PlaceholderFormat(squirrel.Dollar).
 		MustSql()
 	tx, err := r.db.BeginTxx(ctx, nil)
@@ -72,6 +76,12 @@ func (r *OfferRepository) InsertOffer(
 		return 0, apperror.New(apperror.DatabaseError, "error inserting offer into database", err)
 	}
 
+	offer.ID = offerID
+	err = insertOutboxEvent(ctx, tx, entity.EventOfferCreated, offer.UserID, entity.OfferEvent{Offer: offer})
+	if err != nil {
+		return 0, err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
@@ -84,12 +94,21 @@ func (r *OfferRepository) GetOfferByID(
 	ctx context.Context,
 	offerID uint,
 ) (entity.Offer, error) {
-	var offer entity.Offer
+	query, args := squirrel.Select(offerColumns).
+		From("offers").
+		Where(squirrel.Eq{"id": offerID}).
+		PlaceholderFormat(squirrel.Dollar).
+		MustSql()
 
-	_ = ctx
-	_ = offerID
+	var offer model.Offer
+	if err := r.db.GetContext(ctx, &offer, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Offer{}, apperror.ErrOfferNotFound
+		}
+		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to fetch offer", err)
+	}
 
-	return offer, nil
+	return offer.ConvertToEntity(), nil
 }
 
 func (r *OfferRepository) SelectUserOffers(
@@ -99,25 +118,13 @@ func (r *OfferRepository) SelectUserOffers(
 ) ([]entity.Offer, int, error) {
 	var total int
 
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return nil, 0, apperror.New(apperror.DatabaseError,
-			"failed to begin transaction", err)
-	}
-	defer func() {
-		_ = tx.Rollback()
-	}()
-
-	// lazy update
-	if err = r.updateExpiredOffers(ctx, userID, tx); err != nil {
-		return nil, 0, err
-	}
-
+	// Просроченные заявки отменяет Expirer, до этого они просто не показываются
 	selectUserOffersQuery, args := squirrel.Select("id, offer_price, currency, status, " +
 		"created_at, updated_at, expires_at, shop_id, product_id, user_id," +
 		"COUNT (*) OVER() as total_count").
 		From("offers").
 		Where(squirrel.Eq{"status": "pending", "user_id": userID}).
+		Where(squirrel.Gt{"expires_at": time.Now()}).
 		OrderBy("created_at desc").
 		Offset(uint64(offset)).
 		Limit(uint64(limit)).
@@ -126,14 +133,9 @@ func (r *OfferRepository) SelectUserOffers(
 
 	offersWithCount := make([]model.OfferWithCount, 0, limit)
 
-	err = tx.SelectContext(ctx, &offersWithCount, selectUserOffersQuery, args...)
+	err := r.db.SelectContext(ctx, &offersWithCount, selectUserOffersQuery, args...)
 	if err != nil {
-		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting user offers after lazy update", err)
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return nil, 0, apperror.New(apperror.DatabaseError, "failed to commit transaction for lazy update", err)
+		return nil, 0, apperror.New(apperror.DatabaseError, "error selecting user offers", err)
 	}
 
 	if len(offersWithCount) == 0 {
@@ -150,22 +152,43 @@ func (r *OfferRepository) SelectUserOffers(
 	return offers, total, nil
 }
 
-// SelectUserOffers lazy update helper function
-func (r *OfferRepository) updateExpiredOffers(ctx context.Context, userID uint, tx *sqlx.Tx) error {
-	updateExpiredQuery, args := squirrel.Update("offers").
+// ExpireOffers отменяет просроченные заявки и записывает о каждой событие offer.expired
+func (r *OfferRepository) ExpireOffers(ctx context.Context) ([]entity.Offer, error) {
+	query, args := squirrel.Update("offers").
 		Set("status", "cancelled").
 		Set("updated_at", time.Now()).
 		Where(squirrel.Lt{"expires_at": time.Now()}).
 		Where(squirrel.Eq{"status": "pending"}).
-		Where(squirrel.Eq{"user_id": userID}).
+		Suffix("returning " + offerColumns).
 		PlaceholderFormat(squirrel.Dollar).
 		MustSql()
 
-	_, err := tx.ExecContext(ctx, updateExpiredQuery, args...)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return apperror.New(apperror.DatabaseError, "error updating expired offers", err)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
 	}
-	return nil
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var offers []model.Offer
+	if err = tx.SelectContext(ctx, &offers, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "error expiring offers", err)
+	}
+
+	expired := make([]entity.Offer, len(offers))
+	for i, offer := range offers {
+		expired[i] = offer.ConvertToEntity()
+		if err = insertOutboxEvent(ctx, tx, entity.EventOfferExpired, 0, entity.OfferEvent{Offer: expired[i]}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return expired, nil
 }
 
 func (r *OfferRepository) UpdateOfferStatus(
@@ -180,7 +203,7 @@ func (r *OfferRepository) UpdateOfferStatus(
 		Set("status", offer.Status).
 		Set("updated_at", time.Now()).
 		Where(squirrel.Eq{"id": offer.ID}).
-		Suffix("returning status").
+		Suffix("returning " + offerColumns).
 		PlaceholderFormat(squirrel.Dollar).
 		MustSql()
 
@@ -198,7 +221,7 @@ func (r *OfferRepository) UpdateOfferStatus(
 	}
 
 	if isStore {
-		err = isUserShopOwner(ctx, offer.ID, userID, tx)
+		err = isUserShopMember(ctx, offer.ID, userID, tx)
 		if err != nil {
 			return entity.Offer{}, err
 		}
@@ -210,7 +233,7 @@ func (r *OfferRepository) UpdateOfferStatus(
 			Set("status", offer.Status).
 			Set("updated_at", time.Now()).
 			Where(squirrel.Eq{"id": offer.ID, "user_id": userID}).
-			Suffix("returning status").
+			Suffix("returning " + offerColumns).
 			PlaceholderFormat(squirrel.Dollar).
 			MustSql()
 	}
@@ -225,33 +248,42 @@ func (r *OfferRepository) UpdateOfferStatus(
 		return entity.Offer{}, apperror.New(apperror.DatabaseError, "error scanning into struct", err)
 	}
 
+	updated := offerResp.ConvertToEntity()
+	err = insertOutboxEvent(ctx, tx, entity.OfferStatusEvents[updated.Status], userID,
+		entity.OfferEvent{Offer: updated})
+	if err != nil {
+		return entity.Offer{}, err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return entity.Offer{}, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
 	}
 
-	return offerResp.ConvertToEntity(), nil
+	return updated, nil
 }
 
-func isUserShopOwner(ctx context.Context, offerID, userID uint, tx *sqlx.Tx) error {
-	validateShopOwnerIDQuery, args := squirrel.Select("users.id").
-		From("users").
-		InnerJoin("shops on users.id = shops.user_id").
-		InnerJoin("offers on shops.id = offers.shop_id").
-		Where(squirrel.Eq{"offers.id": offerID}).
+// isUserShopMember проверяет, что пользователь работает в магазине, которому адресована заявка,
+// и его роль позволяет отвечать на заявки
+func isUserShopMember(ctx context.Context, offerID, userID uint, tx *sqlx.Tx) error {
+	validateShopMemberQuery, args := squirrel.Select("COUNT(*) > 0").
+		From("offers").
+		InnerJoin("shop_members on shop_members.shop_id = offers.shop_id").
+		Where(squirrel.Eq{
+			"offers.id":            offerID,
+			"shop_members.user_id": userID,
+			"shop_members.role":    entity.ShopRolesWith(entity.PermissionOffersRespond),
+		}).
 		PlaceholderFormat(squirrel.Dollar).
 		MustSql()
 
-	var requiredID uint
-	err := tx.QueryRowxContext(ctx, validateShopOwnerIDQuery, args...).Scan(&requiredID)
+	var isMember bool
+	err := tx.QueryRowxContext(ctx, validateShopMemberQuery, args...).Scan(&isMember)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return apperror.ErrUserNotFound
-		}
-		return apperror.New(apperror.InternalError, "error scanning into uint", err)
+		return apperror.New(apperror.InternalError, "error scanning shop membership", err)
 	}
 
-	if userID != requiredID {
+	if !isMember {
 		return apperror.New(apperror.Unauthorized, "unauthorized to update offer status", nil)
 	}
 
@@ -259,7 +291,7 @@ func isUserShopOwner(ctx context.Context, offerID, userID uint, tx *sqlx.Tx) err
 }
 
 func isPendingOffer(ctx context.Context, offerID uint, tx *sqlx.Tx) error {
-	getOfferStatusQuery, args := squirrel.Select("offers.status = 'pending'").
+	getOfferStatusQuery, args := squirrel.Select("offers.status = 'pending' AND offers.expires_at > NOW()").
 		From("offers").
 		Where(squirrel.Eq{"offers.id": offerID}).
 		PlaceholderFormat(squirrel.Dollar).
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+var outboxEventColumns = []string{
+	"id",
+	"uid",
+	"event_type",
+	"actor_id",
+	"payload",
+	"status",
+	"completed",
+	"attempts",
+	"next_attempt_at",
+	"last_error",
+	"processed_at",
+	"created_at",
+}
+
+// insertOutboxEvent записывает доменное событие. Репозитории вызывают его в транзакции
+// изменения, чтобы событие появилось тогда и только тогда, когда изменение сохранено.
+func insertOutboxEvent(
+	ctx context.Context,
+	execer sqlx.ExecerContext,
+	eventType entity.EventType,
+	actorID uint,
+	payload any,
+) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return apperror.New(apperror.InternalError, "failed to encode event", err)
+	}
+
+	var actor *uint
+	if actorID != 0 {
+		actor = &actorID
+	}
+
+	query, args := sq.Insert("outbox_events").
+		Columns("uid", "event_type", "actor_id", "payload").
+		Values(uuid.NewString(), eventType, actor, string(body)).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = execer.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to insert outbox event", err)
+	}
+
+	return nil
+}
+
+type OutboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// InsertEvent записывает событие, которое не сопровождает изменение в БД
+func (r *OutboxRepository) InsertEvent(
+	ctx context.Context,
+	eventType entity.EventType,
+	actorID uint,
+	payload any,
+) error {
+	return insertOutboxEvent(ctx, r.db, eventType, actorID, payload)
+}
+
+// ClaimEvents берет в работу события, время которых пришло, в порядке записи и откладывает их на lease,
+// чтобы другие экземпляры приложения их не взяли. Если экземпляр упадет во время раздачи,
+// событие вернется в работу после lease.
+func (r *OutboxRepository) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]entity.Event, error) {
+	now := time.Now()
+
+	selectQuery, args := sq.Select(outboxEventColumns...).
+		From("outbox_events").
+		Where(sq.Eq{"status": entity.EventPending}).
+		Where(sq.LtOrEq{"next_attempt_at": now}).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var rows []model.Event
+	if err = tx.SelectContext(ctx, &rows, selectQuery, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch outbox events", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	updateQuery, args := sq.Update("outbox_events").
+		Set("next_attempt_at", now.Add(lease)).
+		Where(sq.Eq{"id": ids}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, updateQuery, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to claim outbox events", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	events := make([]entity.Event, len(rows))
+	for i, row := range rows {
+		events[i] = model.ConvertEventToEntity(row)
+	}
+
+	return events, nil
+}
+
+// FinishEvent сохраняет результат раздачи события: кто из подписчиков его обработал и когда повторить
+func (r *OutboxRepository) FinishEvent(ctx context.Context, event entity.Event) error {
+	var lastError *string
+	if event.LastError != "" {
+		lastError = &event.LastError
+	}
+
+	query, args := sq.Update("outbox_events").
+		Set("status", event.Status).
+		Set("completed", strings.Join(event.Completed, " ")).
+		Set("attempts", event.Attempts).
+		Set("next_attempt_at", event.NextAttemptAt).
+		Set("last_error", lastError).
+		Set("processed_at", event.ProcessedAt).
+		Where(sq.Eq{"id": event.ID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update outbox event", err)
+	}
+
+	return nil
+}
+
+// DeleteProcessedEvents удаляет события, обработанные всеми подписчиками раньше before
+func (r *OutboxRepository) DeleteProcessedEvents(ctx context.Context, before time.Time) (int64, error) {
+	query, args := sq.Delete("outbox_events").
+		Where(sq.Eq{"status": entity.EventProcessed}).
+		Where(sq.Lt{"processed_at": before}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to delete processed outbox events", err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to delete processed outbox events", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+type PermissionRepository struct {
+	db *sqlx.DB
+}
+
+func NewPermissionRepository(db *sqlx.DB) *PermissionRepository {
+	return &PermissionRepository{db: db}
+}
+
+func (r *PermissionRepository) SelectUserPermissions(
+	ctx context.Context,
+	userID uint,
+) ([]entity.Permission, error) {
+	query, args := sq.Select("permission").
+		From("user_permissions").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("permission").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var permissions []entity.Permission
+	if err := r.db.SelectContext(ctx, &permissions, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch user permissions", err)
+	}
+
+	return permissions, nil
+}
+
+// InsertUserPermission выдает право пользователю, повторная выдача ничего не меняет
+func (r *PermissionRepository) InsertUserPermission(
+	ctx context.Context,
+	userID uint,
+	permission entity.Permission,
+	grantedBy uint,
+) error {
+	query, args := sq.Insert("user_permissions").
+		Columns("user_id", "permission", "granted_by").
+		Values(userID, permission, grantedBy).
+		Suffix("ON CONFLICT (user_id, permission) DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return apperror.ErrUserNotFound
+		}
+		return apperror.New(apperror.DatabaseError, "failed to grant permission", err)
+	}
+
+	return nil
+}
+
+func (r *PermissionRepository) DeleteUserPermission(
+	ctx context.Context,
+	userID uint,
+	permission entity.Permission,
+) error {
+	query, args := sq.Delete("user_permissions").
+		Where(sq.Eq{"user_id": userID, "permission": permission}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to revoke permission", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return apperror.ErrPermissionNotGranted
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+)
+
+// GetPriceHistory получает изменения цены продукта за период [from, to).
+// Для каждого магазина добавляется последнее изменение до from, чтобы была известна цена на начало периода.
+func (r *ProductRepository) GetPriceHistory(
+	ctx context.Context,
+	productID int,
+	shopID *int,
+	from, to time.Time,
+) ([]entity.PricePoint, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	lastBeforeFrom := sq.
+		Select("DISTINCT ON (shop_id) id").
+		From("price_history").
+		Where(sq.Eq{"product_id": productID}).
+		Where(sq.Lt{"changed_at": from}).
+		OrderBy("shop_id", "changed_at DESC", "id DESC")
+
+	queryBuilder := psql.
+		Select(
+			"ph.product_id",
+			"ph.shop_id",
+			"s.name AS shop_name",
+			"CAST(ph.price * 100 AS BIGINT) AS price",
+			"ph.currency",
+			"ph.changed_at",
+		).
+		From("price_history ph").
+		Join("shops s ON s.id = ph.shop_id").
+		Where(sq.Eq{"ph.product_id": productID}).
+		Where(sq.Lt{"ph.changed_at": to}).
+		Where(sq.Or{
+			sq.GtOrEq{"ph.changed_at": from},
+			sq.Expr("ph.id IN (?)", lastBeforeFrom),
+		}).
+		OrderBy("ph.shop_id", "ph.changed_at", "ph.id")
+
+	if shopID != nil {
+		queryBuilder = queryBuilder.Where(sq.Eq{"ph.shop_id": *shopID})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to build price history query", err)
+	}
+
+	var points []model.PricePoint
+	if err := r.Db.SelectContext(ctx, &points, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch price history", err)
+	}
+
+	result := make([]entity.PricePoint, len(points))
+	for i, p := range points {
+		result[i] = model.ConvertPricePointToEntity(p)
+	}
+
+	return result, nil
+}
@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
-	"strconv"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 
@@ -21,6 +19,46 @@ import (
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 )
 
+// subcategoriesCondition ограничивает выборку категорией и всеми её потомками по nested set
+const subcategoriesCondition = `p.category_id IN (
+	SELECT d.id FROM categories root
+	JOIN categories d ON d.lft BETWEEN root.lft AND root.rgt
+	WHERE root.id = ?)`
+
+// applyProductFilter добавляет условия фильтра к выборке из products p с LEFT JOIN shop_inventory si
+func applyProductFilter(selectBuilder sq.SelectBuilder, filter model.ProductFilter) sq.SelectBuilder {
+	if filter.CategoryID != nil {
+		selectBuilder = selectBuilder.Where(subcategoriesCondition, *filter.CategoryID)
+	}
+
+	if filter.MinPrice != nil {
+		selectBuilder = selectBuilder.Where(
+			sq.Expr("CAST(si.price * 100 AS BIGINT) >= ?", *filter.MinPrice),
+		)
+	}
+	if filter.MaxPrice != nil {
+		selectBuilder = selectBuilder.Where(
+			sq.Expr("CAST(si.price * 100 AS BIGINT) <= ?", *filter.MaxPrice),
+		)
+	}
+	if filter.ShopID != nil {
+		selectBuilder = selectBuilder.Where(sq.Eq{"si.shop_id": *filter.ShopID})
+	}
+	if filter.Name != nil {
+		selectBuilder = selectBuilder.Where(sq.ILike{"p.name": "%" + *filter.Name + "%"})
+	}
+
+	if len(filter.Attributes) > 0 {
+		selectBuilder = selectBuilder.Join("product_attributes pa ON p.id = pa.product_id")
+		// Имя атрибута передается параметром: фильтры сохраняются в поисках и приходят от пользователей
+		for attr, val := range filter.Attributes {
+			selectBuilder = selectBuilder.Where(sq.Expr("pa.attributes ->> ? = ?", attr, val))
+		}
+	}
+
+	return selectBuilder
+}
+
 type ProductRepository struct {
 	Db *sqlx.DB
 }
@@ -61,13 +99,6 @@ func (r *ProductRepository) GetFilteredProducts(
 	ctx context.Context,
 	filter model.ProductFilter,
 	limit, offset int) ([]entity.Product, error) {
-	args := []interface{}{}
-	categoryID := 0
-	if filter.CategoryID != nil {
-		categoryID = *filter.CategoryID
-	}
-	args = append(args, categoryID)
-
 	selectBuilder := sq.StatementBuilder.
 		PlaceholderFormat(sq.Dollar).
 		Select("DISTINCT ON (p.id) p.*").
@@ -75,65 +106,21 @@ func (r *ProductRepository) GetFilteredProducts(
 		LeftJoin("shop_inventory si ON si.product_id = p.id").
 		OrderBy("p.id")
 
-	if filter.CategoryID != nil {
-		selectBuilder = selectBuilder.Where("p.category_id IN (SELECT id FROM subcategories)")
-	}
-
-	if filter.MinPrice != nil {
-		selectBuilder = selectBuilder.Where(
-			sq.Expr("CAST(si.price * 100 AS BIGINT) >= ?", *filter.MinPrice),
-		)
-	}
-	if filter.MaxPrice != nil {
-		selectBuilder = selectBuilder.Where(
-			sq.Expr("CAST(si.price * 100 AS BIGINT) <= ?", *filter.MaxPrice),
-		)
-	}
-	if filter.ShopID != nil {
-		selectBuilder = selectBuilder.Where(sq.Eq{"si.shop_id": *filter.ShopID})
-	}
-	if filter.Name != nil {
-		selectBuilder = selectBuilder.Where(sq.ILike{"p.name": "%" + *filter.Name + "%"})
-	}
-
-	if len(filter.Attributes) > 0 {
-		selectBuilder = selectBuilder.Join("product_attributes pa ON p.id = pa.product_id")
-		for attr, val := range filter.Attributes {
-			condition := fmt.Sprintf("pa.attributes ->> '%s' = ?", attr)
-			strVal := fmt.Sprintf("%v", val)
-			selectBuilder = selectBuilder.Where(sq.Expr(condition, strVal))
-		}
-	}
+	selectBuilder = applyProductFilter(selectBuilder, filter)
 
 	selectSQL, queryArgs, err := selectBuilder.ToSql()
 	if err != nil {
-		fmt.Println("Ошибка в билде запроса")
 		return nil, apperror.New(apperror.DatabaseError, "failed to build SQL", err)
 	}
 
-	selectSQL = shiftPlaceholders(selectSQL, 1)
-
-	recursivePart := `
-		WITH RECURSIVE subcategories AS (
-			SELECT id FROM categories WHERE id = $1
-			UNION ALL
-			SELECT c.id FROM categories c
-			JOIN subcategories sc ON c.parent_id = sc.id
-		)
-	`
-
 	limitStr := fmt.Sprintf(" LIMIT %d ", limit)
 	offsetStr := fmt.Sprintf("OFFSET %d", offset)
 
-	fullSQL := recursivePart + selectSQL + limitStr + offsetStr
-
-	args = append(args, queryArgs...)
+	fullSQL := selectSQL + limitStr + offsetStr
 
 	var productModels []model.Product
-	err = r.Db.SelectContext(ctx, &productModels, fullSQL, args...)
+	err = r.Db.SelectContext(ctx, &productModels, fullSQL, queryArgs...)
 	if err != nil {
-		fmt.Println(fullSQL)
-		fmt.Println(args...)
 		return nil, apperror.New(apperror.DatabaseError, "failed to fetch filtered products", err)
 	}
 	products := make([]entity.Product, len(productModels))
@@ -146,76 +133,22 @@ func (r *ProductRepository) GetFilteredProducts(
 
 func (r *ProductRepository) GetFilteredProductsCount(ctx context.Context,
 	filter model.ProductFilter) (int, error) {
-	args := []interface{}{}
-	categoryID := 0
-	if filter.CategoryID != nil {
-		categoryID = *filter.CategoryID
-	}
-	args = append(args, categoryID)
-
 	selectBuilder := sq.StatementBuilder.
 		PlaceholderFormat(sq.Dollar).
 		Select("COUNT(DISTINCT p.id)").
 		From("products p").
 		LeftJoin("shop_inventory si ON si.product_id = p.id")
 
-	if filter.CategoryID != nil {
-		selectBuilder = selectBuilder.Where("p.category_id IN (SELECT id FROM subcategories)")
-	}
-
-	if filter.MinPrice != nil {
-		selectBuilder = selectBuilder.Where(
-			sq.Expr("CAST(si.price * 100 AS BIGINT) >= ?", *filter.MinPrice),
-		)
-	}
-	if filter.MaxPrice != nil {
-		selectBuilder = selectBuilder.Where(
-			sq.Expr("CAST(si.price * 100 AS BIGINT) <= ?", *filter.MaxPrice),
-		)
-	}
-	if filter.ShopID != nil {
-		selectBuilder = selectBuilder.Where(sq.Eq{"si.shop_id": *filter.ShopID})
-	}
-	if filter.Name != nil {
-		selectBuilder = selectBuilder.Where(sq.ILike{"p.name": "%" + *filter.Name + "%"})
-	}
-
-	if len(filter.Attributes) > 0 {
-		selectBuilder = selectBuilder.Join("product_attributes pa ON p.id = pa.product_id")
-		for attr, val := range filter.Attributes {
-			condition := fmt.Sprintf("pa.attributes ->> '%s' = ?", attr)
-			strVal := fmt.Sprintf("%v", val)
-			selectBuilder = selectBuilder.Where(sq.Expr(condition, strVal))
-		}
-	}
+	selectBuilder = applyProductFilter(selectBuilder, filter)
 
 	selectSQL, queryArgs, err := selectBuilder.ToSql()
 	if err != nil {
-		fmt.Println("Ошибка в билде запроса")
 		return 0, apperror.New(apperror.DatabaseError, "failed to build SQL", err)
 	}
 
-	selectSQL = shiftPlaceholders(selectSQL, 1)
-
-	recursivePart := `
-		WITH RECURSIVE subcategories AS (
-			SELECT id FROM categories WHERE id = $1
-			UNION ALL
-			SELECT c.id FROM categories c
-			JOIN subcategories sc ON c.parent_id = sc.id
-		)
-	`
-
-	fullSQL := recursivePart + selectSQL
-
-	args = append(args, queryArgs...)
-
 	var count int
-	err = r.Db.GetContext(ctx, &count, fullSQL, args...)
+	err = r.Db.GetContext(ctx, &count, selectSQL, queryArgs...)
 	if err != nil {
-		fmt.Println("Ошибка при запросе количества")
-		fmt.Println(fullSQL)
-		fmt.Println(args...)
 		return 0, apperror.New(apperror.DatabaseError, "failed to fetch filtered products", err)
 	}
 	return count, nil
@@ -330,10 +263,209 @@ func (r *ProductRepository) GetAverageRatingByProductID(ctx context.Context,
 	return avg, count, nil
 }
 
-func shiftPlaceholders(sql string, offset int) string {
-	re := regexp.MustCompile(`\$(\d+)`)
-	return re.ReplaceAllStringFunc(sql, func(match string) string {
-		num, _ := strconv.Atoi(match[1:])
-		return "$" + strconv.Itoa(num+offset)
-	})
+// GetShopPricesByProductIDs получает цены продуктов во всех магазинах одним запросом
+// вместе с рейтингом продавца. Сначала идут доступные предложения, затем по возрастанию цены.
+func (r *ProductRepository) GetShopPricesByProductIDs(ctx context.Context,
+	productIDs []int) ([]entity.ShopPrice, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	queryBuilder := psql.
+		Select(
+			"si.product_id",
+			"si.shop_id",
+			"s.name AS shop_name",
+			"s.user_id AS seller_id",
+			"CAST(si.price * 100 AS BIGINT) AS price",
+			"si.currency",
+			"si.is_available",
+			"COALESCE(sr.average, 0) AS seller_rating",
+			"COALESCE(sr.count, 0) AS seller_reviews_count",
+		).
+		From("shop_inventory si").
+		Join("shops s ON s.id = si.shop_id").
+		LeftJoin("(SELECT seller_id, AVG(rating) average, COUNT(*) count "+
+			"FROM seller_reviews GROUP BY seller_id) sr ON sr.seller_id = s.user_id").
+		Where(sq.Eq{"si.product_id": productIDs}).
+		OrderBy("si.product_id", "si.is_available DESC", "si.price")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to build shop prices query", err)
+	}
+
+	var prices []model.ShopPrice
+	if err := r.Db.SelectContext(ctx, &prices, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch shop prices", err)
+	}
+
+	result := make([]entity.ShopPrice, len(prices))
+	for i, p := range prices {
+		result[i] = model.ConvertShopPriceToEntity(p)
+	}
+
+	return result, nil
+}
+
+// GetProductsByIDs получает продукты по списку ID одним запросом, отсутствующие ID пропускаются
+func (r *ProductRepository) GetProductsByIDs(ctx context.Context, productIDs []int) ([]entity.Product, error) {
+	query, args, err := sq.StatementBuilder.
+		PlaceholderFormat(sq.Dollar).
+		Select("id", "name", "description", "category_id").
+		From("products").
+		Where(sq.Eq{"id": productIDs}).
+		ToSql()
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to build SQL query", err)
+	}
+
+	var productModels []model.Product
+	if err := r.Db.SelectContext(ctx, &productModels, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch products", err)
+	}
+
+	products := make([]entity.Product, len(productModels))
+	for i, pm := range productModels {
+		products[i] = model.ConvertProductToEntity(pm)
+	}
+
+	return products, nil
+}
+
+// GetAttributesByProductIDs получает атрибуты продуктов одним запросом.
+// Как и в GetAttributesByID, для продукта берется одна запись атрибутов.
+func (r *ProductRepository) GetAttributesByProductIDs(ctx context.Context,
+	productIDs []int) (map[int]map[string]interface{}, error) {
+	query, args, err := sq.StatementBuilder.
+		PlaceholderFormat(sq.Dollar).
+		Select("DISTINCT ON (product_id) product_id", "attributes").
+		From("product_attributes").
+		Where(sq.Eq{"product_id": productIDs}).
+		OrderBy("product_id").
+		ToSql()
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to build query", err)
+	}
+
+	var rows []struct {
+		ProductID  int    `db:"product_id"`
+		Attributes []byte `db:"attributes"`
+	}
+	if err := r.Db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch product attributes", err)
+	}
+
+	attributes := make(map[int]map[string]interface{}, len(rows))
+	for _, row := range rows {
+		var attrs map[string]interface{}
+		if err := json.Unmarshal(row.Attributes, &attrs); err != nil {
+			return nil, apperror.New(apperror.DatabaseError, "failed to unmarshal product attributes", err)
+		}
+		attributes[row.ProductID] = attrs
+	}
+
+	return attributes, nil
+}
+
+// GetPriceRangesByProductIDs получает минимальные и максимальные цены продуктов одним запросом.
+// Продуктов без предложений в результате нет.
+func (r *ProductRepository) GetPriceRangesByProductIDs(ctx context.Context,
+	productIDs []int) (map[int]model.PriceRange, error) {
+	query, args, err := sq.StatementBuilder.
+		PlaceholderFormat(sq.Dollar).
+		Select(
+			"product_id",
+			"CAST(MIN(price) * 100 AS BIGINT) AS min",
+			"CAST(MAX(price) * 100 AS BIGINT) AS max",
+		).
+		From("shop_inventory").
+		Where(sq.Eq{"product_id": productIDs}).
+		GroupBy("product_id").
+		ToSql()
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to build price range query", err)
+	}
+
+	var rows []struct {
+		ProductID int `db:"product_id"`
+		Min       int `db:"min"`
+		Max       int `db:"max"`
+	}
+	if err := r.Db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to calculate min/max price", err)
+	}
+
+	ranges := make(map[int]model.PriceRange, len(rows))
+	for _, row := range rows {
+		ranges[row.ProductID] = model.PriceRange{Min: row.Min, Max: row.Max}
+	}
+
+	return ranges, nil
+}
+
+// GetReviewStatsByProductIDs получает средние оценки и количество отзывов продуктов одним запросом.
+// Продуктов без отзывов в результате нет.
+func (r *ProductRepository) GetReviewStatsByProductIDs(ctx context.Context,
+	productIDs []int) (map[int]model.ReviewStats, error) {
+	query, args, err := sq.StatementBuilder.
+		PlaceholderFormat(sq.Dollar).
+		Select("product_id", "AVG(rating) average", "COUNT(*) count").
+		From("product_reviews").
+		Where(sq.Eq{"product_id": productIDs}).
+		GroupBy("product_id").
+		ToSql()
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to build query", err)
+	}
+
+	var rows []struct {
+		ProductID int     `db:"product_id"`
+		Average   float64 `db:"average"`
+		Count     int     `db:"count"`
+	}
+	if err := r.Db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to calculate average rating/count of reviews", err)
+	}
+
+	stats := make(map[int]model.ReviewStats, len(rows))
+	for _, row := range rows {
+		stats[row.ProductID] = model.ReviewStats{Average: row.Average, Count: row.Count}
+	}
+
+	return stats, nil
+}
+
+// GetProductsAfterID получает продукты, подходящие под фильтр, с ID больше afterID.
+// ID продуктов растут, поэтому так находятся продукты, добавленные после предыдущей проверки.
+func (r *ProductRepository) GetProductsAfterID(
+	ctx context.Context,
+	filter model.ProductFilter,
+	afterID, limit int,
+) ([]entity.Product, error) {
+	selectBuilder := sq.StatementBuilder.
+		PlaceholderFormat(sq.Dollar).
+		Select("DISTINCT ON (p.id) p.id, p.name, p.description, p.category_id").
+		From("products p").
+		LeftJoin("shop_inventory si ON si.product_id = p.id").
+		Where(sq.Gt{"p.id": afterID}).
+		OrderBy("p.id").
+		Limit(uint64(limit))
+
+	selectBuilder = applyProductFilter(selectBuilder, filter)
+
+	query, args, err := selectBuilder.ToSql()
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to build SQL", err)
+	}
+
+	var productModels []model.Product
+	if err := r.Db.SelectContext(ctx, &productModels, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch new products", err)
+	}
+
+	products := make([]entity.Product, len(productModels))
+	for i, pm := range productModels {
+		products[i] = model.ConvertProductToEntity(pm)
+	}
+
+	return products, nil
 }
@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/EM-Stawberry/Stawberry/internal/repository"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
 	"github.com/jmoiron/sqlx"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -186,4 +188,98 @@ var _ = Describe("ProductRepository", func() {
 			Expect(count).To(Equal(0))
 		})
 	})
+
+	Describe("GetShopPricesByProductIDs", func() {
+		It("should return prices with shop names and seller ratings", func() {
+			rows := sqlmock.NewRows([]string{"product_id", "shop_id", "shop_name", "seller_id", "price",
+				"currency", "is_available", "seller_rating", "seller_reviews_count"}).
+				AddRow(1, 10, "Shop A", 5, 99900, "RUB", true, 4.5, 12).
+				AddRow(2, 11, "Shop B", 6, 150000, "RUB", false, 0, 0)
+
+			mock.ExpectQuery(`SELECT si.product_id, si.shop_id, s.name AS shop_name, s.user_id AS seller_id, `+
+				`CAST\(si.price \* 100 AS BIGINT\) AS price, si.currency, si.is_available, `+
+				`COALESCE\(sr.average, 0\) AS seller_rating, COALESCE\(sr.count, 0\) AS seller_reviews_count `+
+				`FROM shop_inventory si JOIN shops s ON s.id = si.shop_id `+
+				`LEFT JOIN \(SELECT seller_id, AVG\(rating\) average, COUNT\(\*\) count `+
+				`FROM seller_reviews GROUP BY seller_id\) sr ON sr.seller_id = s.user_id `+
+				`WHERE si.product_id IN \(\$1,\$2\) ORDER BY si.product_id, si.is_available DESC, si.price`).
+				WithArgs(1, 2).
+				WillReturnRows(rows)
+
+			prices, err := repo.GetShopPricesByProductIDs(ctx, []int{1, 2})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(prices).To(HaveLen(2))
+			Expect(prices[0].ShopName).To(Equal("Shop A"))
+			Expect(prices[0].Price).To(Equal(99900))
+			Expect(prices[0].SellerRating).To(Equal(4.5))
+			Expect(prices[0].SellerReviewsCount).To(Equal(12))
+			Expect(prices[1].IsAvailable).To(BeFalse())
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+
+		It("should return error when query fails", func() {
+			mock.ExpectQuery(`SELECT si.product_id`).
+				WillReturnError(sql.ErrConnDone)
+
+			prices, err := repo.GetShopPricesByProductIDs(ctx, []int{1})
+
+			Expect(err).To(HaveOccurred())
+			Expect(prices).To(BeNil())
+		})
+	})
+
+	Describe("GetPriceHistory", func() {
+		It("should return price points including the last one before period", func() {
+			from := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+			to := from.AddDate(0, 0, 7)
+			shopID := 10
+			rows := sqlmock.NewRows([]string{"product_id", "shop_id", "shop_name", "price", "currency", "changed_at"}).
+				AddRow(1, 10, "Shop A", 100000, "RUB", from.AddDate(0, 0, -3)).
+				AddRow(1, 10, "Shop A", 90000, "RUB", from.AddDate(0, 0, 2))
+
+			mock.ExpectQuery(`SELECT ph.product_id, ph.shop_id, s.name AS shop_name, `+
+				`CAST\(ph.price \* 100 AS BIGINT\) AS price, ph.currency, ph.changed_at `+
+				`FROM price_history ph JOIN shops s ON s.id = ph.shop_id `+
+				`WHERE ph.product_id = \$1 AND ph.changed_at < \$2 AND \(ph.changed_at >= \$3 OR ph.id IN `+
+				`\(SELECT DISTINCT ON \(shop_id\) id FROM price_history WHERE product_id = \$4 AND changed_at < \$5 `+
+				`ORDER BY shop_id, changed_at DESC, id DESC\)\) AND ph.shop_id = \$6 `+
+				`ORDER BY ph.shop_id, ph.changed_at, ph.id`).
+				WithArgs(1, to, from, 1, from, shopID).
+				WillReturnRows(rows)
+
+			points, err := repo.GetPriceHistory(ctx, 1, &shopID, from, to)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(points).To(HaveLen(2))
+			Expect(points[0].Price).To(Equal(100000))
+			Expect(points[1].ShopName).To(Equal("Shop A"))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
+	Describe("GetProductsAfterID", func() {
+		It("should pass attribute filter as parameters", func() {
+			name := "phone"
+			filter := model.ProductFilter{
+				Name:       &name,
+				Attributes: map[string]string{"color": "red"},
+			}
+			rows := sqlmock.NewRows([]string{"id", "name", "description", "category_id"}).
+				AddRow(11, "Red phone", "desc", 3)
+
+			mock.ExpectQuery(`SELECT DISTINCT ON \(p.id\) p.id, p.name, p.description, p.category_id `+
+				`FROM products p LEFT JOIN shop_inventory si ON si.product_id = p.id `+
+				`JOIN product_attributes pa ON p.id = pa.product_id `+
+				`WHERE p.id > \$1 AND p.name ILIKE \$2 AND pa.attributes ->> \$3 = \$4 ORDER BY p.id LIMIT 50`).
+				WithArgs(10, "%phone%", "color", "red").
+				WillReturnRows(rows)
+
+			products, err := repo.GetProductsAfterID(ctx, filter, 10, 50)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(products).To(HaveLen(1))
+			Expect(products[0].ID).To(Equal(11))
+			Expect(mock.ExpectationsWereMet()).To(Succeed())
+		})
+	})
 })
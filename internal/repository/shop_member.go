@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type ShopMemberRepository struct {
+	db *sqlx.DB
+}
+
+func NewShopMemberRepository(db *sqlx.DB) *ShopMemberRepository {
+	return &ShopMemberRepository{db: db}
+}
+
+func (r *ShopMemberRepository) GetShopName(ctx context.Context, shopID uint) (string, error) {
+	query, args := sq.Select("name").
+		From("shops").
+		Where(sq.Eq{"id": shopID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var name string
+	if err := r.db.GetContext(ctx, &name, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperror.ErrStoreNotFound
+		}
+		return "", apperror.New(apperror.DatabaseError, "failed to fetch shop", err)
+	}
+
+	return name, nil
+}
+
+func (r *ShopMemberRepository) GetMemberRole(ctx context.Context, shopID, userID uint) (entity.ShopRole, error) {
+	query, args := sq.Select("role").
+		From("shop_members").
+		Where(sq.Eq{"shop_id": shopID, "user_id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var role string
+	if err := r.db.GetContext(ctx, &role, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", apperror.ErrShopMemberNotFound
+		}
+		return "", apperror.New(apperror.DatabaseError, "failed to fetch shop member", err)
+	}
+
+	return entity.ShopRole(role), nil
+}
+
+func (r *ShopMemberRepository) SelectMembers(ctx context.Context, shopID uint) ([]entity.ShopMember, error) {
+	query, args := sq.Select(
+		"sm.shop_id",
+		"sm.user_id",
+		"u.name AS user_name",
+		"u.email AS user_email",
+		"sm.role",
+		"sm.created_at",
+	).
+		From("shop_members sm").
+		Join("users u ON u.id = sm.user_id").
+		Where(sq.Eq{"sm.shop_id": shopID}).
+		OrderBy("sm.role", "sm.created_at").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var members []model.ShopMember
+	if err := r.db.SelectContext(ctx, &members, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch shop members", err)
+	}
+
+	result := make([]entity.ShopMember, len(members))
+	for i, member := range members {
+		result[i] = model.ConvertShopMemberToEntity(member)
+	}
+
+	return result, nil
+}
+
+func (r *ShopMemberRepository) InsertInvitation(ctx context.Context, invitation entity.ShopInvitation) (uint, error) {
+	query, args := sq.Insert("shop_invitations").
+		Columns("shop_id", "email", "role", "token_hash", "invited_by", "expires_at").
+		Values(invitation.ShopID, invitation.Email, invitation.Role, invitation.TokenHash,
+			invitation.InvitedBy, invitation.ExpiresAt).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var id uint
+	if err := r.db.GetContext(ctx, &id, query, args...); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to save invitation", err)
+	}
+
+	return id, nil
+}
+
+func (r *ShopMemberRepository) GetInvitationByTokenHash(
+	ctx context.Context,
+	tokenHash string,
+) (entity.ShopInvitation, error) {
+	query, args := sq.Select("id", "shop_id", "email", "role", "token_hash", "invited_by",
+		"expires_at", "accepted_at", "created_at").
+		From("shop_invitations").
+		Where(sq.Eq{"token_hash": tokenHash}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var invitation model.ShopInvitation
+	if err := r.db.GetContext(ctx, &invitation, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.ShopInvitation{}, apperror.ErrShopInvitationNotFound
+		}
+		return entity.ShopInvitation{}, apperror.New(apperror.DatabaseError, "failed to fetch invitation", err)
+	}
+
+	return model.ConvertShopInvitationToEntity(invitation), nil
+}
+
+// AcceptInvitation добавляет пользователя в магазин с ролью из приглашения.
+// Пользователь получает роль shop, чтобы работать с заявками от лица магазина.
+func (r *ShopMemberRepository) AcceptInvitation(
+	ctx context.Context,
+	invitation entity.ShopInvitation,
+	userID uint,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args := sq.Update("shop_invitations").
+		Set("accepted_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": invitation.ID, "accepted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to accept invitation", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return apperror.New(apperror.Conflict, "invitation has already been accepted", nil)
+	}
+
+	// Владелец остается владельцем, остальным роль меняется на роль из приглашения
+	query, args = sq.Insert("shop_members").
+		Columns("shop_id", "user_id", "role").
+		Values(invitation.ShopID, userID, invitation.Role).
+		Suffix("ON CONFLICT (shop_id, user_id) DO UPDATE SET role = EXCLUDED.role " +
+			"WHERE shop_members.role <> 'owner'").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to add shop member", err)
+	}
+
+	query, args = sq.Update("users").
+		Set("role", entity.RoleShop).
+		Where(sq.Eq{"id": userID, "role": entity.RoleUser}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update user role", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// DeleteMember убирает пользователя из магазина. Если он больше нигде не работает,
+// ему возвращается роль user.
+func (r *ShopMemberRepository) DeleteMember(ctx context.Context, shopID, userID uint) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args := sq.Delete("shop_members").
+		Where(sq.Eq{"shop_id": shopID, "user_id": userID}).
+		Where(sq.NotEq{"role": entity.ShopRoleOwner}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to delete shop member", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return apperror.ErrShopMemberNotFound
+	}
+
+	query, args = sq.Update("users").
+		Set("role", entity.RoleUser).
+		Where(sq.Eq{"id": userID, "role": entity.RoleShop}).
+		Where("NOT EXISTS (SELECT 1 FROM shop_members WHERE user_id = users.id)").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update user role", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
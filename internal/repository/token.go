@@ -5,16 +5,23 @@ import (
 	"database/sql"
 	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
 	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 )
 
+var tokenColumns = []string{
+	"uuid", "created_at", "expires_at", "revoked_at", "fingerprint", "user_id",
+	"last_used_at", "last_ip", "user_agent", "family_id", "replaced_by", "access_jti",
+}
+
 type TokenRepository struct {
 	db *sqlx.DB
 }
@@ -28,11 +35,7 @@ func (r *TokenRepository) InsertToken(
 	ctx context.Context,
 	token entity.RefreshToken,
 ) error {
-	stmt := sq.Insert("refresh_tokens").
-		Columns("uuid", "created_at", "expires_at", "revoked_at", "fingerprint", "user_id").
-		Values(token.UUID, token.CreatedAt, token.ExpiresAt, token.RevokedAt, token.Fingerprint, token.UserID)
-
-	query, args := stmt.PlaceholderFormat(sq.Dollar).MustSql()
+	query, args := insertTokenQuery(token)
 
 	_, err := r.db.ExecContext(ctx, query, args...)
 
@@ -47,14 +50,128 @@ func (r *TokenRepository) InsertToken(
 	return nil
 }
 
+func insertTokenQuery(token entity.RefreshToken) (string, []any) {
+	return sq.Insert("refresh_tokens").
+		Columns("uuid", "created_at", "expires_at", "revoked_at", "fingerprint", "user_id",
+			"last_used_at", "last_ip", "user_agent", "family_id", "access_jti").
+		Values(token.UUID, token.CreatedAt, token.ExpiresAt, token.RevokedAt, token.Fingerprint, token.UserID,
+			token.LastUsedAt, token.LastIP, token.UserAgent, token.FamilyID, token.AccessJTI).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+}
+
+// Rotate обменивает действующий refresh токен на новый в одной транзакции.
+// Если старый токен уже отозван или обменян, например при параллельном обновлении,
+// возвращается ErrRefreshTokenReused и новый токен не сохраняется.
+func (r *TokenRepository) Rotate(
+	ctx context.Context,
+	oldUUID uuid.UUID,
+	token entity.RefreshToken,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args := sq.Update("refresh_tokens").
+		Set("revoked_at", sq.Expr("NOW()")).
+		Set("replaced_by", token.UUID).
+		Where(sq.Eq{"uuid": oldUUID, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to revoke refresh token", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return apperror.ErrRefreshTokenReused
+	}
+
+	query, args = insertTokenQuery(token)
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to create token", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// RevokeFamily отзывает все действующие токены семьи
+func (r *TokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	query, args := sq.Update("refresh_tokens").
+		Set("revoked_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"family_id": familyID, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to revoke token family", err)
+	}
+
+	return nil
+}
+
+// GetIssuedSinceByUserID возвращает refresh токены пользователя, выпущенные после since,
+// включая отозванные. Нужен, чтобы найти еще не истекшие access токены его сессий.
+func (r *TokenRepository) GetIssuedSinceByUserID(
+	ctx context.Context,
+	userID uint,
+	since time.Time,
+) ([]entity.RefreshToken, error) {
+	return r.selectTokens(ctx, sq.Eq{"user_id": userID}, since)
+}
+
+// GetIssuedSinceByFamily возвращает токены семьи, выпущенные после since, включая отозванные
+func (r *TokenRepository) GetIssuedSinceByFamily(
+	ctx context.Context,
+	familyID uuid.UUID,
+	since time.Time,
+) ([]entity.RefreshToken, error) {
+	return r.selectTokens(ctx, sq.Eq{"family_id": familyID}, since)
+}
+
+func (r *TokenRepository) selectTokens(
+	ctx context.Context,
+	where sq.Eq,
+	since time.Time,
+) ([]entity.RefreshToken, error) {
+	query, args := sq.Select(tokenColumns...).
+		From("refresh_tokens").
+		Where(where).
+		Where(sq.Gt{"created_at": since}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var tokenModels []model.RefreshToken
+	if err := r.db.SelectContext(ctx, &tokenModels, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch tokens", err)
+	}
+
+	tokens := make([]entity.RefreshToken, len(tokenModels))
+	for i, t := range tokenModels {
+		tokens[i] = model.ConvertTokenToEntity(t)
+	}
+
+	return tokens, nil
+}
+
 // GetActivesTokenByUserID получает список активных refresh токенов пользователя по userID.
 func (r *TokenRepository) GetActivesTokenByUserID(
 	ctx context.Context,
 	userID uint,
 ) ([]entity.RefreshToken, error) {
-	stmt := sq.Select("uuid", "created_at", "expires_at", "revoked_at", "fingerprint", "user_id").
+	stmt := sq.Select(tokenColumns...).
 		From("refresh_tokens").
-		Where(sq.Eq{"user_id": userID})
+		Where(sq.Eq{"user_id": userID, "revoked_at": nil}).
+		Where("expires_at > NOW()").
+		OrderBy("last_used_at DESC")
 
 	query, args := stmt.PlaceholderFormat(sq.Dollar).MustSql()
 
@@ -117,7 +234,7 @@ func (r *TokenRepository) GetByUUID(
 ) (entity.RefreshToken, error) {
 	var tokenModel model.RefreshToken
 
-	stmt := sq.Select("uuid", "created_at", "expires_at", "revoked_at", "fingerprint", "user_id").
+	stmt := sq.Select(tokenColumns...).
 		From("refresh_tokens").
 		Where(sq.Eq{"uuid": uuid})
 
@@ -147,6 +264,9 @@ func (r *TokenRepository) Update(
 		Set("revoked_at", refresh.RevokedAt).
 		Set("fingerprint", refresh.Fingerprint).
 		Set("user_id", refresh.UserID).
+		Set("last_used_at", refresh.LastUsedAt).
+		Set("last_ip", refresh.LastIP).
+		Set("user_agent", refresh.UserAgent).
 		Where(sq.Eq{"uuid": refresh.UUID})
 
 	query, args := stmt.PlaceholderFormat(sq.Dollar).MustSql()
@@ -170,14 +290,19 @@ func (r *TokenRepository) Update(
 }
 
 // CleanExpired удаляет все отозванные и устаревшие токены пользователя за исключением
-// пяти самых последних
+// пяти самых последних. Обмененные токены живых семей не удаляются до истечения срока,
+// иначе их повторное предъявление нельзя будет распознать.
 func (r *TokenRepository) CleanExpired(ctx context.Context, userID uint, retain uint) error {
 	substmt := sq.Select("uuid").
-		From("refresh_tokens").
+		From("refresh_tokens AS t").
 		Where(sq.Eq{"user_id": userID}).
 		Where(sq.Or{
-			sq.LtOrEq{"expires_at": "now()"},
-			sq.LtOrEq{"revoked_at": "now()"},
+			sq.Expr("expires_at <= NOW()"),
+			sq.And{
+				sq.Expr("revoked_at <= NOW()"),
+				sq.Expr("NOT EXISTS (SELECT 1 FROM refresh_tokens a " +
+					"WHERE a.family_id = t.family_id AND a.revoked_at IS NULL AND a.expires_at > NOW())"),
+			},
 		}).
 		OrderBy("created_at DESC").
 		Offset(uint64(retain))
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 
 	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
 	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
@@ -15,6 +16,11 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+var userColumns = []string{
+	"id", "name", "email", "phone_number", "password_hash", "role", "email_verified_at", "banned_at",
+	"totp_secret", "totp_enabled_at", "totp_last_step",
+}
+
 type UserRepository struct {
 	db *sqlx.DB
 }
@@ -31,8 +37,8 @@ func (r *UserRepository) InsertUser(
 	userModel := model.ConvertUserFromSvc(user)
 
 	stmt := sq.Insert("users").
-		Columns("name", "email", "phone_number", "password_hash", "is_store").
-		Values(user.Name, user.Email, user.Phone, user.Password, user.IsStore).
+		Columns("name", "email", "phone_number", "password_hash").
+		Values(user.Name, user.Email, user.Phone, user.Password).
 		Suffix("RETURNING id").
 		PlaceholderFormat(sq.Dollar)
 
@@ -57,9 +63,9 @@ func (r *UserRepository) GetUser(
 ) (entity.User, error) {
 	var userModel model.User
 
-	stmt := sq.Select("id", "name", "email", "phone_number", "password_hash", "is_store").
+	stmt := sq.Select(userColumns...).
 		From("users").
-		Where(sq.Eq{"email": email}).
+		Where(sq.Eq{"email": email, "deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
 	query, args := stmt.MustSql()
@@ -82,9 +88,9 @@ func (r *UserRepository) GetUserByID(
 ) (entity.User, error) {
 	var userModel model.User
 
-	stmt := sq.Select("id", "name", "email", "phone_number", "password_hash", "is_store").
+	stmt := sq.Select(userColumns...).
 		From("users").
-		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		PlaceholderFormat(sq.Dollar)
 
 	query, args := stmt.MustSql()
@@ -99,3 +105,310 @@ func (r *UserRepository) GetUserByID(
 
 	return model.ConvertUserToEntity(userModel), nil
 }
+
+// InsertActionToken сохраняет хеш одноразового токена подтверждения почты или сброса пароля
+func (r *UserRepository) InsertActionToken(ctx context.Context, token entity.UserActionToken) error {
+	query, args := sq.Insert("user_action_tokens").
+		Columns("user_id", "purpose", "token_hash", "expires_at").
+		Values(token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to save token", err)
+	}
+
+	return nil
+}
+
+// UseActionToken гасит действующий токен и возвращает ID его владельца.
+// Остальные токены пользователя с тем же назначением тоже гасятся.
+func (r *UserRepository) UseActionToken(ctx context.Context, tokenHash, purpose string) (uint, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args := sq.Update("user_action_tokens").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"token_hash": tokenHash, "purpose": purpose, "used_at": nil}).
+		Where("expires_at > NOW()").
+		Suffix("RETURNING user_id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var userID uint
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, apperror.ErrInvalidActionToken
+		}
+		return 0, apperror.New(apperror.DatabaseError, "failed to use token", err)
+	}
+
+	query, args = sq.Update("user_action_tokens").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "purpose": purpose, "used_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to invalidate tokens", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return userID, nil
+}
+
+// SetBanned блокирует пользователя или снимает блокировку
+func (r *UserRepository) SetBanned(ctx context.Context, userID uint, banned bool) error {
+	bannedAt := sq.Expr("NULL")
+	if banned {
+		bannedAt = sq.Expr("COALESCE(banned_at, NOW())")
+	}
+
+	query, args := sq.Update("users").
+		Set("banned_at", bannedAt).
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update user ban", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return apperror.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, userID uint) error {
+	query, args := sq.Update("users").
+		Set("email_verified_at", sq.Expr("COALESCE(email_verified_at, NOW())")).
+		Where(sq.Eq{"id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to verify email", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID uint, passwordHash string) error {
+	query, args := sq.Update("users").
+		Set("password_hash", passwordHash).
+		Where(sq.Eq{"id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update password", err)
+	}
+
+	return nil
+}
+
+// ReplacePasswordHash заменяет хэш пароля, только если он не изменился с момента чтения,
+// чтобы пересчет хэша при входе не затер пароль, смененный параллельно
+func (r *UserRepository) ReplacePasswordHash(ctx context.Context, userID uint, oldHash, newHash string) error {
+	query, args := sq.Update("users").
+		Set("password_hash", newHash).
+		Where(sq.Eq{"id": userID, "password_hash": oldHash}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to replace password hash", err)
+	}
+
+	return nil
+}
+
+// UpdateProfile меняет имя и телефон пользователя. Пустые поля не меняются.
+func (r *UserRepository) UpdateProfile(ctx context.Context, userID uint, name, phone *string) error {
+	stmt := sq.Update("users").
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar)
+	if name != nil {
+		stmt = stmt.Set("name", *name)
+	}
+	if phone != nil {
+		stmt = stmt.Set("phone_number", *phone)
+	}
+
+	query, args := stmt.MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update profile", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return apperror.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateEmail меняет почту пользователя и сбрасывает ее подтверждение.
+// Неиспользованные токены подтверждения старой почты гасятся.
+func (r *UserRepository) UpdateEmail(ctx context.Context, userID uint, email string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args := sq.Update("users").
+		Set("email", email).
+		Set("email_verified_at", nil).
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return apperror.New(apperror.DuplicateError, "user with this email already exists", err)
+		}
+		return apperror.New(apperror.DatabaseError, "failed to update email", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return apperror.ErrUserNotFound
+	}
+
+	query, args = sq.Update("user_action_tokens").
+		Set("used_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "purpose": entity.TokenPurposeVerifyEmail, "used_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to invalidate tokens", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// AnonymizeUser удаляет аккаунт: стирает персональные данные, отменяет активные заявки
+// и удаляет сессии, права, членство в магазинах и списки пользователя.
+// Сама строка остается, потому что на нее ссылаются заявки и отзывы.
+// Владелец магазина удалить аккаунт не может, пока не передаст или не удалит магазины.
+func (r *UserRepository) AnonymizeUser(ctx context.Context, userID uint) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query, args := sq.Select("1").
+		From("users").
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var exists int
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&exists); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.ErrUserNotFound
+		}
+		return apperror.New(apperror.DatabaseError, "failed to lock user", err)
+	}
+
+	query, args = sq.Select().
+		Column(sq.Expr("EXISTS (SELECT 1 FROM shops WHERE user_id = ?)", userID)).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var ownsShops bool
+	if err = tx.QueryRowxContext(ctx, query, args...).Scan(&ownsShops); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to check user shops", err)
+	}
+	if ownsShops {
+		return apperror.New(apperror.Conflict, "user owns shops, transfer or delete them first", nil)
+	}
+
+	query, args = sq.Update("offers").
+		Set("status", "cancelled").
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": userID, "status": "pending"}).
+		Suffix("RETURNING " + offerColumns).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var cancelled []model.Offer
+	if err = tx.SelectContext(ctx, &cancelled, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to cancel user offers", err)
+	}
+
+	// Магазины узнают об отмене заявок так же, как при отмене покупателем
+	for _, offer := range cancelled {
+		payload := entity.OfferEvent{Offer: offer.ConvertToEntity()}
+		if err = insertOutboxEvent(ctx, tx, entity.EventOfferCancelled, userID, payload); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range []string{
+		"refresh_tokens",
+		"user_action_tokens",
+		"user_recovery_codes",
+		"identities",
+		"user_permissions",
+		"shop_members",
+		"wishlist_items",
+		"saved_searches",
+		"notifications",
+	} {
+		query, args = sq.Delete(table).
+			Where(sq.Eq{"user_id": userID}).
+			PlaceholderFormat(sq.Dollar).
+			MustSql()
+
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return apperror.New(apperror.DatabaseError, "failed to delete user data from "+table, err)
+		}
+	}
+
+	query, args = sq.Update("users").
+		Set("name", "Deleted user").
+		Set("email", fmt.Sprintf("deleted-%d@users.invalid", userID)).
+		Set("phone_number", "").
+		Set("password_hash", "").
+		Set("role", string(entity.RoleUser)).
+		Set("email_verified_at", nil).
+		Set("totp_secret", nil).
+		Set("totp_enabled_at", nil).
+		Set("deleted_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to anonymize user", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,386 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var webhookEndpointColumns = []string{
+	"id",
+	"shop_id",
+	"created_by",
+	"url",
+	"secret",
+	"events",
+	"active",
+	"created_at",
+}
+
+var webhookDeliveryColumns = []string{
+	"d.id",
+	"d.endpoint_id",
+	"d.event_id",
+	"d.event",
+	"d.payload",
+	"d.status",
+	"d.attempts",
+	"d.next_attempt_at",
+	"d.last_status_code",
+	"d.last_error",
+	"d.delivered_at",
+	"d.created_at",
+}
+
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) InsertWebhookEndpoint(ctx context.Context, endpoint entity.WebhookEndpoint) (uint, error) {
+	query, args := sq.Insert("webhook_endpoints").
+		Columns("shop_id", "created_by", "url", "secret", "events", "active").
+		Values(endpoint.ShopID, endpoint.CreatedBy, endpoint.URL, endpoint.Secret,
+			model.JoinWebhookEvents(endpoint.Events), endpoint.Active).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var id uint
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to insert webhook", err)
+	}
+
+	return id, nil
+}
+
+func (r *WebhookRepository) SelectWebhookEndpoints(ctx context.Context, shopID uint) ([]entity.WebhookEndpoint, error) {
+	query, args := sq.Select(webhookEndpointColumns...).
+		From("webhook_endpoints").
+		Where(sq.Eq{"shop_id": shopID}).
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var rows []model.WebhookEndpoint
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch webhooks", err)
+	}
+
+	endpoints := make([]entity.WebhookEndpoint, len(rows))
+	for i, row := range rows {
+		endpoints[i] = model.ConvertWebhookEndpointToEntity(row)
+	}
+
+	return endpoints, nil
+}
+
+func (r *WebhookRepository) GetWebhookEndpoint(
+	ctx context.Context,
+	shopID, endpointID uint,
+) (entity.WebhookEndpoint, error) {
+	query, args := sq.Select(webhookEndpointColumns...).
+		From("webhook_endpoints").
+		Where(sq.Eq{"id": endpointID, "shop_id": shopID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var row model.WebhookEndpoint
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WebhookEndpoint{}, apperror.ErrWebhookNotFound
+		}
+		return entity.WebhookEndpoint{}, apperror.New(apperror.DatabaseError, "failed to fetch webhook", err)
+	}
+
+	return model.ConvertWebhookEndpointToEntity(row), nil
+}
+
+// DeleteWebhookEndpoint удаляет адрес вместе с его доставками и журналом
+func (r *WebhookRepository) DeleteWebhookEndpoint(ctx context.Context, shopID, endpointID uint) error {
+	query, args := sq.Delete("webhook_endpoints").
+		Where(sq.Eq{"id": endpointID, "shop_id": shopID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to delete webhook", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to delete webhook", err)
+	}
+	if affected == 0 {
+		return apperror.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// InsertWebhookDeliveries ставит доставки в очередь одним запросом
+func (r *WebhookRepository) InsertWebhookDeliveries(ctx context.Context, deliveries []entity.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	builder := sq.Insert("webhook_deliveries").
+		Columns("endpoint_id", "event_id", "event", "payload", "status", "next_attempt_at")
+	for _, d := range deliveries {
+		builder = builder.Values(d.EndpointID, d.EventID, d.Event, string(d.Payload), d.Status, d.NextAttemptAt)
+	}
+
+	query, args := builder.PlaceholderFormat(sq.Dollar).MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to enqueue webhook deliveries", err)
+	}
+
+	return nil
+}
+
+// SelectWebhookDeliveries возвращает доставки адреса, новые первыми, и их общее количество
+func (r *WebhookRepository) SelectWebhookDeliveries(
+	ctx context.Context,
+	endpointID uint,
+	limit, offset int,
+) ([]entity.WebhookDelivery, int, error) {
+	query, args := sq.Select(append(webhookDeliveryColumns, "COUNT(*) OVER() AS total_count")...).
+		From("webhook_deliveries d").
+		Where(sq.Eq{"d.endpoint_id": endpointID}).
+		OrderBy("d.created_at DESC", "d.id DESC").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var rows []model.WebhookDeliveryWithCount
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, apperror.New(apperror.DatabaseError, "failed to fetch webhook deliveries", err)
+	}
+
+	if len(rows) == 0 {
+		return []entity.WebhookDelivery{}, 0, nil
+	}
+
+	deliveries := make([]entity.WebhookDelivery, len(rows))
+	for i, row := range rows {
+		deliveries[i] = model.ConvertWebhookDeliveryToEntity(row.WebhookDelivery)
+	}
+
+	return deliveries, rows[0].TotalCount, nil
+}
+
+func (r *WebhookRepository) GetWebhookDelivery(
+	ctx context.Context,
+	endpointID, deliveryID uint,
+) (entity.WebhookDelivery, error) {
+	query, args := sq.Select(webhookDeliveryColumns...).
+		From("webhook_deliveries d").
+		Where(sq.Eq{"d.id": deliveryID, "d.endpoint_id": endpointID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var row model.WebhookDelivery
+	if err := r.db.GetContext(ctx, &row, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.WebhookDelivery{}, apperror.ErrWebhookDeliveryNotFound
+		}
+		return entity.WebhookDelivery{}, apperror.New(apperror.DatabaseError, "failed to fetch webhook delivery", err)
+	}
+
+	return model.ConvertWebhookDeliveryToEntity(row), nil
+}
+
+// SelectWebhookAttempts возвращает журнал попыток доставки по порядку
+func (r *WebhookRepository) SelectWebhookAttempts(
+	ctx context.Context,
+	deliveryID uint,
+) ([]entity.WebhookAttempt, error) {
+	query, args := sq.Select("id", "delivery_id", "status_code", "error", "response_body",
+		"duration_ms", "attempted_at").
+		From("webhook_delivery_attempts").
+		Where(sq.Eq{"delivery_id": deliveryID}).
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var rows []model.WebhookAttempt
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch webhook attempts", err)
+	}
+
+	attempts := make([]entity.WebhookAttempt, len(rows))
+	for i, row := range rows {
+		attempts[i] = model.ConvertWebhookAttemptToEntity(row)
+	}
+
+	return attempts, nil
+}
+
+// RequeueWebhookDelivery возвращает завершенную доставку в очередь с новым счетчиком попыток.
+// Доставка, которая еще в очереди, не трогается, чтобы не отправить ее дважды одновременно.
+func (r *WebhookRepository) RequeueWebhookDelivery(ctx context.Context, deliveryID uint) error {
+	query, args := sq.Update("webhook_deliveries").
+		Set("status", entity.WebhookDeliveryPending).
+		Set("attempts", 0).
+		Set("next_attempt_at", sq.Expr("NOW()")).
+		Set("delivered_at", nil).
+		Where(sq.Eq{"id": deliveryID}).
+		Where(sq.NotEq{"status": entity.WebhookDeliveryPending}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to requeue webhook delivery", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to requeue webhook delivery", err)
+	}
+	if affected == 0 {
+		return apperror.New(apperror.Conflict, "webhook delivery is already queued", nil)
+	}
+
+	return nil
+}
+
+// ClaimWebhookDeliveries берет в работу доставки, время которых пришло, и откладывает их на lease,
+// чтобы другие экземпляры приложения их не взяли. Если экземпляр упадет во время отправки,
+// доставка вернется в работу после lease.
+func (r *WebhookRepository) ClaimWebhookDeliveries(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]entity.WebhookJob, error) {
+	now := time.Now()
+
+	selectQuery, args := sq.Select(append(webhookDeliveryColumns, "e.url", "e.secret")...).
+		From("webhook_deliveries d").
+		InnerJoin("webhook_endpoints e ON e.id = d.endpoint_id").
+		Where(sq.Eq{"d.status": entity.WebhookDeliveryPending}).
+		Where(sq.LtOrEq{"d.next_attempt_at": now}).
+		OrderBy("d.next_attempt_at").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE OF d SKIP LOCKED").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var rows []model.WebhookJob
+	if err = tx.SelectContext(ctx, &rows, selectQuery, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch due webhook deliveries", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	updateQuery, args := sq.Update("webhook_deliveries").
+		Set("next_attempt_at", now.Add(lease)).
+		Where(sq.Eq{"id": ids}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = tx.ExecContext(ctx, updateQuery, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to claim webhook deliveries", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	jobs := make([]entity.WebhookJob, len(rows))
+	for i, row := range rows {
+		jobs[i] = entity.WebhookJob{
+			Delivery: model.ConvertWebhookDeliveryToEntity(row.WebhookDelivery),
+			URL:      row.URL,
+			Secret:   row.Secret,
+		}
+	}
+
+	return jobs, nil
+}
+
+// FinishWebhookAttempt сохраняет новое состояние доставки и запись о попытке в журнал
+func (r *WebhookRepository) FinishWebhookAttempt(
+	ctx context.Context,
+	delivery entity.WebhookDelivery,
+	attempt entity.WebhookAttempt,
+) error {
+	var lastError *string
+	if delivery.LastError != "" {
+		lastError = &delivery.LastError
+	}
+
+	updateQuery, args := sq.Update("webhook_deliveries").
+		Set("status", delivery.Status).
+		Set("attempts", delivery.Attempts).
+		Set("next_attempt_at", delivery.NextAttemptAt).
+		Set("last_status_code", delivery.LastStatusCode).
+		Set("last_error", lastError).
+		Set("delivered_at", delivery.DeliveredAt).
+		Where(sq.Eq{"id": delivery.ID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var attemptError, responseBody *string
+	if attempt.Error != "" {
+		attemptError = &attempt.Error
+	}
+	if attempt.ResponseBody != "" {
+		responseBody = &attempt.ResponseBody
+	}
+
+	insertQuery, insertArgs := sq.Insert("webhook_delivery_attempts").
+		Columns("delivery_id", "status_code", "error", "response_body", "duration_ms", "attempted_at").
+		Values(delivery.ID, attempt.StatusCode, attemptError, responseBody,
+			attempt.Duration.Milliseconds(), attempt.AttemptedAt).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to begin transaction", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err = tx.ExecContext(ctx, updateQuery, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to update webhook delivery", err)
+	}
+	if _, err = tx.ExecContext(ctx, insertQuery, insertArgs...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to record webhook attempt", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to commit transaction", err)
+	}
+
+	return nil
+}
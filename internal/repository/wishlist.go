@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EM-Stawberry/Stawberry/internal/app/apperror"
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
+	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+// minPriceJoin это минимальная цена продукта по всем магазинам в копейках
+const minPriceJoin = "(SELECT product_id, CAST(MIN(price) * 100 AS BIGINT) AS price " +
+	"FROM shop_inventory GROUP BY product_id) mp ON mp.product_id = w.product_id"
+
+type WishlistRepository struct {
+	db *sqlx.DB
+}
+
+func NewWishlistRepository(db *sqlx.DB) *WishlistRepository {
+	return &WishlistRepository{db: db}
+}
+
+func (r *WishlistRepository) SelectWishlist(ctx context.Context, userID uint) ([]entity.WishlistItem, error) {
+	query, args := sq.Select(
+		"w.user_id",
+		"w.product_id",
+		"p.name AS product_name",
+		"w.target_price",
+		"COALESCE(mp.price, 0) AS minimal_price",
+		"w.created_at",
+	).
+		From("wishlist_items w").
+		Join("products p ON p.id = w.product_id").
+		LeftJoin(minPriceJoin).
+		Where(sq.Eq{"w.user_id": userID}).
+		OrderBy("w.created_at DESC").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var items []model.WishlistItem
+	if err := r.db.SelectContext(ctx, &items, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch wishlist", err)
+	}
+
+	result := make([]entity.WishlistItem, len(items))
+	for i, item := range items {
+		result[i] = model.ConvertWishlistItemToEntity(item)
+	}
+
+	return result, nil
+}
+
+// UpsertWishlistItem добавляет продукт в список желаемого или меняет целевую цену.
+// После смены цены уведомление о снижении может прийти заново.
+func (r *WishlistRepository) UpsertWishlistItem(
+	ctx context.Context,
+	userID uint,
+	productID int,
+	targetPrice *int,
+) error {
+	query, args := sq.Insert("wishlist_items").
+		Columns("user_id", "product_id", "target_price").
+		Values(userID, productID, targetPrice).
+		Suffix("ON CONFLICT (user_id, product_id) DO UPDATE " +
+			"SET target_price = EXCLUDED.target_price, notified_price = NULL").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return apperror.ErrProductNotFound
+		}
+		return apperror.New(apperror.DatabaseError, "failed to save wishlist item", err)
+	}
+
+	return nil
+}
+
+func (r *WishlistRepository) DeleteWishlistItem(ctx context.Context, userID uint, productID int) error {
+	query, args := sq.Delete("wishlist_items").
+		Where(sq.Eq{"user_id": userID, "product_id": productID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	return r.execDelete(ctx, query, args, apperror.ErrWishlistItemNotFound)
+}
+
+func (r *WishlistRepository) SelectSavedSearches(ctx context.Context, userID uint) ([]entity.SavedSearch, error) {
+	query, args := sq.Select("id", "user_id", "name", "filter", "last_product_id", "created_at").
+		From("saved_searches").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("created_at DESC").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	return r.selectSavedSearches(ctx, query, args)
+}
+
+// InsertSavedSearch сохраняет поиск. Уже существующие продукты считаются известными пользователю.
+func (r *WishlistRepository) InsertSavedSearch(ctx context.Context, search entity.SavedSearch) (uint, error) {
+	query, args := sq.Insert("saved_searches").
+		Columns("user_id", "name", "filter", "last_product_id").
+		Values(search.UserID, search.Name, string(search.Filter), sq.Expr("(SELECT COALESCE(MAX(id), 0) FROM products)")).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var id uint
+	if err := r.db.GetContext(ctx, &id, query, args...); err != nil {
+		return 0, apperror.New(apperror.DatabaseError, "failed to save search", err)
+	}
+
+	return id, nil
+}
+
+func (r *WishlistRepository) DeleteSavedSearch(ctx context.Context, userID, searchID uint) error {
+	query, args := sq.Delete("saved_searches").
+		Where(sq.Eq{"id": searchID, "user_id": userID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	return r.execDelete(ctx, query, args, apperror.ErrSavedSearchNotFound)
+}
+
+// SelectPriceDrops находит товары из списков желаемого, цена на которые опустилась до целевой
+// и ниже цены из последнего уведомления
+func (r *WishlistRepository) SelectPriceDrops(ctx context.Context) ([]entity.PriceDrop, error) {
+	query, args := sq.Select(
+		"w.user_id",
+		"u.email AS user_email",
+		"w.product_id",
+		"p.name AS product_name",
+		"mp.price",
+		"w.target_price",
+		"w.notified_price",
+	).
+		From("wishlist_items w").
+		Join("users u ON u.id = w.user_id").
+		Join("products p ON p.id = w.product_id").
+		Join(minPriceJoin).
+		Where("w.target_price IS NOT NULL").
+		Where("mp.price <= w.target_price").
+		Where("(w.notified_price IS NULL OR mp.price < w.notified_price)").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var drops []model.PriceDrop
+	if err := r.db.SelectContext(ctx, &drops, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch price drops", err)
+	}
+
+	result := make([]entity.PriceDrop, len(drops))
+	for i, drop := range drops {
+		result[i] = model.ConvertPriceDropToEntity(drop)
+	}
+
+	return result, nil
+}
+
+// SwapNotifiedPrice меняет цену последнего уведомления на to, только если она все еще равна from.
+// Так уведомление о снижении забирает один экземпляр приложения, остальные получают false.
+func (r *WishlistRepository) SwapNotifiedPrice(
+	ctx context.Context,
+	userID uint,
+	productID int,
+	from, to *int,
+) (bool, error) {
+	query, args := sq.Update("wishlist_items").
+		Set("notified_price", to).
+		Where(sq.Eq{"user_id": userID, "product_id": productID}).
+		Where(sq.Expr("notified_price IS NOT DISTINCT FROM ?", from)).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, apperror.New(apperror.DatabaseError, "failed to mark price drop as notified", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, apperror.New(apperror.DatabaseError, "failed to mark price drop as notified", err)
+	}
+
+	return affected > 0, nil
+}
+
+// ResetRecoveredPrices сбрасывает отметку об уведомлении, если цена снова поднялась выше целевой,
+// чтобы о следующем снижении пользователь тоже узнал
+func (r *WishlistRepository) ResetRecoveredPrices(ctx context.Context) error {
+	query, args := sq.Update("wishlist_items w").
+		Set("notified_price", nil).
+		Where("w.notified_price IS NOT NULL").
+		Where("NOT EXISTS (SELECT 1 FROM shop_inventory si " +
+			"WHERE si.product_id = w.product_id AND CAST(si.price * 100 AS BIGINT) <= w.target_price)").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to reset recovered prices", err)
+	}
+
+	return nil
+}
+
+// SelectAllSavedSearches возвращает сохраненные поиски всех пользователей вместе с их почтой
+func (r *WishlistRepository) SelectAllSavedSearches(ctx context.Context) ([]entity.SavedSearch, error) {
+	query, args := sq.Select(
+		"ss.id",
+		"ss.user_id",
+		"u.email AS user_email",
+		"ss.name",
+		"ss.filter",
+		"ss.last_product_id",
+		"ss.created_at",
+	).
+		From("saved_searches ss").
+		Join("users u ON u.id = ss.user_id").
+		OrderBy("ss.id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	return r.selectSavedSearches(ctx, query, args)
+}
+
+// SwapSavedSearchCursor сдвигает курсор поиска на to, только если он все еще равен from.
+// Так новые продукты поиска забирает один экземпляр приложения, остальные получают false.
+func (r *WishlistRepository) SwapSavedSearchCursor(ctx context.Context, searchID uint, from, to int) (bool, error) {
+	query, args := sq.Update("saved_searches").
+		Set("last_product_id", to).
+		Where(sq.Eq{"id": searchID, "last_product_id": from}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, apperror.New(apperror.DatabaseError, "failed to update saved search", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, apperror.New(apperror.DatabaseError, "failed to update saved search", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *WishlistRepository) selectSavedSearches(
+	ctx context.Context,
+	query string,
+	args []interface{},
+) ([]entity.SavedSearch, error) {
+	var searches []model.SavedSearch
+	if err := r.db.SelectContext(ctx, &searches, query, args...); err != nil {
+		return nil, apperror.New(apperror.DatabaseError, "failed to fetch saved searches", err)
+	}
+
+	result := make([]entity.SavedSearch, len(searches))
+	for i, search := range searches {
+		result[i] = model.ConvertSavedSearchToEntity(search)
+	}
+
+	return result, nil
+}
+
+func (r *WishlistRepository) execDelete(ctx context.Context, query string, args []interface{}, notFound error) error {
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to delete", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return apperror.New(apperror.DatabaseError, "failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return notFound
+	}
+
+	return nil
+}
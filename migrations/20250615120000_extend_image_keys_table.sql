@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE image_keys
+    ALTER COLUMN image_key TYPE VARCHAR(255),
+    ADD COLUMN thumbnail_key VARCHAR(255),
+    ADD COLUMN content_type VARCHAR(50) NOT NULL DEFAULT 'image/jpeg',
+    ADD COLUMN size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE image_keys
+    DROP COLUMN created_at,
+    DROP COLUMN size,
+    DROP COLUMN content_type,
+    DROP COLUMN thumbnail_key,
+    ALTER COLUMN image_key TYPE VARCHAR(50);
+-- +goose StatementEnd
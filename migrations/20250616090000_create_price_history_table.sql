@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE price_history (
+    id BIGSERIAL PRIMARY KEY,
+    product_id INT NOT NULL,
+    shop_id INT NOT NULL,
+    price DECIMAL(10, 2) NOT NULL,
+    currency char(3) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (product_id, shop_id) REFERENCES shop_inventory(product_id, shop_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_price_history_product_shop_changed ON price_history(product_id, shop_id, changed_at);
+
+-- Цена записывается триггером, чтобы история не зависела от того, кто и как меняет ассортимент
+CREATE FUNCTION record_price_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT'
+        OR NEW.price IS DISTINCT FROM OLD.price
+        OR NEW.currency IS DISTINCT FROM OLD.currency THEN
+        INSERT INTO price_history (product_id, shop_id, price, currency)
+        VALUES (NEW.product_id, NEW.shop_id, NEW.price, NEW.currency);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER shop_inventory_price_history
+    AFTER INSERT OR UPDATE OF price, currency ON shop_inventory
+    FOR EACH ROW EXECUTE FUNCTION record_price_change();
+
+-- Текущие цены становятся начальной точкой истории
+INSERT INTO price_history (product_id, shop_id, price, currency)
+SELECT product_id, shop_id, price, currency FROM shop_inventory;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS shop_inventory_price_history ON shop_inventory;
+DROP FUNCTION IF EXISTS record_price_change();
+DROP TABLE IF EXISTS price_history;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN role user_role NOT NULL DEFAULT 'user';
+
+UPDATE users SET role = 'shop' WHERE is_store;
+
+ALTER TABLE users DROP COLUMN is_store;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN is_store BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE users SET is_store = TRUE WHERE role = 'shop';
+
+ALTER TABLE users DROP COLUMN role;
+-- +goose StatementEnd
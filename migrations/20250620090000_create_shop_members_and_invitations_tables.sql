@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE shop_member_role AS ENUM ('owner', 'manager', 'agent');
+
+CREATE TABLE shop_members (
+    shop_id INT NOT NULL,
+    user_id INT NOT NULL,
+    role shop_member_role NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (shop_id, user_id),
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_shop_members_user_id ON shop_members(user_id);
+-- У магазина ровно один владелец
+CREATE UNIQUE INDEX idx_shop_members_owner ON shop_members(shop_id) WHERE role = 'owner';
+
+CREATE TABLE shop_invitations (
+    id SERIAL PRIMARY KEY,
+    shop_id INT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    role shop_member_role NOT NULL CHECK (role <> 'owner'),
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    invited_by INT,
+    expires_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (shop_id) REFERENCES shops(id) ON DELETE CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Владелец из shops.user_id становится участником магазина автоматически
+CREATE FUNCTION add_shop_owner_member() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO shop_members (shop_id, user_id, role)
+    VALUES (NEW.id, NEW.user_id, 'owner');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER shops_owner_member
+    AFTER INSERT ON shops
+    FOR EACH ROW EXECUTE FUNCTION add_shop_owner_member();
+
+INSERT INTO shop_members (shop_id, user_id, role)
+SELECT id, user_id, 'owner' FROM shops;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS shops_owner_member ON shops;
+DROP FUNCTION IF EXISTS add_shop_owner_member();
+DROP TABLE IF EXISTS shop_invitations;
+DROP TABLE IF EXISTS shop_members;
+DROP TYPE IF EXISTS shop_member_role;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Удаленные аккаунты не удаляются физически: на них ссылаются заявки и отзывы,
+-- поэтому персональные данные обезличиваются, а строка помечается deleted_at
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- family_id объединяет все refresh токены, выпущенные по цепочке обновлений от одного входа.
+-- Уже выданные токены получают каждый свою семью.
+-- replaced_by указывает на токен, выпущенный взамен при обновлении.
+ALTER TABLE refresh_tokens
+    ADD COLUMN family_id UUID NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN replaced_by UUID;
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE refresh_tokens
+    DROP COLUMN family_id,
+    DROP COLUMN replaced_by;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- access_jti это jti последнего access токена, выпущенного вместе с refresh токеном.
+-- По нему гасятся access токены сессии при выходе, смене пароля и блокировке.
+ALTER TABLE refresh_tokens
+    ADD COLUMN access_jti VARCHAR(64) NOT NULL DEFAULT '';
+
+-- revoked_access_tokens это список отозванных access токенов, запись нужна только до истечения токена
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at);
+
+ALTER TABLE users
+    ADD COLUMN banned_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN banned_at;
+
+DROP TABLE IF EXISTS revoked_access_tokens;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN access_jti;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- login_attempts считает неудачные попытки входа по учетной записи (account:<email>) и по адресу клиента (ip:<addr>).
+-- blocked_until задает, до какого момента попытки входа отклоняются без проверки пароля.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    blocked_until TIMESTAMP
+);
+
+CREATE INDEX idx_login_attempts_last_failed_at ON login_attempts(last_failed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS login_attempts;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- totp_secret хранит секрет TOTP в base32. Пока totp_enabled_at пуст, секрет ожидает подтверждения кодом
+-- и вход по-прежнему выполняется только по паролю.
+-- totp_last_step хранит последний принятый шаг TOTP, чтобы один код нельзя было использовать повторно.
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(64),
+    ADD COLUMN totp_enabled_at TIMESTAMP,
+    ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+-- user_recovery_codes хранит Argon2id хэши резервных кодов для входа без приложения-аутентификатора
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMP
+);
+
+CREATE INDEX idx_user_recovery_codes_user_id ON user_recovery_codes(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_recovery_codes;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS totp_last_step,
+    DROP COLUMN IF EXISTS totp_enabled_at,
+    DROP COLUMN IF EXISTS totp_secret;
+-- +goose StatementEnd
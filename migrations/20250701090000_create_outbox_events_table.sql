@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- outbox доменных событий: строка пишется в одной транзакции с изменением,
+-- а фоновый relay раздает событие подписчикам
+CREATE TABLE outbox_events (
+    id SERIAL PRIMARY KEY,
+    uid UUID NOT NULL UNIQUE,
+    event_type VARCHAR(50) NOT NULL,
+    actor_id INT,
+    payload JSONB NOT NULL,
+    -- pending ждут раздачи, processed обработаны всеми подписчиками, dead исчерпали попытки
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    -- подписчики, которые уже обработали событие, через пробел
+    completed TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    processed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_outbox_events_due ON outbox_events(next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX idx_outbox_events_processed_at ON outbox_events(processed_at) WHERE status = 'processed';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox_events;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notifications
+    ADD COLUMN type VARCHAR(32) NOT NULL DEFAULT 'system',
+    ADD COLUMN link TEXT NOT NULL DEFAULT '',
+    ADD COLUMN read_at TIMESTAMP;
+
+DROP INDEX IF EXISTS idx_notifications_user_id;
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, id DESC);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifications_unread;
+DROP INDEX IF EXISTS idx_notifications_user_id;
+CREATE INDEX idx_notifications_user_id ON notifications(user_id);
+
+ALTER TABLE notifications
+    DROP COLUMN read_at,
+    DROP COLUMN link,
+    DROP COLUMN type;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    channels JSONB NOT NULL DEFAULT '{}',
+    quiet_hours_start SMALLINT,
+    quiet_hours_end SMALLINT,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    digest VARCHAR(16) NOT NULL DEFAULT 'off',
+    last_digest_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE notifications
+    ADD COLUMN email_pending BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_notifications_email_pending ON notifications(user_id) WHERE email_pending;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifications_email_pending;
+
+ALTER TABLE notifications
+    DROP COLUMN email_pending;
+
+DROP TABLE IF EXISTS notification_preferences;
+-- +goose StatementEnd
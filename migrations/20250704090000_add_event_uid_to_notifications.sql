@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE notifications
+    ADD COLUMN event_uid UUID,
+    ADD CONSTRAINT notifications_event_uid_user_id_key UNIQUE (event_uid, user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notifications
+    DROP CONSTRAINT IF EXISTS notifications_event_uid_user_id_key,
+    DROP COLUMN event_uid;
+-- +goose StatementEnd
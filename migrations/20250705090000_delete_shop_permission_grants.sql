@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- offers:respond и inventory:write дает только роль сотрудника магазина, личные выдачи ничего не разрешали
+DELETE FROM user_permissions WHERE permission IN ('offers:respond', 'inventory:write');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 1;
+-- +goose StatementEnd
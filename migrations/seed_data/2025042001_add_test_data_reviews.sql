@@ -52,7 +52,7 @@ INSERT INTO
         phone_number,
         password_hash,
         email,
-        is_store
+        role
     )
 VALUES
     (
@@ -60,28 +60,28 @@ VALUES
         '+1234567890',
         'hashed_password_1',
         'john.doe@example.com',
-        FALSE
+        'user'
     ), -- Покупатель, id=1
     (
         'Jane Smith',
         '+1234567891',
         'hashed_password_2',
         'jane.smith@example.com',
-        FALSE
+        'user'
     ), -- Покупатель, id=2
     (
         'Store Owner 1',
         '+1234567892',
         'hashed_password_3',
         'store1@example.com',
-        TRUE
+        'shop'
     ), -- Продавец, id=3
     (
         'Store Owner 2',
         '+1234567893',
         'hashed_password_4',
         'store2@example.com',
-        TRUE
+        'shop'
     );
 
 -- Продавец, id=4
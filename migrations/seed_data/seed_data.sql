@@ -103,17 +103,17 @@ INSERT INTO offers (offer_price, currency, status, created_at, updated_at, expir
 
 
 -- Insert test notifications
-INSERT INTO notifications (message, user_id) VALUES
-    ('A new offer was placed on "SuperFast Laptop".', 1),      -- To owner of shop 1 (user 1)
-    ('Your offer for "SuperFast Laptop" has been received.', 3), -- To user 3
-    ('You accepted an offer for "4K UltraWide Monitor".', 1),   -- To owner of shop 1 (user 1)
-    ('Your offer for "4K UltraWide Monitor" was accepted!', 4), -- To user 4
-    ('You rejected an offer for "Tough Phone Case".', 2),       -- To owner of shop 2 (user 2)
-    ('Your offer for "Tough Phone Case" was rejected.', 3),     -- To user 3
-    ('A new offer was placed on "The Art of Go".', 2),          -- To owner of shop 2 (user 2)
-    ('Your offer for "The Art of Go" has been received.', 4),    -- To user 4
-    ('A new offer was placed on "Non-stick Pan Set".', 2),      -- To owner of shop 2 (user 2)
-    ('Your offer for "Non-stick Pan Set" has been received.', 3); -- To user 3
+INSERT INTO notifications (type, message, link, user_id) VALUES
+    ('offer_received', 'A new offer was placed on "SuperFast Laptop".', '/offers/1', 1),               -- To owner of shop 1 (user 1)
+    ('system', 'Your offer for "SuperFast Laptop" has been received.', '/offers/1', 3),               -- To user 3
+    ('system', 'You accepted an offer for "4K UltraWide Monitor".', '/offers/2', 1),                 -- To owner of shop 1 (user 1)
+    ('offer_status_changed', 'Your offer for "4K UltraWide Monitor" was accepted!', '/offers/2', 4), -- To user 4
+    ('system', 'You rejected an offer for "Tough Phone Case".', '/offers/3', 2),                     -- To owner of shop 2 (user 2)
+    ('offer_status_changed', 'Your offer for "Tough Phone Case" was rejected.', '/offers/3', 3),     -- To user 3
+    ('offer_received', 'A new offer was placed on "The Art of Go".', '/offers/4', 2),                -- To owner of shop 2 (user 2)
+    ('system', 'Your offer for "The Art of Go" has been received.', '/offers/4', 4),                 -- To user 4
+    ('offer_received', 'A new offer was placed on "Non-stick Pan Set".', '/offers/5', 2),            -- To owner of shop 2 (user 2)
+    ('system', 'Your offer for "Non-stick Pan Set" has been received.', '/offers/5', 3);             -- To user 3
 
 -- Insert test refresh tokens
 INSERT INTO refresh_tokens (uuid, created_at, expires_at, revoked_at, fingerprint, user_id) VALUES
@@ -3,8 +3,10 @@ package database
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/EM-Stawberry/Stawberry/internal/domain/entity"
 	"github.com/EM-Stawberry/Stawberry/internal/repository/model"
 	"github.com/EM-Stawberry/Stawberry/pkg/security"
 	"github.com/Masterminds/squirrel"
@@ -42,10 +44,10 @@ func SeedDB() {
 	}
 
 	q := squirrel.Insert("users").
-		Columns("name", "email", "phone_number", "password_hash", "is_store")
+		Columns("name", "email", "phone_number", "password_hash", "role")
 
 	for _, u := range users {
-		q = q.Values(u.Name, u.Email, u.Phone, u.Password, u.IsStore)
+		q = q.Values(u.Name, u.Email, u.Phone, u.Password, u.Role)
 	}
 
 	sql, args, err := q.PlaceholderFormat(squirrel.Dollar).ToSql()
@@ -72,7 +74,7 @@ func SeedDB() {
 		return
 	}
 
-	DefaultAdminAcc()
+	seedDefaultAdmin()
 }
 
 func formDefaultUsers() ([]model.User, error) {
@@ -84,13 +86,16 @@ func formDefaultUsers() ([]model.User, error) {
 			pkgLog.Error("Failed to hash password, aborting seeding", zap.Error(err))
 			return nil, err
 		}
+		role := entity.RoleUser
+		if strings.Contains(psw, "shop") {
+			role = entity.RoleShop
+		}
 		users = append(users, model.User{
 			Name:     psw,
 			Phone:    fmt.Sprintf("%sphone", psw),
 			Email:    fmt.Sprintf("%s@%s.com", psw, psw),
 			Password: hash,
-			IsStore:  strings.Contains(psw, "shop"),
-			// IsAdmin: false,
+			Role:     string(role),
 		})
 	}
 	return users, nil
@@ -111,14 +116,27 @@ func ClearDB() {
 		return
 	}
 
-	DefaultAdminAcc()
+	seedDefaultAdmin()
 }
 
-func DefaultAdminAcc() {
+// minAdminPasswordLength это минимальная длина пароля создаваемого администратора
+const minAdminPasswordLength = 12
+
+// weakAdminPasswords это пароли из примеров конфигурации, с ними администратор не создается
+var weakAdminPasswords = []string{"default_admin_password", "admin", "password"}
+
+// DefaultAdminAcc создает аккаунт администратора admin@admin.com, если его еще нет.
+// Существующий аккаунт с этим адресом не меняется: иначе адрес, освобожденный администратором,
+// мог бы занять другой пользователь и получить роль администратора при следующем запуске.
+func DefaultAdminAcc() error {
+	if len(pkgCfg.DefAdmPswd) < minAdminPasswordLength || slices.Contains(weakAdminPasswords, pkgCfg.DefAdmPswd) {
+		return fmt.Errorf("DEFAULT_ADMIN_PSWD must be set to a non-default password of at least %d characters",
+			minAdminPasswordLength)
+	}
+
 	hash, err := security.HashArgon2id(pkgCfg.DefAdmPswd)
 	if err != nil {
-		pkgLog.Error("Failed to hash default admin password", zap.Error(err))
-		return
+		return fmt.Errorf("failed to hash default admin password: %w", err)
 	}
 
 	admin := model.User{
@@ -126,19 +144,33 @@ func DefaultAdminAcc() {
 		Phone:    "adminphone",
 		Email:    "admin@admin.com",
 		Password: hash,
-		IsStore:  false,
-		// IsAdmin: true,
+		Role:     string(entity.RoleAdmin),
 	}
 
-	// PLEASE ADD ADMIN FLAG WHEN POSSIBLE
 	q, args := squirrel.Insert("users").
-		Columns("name", "email", "phone_number", "password_hash", "is_store").
-		Values(admin.Name, admin.Email, admin.Phone, admin.Password, admin.IsStore).
+		Columns("name", "email", "phone_number", "password_hash", "role").
+		Values(admin.Name, admin.Email, admin.Phone, admin.Password, admin.Role).
+		Suffix("ON CONFLICT (email) DO NOTHING").
 		PlaceholderFormat(squirrel.Dollar).MustSql()
 
-	_, err = pkgDB.Exec(q, args...)
-	if err != nil && !strings.Contains(err.Error(), "duplicate key value") {
-		pkgLog.Error("Failed to insert default admin account", zap.Error(err))
+	res, err := pkgDB.Exec(q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert default admin account: %w", err)
+	}
+
+	if created, err := res.RowsAffected(); err == nil && created == 0 {
+		pkgLog.Info("Default admin account email is already taken, account left unchanged")
+	}
+
+	return nil
+}
+
+// seedDefaultAdmin создает администратора после очистки базы, если это включено в конфигурации
+func seedDefaultAdmin() {
+	if !pkgCfg.CreateDefaultAdmin {
 		return
 	}
+	if err := DefaultAdminAcc(); err != nil {
+		pkgLog.Error("Failed to create default admin account", zap.Error(err))
+	}
 }
@@ -3,6 +3,7 @@ package email
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,11 +15,16 @@ import (
 //go:generate go.uber.org/mock/mockgen -source=$GOFILE -destination=mock_email/mock_email.go -package=mock_email
 
 type MailerService interface {
-	Registered(userName string, userMail string)
-	StatusUpdate(offerID uint, status string, userMail string)
-	OfferReceived(offerID uint, userMail string)
+	Registered(userName string, verificationToken string, userMail string)
+	VerificationRequested(verificationToken string, userMail string)
+	PasswordResetRequested(resetToken string, userMail string)
+	RefreshTokenReused(ip string, userMail string)
+	AccountLocked(unlockToken string, userMail string)
+	Notification(message string, userMail string)
+	NotificationDigest(messages []string, userMail string)
 	Stop(ctx context.Context)
 	SendGuestOfferNotification(email string, subject string, body string)
+	ShopInvitation(shopName string, role string, token string, userMail string)
 }
 
 type SMTPMailer struct {
@@ -166,37 +172,103 @@ func (m *SMTPMailer) enqueue(msg *gomail.Message) {
 	}
 }
 
-func (m *SMTPMailer) StatusUpdate(offerID uint, status string, userMail string) {
+func (m *SMTPMailer) Notification(message string, userMail string) {
 	if !m.enabled {
 		return
 	}
 
-	subject := fmt.Sprintf("Stawberry: Offer Status Update (ID %d)", offerID)
-	body := fmt.Sprintf("The status of your offer (%d) has been changed to: %s", offerID, status)
-	msg := m.createMessage(userMail, subject, body)
+	subject := "Stawberry: " + message
+	msg := m.createMessage(userMail, subject, message)
 
 	m.enqueue(msg)
 }
 
-func (m *SMTPMailer) OfferReceived(offerID uint, userMail string) {
+func (m *SMTPMailer) NotificationDigest(messages []string, userMail string) {
 	if !m.enabled {
 		return
 	}
 
-	subject := fmt.Sprintf("Stawberry: New Offer Received (ID %d)", offerID)
-	body := fmt.Sprintf("A new offer (%d) has been received", offerID)
+	subject := fmt.Sprintf("Stawberry: %d new notification(s)", len(messages))
+	body := "Here is what happened since our last email:\n\n- " + strings.Join(messages, "\n- ")
 	msg := m.createMessage(userMail, subject, body)
 
 	m.enqueue(msg)
 }
 
-func (m *SMTPMailer) Registered(userName string, userMail string) {
+func (m *SMTPMailer) Registered(userName string, verificationToken string, userMail string) {
 	if !m.enabled {
 		return
 	}
 
 	subject := "Welcome to Strawberry!"
-	body := fmt.Sprintf("Thank you for registering, %s.", userName)
+	body := fmt.Sprintf("Thank you for registering, %s. "+
+		"Please verify your email using the token: %s", userName, verificationToken)
+	msg := m.createMessage(userMail, subject, body)
+
+	m.enqueue(msg)
+}
+
+func (m *SMTPMailer) VerificationRequested(verificationToken string, userMail string) {
+	if !m.enabled {
+		return
+	}
+
+	subject := "Stawberry: Email Verification"
+	body := fmt.Sprintf("Please verify your email using the token: %s", verificationToken)
+	msg := m.createMessage(userMail, subject, body)
+
+	m.enqueue(msg)
+}
+
+func (m *SMTPMailer) PasswordResetRequested(resetToken string, userMail string) {
+	if !m.enabled {
+		return
+	}
+
+	subject := "Stawberry: Password Reset"
+	body := fmt.Sprintf("To reset your password use the token: %s\n"+
+		"If you did not request a password reset, ignore this email.", resetToken)
+	msg := m.createMessage(userMail, subject, body)
+
+	m.enqueue(msg)
+}
+
+func (m *SMTPMailer) RefreshTokenReused(ip string, userMail string) {
+	if !m.enabled {
+		return
+	}
+
+	subject := "Stawberry: Suspicious Sign-In Activity"
+	body := fmt.Sprintf("An already used session token was presented from %s. "+
+		"We signed out the affected session on all devices. "+
+		"If this was not you, change your password.", ip)
+	msg := m.createMessage(userMail, subject, body)
+
+	m.enqueue(msg)
+}
+
+func (m *SMTPMailer) AccountLocked(unlockToken string, userMail string) {
+	if !m.enabled {
+		return
+	}
+
+	subject := "Stawberry: Account Temporarily Locked"
+	body := fmt.Sprintf("We locked sign-in to your account after too many failed password attempts.\n"+
+		"To unlock it now use the token: %s\n"+
+		"If these attempts were not yours, consider changing your password.", unlockToken)
+	msg := m.createMessage(userMail, subject, body)
+
+	m.enqueue(msg)
+}
+
+func (m *SMTPMailer) ShopInvitation(shopName string, role string, token string, userMail string) {
+	if !m.enabled {
+		return
+	}
+
+	subject := fmt.Sprintf("Stawberry: Invitation to %s", shopName)
+	body := fmt.Sprintf("You have been invited to join %s as %s. "+
+		"Sign in with this email and accept the invitation using the token: %s", shopName, role, token)
 	msg := m.createMessage(userMail, subject, body)
 
 	m.enqueue(msg)
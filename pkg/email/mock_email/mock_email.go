@@ -40,28 +40,76 @@ func (m *MockMailerService) EXPECT() *MockMailerServiceMockRecorder {
 	return m.recorder
 }
 
-// OfferReceived mocks base method.
-func (m *MockMailerService) OfferReceived(offerID uint, userMail string) {
+// AccountLocked mocks base method.
+func (m *MockMailerService) AccountLocked(unlockToken, userMail string) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "OfferReceived", offerID, userMail)
+	m.ctrl.Call(m, "AccountLocked", unlockToken, userMail)
 }
 
-// OfferReceived indicates an expected call of OfferReceived.
-func (mr *MockMailerServiceMockRecorder) OfferReceived(offerID, userMail any) *gomock.Call {
+// AccountLocked indicates an expected call of AccountLocked.
+func (mr *MockMailerServiceMockRecorder) AccountLocked(unlockToken, userMail any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OfferReceived", reflect.TypeOf((*MockMailerService)(nil).OfferReceived), offerID, userMail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountLocked", reflect.TypeOf((*MockMailerService)(nil).AccountLocked), unlockToken, userMail)
+}
+
+// Notification mocks base method.
+func (m *MockMailerService) Notification(message, userMail string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Notification", message, userMail)
+}
+
+// Notification indicates an expected call of Notification.
+func (mr *MockMailerServiceMockRecorder) Notification(message, userMail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notification", reflect.TypeOf((*MockMailerService)(nil).Notification), message, userMail)
+}
+
+// NotificationDigest mocks base method.
+func (m *MockMailerService) NotificationDigest(messages []string, userMail string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotificationDigest", messages, userMail)
+}
+
+// NotificationDigest indicates an expected call of NotificationDigest.
+func (mr *MockMailerServiceMockRecorder) NotificationDigest(messages, userMail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotificationDigest", reflect.TypeOf((*MockMailerService)(nil).NotificationDigest), messages, userMail)
+}
+
+// PasswordResetRequested mocks base method.
+func (m *MockMailerService) PasswordResetRequested(resetToken, userMail string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PasswordResetRequested", resetToken, userMail)
+}
+
+// PasswordResetRequested indicates an expected call of PasswordResetRequested.
+func (mr *MockMailerServiceMockRecorder) PasswordResetRequested(resetToken, userMail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PasswordResetRequested", reflect.TypeOf((*MockMailerService)(nil).PasswordResetRequested), resetToken, userMail)
+}
+
+// RefreshTokenReused mocks base method.
+func (m *MockMailerService) RefreshTokenReused(ip, userMail string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RefreshTokenReused", ip, userMail)
+}
+
+// RefreshTokenReused indicates an expected call of RefreshTokenReused.
+func (mr *MockMailerServiceMockRecorder) RefreshTokenReused(ip, userMail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokenReused", reflect.TypeOf((*MockMailerService)(nil).RefreshTokenReused), ip, userMail)
 }
 
 // Registered mocks base method.
-func (m *MockMailerService) Registered(userName, userMail string) {
+func (m *MockMailerService) Registered(userName, verificationToken, userMail string) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Registered", userName, userMail)
+	m.ctrl.Call(m, "Registered", userName, verificationToken, userMail)
 }
 
 // Registered indicates an expected call of Registered.
-func (mr *MockMailerServiceMockRecorder) Registered(userName, userMail any) *gomock.Call {
+func (mr *MockMailerServiceMockRecorder) Registered(userName, verificationToken, userMail any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Registered", reflect.TypeOf((*MockMailerService)(nil).Registered), userName, userMail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Registered", reflect.TypeOf((*MockMailerService)(nil).Registered), userName, verificationToken, userMail)
 }
 
 // SendGuestOfferNotification mocks base method.
@@ -76,16 +124,16 @@ func (mr *MockMailerServiceMockRecorder) SendGuestOfferNotification(email, subje
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendGuestOfferNotification", reflect.TypeOf((*MockMailerService)(nil).SendGuestOfferNotification), email, subject, body)
 }
 
-// StatusUpdate mocks base method.
-func (m *MockMailerService) StatusUpdate(offerID uint, status, userMail string) {
+// ShopInvitation mocks base method.
+func (m *MockMailerService) ShopInvitation(shopName, role, token, userMail string) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "StatusUpdate", offerID, status, userMail)
+	m.ctrl.Call(m, "ShopInvitation", shopName, role, token, userMail)
 }
 
-// StatusUpdate indicates an expected call of StatusUpdate.
-func (mr *MockMailerServiceMockRecorder) StatusUpdate(offerID, status, userMail any) *gomock.Call {
+// ShopInvitation indicates an expected call of ShopInvitation.
+func (mr *MockMailerServiceMockRecorder) ShopInvitation(shopName, role, token, userMail any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusUpdate", reflect.TypeOf((*MockMailerService)(nil).StatusUpdate), offerID, status, userMail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShopInvitation", reflect.TypeOf((*MockMailerService)(nil).ShopInvitation), shopName, role, token, userMail)
 }
 
 // Stop mocks base method.
@@ -99,3 +147,15 @@ func (mr *MockMailerServiceMockRecorder) Stop(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockMailerService)(nil).Stop), ctx)
 }
+
+// VerificationRequested mocks base method.
+func (m *MockMailerService) VerificationRequested(verificationToken, userMail string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "VerificationRequested", verificationToken, userMail)
+}
+
+// VerificationRequested indicates an expected call of VerificationRequested.
+func (mr *MockMailerServiceMockRecorder) VerificationRequested(verificationToken, userMail any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerificationRequested", reflect.TypeOf((*MockMailerService)(nil).VerificationRequested), verificationToken, userMail)
+}
@@ -0,0 +1,48 @@
+package security
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	// apiKeyTag начинает каждый ключ, чтобы его можно было найти в утекшем коде и логах
+	apiKeyTag = "stw_"
+	// apiKeyPrefixSize это размер открытого префикса ключа в байтах до кодирования
+	apiKeyPrefixSize = 6
+)
+
+// GenerateAPIKey возвращает ключ для выдачи клиенту, его открытый префикс и хэш секрета для хранения в БД.
+// Ключ имеет вид stw_<префикс>_<секрет>.
+func GenerateAPIKey() (string, string, string, error) {
+	buf := make([]byte, apiKeyPrefixSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate api key prefix: %w", err)
+	}
+	prefix := hex.EncodeToString(buf)
+
+	secret, secretHash, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return apiKeyTag + prefix + "_" + secret, prefix, secretHash, nil
+}
+
+// ParseAPIKey разбирает ключ на префикс и хэш секрета
+func ParseAPIKey(key string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(key, apiKeyTag)
+	if !ok {
+		return "", "", false
+	}
+
+	// префикс в hex не содержит "_", а секрет в base64url может его содержать
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 2*apiKeyPrefixSize || secret == "" {
+		return "", "", false
+	}
+
+	return prefix, HashOpaqueToken(secret), true
+}
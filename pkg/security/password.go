@@ -1,13 +1,17 @@
 package security
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/EM-Stawberry/Stawberry/config"
 	"golang.org/x/crypto/argon2"
 )
 
@@ -17,6 +21,8 @@ type param struct {
 	threads  uint8
 	saltSize uint32
 	keyLen   uint32
+	// peppered означает, что перед Argon2id пароль пропущен через HMAC-SHA256 с перцем
+	peppered bool
 }
 
 const (
@@ -27,53 +33,172 @@ const (
 	memory    = 64 * 1024
 )
 
-// Argon2idPasswordManager реализует интерфейс для хеширования и проверки паролей с использованием алгоритма Argon2id
-type Argon2idPasswordManager struct{}
+// defaultParams это параметры HashArgon2id и параметры по умолчанию для менеджера паролей
+var defaultParams = param{
+	memory:   memory,
+	time:     argonTime,
+	threads:  threads,
+	saltSize: saltSize,
+	keyLen:   keyLen,
+}
+
+// ErrPepperRequired возвращается при проверке хэша с перцем, когда перец не задан в конфигурации
+var ErrPepperRequired = errors.New("password hash requires a pepper")
+
+// ErrHashingBusy возвращается, когда все слоты вычисления хэшей заняты дольше допустимого ожидания
+var ErrHashingBusy = errors.New("password hashing is busy")
+
+// Argon2idPasswordManager реализует интерфейс для хеширования и проверки паролей с использованием алгоритма Argon2id.
+// Каждое вычисление занимает десятки мегабайт памяти, поэтому число одновременных вычислений ограничено.
+// Хэши проверяются с параметрами, записанными в них самих, поэтому после смены параметров
+// старые хэши продолжают работать, а NeedsRehash подсказывает, какие из них пора пересчитать.
+type Argon2idPasswordManager struct {
+	params param
+	pepper []byte
+	slots  chan struct{}
+	wait   time.Duration
+}
+
+// NewArgon2idPasswordManager создает новый экземпляр менеджера паролей Argon2id.
+// HashConcurrency ограничивает число одновременных вычислений, HashWait задает, сколько ждать свободного слота.
+// Нулевые параметры Argon2id заменяются значениями по умолчанию.
+func NewArgon2idPasswordManager(cfg *config.PasswordConfig) *Argon2idPasswordManager {
+	params := defaultParams
+	if cfg.ArgonMemory > 0 {
+		params.memory = cfg.ArgonMemory
+	}
+	if cfg.ArgonTime > 0 {
+		params.time = cfg.ArgonTime
+	}
+	if cfg.ArgonThreads > 0 {
+		params.threads = cfg.ArgonThreads
+	}
+
+	var pepper []byte
+	if cfg.Pepper != "" {
+		pepper = []byte(cfg.Pepper)
+		params.peppered = true
+	}
 
-// NewArgon2idPasswordManager создает новый экземпляр менеджера паролей Argon2id
-func NewArgon2idPasswordManager() *Argon2idPasswordManager {
-	return &Argon2idPasswordManager{}
+	return &Argon2idPasswordManager{
+		params: params,
+		pepper: pepper,
+		slots:  make(chan struct{}, max(cfg.HashConcurrency, 1)),
+		wait:   cfg.HashWait,
+	}
 }
 
 // Hash хеширует пароль с использованием алгоритма Argon2id
 func (a *Argon2idPasswordManager) Hash(password string) (string, error) {
-	return HashArgon2id(password)
+	if err := a.acquire(); err != nil {
+		return "", err
+	}
+	defer a.release()
+
+	return hashArgon2id(password, a.params, a.pepper)
 }
 
 // Compare сравнивает пароль с его хешем используя алгоритм Argon2id
 func (a *Argon2idPasswordManager) Compare(password, hash string) (bool, error) {
-	return ComparePasswordAndArgon2id(password, hash)
+	if err := a.acquire(); err != nil {
+		return false, err
+	}
+	defer a.release()
+
+	return compareArgon2id(password, hash, a.pepper)
 }
 
+// NeedsRehash сообщает, что хэш вычислен с другими параметрами или без перца
+// и его нужно пересчитать, пока пароль известен
+func (a *Argon2idPasswordManager) NeedsRehash(hash string) bool {
+	p, _, _, err := decodeArgon2id(hash)
+	if err != nil {
+		return false
+	}
+
+	return p.memory != a.params.memory ||
+		p.time != a.params.time ||
+		p.threads != a.params.threads ||
+		p.keyLen != a.params.keyLen ||
+		p.peppered != a.params.peppered
+}
+
+func (a *Argon2idPasswordManager) acquire() error {
+	timer := time.NewTimer(a.wait)
+	defer timer.Stop()
+
+	select {
+	case a.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrHashingBusy
+	}
+}
+
+func (a *Argon2idPasswordManager) release() {
+	<-a.slots
+}
+
+// HashArgon2id хеширует пароль с параметрами по умолчанию и без перца.
+// Такие хэши менеджер паролей пересчитывает при входе, если его параметры отличаются.
 func HashArgon2id(password string) (string, error) {
-	salt, err := generateRandomBytes(saltSize)
+	return hashArgon2id(password, defaultParams, nil)
+}
+
+// ComparePasswordAndArgon2id сравнивает пароль с хэшем без перца
+func ComparePasswordAndArgon2id(password, encodedHash string) (bool, error) {
+	return compareArgon2id(password, encodedHash, nil)
+}
+
+func hashArgon2id(password string, p param, pepper []byte) (string, error) {
+	salt, err := generateRandomBytes(p.saltSize)
 	if err != nil {
 		return "", err
 	}
 
-	hash := argon2.IDKey([]byte(password), salt, argonTime, memory, threads, keyLen)
+	hash := argon2.IDKey(pepperPassword(password, pepper), salt, p.time, p.memory, p.threads, p.keyLen)
 	b64Salt := base64.RawStdEncoding.EncodeToString(salt)
 	b64Hash := base64.RawStdEncoding.EncodeToString(hash)
 
-	encodedHash := fmt.Sprintf(
-		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
-		argon2.Version, memory, argonTime, threads, b64Salt, b64Hash,
-	)
+	options := fmt.Sprintf("m=%d,t=%d,p=%d", p.memory, p.time, p.threads)
+	if p.peppered {
+		options += ",pepper=1"
+	}
+
+	encodedHash := fmt.Sprintf("$argon2id$v=%d$%s$%s$%s", argon2.Version, options, b64Salt, b64Hash)
 
 	return encodedHash, nil
 }
 
-func ComparePasswordAndArgon2id(password, encodedHash string) (bool, error) {
+func compareArgon2id(password, encodedHash string, pepper []byte) (bool, error) {
 	p, salt, hash, err := decodeArgon2id(encodedHash)
 	if err != nil {
 		return false, err
 	}
 
-	otherHash := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, p.keyLen)
+	if !p.peppered {
+		pepper = nil
+	} else if len(pepper) == 0 {
+		return false, ErrPepperRequired
+	}
+
+	otherHash := argon2.IDKey(pepperPassword(password, pepper), salt, p.time, p.memory, p.threads, p.keyLen)
 
 	return subtle.ConstantTimeCompare(hash, otherHash) == 1, nil
 }
 
+// pepperPassword пропускает пароль через HMAC-SHA256 с перцем. Перец хранится вне базы,
+// поэтому утекшие из базы хэши нельзя перебирать без него.
+func pepperPassword(password string, pepper []byte) []byte {
+	if len(pepper) == 0 {
+		return []byte(password)
+	}
+
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write([]byte(password))
+	return mac.Sum(nil)
+}
+
 func generateRandomBytes(n uint32) ([]byte, error) {
 	b := make([]byte, n)
 	if _, err := rand.Read(b); err != nil {
@@ -103,6 +228,7 @@ func decodeArgon2id(encodedHash string) (param, []byte, []byte, error) {
 	if err != nil {
 		return param{}, nil, nil, err
 	}
+	p.peppered = strings.HasSuffix(vals[3], ",pepper=1")
 
 	salt, err := base64.RawStdEncoding.Strict().DecodeString(vals[4])
 	if err != nil {
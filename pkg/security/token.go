@@ -0,0 +1,29 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// opaqueTokenSize это размер одноразового токена в байтах до кодирования
+const opaqueTokenSize = 32
+
+// GenerateOpaqueToken возвращает случайный токен для отправки пользователю и его хеш для хранения в БД
+func GenerateOpaqueToken() (string, string, error) {
+	buf := make([]byte, opaqueTokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashOpaqueToken(token), nil
+}
+
+// HashOpaqueToken возвращает sha256 токена в hex, по нему токен ищется в БД
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,37 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+const (
+	webhookSecretTag  = "whsec_"
+	webhookSecretSize = 32
+)
+
+// GenerateWebhookSecret возвращает секрет для подписи вебхуков адреса
+func GenerateWebhookSecret() (string, error) {
+	buf := make([]byte, webhookSecretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return webhookSecretTag + hex.EncodeToString(buf), nil
+}
+
+// SignWebhook возвращает значение заголовка подписи вида t=<unix время>,v1=<hex HMAC-SHA256>.
+// Подписывается строка "<unix время>.<тело запроса>", так получатель может отбросить старые запросы.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
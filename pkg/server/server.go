@@ -18,16 +18,22 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// StartServer запускает сервер и останавливает его по сигналу. onShutdown вызываются в начале
+// остановки, ими закрываются долгие соединения, которых Shutdown иначе дожидался бы.
 func StartServer(
 	router *gin.Engine,
 	mailer email.MailerService,
 	cfg *config.ServerConfig,
-	log *zap.Logger) error {
+	log *zap.Logger,
+	onShutdown ...func()) error {
 	srv := &http.Server{
 		Addr:              ":" + cfg.Port,
 		Handler:           router,
 		ReadHeaderTimeout: 3 * time.Second,
 	}
+	for _, f := range onShutdown {
+		srv.RegisterOnShutdown(f)
+	}
 
 	switch cfg.GinMode {
 	case gin.DebugMode: